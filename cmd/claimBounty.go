@@ -45,6 +45,12 @@ func (*UtilsStruct) ExecuteClaimBounty(flagSet *pflag.FlagSet) {
 
 	password := razorUtils.AssignPassword(flagSet)
 
+	// The dispute data file is rewritten after claiming, so it is encrypted again if it has to be
+	encryptDataFiles, err := flagSetUtils.GetBoolEncryptDataFiles(flagSet)
+	utils.CheckError("Error in getting encrypt data files status: ", err)
+	err = razorUtils.SetDataFilesPassword(password, encryptDataFiles)
+	utils.CheckError("Error in setting password of data files: ", err)
+
 	client := razorUtils.ConnectToClient(config.Provider)
 
 	if utilsInterface.IsFlagPassed("bountyId") {
@@ -181,14 +187,16 @@ func (*UtilsStruct) ClaimBounty(config types.Configurations, client *ethclient.C
 func init() {
 	rootCmd.AddCommand(claimBountyCmd)
 	var (
-		Address  string
-		Password string
-		BountyId uint32
+		Address          string
+		Password         string
+		BountyId         uint32
+		EncryptDataFiles bool
 	)
 
 	claimBountyCmd.Flags().StringVarP(&Address, "address", "a", "", "address of the staker")
 	claimBountyCmd.Flags().StringVarP(&Password, "password", "", "", "password path of staker to protect the keystore")
 	claimBountyCmd.Flags().Uint32VarP(&BountyId, "bountyId", "", 0, "bountyId of the bounty hunter")
+	claimBountyCmd.Flags().BoolVarP(&EncryptDataFiles, "encryptDataFiles", "", false, "encrypt the dispute data saved after claiming with the password of the staker")
 
 	addrErr := claimBountyCmd.MarkFlagRequired("address")
 	utils.CheckError("Address error: ", addrErr)
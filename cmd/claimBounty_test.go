@@ -137,6 +137,8 @@ func TestExecuteClaimBounty(t *testing.T) {
 			utilsMock.On("AssignLogFile", mock.AnythingOfType("*pflag.FlagSet"))
 			cmdUtilsMock.On("GetConfigData").Return(tt.args.config, tt.args.configErr)
 			utilsMock.On("AssignPassword", mock.AnythingOfType("*pflag.FlagSet")).Return(tt.args.password)
+			flagSetUtilsMock.On("GetBoolEncryptDataFiles", mock.AnythingOfType("*pflag.FlagSet")).Return(false, nil)
+			utilsMock.On("SetDataFilesPassword", mock.AnythingOfType("string"), false).Return(nil)
 			flagSetUtilsMock.On("GetStringAddress", mock.AnythingOfType("*pflag.FlagSet")).Return(tt.args.address, tt.args.addressErr)
 			flagSetUtilsMock.On("GetUint32BountyId", mock.AnythingOfType("*pflag.FlagSet")).Return(tt.args.bountyId, tt.args.bountyIdErr)
 			utilsMock.On("ConnectToClient", mock.AnythingOfType("string")).Return(client)
@@ -3,7 +3,9 @@ package cmd
 
 import (
 	"errors"
+	"fmt"
 	"math/big"
+	"razor/core/types"
 	"razor/utils"
 	"strconv"
 	"time"
@@ -107,3 +109,55 @@ func GetStatesAllowed(states []int) string {
 	}
 	return statesAllowed
 }
+
+//This function returns the committed data of the epoch from the commit data file of the epoch, or from the legacy commit data file if it has the data of the epoch
+func readCommitDataFile(address string, epoch uint32) (types.CommitFileData, error) {
+	fileName, err := razorUtils.GetCommitDataFileName(address, epoch)
+	if err != nil {
+		return types.CommitFileData{}, err
+	}
+	committedData, err := razorUtils.ReadFromCommitJsonFile(fileName)
+	if err == nil && committedData.Epoch == epoch {
+		return committedData, nil
+	}
+	if err == nil {
+		err = fmt.Errorf("file %s has the committed data of epoch %d, not of epoch %d", fileName, committedData.Epoch, epoch)
+	}
+
+	legacyFileName, legacyErr := razorUtils.GetLegacyCommitDataFileName(address)
+	if legacyErr != nil {
+		return types.CommitFileData{}, err
+	}
+	legacyData, legacyErr := razorUtils.ReadFromCommitJsonFile(legacyFileName)
+	if legacyErr != nil || legacyData.Epoch != epoch {
+		return types.CommitFileData{}, err
+	}
+	log.Debugf("Read the committed data of epoch %d from legacy file %s", epoch, legacyFileName)
+	return legacyData, nil
+}
+
+//This function returns the proposed data of the epoch from the propose data file of the epoch, or from the legacy propose data file if it has the data of the epoch
+func readProposeDataFile(address string, epoch uint32) (types.ProposeFileData, error) {
+	fileName, err := razorUtils.GetProposeDataFileName(address, epoch)
+	if err != nil {
+		return types.ProposeFileData{}, err
+	}
+	proposedData, err := razorUtils.ReadFromProposeJsonFile(fileName)
+	if err == nil && proposedData.Epoch == epoch {
+		return proposedData, nil
+	}
+	if err == nil {
+		err = fmt.Errorf("file %s has the proposed data of epoch %d, not of epoch %d", fileName, proposedData.Epoch, epoch)
+	}
+
+	legacyFileName, legacyErr := razorUtils.GetLegacyProposeDataFileName(address)
+	if legacyErr != nil {
+		return types.ProposeFileData{}, err
+	}
+	legacyData, legacyErr := razorUtils.ReadFromProposeJsonFile(legacyFileName)
+	if legacyErr != nil || legacyData.Epoch != epoch {
+		return types.ProposeFileData{}, err
+	}
+	log.Debugf("Read the proposed data of epoch %d from legacy file %s", epoch, legacyFileName)
+	return legacyData, nil
+}
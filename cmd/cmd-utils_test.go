@@ -7,8 +7,10 @@ import (
 	"github.com/stretchr/testify/mock"
 	"math/big"
 	"razor/cmd/mocks"
+	"razor/core/types"
 	"razor/utils"
 	mocks2 "razor/utils/mocks"
+	"reflect"
 	"testing"
 )
 
@@ -436,3 +438,173 @@ func TestGetStatesAllowed(t *testing.T) {
 		})
 	}
 }
+
+func TestReadCommitDataFile(t *testing.T) {
+	const (
+		address        = "0x000000000000000000000000000000000000dea1"
+		fileName       = "/home/data_files/5/0x000000000000000000000000000000000000dea1_CommitData.json"
+		legacyFileName = "/home/data_files/0x000000000000000000000000000000000000dea1_CommitData.json"
+	)
+	type args struct {
+		fileNameErr       error
+		commitData        types.CommitFileData
+		commitDataErr     error
+		legacyFileNameErr error
+		legacyData        types.CommitFileData
+		legacyDataErr     error
+	}
+	tests := []struct {
+		name    string
+		args    args
+		want    types.CommitFileData
+		wantErr bool
+	}{
+		{
+			name: "Test 1: When the commit data file of the epoch has the committed data",
+			args: args{
+				commitData: types.CommitFileData{Epoch: 5, Leaves: []*big.Int{big.NewInt(295050)}},
+			},
+			want: types.CommitFileData{Epoch: 5, Leaves: []*big.Int{big.NewInt(295050)}},
+		},
+		{
+			name: "Test 2: When the commit data file of the epoch is missing and the legacy file has the committed data of the epoch",
+			args: args{
+				commitDataErr: errors.New("no such file or directory"),
+				legacyData:    types.CommitFileData{Epoch: 5, Leaves: []*big.Int{big.NewInt(295060)}},
+			},
+			want: types.CommitFileData{Epoch: 5, Leaves: []*big.Int{big.NewInt(295060)}},
+		},
+		{
+			name: "Test 3: When the legacy file has the committed data of another epoch",
+			args: args{
+				commitDataErr: errors.New("no such file or directory"),
+				legacyData:    types.CommitFileData{Epoch: 4, Leaves: []*big.Int{big.NewInt(295060)}},
+			},
+			wantErr: true,
+		},
+		{
+			name: "Test 4: When the commit data file of the epoch has the data of another epoch and the legacy file cannot be read",
+			args: args{
+				commitData:    types.CommitFileData{Epoch: 3},
+				legacyDataErr: errors.New("no such file or directory"),
+			},
+			wantErr: true,
+		},
+		{
+			name: "Test 5: When there is an error in getting the file name of the legacy file",
+			args: args{
+				commitDataErr:     errors.New("no such file or directory"),
+				legacyFileNameErr: errors.New("path error"),
+			},
+			wantErr: true,
+		},
+		{
+			name: "Test 6: When there is an error in getting the file name of the epoch",
+			args: args{
+				fileNameErr: errors.New("path error"),
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			utilsMock := new(mocks.UtilsInterface)
+			razorUtils = utilsMock
+
+			utilsMock.On("GetCommitDataFileName", address, uint32(5)).Return(fileName, tt.args.fileNameErr)
+			utilsMock.On("ReadFromCommitJsonFile", fileName).Return(tt.args.commitData, tt.args.commitDataErr)
+			utilsMock.On("GetLegacyCommitDataFileName", address).Return(legacyFileName, tt.args.legacyFileNameErr)
+			utilsMock.On("ReadFromCommitJsonFile", legacyFileName).Return(tt.args.legacyData, tt.args.legacyDataErr)
+
+			got, err := readCommitDataFile(address, 5)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("readCommitDataFile() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("readCommitDataFile() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestReadProposeDataFile(t *testing.T) {
+	const (
+		address        = "0x000000000000000000000000000000000000dea1"
+		fileName       = "/home/data_files/5/0x000000000000000000000000000000000000dea1_proposedData.json"
+		legacyFileName = "/home/data_files/0x000000000000000000000000000000000000dea1_proposedData.json"
+	)
+	type args struct {
+		fileNameErr       error
+		proposeData       types.ProposeFileData
+		proposeDataErr    error
+		legacyFileNameErr error
+		legacyData        types.ProposeFileData
+		legacyDataErr     error
+	}
+	tests := []struct {
+		name    string
+		args    args
+		want    types.ProposeFileData
+		wantErr bool
+	}{
+		{
+			name: "Test 1: When the propose data file of the epoch has the proposed data",
+			args: args{
+				proposeData: types.ProposeFileData{Epoch: 5, MediansData: []*big.Int{big.NewInt(295075)}},
+			},
+			want: types.ProposeFileData{Epoch: 5, MediansData: []*big.Int{big.NewInt(295075)}},
+		},
+		{
+			name: "Test 2: When the propose data file of the epoch is missing and the legacy file has the proposed data of the epoch",
+			args: args{
+				proposeDataErr: errors.New("no such file or directory"),
+				legacyData:     types.ProposeFileData{Epoch: 5, MediansData: []*big.Int{big.NewInt(295085)}},
+			},
+			want: types.ProposeFileData{Epoch: 5, MediansData: []*big.Int{big.NewInt(295085)}},
+		},
+		{
+			name: "Test 3: When the legacy file has the proposed data of another epoch",
+			args: args{
+				proposeDataErr: errors.New("no such file or directory"),
+				legacyData:     types.ProposeFileData{Epoch: 4},
+			},
+			wantErr: true,
+		},
+		{
+			name: "Test 4: When there is an error in getting the file name of the legacy file",
+			args: args{
+				proposeData:       types.ProposeFileData{Epoch: 3},
+				legacyFileNameErr: errors.New("path error"),
+			},
+			wantErr: true,
+		},
+		{
+			name: "Test 5: When there is an error in getting the file name of the epoch",
+			args: args{
+				fileNameErr: errors.New("path error"),
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			utilsMock := new(mocks.UtilsInterface)
+			razorUtils = utilsMock
+
+			utilsMock.On("GetProposeDataFileName", address, uint32(5)).Return(fileName, tt.args.fileNameErr)
+			utilsMock.On("ReadFromProposeJsonFile", fileName).Return(tt.args.proposeData, tt.args.proposeDataErr)
+			utilsMock.On("GetLegacyProposeDataFileName", address).Return(legacyFileName, tt.args.legacyFileNameErr)
+			utilsMock.On("ReadFromProposeJsonFile", legacyFileName).Return(tt.args.legacyData, tt.args.legacyDataErr)
+
+			got, err := readProposeDataFile(address, 5)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("readProposeDataFile() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("readProposeDataFile() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
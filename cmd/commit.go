@@ -2,8 +2,10 @@
 package cmd
 
 import (
+	"context"
 	"encoding/hex"
 	"errors"
+	"fmt"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/ethclient"
 	solsha3 "github.com/miguelmota/go-solidity-sha3"
@@ -12,6 +14,8 @@ import (
 	"razor/core/types"
 	"razor/pkg/bindings"
 	"razor/utils"
+	"sync"
+	"time"
 )
 
 /*
@@ -42,9 +46,17 @@ func (*UtilsStruct) GetSalt(client *ethclient.Client, epoch uint32) ([32]byte, e
 	return utils.UtilsInterface.CalculateSalt(previousEpoch, previousBlock.Medians), nil
 }
 
+//This function returns the seed of the epoch from its salt and the secret of the staker
+func calculateSeed(salt [32]byte, secret []byte) []byte {
+	return solsha3.SoliditySHA3([]string{"bytes32", "bytes32"}, []interface{}{"0x" + hex.EncodeToString(salt[:]), "0x" + hex.EncodeToString(secret)})
+}
+
 /*
 HandleCommitState fetches the collections assigned to the staker and creates the leaves required for the merkle tree generation.
 Values for only the collections assigned to the staker is fetched for others, 0 is added to the leaves of tree.
+Assigned collections are fetched concurrently by a bounded pool of workers which is stopped once the commit state is about to end.
+Requests to sources are cancelled a little before that, so that failing collections can still use their fallback.
+The commit fails if any assigned collection fails or is not fetched in time, as a leaf of 0 for an assigned collection cannot be revealed.
 */
 func (*UtilsStruct) HandleCommitState(client *ethclient.Client, epoch uint32, seed []byte, rogueData types.Rogue) (types.CommitData, error) {
 	numActiveCollections, err := utils.UtilsInterface.GetNumActiveCollections(client)
@@ -57,24 +69,76 @@ func (*UtilsStruct) HandleCommitState(client *ethclient.Client, epoch uint32, se
 		return types.CommitData{}, err
 	}
 
-	var leavesOfTree []*big.Int
+	bufferPercent, err := cmdUtils.GetBufferPercent()
+	if err != nil {
+		return types.CommitData{}, err
+	}
+	stateRemainingTime, err := utilsInterface.GetRemainingTimeOfCurrentState(client, bufferPercent)
+	if err != nil {
+		return types.CommitData{}, err
+	}
+	// The epoch is set before any collection is fetched, so that everything read for the collections of the epoch is recorded with it
+	utils.UtilsInterface.SetAggregationEpoch(epoch)
+	stateTimeout := time.NewTimer(time.Second * time.Duration(stateRemainingTime))
+	defer stateTimeout.Stop()
+	ctx, cancel := context.WithCancel(context.Background())
+	// Sources which are slower than the fetch deadline fail, so that collections are aggregated from their other jobs or fallback before the state timeout
+	removeFetchDeadline := utils.SetFetchDeadline(ctx, time.Now().Add(time.Second*time.Duration(stateRemainingTime)-core.FetchDeadlineReserve))
+	workers := &sync.WaitGroup{}
+	defer func() {
+		// Collections and jobs still being fetched are cancelled, the deadline is removed once the workers stopped so that none of them fetches without it
+		cancel()
+		go func() {
+			workers.Wait()
+			removeFetchDeadline()
+		}()
+	}()
+
+	var assignedIndexes []int
+	leavesOfTree := make([]*big.Int, numActiveCollections)
 	for i := 0; i < int(numActiveCollections); i++ {
+		leavesOfTree[i] = big.NewInt(0)
 		if assignedCollections[i] {
-			collectionId, err := utils.UtilsInterface.GetCollectionIdFromIndex(client, uint16(i))
-			if err != nil {
-				return types.CommitData{}, err
-			}
-			collectionData, err := utils.UtilsInterface.GetAggregatedDataOfCollection(client, collectionId, epoch)
-			if err != nil {
-				return types.CommitData{}, err
+			assignedIndexes = append(assignedIndexes, i)
+		}
+	}
+
+	indexesToFetch := make(chan int, len(assignedIndexes))
+	for _, index := range assignedIndexes {
+		indexesToFetch <- index
+	}
+	close(indexesToFetch)
+
+	results := make(chan types.CollectionResult, len(assignedIndexes))
+
+	numRoutines := core.NumFetchRoutines
+	if len(assignedIndexes) < numRoutines {
+		numRoutines = len(assignedIndexes)
+	}
+	workers.Add(numRoutines)
+	for routine := 0; routine < numRoutines; routine++ {
+		go func() {
+			defer workers.Done()
+			fetchCollectionsConcurrently(client, epoch, indexesToFetch, results, ctx.Done())
+		}()
+	}
+
+	// The reveal of a zero value for an assigned collection is rejected, so the commit stops if any assigned collection has no value by the state timeout
+	for received := 0; received < len(assignedIndexes); received++ {
+		select {
+		case <-stateTimeout.C:
+			return types.CommitData{}, fmt.Errorf("state timeout! %d assigned collections are not fetched yet", len(assignedIndexes)-received)
+		case result := <-results:
+			if result.Err != nil {
+				log.Errorf("Error in getting data of collection at index %d: %s", result.Index, result.Err)
+				return types.CommitData{}, result.Err
 			}
+			collectionData := result.Data
 			if rogueData.IsRogue && utils.Contains(rogueData.RogueMode, "commit") {
 				collectionData = razorUtils.GetRogueRandomValue(100000)
 			}
-			log.Debugf("Data of collection %d:%s", collectionId, collectionData)
-			leavesOfTree = append(leavesOfTree, collectionData)
-		} else {
-			leavesOfTree = append(leavesOfTree, big.NewInt(0))
+			log.Debugf("Data of collection %d:%s", result.CollectionId, collectionData)
+			leavesOfTree[result.Index] = collectionData
 		}
 	}
 	log.Debug("Assigned Collections: ", assignedCollections)
@@ -87,6 +151,31 @@ func (*UtilsStruct) HandleCommitState(client *ethclient.Client, epoch uint32, se
 	}, nil
 }
 
+//This function fetches the data of the collections whose indexes are received and checks it against their sanity bounds until there are none left or quit is closed
+func fetchCollectionsConcurrently(client *ethclient.Client, epoch uint32, indexesToFetch <-chan int, results chan<- types.CollectionResult, quit <-chan struct{}) {
+	for index := range indexesToFetch {
+		select {
+		case <-quit:
+			return
+		default:
+		}
+		results <- getCollectionResult(client, epoch, index)
+	}
+}
+
+//This function returns the value of the collection at the index as it is committed, checked against its sanity bounds
+func getCollectionResult(client *ethclient.Client, epoch uint32, index int) types.CollectionResult {
+	collectionId, err := utils.UtilsInterface.GetCollectionIdFromIndex(client, uint16(index))
+	if err != nil {
+		return types.CollectionResult{Index: index, Err: err}
+	}
+	collectionData, err := utils.UtilsInterface.GetAggregatedDataOfCollection(client, collectionId, epoch)
+	if err == nil {
+		collectionData, err = utils.UtilsInterface.CheckSanityBounds(client, collectionId, epoch, collectionData)
+	}
+	return types.CollectionResult{Index: index, CollectionId: collectionId, Data: collectionData, Err: err}
+}
+
 /*
 Commit finally commits the data to the smart contract. It calculates the commitment to send using the merkle tree root and the seed.
 */
@@ -96,9 +185,7 @@ func (*UtilsStruct) Commit(client *ethclient.Client, config types.Configurations
 		return core.NilHash, err
 	}
 
-	commitment := solsha3.SoliditySHA3([]string{"bytes32", "bytes32"}, []interface{}{"0x" + hex.EncodeToString(root[:]), "0x" + hex.EncodeToString(seed)})
-	commitmentToSend := [32]byte{}
-	copy(commitmentToSend[:], commitment)
+	commitmentToSend := utils.CalculateCommitment(root, seed)
 	txnOpts := razorUtils.GetTxnOpts(types.TransactionOptions{
 		Client:          client,
 		Password:        account.Password,
@@ -111,7 +198,7 @@ func (*UtilsStruct) Commit(client *ethclient.Client, config types.Configurations
 		Parameters:      []interface{}{epoch, commitmentToSend},
 	})
 
-	log.Debugf("Committing: epoch: %d, commitment: %s, seed: %s, account: %s", epoch, "0x"+hex.EncodeToString(commitment), "0x"+hex.EncodeToString(seed), account.Address)
+	log.Debugf("Committing: epoch: %d, commitment: %s, seed: %s, account: %s", epoch, "0x"+hex.EncodeToString(commitmentToSend[:]), "0x"+hex.EncodeToString(seed), account.Address)
 
 	log.Info("Commitment sent...")
 	txn, err := voteManagerUtils.Commit(client, txnOpts, epoch, commitmentToSend)
@@ -20,6 +20,7 @@ import (
 	mocks2 "razor/utils/mocks"
 	"reflect"
 	"testing"
+	"time"
 )
 
 func TestCommit(t *testing.T) {
@@ -141,6 +142,15 @@ func TestHandleCommitState(t *testing.T) {
 		collectionData          *big.Int
 		collectionDataErr       error
 		rogueData               types.Rogue
+		bufferPercent           int32
+		bufferPercentErr        error
+		remainingTime           int64
+		remainingTimeErr        error
+		collectionDataDelay     time.Duration
+		sanityErr               error
+		otherCollectionId       uint16
+		otherCollectionDataErr  error
+		otherCollectionDelay    time.Duration
 	}
 	tests := []struct {
 		name    string
@@ -156,6 +166,7 @@ func TestHandleCommitState(t *testing.T) {
 				seqAllottedCollections: []*big.Int{big.NewInt(1), big.NewInt(2)},
 				collectionId:           1,
 				collectionData:         big.NewInt(1),
+				remainingTime:          10,
 			},
 			want: types.CommitData{
 				AssignedCollections:    map[int]bool{1: true, 2: true},
@@ -188,6 +199,7 @@ func TestHandleCommitState(t *testing.T) {
 				assignedCollections:    map[int]bool{1: true, 2: true},
 				seqAllottedCollections: []*big.Int{big.NewInt(1), big.NewInt(2)},
 				collectionIdErr:        errors.New("error in getting collectionId"),
+				remainingTime:          10,
 			},
 			want:    types.CommitData{},
 			wantErr: errors.New("error in getting collectionId"),
@@ -200,6 +212,7 @@ func TestHandleCommitState(t *testing.T) {
 				seqAllottedCollections: []*big.Int{big.NewInt(1), big.NewInt(2)},
 				collectionId:           1,
 				collectionDataErr:      errors.New("error in getting collectionData"),
+				remainingTime:          10,
 			},
 			want:    types.CommitData{},
 			wantErr: errors.New("error in getting collectionData"),
@@ -216,6 +229,7 @@ func TestHandleCommitState(t *testing.T) {
 					IsRogue:   true,
 					RogueMode: []string{"commit"},
 				},
+				remainingTime: 10,
 			},
 			want: types.CommitData{
 				AssignedCollections:    map[int]bool{1: true, 2: true},
@@ -224,20 +238,126 @@ func TestHandleCommitState(t *testing.T) {
 			},
 			wantErr: nil,
 		},
+		{
+			name: "Test 7: When there is an error in getting bufferPercent",
+			args: args{
+				numActiveCollections:   3,
+				assignedCollections:    map[int]bool{1: true, 2: true},
+				seqAllottedCollections: []*big.Int{big.NewInt(1), big.NewInt(2)},
+				bufferPercentErr:       errors.New("error in getting buffer percent"),
+			},
+			want:    types.CommitData{},
+			wantErr: errors.New("error in getting buffer percent"),
+		},
+		{
+			name: "Test 8: When there is an error in getting remaining time of commit state",
+			args: args{
+				numActiveCollections:   3,
+				assignedCollections:    map[int]bool{1: true, 2: true},
+				seqAllottedCollections: []*big.Int{big.NewInt(1), big.NewInt(2)},
+				remainingTimeErr:       errors.New("error in getting remaining time"),
+			},
+			want:    types.CommitData{},
+			wantErr: errors.New("error in getting remaining time"),
+		},
+		{
+			name: "Test 9: When commit state ends before the collections are fetched",
+			args: args{
+				numActiveCollections:   3,
+				assignedCollections:    map[int]bool{1: true, 2: true},
+				seqAllottedCollections: []*big.Int{big.NewInt(1), big.NewInt(2)},
+				collectionId:           1,
+				collectionData:         big.NewInt(1),
+				remainingTime:          0,
+				collectionDataDelay:    200 * time.Millisecond,
+			},
+			want:    types.CommitData{},
+			wantErr: errors.New("state timeout! 2 assigned collections are not fetched yet"),
+		},
+		{
+			name: "Test 10: When no collection is assigned",
+			args: args{
+				numActiveCollections:   2,
+				assignedCollections:    map[int]bool{},
+				seqAllottedCollections: []*big.Int{},
+				remainingTime:          10,
+			},
+			want: types.CommitData{
+				AssignedCollections:    map[int]bool{},
+				SeqAllottedCollections: []*big.Int{},
+				Leaves:                 []*big.Int{big.NewInt(0), big.NewInt(0)},
+			},
+			wantErr: nil,
+		},
+		{
+			name: "Test 11: When value of a collection fails its sanity check and commit is blocked",
+			args: args{
+				numActiveCollections:   3,
+				assignedCollections:    map[int]bool{1: true, 2: true},
+				seqAllottedCollections: []*big.Int{big.NewInt(1), big.NewInt(2)},
+				collectionId:           1,
+				collectionData:         big.NewInt(1),
+				remainingTime:          10,
+				sanityErr:              errors.New("commit is blocked as value of collection ethCollectionMean failed its sanity check"),
+			},
+			want:    types.CommitData{},
+			wantErr: errors.New("commit is blocked as value of collection ethCollectionMean failed its sanity check"),
+		},
+		{
+			name: "Test 12: When an assigned collection fails while the other is fetched",
+			args: args{
+				numActiveCollections:   3,
+				assignedCollections:    map[int]bool{1: true, 2: true},
+				seqAllottedCollections: []*big.Int{big.NewInt(1), big.NewInt(2)},
+				collectionId:           1,
+				collectionData:         big.NewInt(1),
+				remainingTime:          10,
+				otherCollectionId:      2,
+				otherCollectionDataErr: errors.New("error in getting collectionData"),
+			},
+			want:    types.CommitData{},
+			wantErr: errors.New("error in getting collectionData"),
+		},
+		{
+			name: "Test 13: When commit state ends before an assigned collection is fetched while the other is fetched",
+			args: args{
+				numActiveCollections:   3,
+				assignedCollections:    map[int]bool{1: true, 2: true},
+				seqAllottedCollections: []*big.Int{big.NewInt(1), big.NewInt(2)},
+				collectionId:           1,
+				collectionData:         big.NewInt(1),
+				remainingTime:          1,
+				otherCollectionId:      2,
+				otherCollectionDelay:   2 * time.Second,
+			},
+			want:    types.CommitData{},
+			wantErr: errors.New("state timeout! 1 assigned collections are not fetched yet"),
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			utilsPkgMock := new(mocks2.Utils)
 			utilsMock := new(mocks.UtilsInterface)
+			cmdUtilsMock := new(mocks.UtilsCmdInterface)
 
 			utils.UtilsInterface = utilsPkgMock
+			utilsInterface = utilsPkgMock
 			razorUtils = utilsMock
+			cmdUtils = cmdUtilsMock
 
 			utilsPkgMock.On("GetNumActiveCollections", mock.AnythingOfType("*ethclient.Client")).Return(tt.args.numActiveCollections, tt.args.numActiveCollectionsErr)
 			utilsPkgMock.On("GetAssignedCollections", mock.AnythingOfType("*ethclient.Client"), mock.Anything, mock.Anything).Return(tt.args.assignedCollections, tt.args.seqAllottedCollections, tt.args.assignedCollectionsErr)
+			if tt.args.otherCollectionId != 0 {
+				utilsPkgMock.On("GetCollectionIdFromIndex", mock.AnythingOfType("*ethclient.Client"), uint16(2)).Return(tt.args.otherCollectionId, nil)
+				utilsPkgMock.On("GetAggregatedDataOfCollection", mock.AnythingOfType("*ethclient.Client"), tt.args.otherCollectionId, mock.Anything).Return(tt.args.collectionData, tt.args.otherCollectionDataErr).After(tt.args.otherCollectionDelay)
+			}
 			utilsPkgMock.On("GetCollectionIdFromIndex", mock.AnythingOfType("*ethclient.Client"), mock.Anything).Return(tt.args.collectionId, tt.args.collectionIdErr)
-			utilsPkgMock.On("GetAggregatedDataOfCollection", mock.AnythingOfType("*ethclient.Client"), mock.Anything, mock.Anything).Return(tt.args.collectionData, tt.args.collectionDataErr)
+			utilsPkgMock.On("GetAggregatedDataOfCollection", mock.AnythingOfType("*ethclient.Client"), mock.Anything, mock.Anything).Return(tt.args.collectionData, tt.args.collectionDataErr).After(tt.args.collectionDataDelay)
+			utilsPkgMock.On("CheckSanityBounds", mock.AnythingOfType("*ethclient.Client"), mock.Anything, mock.Anything, mock.Anything).Return(tt.args.collectionData, tt.args.sanityErr)
 			utilsMock.On("GetRogueRandomValue", mock.Anything).Return(rogueValue)
+			cmdUtilsMock.On("GetBufferPercent").Return(tt.args.bufferPercent, tt.args.bufferPercentErr)
+			utilsPkgMock.On("GetRemainingTimeOfCurrentState", mock.AnythingOfType("*ethclient.Client"), mock.AnythingOfType("int32")).Return(tt.args.remainingTime, tt.args.remainingTimeErr)
+			utilsPkgMock.On("SetAggregationEpoch", mock.AnythingOfType("uint32"))
 
 			utils := &UtilsStruct{}
 			got, err := utils.HandleCommitState(client, epoch, seed, tt.args.rogueData)
@@ -253,7 +373,8 @@ func TestHandleCommitState(t *testing.T) {
 					t.Errorf("Error from HandleCommitState function, got = %v, want = %v", err, tt.wantErr)
 				}
 			}
-
+			// Collections still being fetched after the state timeout use the mocks of this test, so they finish before the next test replaces them
+			time.Sleep(tt.args.collectionDataDelay + tt.args.otherCollectionDelay)
 		})
 	}
 }
@@ -410,6 +531,7 @@ func BenchmarkHandleCommitState(b *testing.B) {
 				utilsPkgMock.On("GetAssignedCollections", mock.AnythingOfType("*ethclient.Client"), mock.Anything, mock.Anything).Return(v.assignedCollections, nil, nil)
 				utilsPkgMock.On("GetCollectionIdFromIndex", mock.AnythingOfType("*ethclient.Client"), mock.Anything).Return(uint16(1), nil)
 				utilsPkgMock.On("GetAggregatedDataOfCollection", mock.AnythingOfType("*ethclient.Client"), mock.Anything, mock.Anything).Return(big.NewInt(1000), nil)
+				utilsPkgMock.On("CheckSanityBounds", mock.AnythingOfType("*ethclient.Client"), mock.Anything, mock.Anything, mock.Anything).Return(big.NewInt(1000), nil)
 				utilsMock.On("GetRogueRandomValue", mock.Anything).Return(rogueValue)
 
 				ut := &UtilsStruct{}
@@ -0,0 +1,173 @@
+//Package cmd provides all functions related to command line
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"razor/core"
+	"razor/core/types"
+	"razor/utils"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/olekukonko/tablewriter"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+var dataFilesCmd = &cobra.Command{
+	Use:   "dataFiles",
+	Short: "dataFiles lists, inspects and prunes the data saved for recovery while voting",
+	Long: `Lists the data files saved while voting by epoch: the committed values and the proposed block of every epoch, and the bounty ids left to claim. Pass an epoch to print the data saved in it, or prune to remove the data files of the epochs older than the retention.
+
+Example:
+  ./razor dataFiles
+  ./razor dataFiles --address 0x5a0b54d5dc17e0aadc383d2db43b0a0d3e029c4c --epoch 1200
+  ./razor dataFiles --prune --dataFilesRetention 50
+`,
+	Run: initialiseDataFiles,
+}
+
+//This function initialises the ExecuteDataFiles function
+func initialiseDataFiles(cmd *cobra.Command, args []string) {
+	cmdUtils.ExecuteDataFiles(cmd.Flags())
+}
+
+//This function sets the flags appropriately and lists, inspects or prunes the data files
+func (*UtilsStruct) ExecuteDataFiles(flagSet *pflag.FlagSet) {
+	razorUtils.AssignLogFile(flagSet)
+
+	address, err := flagSetUtils.GetStringAddress(flagSet)
+	utils.CheckError("Error in getting address: ", err)
+
+	epoch, err := flagSetUtils.GetUint32Epoch(flagSet)
+	utils.CheckError("Error in getting epoch: ", err)
+
+	prune, err := flagSetUtils.GetBoolPrune(flagSet)
+	utils.CheckError("Error in getting prune status: ", err)
+
+	if prune {
+		retention, err := flagSetUtils.GetUint32DataFilesRetention(flagSet)
+		utils.CheckError("Error in getting data files retention: ", err)
+
+		config, err := cmdUtils.GetConfigData()
+		utils.CheckError("Error in getting config: ", err)
+
+		client := razorUtils.ConnectToClient(config.Provider)
+		currentEpoch, err := razorUtils.GetEpoch(client)
+		utils.CheckError("Error in getting epoch: ", err)
+
+		prunedEpochs, err := razorUtils.PruneDataFiles(currentEpoch, retention)
+		utils.CheckError("Error in pruning data files: ", err)
+		log.Infof("Removed the data files of %d epochs: %v", len(prunedEpochs), prunedEpochs)
+		return
+	}
+
+	if epoch == 0 {
+		err = cmdUtils.ListDataFiles(address, os.Stdout)
+		utils.CheckError("Error in listing data files: ", err)
+		return
+	}
+
+	// Encrypted data files can only be read with the password, which is only asked for if the flag is passed
+	if razorUtils.IsFlagPassed("password") {
+		password := razorUtils.AssignPassword(flagSet)
+		err = razorUtils.SetDataFilesPassword(password, false)
+		utils.CheckError("Error in setting password of data files: ", err)
+	}
+	err = cmdUtils.InspectDataFiles(address, epoch, os.Stdout)
+	utils.CheckError("Error in inspecting data files: ", err)
+}
+
+//This function writes a table of the data files of the address, or of every address if it is empty
+func (*UtilsStruct) ListDataFiles(address string, writer io.Writer) error {
+	entries, err := razorUtils.ListDataFiles()
+	if err != nil {
+		return err
+	}
+	entries = filterDataFiles(entries, address, 0)
+	if len(entries) == 0 {
+		log.Info("No data files have been saved, they are saved while voting")
+		return nil
+	}
+
+	table := tablewriter.NewWriter(writer)
+	table.SetHeader([]string{"Epoch", "Address", "Kind", "Size", "Modified"})
+	for _, entry := range entries {
+		epoch := "-"
+		if entry.Epoch != 0 {
+			epoch = strconv.FormatUint(uint64(entry.Epoch), 10)
+		}
+		table.Append([]string{epoch, entry.Address, entry.Kind, strconv.FormatInt(entry.Size, 10), entry.ModTime.Format(time.RFC3339)})
+	}
+	table.Render()
+	return nil
+}
+
+//This function writes the data saved in the epoch for the address, or for every address if it is empty, as JSON
+func (*UtilsStruct) InspectDataFiles(address string, epoch uint32, writer io.Writer) error {
+	entries, err := razorUtils.ListDataFiles()
+	if err != nil {
+		return err
+	}
+	entries = filterDataFiles(entries, address, epoch)
+	if len(entries) == 0 {
+		return fmt.Errorf("no data files of epoch %d are kept", epoch)
+	}
+
+	var contents []types.DataFileContent
+	for _, entry := range entries {
+		content := types.DataFileContent{Epoch: entry.Epoch, Address: entry.Address, Kind: entry.Kind}
+		switch entry.Kind {
+		case "commit":
+			content.Data, err = razorUtils.ReadFromCommitJsonFile(entry.Path)
+		case "propose":
+			content.Data, err = razorUtils.ReadFromProposeJsonFile(entry.Path)
+		default:
+			continue
+		}
+		if err != nil {
+			return fmt.Errorf("%s: %w", entry.Path, err)
+		}
+		contents = append(contents, content)
+	}
+	encoder := json.NewEncoder(writer)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(contents)
+}
+
+//This function returns the data files of the address and the epoch, an empty address matches every address and epoch 0 matches every epoch
+func filterDataFiles(entries []types.DataFileEntry, address string, epoch uint32) []types.DataFileEntry {
+	var filtered []types.DataFileEntry
+	for _, entry := range entries {
+		if address != "" && !strings.EqualFold(entry.Address, address) {
+			continue
+		}
+		if epoch != 0 && entry.Epoch != epoch {
+			continue
+		}
+		filtered = append(filtered, entry)
+	}
+	return filtered
+}
+
+func init() {
+	rootCmd.AddCommand(dataFilesCmd)
+
+	var (
+		Address            string
+		Epoch              uint32
+		Prune              bool
+		DataFilesRetention uint32
+		Password           string
+	)
+
+	dataFilesCmd.Flags().StringVarP(&Address, "address", "a", "", "address of the staker, every staker if not passed")
+	dataFilesCmd.Flags().Uint32VarP(&Epoch, "epoch", "", 0, "epoch to print the data saved in")
+	dataFilesCmd.Flags().BoolVarP(&Prune, "prune", "", false, "remove the data files of the epochs older than the retention")
+	dataFilesCmd.Flags().Uint32VarP(&DataFilesRetention, "dataFilesRetention", "", core.DataFilesRetention, "number of the latest epochs to keep data files of when pruning")
+	dataFilesCmd.Flags().StringVarP(&Password, "password", "", "", "password path of staker to read encrypted data files")
+}
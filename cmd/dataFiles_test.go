@@ -0,0 +1,272 @@
+package cmd
+
+import (
+	"bytes"
+	"errors"
+	"math/big"
+	"razor/cmd/mocks"
+	"razor/core/types"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/spf13/pflag"
+	"github.com/stretchr/testify/mock"
+)
+
+var dataFileEntries = []types.DataFileEntry{
+	{Address: "0x000000000000000000000000000000000000dea1", Kind: "dispute", Path: "/home/data_files/0x000000000000000000000000000000000000dea1_disputeData.json", Size: 24, ModTime: time.Unix(0, 0).UTC()},
+	{Epoch: 5, Address: "0x000000000000000000000000000000000000dea1", Kind: "commit", Path: "/home/data_files/5/0x000000000000000000000000000000000000dea1_CommitData.json", Size: 180, ModTime: time.Unix(0, 0).UTC()},
+	{Epoch: 5, Address: "0x000000000000000000000000000000000000dea1", Kind: "propose", Path: "/home/data_files/5/0x000000000000000000000000000000000000dea1_proposedData.json", Size: 320, ModTime: time.Unix(0, 0).UTC()},
+	{Epoch: 6, Address: "0x000000000000000000000000000000000000dea2", Kind: "commit", Path: "/home/data_files/6/0x000000000000000000000000000000000000dea2_CommitData.json", Size: 180, ModTime: time.Unix(0, 0).UTC()},
+}
+
+func TestListDataFiles(t *testing.T) {
+	type args struct {
+		address    string
+		entries    []types.DataFileEntry
+		entriesErr error
+	}
+	tests := []struct {
+		name     string
+		args     args
+		wantRows []string
+		wantErr  bool
+	}{
+		{
+			name: "Test 1: When data files of every address are listed",
+			args: args{
+				entries: dataFileEntries,
+			},
+			wantRows: []string{"dispute", "commit", "propose", "0x000000000000000000000000000000000000dea2"},
+		},
+		{
+			name: "Test 2: When data files of an address are listed",
+			args: args{
+				address: "0x000000000000000000000000000000000000DEA2",
+				entries: dataFileEntries,
+			},
+			wantRows: []string{"0x000000000000000000000000000000000000dea2"},
+		},
+		{
+			name: "Test 3: When no data files have been saved",
+			args: args{
+				entries: nil,
+			},
+		},
+		{
+			name: "Test 4: When data files cannot be listed",
+			args: args{
+				entriesErr: errors.New("permission denied"),
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			utilsMock := new(mocks.UtilsInterface)
+			razorUtils = utilsMock
+
+			utilsMock.On("ListDataFiles").Return(tt.args.entries, tt.args.entriesErr)
+
+			utils := &UtilsStruct{}
+			var output bytes.Buffer
+			err := utils.ListDataFiles(tt.args.address, &output)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ListDataFiles() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			for _, row := range tt.wantRows {
+				if !strings.Contains(output.String(), row) {
+					t.Errorf("ListDataFiles() wrote %q, want it to contain %q", output.String(), row)
+				}
+			}
+			if len(tt.wantRows) == 0 && output.Len() != 0 {
+				t.Errorf("ListDataFiles() wrote %q, want nothing", output.String())
+			}
+			if tt.args.address != "" && strings.Contains(output.String(), "0x000000000000000000000000000000000000dea1") {
+				t.Errorf("ListDataFiles() wrote data files of another address: %q", output.String())
+			}
+		})
+	}
+}
+
+func TestInspectDataFiles(t *testing.T) {
+	type args struct {
+		address        string
+		epoch          uint32
+		commitData     types.CommitFileData
+		commitDataErr  error
+		proposeData    types.ProposeFileData
+		proposeDataErr error
+	}
+	tests := []struct {
+		name    string
+		args    args
+		want    []string
+		wantErr bool
+	}{
+		{
+			name: "Test 1: When data files of the epoch are inspected",
+			args: args{
+				address:     "0x000000000000000000000000000000000000dea1",
+				epoch:       5,
+				commitData:  types.CommitFileData{Epoch: 5, Leaves: []*big.Int{big.NewInt(295050)}},
+				proposeData: types.ProposeFileData{Epoch: 5, MediansData: []*big.Int{big.NewInt(295075)}},
+			},
+			want: []string{`"kind": "commit"`, "295050", `"kind": "propose"`, "295075"},
+		},
+		{
+			name: "Test 2: When no data files of the epoch are kept",
+			args: args{
+				epoch: 4,
+			},
+			wantErr: true,
+		},
+		{
+			name: "Test 3: When a data file of the epoch cannot be read",
+			args: args{
+				epoch:         6,
+				commitDataErr: errors.New("data file is corrupted, its checksum does not match"),
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			utilsMock := new(mocks.UtilsInterface)
+			razorUtils = utilsMock
+
+			utilsMock.On("ListDataFiles").Return(dataFileEntries, nil)
+			utilsMock.On("ReadFromCommitJsonFile", mock.AnythingOfType("string")).Return(tt.args.commitData, tt.args.commitDataErr)
+			utilsMock.On("ReadFromProposeJsonFile", mock.AnythingOfType("string")).Return(tt.args.proposeData, tt.args.proposeDataErr)
+
+			utils := &UtilsStruct{}
+			var output bytes.Buffer
+			err := utils.InspectDataFiles(tt.args.address, tt.args.epoch, &output)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("InspectDataFiles() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			for _, want := range tt.want {
+				if !strings.Contains(output.String(), want) {
+					t.Errorf("InspectDataFiles() wrote %q, want it to contain %q", output.String(), want)
+				}
+			}
+		})
+	}
+}
+
+func TestExecuteDataFiles(t *testing.T) {
+	var client *ethclient.Client
+	var flagSet *pflag.FlagSet
+
+	type args struct {
+		epoch          uint32
+		prune          bool
+		configErr      error
+		currentEpoch   uint32
+		pruneErr       error
+		passwordPassed bool
+		listErr        error
+		inspectErr     error
+	}
+	tests := []struct {
+		name          string
+		args          args
+		expectedFatal bool
+	}{
+		{
+			name:          "Test 1: When data files are listed",
+			args:          args{},
+			expectedFatal: false,
+		},
+		{
+			name: "Test 2: When data files cannot be listed",
+			args: args{
+				listErr: errors.New("permission denied"),
+			},
+			expectedFatal: true,
+		},
+		{
+			name: "Test 3: When data files of an epoch are inspected with the password",
+			args: args{
+				epoch:          5,
+				passwordPassed: true,
+			},
+			expectedFatal: false,
+		},
+		{
+			name: "Test 4: When data files of an epoch cannot be inspected",
+			args: args{
+				epoch:      5,
+				inspectErr: errors.New("data file is encrypted, the password of the account is needed to read it"),
+			},
+			expectedFatal: true,
+		},
+		{
+			name: "Test 5: When data files of old epochs are pruned",
+			args: args{
+				prune:        true,
+				currentEpoch: 200,
+			},
+			expectedFatal: false,
+		},
+		{
+			name: "Test 6: When data files of old epochs cannot be pruned",
+			args: args{
+				prune:        true,
+				currentEpoch: 200,
+				pruneErr:     errors.New("permission denied"),
+			},
+			expectedFatal: true,
+		},
+		{
+			name: "Test 7: When there is an error in getting config while pruning",
+			args: args{
+				prune:     true,
+				configErr: errors.New("config error"),
+			},
+			expectedFatal: true,
+		},
+	}
+	defer func() { log.ExitFunc = nil }()
+	var fatal bool
+	log.ExitFunc = func(int) { fatal = true }
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			utilsMock := new(mocks.UtilsInterface)
+			cmdUtilsMock := new(mocks.UtilsCmdInterface)
+			flagSetUtilsMock := new(mocks.FlagSetInterface)
+
+			razorUtils = utilsMock
+			cmdUtils = cmdUtilsMock
+			flagSetUtils = flagSetUtilsMock
+
+			utilsMock.On("AssignLogFile", mock.AnythingOfType("*pflag.FlagSet"))
+			flagSetUtilsMock.On("GetStringAddress", mock.AnythingOfType("*pflag.FlagSet")).Return("", nil)
+			flagSetUtilsMock.On("GetUint32Epoch", mock.AnythingOfType("*pflag.FlagSet")).Return(tt.args.epoch, nil)
+			flagSetUtilsMock.On("GetBoolPrune", mock.AnythingOfType("*pflag.FlagSet")).Return(tt.args.prune, nil)
+			flagSetUtilsMock.On("GetUint32DataFilesRetention", mock.AnythingOfType("*pflag.FlagSet")).Return(uint32(100), nil)
+			cmdUtilsMock.On("GetConfigData").Return(types.Configurations{}, tt.args.configErr)
+			utilsMock.On("ConnectToClient", mock.AnythingOfType("string")).Return(client)
+			utilsMock.On("GetEpoch", mock.AnythingOfType("*ethclient.Client")).Return(tt.args.currentEpoch, nil)
+			utilsMock.On("PruneDataFiles", tt.args.currentEpoch, uint32(100)).Return([]uint32{99, 100}, tt.args.pruneErr)
+			utilsMock.On("IsFlagPassed", "password").Return(tt.args.passwordPassed)
+			utilsMock.On("AssignPassword", mock.AnythingOfType("*pflag.FlagSet")).Return("test")
+			utilsMock.On("SetDataFilesPassword", "test", false).Return(nil)
+			cmdUtilsMock.On("ListDataFiles", "", mock.Anything).Return(tt.args.listErr)
+			cmdUtilsMock.On("InspectDataFiles", "", tt.args.epoch, mock.Anything).Return(tt.args.inspectErr)
+
+			utils := &UtilsStruct{}
+			fatal = false
+
+			utils.ExecuteDataFiles(flagSet)
+			if fatal != tt.expectedFatal {
+				t.Error("The ExecuteDataFiles function didn't execute as expected")
+			}
+		})
+	}
+}
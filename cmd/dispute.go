@@ -165,27 +165,20 @@ func (*UtilsStruct) HandleDispute(client *ethclient.Client, config types.Configu
 //This function returns the local median data
 func (*UtilsStruct) GetLocalMediansData(client *ethclient.Client, account types.Account, epoch uint32, blockNumber *big.Int, rogueData types.Rogue) ([]*big.Int, []uint16, *types.RevealedDataMaps, error) {
 
-	if _mediansData == nil && !rogueData.IsRogue {
-		fileName, err := razorUtils.GetProposeDataFileName(account.Address)
+	// Medians kept in memory are only used in the epoch they were calculated in
+	if (_mediansData == nil || _mediansDataEpoch != epoch) && !rogueData.IsRogue {
+		proposedata, err := readProposeDataFile(account.Address, epoch)
 		if err != nil {
-			log.Error("Error in getting file name to read median data: ", err)
-			goto CalculateMedian
-		}
-		proposedata, err := razorUtils.ReadFromProposeJsonFile(fileName)
-		if err != nil {
-			log.Errorf("Error in getting propose data from file %s: %t", fileName, err)
-			goto CalculateMedian
-		}
-		if proposedata.Epoch != epoch {
-			log.Errorf("File %s doesn't contain latest median data: %t", fileName, err)
+			log.Error("Error in getting propose data from file: ", err)
 			goto CalculateMedian
 		}
 		_mediansData = proposedata.MediansData
 		_revealedDataMaps = proposedata.RevealedDataMaps
 		_revealedCollectionIds = proposedata.RevealedCollectionIds
+		_mediansDataEpoch = epoch
 	}
 CalculateMedian:
-	if _mediansData == nil || _revealedCollectionIds == nil || _revealedDataMaps == nil || rogueData.IsRogue {
+	if _mediansData == nil || _revealedCollectionIds == nil || _revealedDataMaps == nil || _mediansDataEpoch != epoch || rogueData.IsRogue {
 		medians, revealedCollectionIds, revealedDataMaps, err := cmdUtils.MakeBlock(client, blockNumber, epoch, types.Rogue{IsRogue: false})
 		if err != nil {
 			log.Error("Error in calculating block medians")
@@ -194,6 +187,7 @@ CalculateMedian:
 		_mediansData = medians
 		_revealedCollectionIds = revealedCollectionIds
 		_revealedDataMaps = revealedDataMaps
+		_mediansDataEpoch = epoch
 	}
 
 	log.Debug("Locally calculated data:")
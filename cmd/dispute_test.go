@@ -580,6 +580,8 @@ func TestGetLocalMediansData(t *testing.T) {
 		revealedCollectionIds []uint16
 		revealedDataMaps      *types.RevealedDataMaps
 		mediansErr            error
+		mediansInMemory       []*big.Int
+		mediansDataEpoch      uint32
 	}
 	tests := []struct {
 		name    string
@@ -644,6 +646,24 @@ func TestGetLocalMediansData(t *testing.T) {
 			want2:   &types.RevealedDataMaps{},
 			wantErr: false,
 		},
+		{
+			name: "Test 6: When medians in memory are of the previous epoch and the propose data file of the epoch is read",
+			args: args{
+				epoch:            5,
+				mediansInMemory:  []*big.Int{big.NewInt(100)},
+				mediansDataEpoch: 4,
+				proposedData: types.ProposeFileData{
+					Epoch:                 5,
+					MediansData:           []*big.Int{big.NewInt(400), big.NewInt(500)},
+					RevealedCollectionIds: []uint16{1, 2},
+					RevealedDataMaps:      &types.RevealedDataMaps{},
+				},
+			},
+			want:    []*big.Int{big.NewInt(400), big.NewInt(500)},
+			want1:   []uint16{1, 2},
+			want2:   &types.RevealedDataMaps{},
+			wantErr: false,
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -653,7 +673,13 @@ func TestGetLocalMediansData(t *testing.T) {
 			razorUtils = utilsMock
 			cmdUtils = cmdUtilsMock
 
-			utilsMock.On("GetProposeDataFileName", mock.AnythingOfType("string")).Return(tt.args.fileName, tt.args.fileNameErr)
+			if tt.args.mediansDataEpoch != 0 {
+				_mediansData = tt.args.mediansInMemory
+				_mediansDataEpoch = tt.args.mediansDataEpoch
+			}
+
+			utilsMock.On("GetProposeDataFileName", mock.AnythingOfType("string"), mock.AnythingOfType("uint32")).Return(tt.args.fileName, tt.args.fileNameErr)
+			utilsMock.On("GetLegacyProposeDataFileName", mock.AnythingOfType("string")).Return("/home/data_files/0x000000000000000000000000000000000000dea1_proposedData.json", nil)
 			utilsMock.On("ReadFromProposeJsonFile", mock.Anything).Return(tt.args.proposedData, tt.args.proposeDataErr)
 			cmdUtilsMock.On("MakeBlock", mock.AnythingOfType("*ethclient.Client"), mock.Anything, mock.Anything, mock.Anything).Return(tt.args.medians, tt.args.revealedCollectionIds, tt.args.revealedDataMaps, tt.args.mediansErr)
 			ut := &UtilsStruct{}
@@ -0,0 +1,156 @@
+//Package cmd provides all functions related to command line
+package cmd
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"razor/core/types"
+	"razor/utils"
+	"strconv"
+	"strings"
+
+	"github.com/olekukonko/tablewriter"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+var historyCmd = &cobra.Command{
+	Use:   "history",
+	Short: "history exports the values of a collection kept while voting",
+	Long: `Exports the values of a collection kept in the local history database while voting, by epoch: the value of every job fetched, the aggregated value, the committed and revealed leaves and the median confirmed by the network. The values are printed as a table, CSV or JSON, either to the terminal or to a file.
+
+Example:
+  ./razor history --collectionId 1 --fromEpoch 1200 --toEpoch 1300 --format csv --output ethCollectionMean.csv
+`,
+	Run: initialiseHistory,
+}
+
+var historyFormats = []string{"table", "csv", "json"}
+
+//This function initialises the ExecuteHistory function
+func initialiseHistory(cmd *cobra.Command, args []string) {
+	cmdUtils.ExecuteHistory(cmd.Flags())
+}
+
+//This function sets the flags appropriately and executes the History function
+func (*UtilsStruct) ExecuteHistory(flagSet *pflag.FlagSet) {
+	razorUtils.AssignLogFile(flagSet)
+
+	collectionId, err := flagSetUtils.GetUint16CollectionId(flagSet)
+	utils.CheckError("Error in getting collectionId: ", err)
+
+	fromEpoch, err := flagSetUtils.GetUint32FromEpoch(flagSet)
+	utils.CheckError("Error in getting from epoch: ", err)
+
+	toEpoch, err := flagSetUtils.GetUint32ToEpoch(flagSet)
+	utils.CheckError("Error in getting to epoch: ", err)
+
+	format, err := flagSetUtils.GetStringFormat(flagSet)
+	utils.CheckError("Error in getting format: ", err)
+
+	output, err := flagSetUtils.GetStringOutput(flagSet)
+	utils.CheckError("Error in getting output: ", err)
+
+	if output == "" {
+		err = cmdUtils.History(collectionId, fromEpoch, toEpoch, format, os.Stdout)
+		utils.CheckError("Error in getting history: ", err)
+		return
+	}
+	// The output file is only created once the format is valid and the values are read, so that no empty file is left behind
+	var buffer bytes.Buffer
+	err = cmdUtils.History(collectionId, fromEpoch, toEpoch, format, &buffer)
+	utils.CheckError("Error in getting history: ", err)
+	err = os.WriteFile(output, buffer.Bytes(), 0644)
+	utils.CheckError("Error in writing output file: ", err)
+}
+
+//This function writes the values of the collection kept between the epochs in the format
+func (*UtilsStruct) History(collectionId uint16, fromEpoch uint32, toEpoch uint32, format string, writer io.Writer) error {
+	format = strings.ToLower(format)
+	if !utils.Contains(historyFormats, format) {
+		return fmt.Errorf("invalid format %s, it should be one of %s", format, strings.Join(historyFormats, ", "))
+	}
+	if toEpoch == 0 {
+		// No to epoch means up to the latest epoch kept
+		toEpoch = math.MaxUint32
+	}
+	if fromEpoch > toEpoch {
+		return errors.New("from epoch is after to epoch")
+	}
+	records, err := razorUtils.ReadHistory(collectionId, fromEpoch, toEpoch)
+	if err != nil {
+		return err
+	}
+
+	switch format {
+	case "json":
+		if records == nil {
+			records = []types.HistoryRecord{}
+		}
+		encoder := json.NewEncoder(writer)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(records)
+	case "csv":
+		csvWriter := csv.NewWriter(writer)
+		if err := csvWriter.Write([]string{"collectionId", "epoch", "kind", "job", "value"}); err != nil {
+			return err
+		}
+		for _, record := range records {
+			if err := csvWriter.Write(getHistoryRow(record)); err != nil {
+				return err
+			}
+		}
+		csvWriter.Flush()
+		return csvWriter.Error()
+	default:
+		if len(records) == 0 {
+			log.Infof("No values of collection %d have been kept in these epochs, they are kept while voting", collectionId)
+			return nil
+		}
+		table := tablewriter.NewWriter(writer)
+		table.SetHeader([]string{"Collection Id", "Epoch", "Kind", "Job", "Value"})
+		for _, record := range records {
+			table.Append(getHistoryRow(record))
+		}
+		table.Render()
+		return nil
+	}
+}
+
+//This function returns the fields of the record in the order they are exported
+func getHistoryRow(record types.HistoryRecord) []string {
+	return []string{
+		strconv.Itoa(int(record.CollectionId)),
+		strconv.FormatUint(uint64(record.Epoch), 10),
+		record.Kind,
+		record.Job,
+		record.Value.String(),
+	}
+}
+
+func init() {
+	rootCmd.AddCommand(historyCmd)
+
+	var (
+		CollectionId uint16
+		FromEpoch    uint32
+		ToEpoch      uint32
+		Format       string
+		Output       string
+	)
+
+	historyCmd.Flags().Uint16VarP(&CollectionId, "collectionId", "", 0, "collection id")
+	historyCmd.Flags().Uint32VarP(&FromEpoch, "fromEpoch", "", 0, "first epoch to export")
+	historyCmd.Flags().Uint32VarP(&ToEpoch, "toEpoch", "", 0, "last epoch to export, the latest epoch kept if not passed")
+	historyCmd.Flags().StringVarP(&Format, "format", "", "table", "format to export in: table, csv or json")
+	historyCmd.Flags().StringVarP(&Output, "output", "", "", "file to export to instead of the terminal")
+
+	collectionIdErr := historyCmd.MarkFlagRequired("collectionId")
+	utils.CheckError("Collection Id error: ", collectionIdErr)
+}
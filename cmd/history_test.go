@@ -0,0 +1,204 @@
+package cmd
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"math/big"
+	"os"
+	"path/filepath"
+	"razor/cmd/mocks"
+	"razor/core/types"
+	"testing"
+
+	"github.com/spf13/pflag"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestHistory(t *testing.T) {
+	records := []types.HistoryRecord{
+		{CollectionId: 1, Epoch: 10, Kind: "aggregated", Value: big.NewInt(295075)},
+		{CollectionId: 1, Epoch: 10, Kind: "job", Job: "ethusd_gemini", Value: big.NewInt(295100)},
+	}
+
+	type args struct {
+		fromEpoch  uint32
+		toEpoch    uint32
+		format     string
+		records    []types.HistoryRecord
+		historyErr error
+	}
+	tests := []struct {
+		name    string
+		args    args
+		want    string
+		wantErr bool
+	}{
+		{
+			name: "Test 1: When history is exported as CSV",
+			args: args{
+				fromEpoch: 10,
+				toEpoch:   10,
+				format:    "csv",
+				records:   records,
+			},
+			want: "collectionId,epoch,kind,job,value\n1,10,aggregated,,295075\n1,10,job,ethusd_gemini,295100\n",
+		},
+		{
+			name: "Test 2: When history is exported as JSON",
+			args: args{
+				format:  "JSON",
+				records: records[:1],
+			},
+			want: "[\n  {\n    \"collectionId\": 1,\n    \"epoch\": 10,\n    \"kind\": \"aggregated\",\n    \"value\": 295075\n  }\n]\n",
+		},
+		{
+			name: "Test 3: When there is no history in the epochs",
+			args: args{
+				format: "json",
+			},
+			want: "[]\n",
+		},
+		{
+			name: "Test 4: When format is invalid",
+			args: args{
+				format: "xml",
+			},
+			wantErr: true,
+		},
+		{
+			name: "Test 5: When from epoch is after to epoch",
+			args: args{
+				fromEpoch: 11,
+				toEpoch:   10,
+				format:    "csv",
+			},
+			wantErr: true,
+		},
+		{
+			name: "Test 6: When history cannot be read",
+			args: args{
+				format:     "table",
+				historyErr: errors.New("no history has been kept yet, it is kept while voting"),
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			utilsMock := new(mocks.UtilsInterface)
+			razorUtils = utilsMock
+
+			utilsMock.On("ReadHistory", uint16(1), mock.AnythingOfType("uint32"), mock.AnythingOfType("uint32")).Return(tt.args.records, tt.args.historyErr)
+
+			utils := &UtilsStruct{}
+			var output bytes.Buffer
+			err := utils.History(1, tt.args.fromEpoch, tt.args.toEpoch, tt.args.format, &output)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("History() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if output.String() != tt.want {
+				t.Errorf("History() wrote %q, want %q", output.String(), tt.want)
+			}
+		})
+	}
+}
+
+func TestExecuteHistory(t *testing.T) {
+	var flagSet *pflag.FlagSet
+
+	type args struct {
+		collectionIdErr error
+		format          string
+		formatErr       error
+		output          string
+		historyErr      error
+	}
+	tests := []struct {
+		name          string
+		args          args
+		wantOutput    string
+		expectedFatal bool
+	}{
+		{
+			name: "Test 1: When ExecuteHistory function executes successfully",
+			args: args{
+				format: "csv",
+			},
+			expectedFatal: false,
+		},
+		{
+			name: "Test 2: When there is an error in getting collectionId",
+			args: args{
+				collectionIdErr: errors.New("collectionId error"),
+			},
+			expectedFatal: true,
+		},
+		{
+			name: "Test 3: When there is an error in getting format",
+			args: args{
+				formatErr: errors.New("format error"),
+			},
+			expectedFatal: true,
+		},
+		{
+			name: "Test 4: When there is an error in getting history",
+			args: args{
+				format:     "xml",
+				historyErr: errors.New("invalid format xml"),
+			},
+			expectedFatal: true,
+		},
+		{
+			name: "Test 5: When history is written to an output file",
+			args: args{
+				format: "csv",
+				output: filepath.Join(t.TempDir(), "history.csv"),
+			},
+			wantOutput:    "collectionId,epoch,kind,job,value\n",
+			expectedFatal: false,
+		},
+	}
+	defer func() { log.ExitFunc = nil }()
+	var fatal bool
+	log.ExitFunc = func(int) { fatal = true }
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			utilsMock := new(mocks.UtilsInterface)
+			cmdUtilsMock := new(mocks.UtilsCmdInterface)
+			flagSetUtilsMock := new(mocks.FlagSetInterface)
+
+			razorUtils = utilsMock
+			cmdUtils = cmdUtilsMock
+			flagSetUtils = flagSetUtilsMock
+
+			utilsMock.On("AssignLogFile", mock.AnythingOfType("*pflag.FlagSet"))
+			flagSetUtilsMock.On("GetUint16CollectionId", mock.AnythingOfType("*pflag.FlagSet")).Return(uint16(1), tt.args.collectionIdErr)
+			flagSetUtilsMock.On("GetUint32FromEpoch", mock.AnythingOfType("*pflag.FlagSet")).Return(uint32(10), nil)
+			flagSetUtilsMock.On("GetUint32ToEpoch", mock.AnythingOfType("*pflag.FlagSet")).Return(uint32(20), nil)
+			flagSetUtilsMock.On("GetStringFormat", mock.AnythingOfType("*pflag.FlagSet")).Return(tt.args.format, tt.args.formatErr)
+			flagSetUtilsMock.On("GetStringOutput", mock.AnythingOfType("*pflag.FlagSet")).Return(tt.args.output, nil)
+			cmdUtilsMock.On("History", uint16(1), uint32(10), uint32(20), tt.args.format, mock.Anything).Run(func(args mock.Arguments) {
+				if tt.wantOutput != "" {
+					io.WriteString(args.Get(4).(io.Writer), tt.wantOutput)
+				}
+			}).Return(tt.args.historyErr)
+
+			utils := &UtilsStruct{}
+			fatal = false
+
+			utils.ExecuteHistory(flagSet)
+			if fatal != tt.expectedFatal {
+				t.Error("The ExecuteHistory function didn't execute as expected")
+			}
+			if tt.args.output != "" {
+				got, err := os.ReadFile(tt.args.output)
+				if err != nil || string(got) != tt.wantOutput {
+					t.Errorf("ExecuteHistory() wrote %q to output file, err = %v, want %q", got, err, tt.wantOutput)
+				}
+			}
+		})
+	}
+}
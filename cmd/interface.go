@@ -11,6 +11,7 @@ import (
 	Types "github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/ethclient"
 	"github.com/spf13/pflag"
+	"io"
 	"math/big"
 	Accounts "razor/accounts"
 	"razor/core/types"
@@ -124,9 +125,33 @@ type UtilsInterface interface {
 	SaveDataToDisputeJsonFile(filePath string, bountyIdQueue []uint32) error
 	ReadFromDisputeJsonFile(filePath string) (types.DisputeFileData, error)
 	AssignLogFile(flagSet *pflag.FlagSet)
-	GetCommitDataFileName(address string) (string, error)
-	GetProposeDataFileName(address string) (string, error)
+	GetCommitDataFileName(address string, epoch uint32) (string, error)
+	GetProposeDataFileName(address string, epoch uint32) (string, error)
+	GetLegacyCommitDataFileName(address string) (string, error)
+	GetLegacyProposeDataFileName(address string) (string, error)
 	GetDisputeDataFileName(address string) (string, error)
+	GetActiveJob(client *ethclient.Client, jobId uint16) (bindings.StructsJob, error)
+	GetActiveCollection(client *ethclient.Client, collectionId uint16) (bindings.StructsCollection, error)
+	GetCollectionJobs(client *ethclient.Client, collection bindings.StructsCollection) ([]types.AssetJob, error)
+	GetAggregationReport(collectionId uint16) (types.AggregationReport, bool)
+	GetDataFromAPI(url string, request types.JobRequest) ([]byte, error)
+	GetDatumFromJob(job types.AssetJob) (*big.Rat, error)
+	GetDataToCommitFromJob(job types.AssetJob) (*big.Int, error)
+	SetResponseCacheEpoch(epoch uint32)
+	LoadHTTPConfig() error
+	LoadJobHealth() error
+	RecordResponses(retention uint32) error
+	SetDataFilesPassword(password string, encrypt bool) error
+	ListDataFiles() ([]types.DataFileEntry, error)
+	PruneDataFiles(epoch uint32, retention uint32) ([]uint32, error)
+	ReplayResponses(epoch uint32) error
+	ReadJobHealth() ([]types.JobHealth, error)
+	OpenHistory() error
+	RecordLeavesHistory(client *ethclient.Client, epoch uint32, kind string, commitData types.CommitData)
+	RecordMediansHistory(client *ethclient.Client, epoch uint32)
+	ReadHistory(collectionId uint16, fromEpoch uint32, toEpoch uint32) ([]types.HistoryRecord, error)
+	ValidateAssetsFile(client *ethclient.Client) ([]error, error)
+	WatchAssetsFile(client *ethclient.Client) error
 }
 
 type StakeManagerInterface interface {
@@ -226,6 +251,15 @@ type FlagSetInterface interface {
 	GetBoolRogue(flagSet *pflag.FlagSet) (bool, error)
 	GetStringSliceRogueMode(flagSet *pflag.FlagSet) ([]string, error)
 	GetStringExposeMetrics(flagSet *pflag.FlagSet) (string, error)
+	GetBoolRecordResponses(flagSet *pflag.FlagSet) (bool, error)
+	GetBoolEncryptDataFiles(flagSet *pflag.FlagSet) (bool, error)
+	GetUint32DataFilesRetention(flagSet *pflag.FlagSet) (uint32, error)
+	GetBoolPrune(flagSet *pflag.FlagSet) (bool, error)
+	GetUint32Epoch(flagSet *pflag.FlagSet) (uint32, error)
+	GetUint32FromEpoch(flagSet *pflag.FlagSet) (uint32, error)
+	GetUint32ToEpoch(flagSet *pflag.FlagSet) (uint32, error)
+	GetStringFormat(flagSet *pflag.FlagSet) (string, error)
+	GetStringOutput(flagSet *pflag.FlagSet) (string, error)
 }
 
 type UtilsCmdInterface interface {
@@ -261,6 +295,24 @@ type UtilsCmdInterface interface {
 	WaitForAppropriateState(client *ethclient.Client, action string, states ...int) (uint32, error)
 	ExecuteJobList(flagSet *pflag.FlagSet)
 	GetJobList(client *ethclient.Client) error
+	ExecuteTestJob(flagSet *pflag.FlagSet)
+	TestJob(client *ethclient.Client, job types.AssetJob, epoch uint32) error
+	ExecuteTestCollection(flagSet *pflag.FlagSet)
+	TestCollection(client *ethclient.Client, collectionId uint16, epoch uint32) error
+	ExecuteValidateAssets(flagSet *pflag.FlagSet)
+	ValidateAssets(client *ethclient.Client) error
+	ExecuteJobHealth(flagSet *pflag.FlagSet)
+	GetJobHealth() error
+	ExecuteReplayCommit(flagSet *pflag.FlagSet)
+	ReplayCommit(address string, epoch uint32) error
+	ExecuteHistory(flagSet *pflag.FlagSet)
+	ExecuteVerifyReveal(flagSet *pflag.FlagSet)
+	VerifyReveal(client *ethclient.Client, account types.Account, epoch uint32, commitData types.CommitData, secret []byte) error
+	RecoverCommitData(client *ethclient.Client, account types.Account, epoch uint32, secret []byte) (types.CommitData, string, error)
+	History(collectionId uint16, fromEpoch uint32, toEpoch uint32, format string, writer io.Writer) error
+	ExecuteDataFiles(flagSet *pflag.FlagSet)
+	ListDataFiles(address string, writer io.Writer) error
+	InspectDataFiles(address string, epoch uint32, writer io.Writer) error
 	ExecuteUnstake(flagSet *pflag.FlagSet)
 	Unstake(config types.Configurations, client *ethclient.Client, input types.UnstakeInput) (common.Hash, error)
 	ApproveUnstake(client *ethclient.Client, staker bindings.StructsStaker, txnArgs types.TransactionOptions) (common.Hash, error)
@@ -286,8 +338,8 @@ type UtilsCmdInterface interface {
 	MakeBlock(client *ethclient.Client, blockNumber *big.Int, epoch uint32, rogueData types.Rogue) ([]*big.Int, []uint16, *types.RevealedDataMaps, error)
 	IsElectedProposer(proposer types.ElectedProposer, currentStakerStake *big.Int) bool
 	GetSortedRevealedValues(client *ethclient.Client, blockNumber *big.Int, epoch uint32) (*types.RevealedDataMaps, error)
-	GetIteration(client *ethclient.Client, proposer types.ElectedProposer, bufferPercent int32) int
-	Propose(client *ethclient.Client, config types.Configurations, account types.Account, staker bindings.StructsStaker, epoch uint32, blockNumber *big.Int, rogueData types.Rogue) (common.Hash, error)
+	GetIteration(ctx context.Context, client *ethclient.Client, proposer types.ElectedProposer, bufferPercent int32) int
+	Propose(ctx context.Context, client *ethclient.Client, config types.Configurations, account types.Account, staker bindings.StructsStaker, epoch uint32, blockNumber *big.Int, rogueData types.Rogue) (common.Hash, error)
 	GiveSorted(client *ethclient.Client, blockManager *bindings.BlockManager, txnOpts *bind.TransactOpts, epoch uint32, assetId uint16, sortedStakers []*big.Int)
 	GetLocalMediansData(client *ethclient.Client, account types.Account, epoch uint32, blockNumber *big.Int, rogueData types.Rogue) ([]*big.Int, []uint16, *types.RevealedDataMaps, error)
 	CheckDisputeForIds(client *ethclient.Client, transactionOpts types.TransactionOptions, epoch uint32, blockIndex uint8, idsInProposedBlock []uint16, revealedCollectionIds []uint16) (*Types.Transaction, error)
@@ -313,7 +365,7 @@ type UtilsCmdInterface interface {
 	AutoUnstakeAndWithdraw(client *ethclient.Client, account types.Account, amount *big.Int, config types.Configurations)
 	CalculateSecret(account types.Account, epoch uint32) ([]byte, error)
 	GetLastProposedEpoch(client *ethclient.Client, blockNumber *big.Int, stakerId uint32) (uint32, error)
-	HandleBlock(client *ethclient.Client, account types.Account, blockNumber *big.Int, config types.Configurations, rogueData types.Rogue)
+	HandleBlock(ctx context.Context, client *ethclient.Client, account types.Account, blockNumber *big.Int, config types.Configurations, rogueData types.Rogue)
 	ExecuteVote(flagSet *pflag.FlagSet)
 	Vote(ctx context.Context, config types.Configurations, client *ethclient.Client, rogueData types.Rogue, account types.Account) error
 	HandleExit()
@@ -322,7 +374,7 @@ type UtilsCmdInterface interface {
 	ExecuteStake(flagSet *pflag.FlagSet)
 	InitiateCommit(client *ethclient.Client, config types.Configurations, account types.Account, epoch uint32, stakerId uint32, rogueData types.Rogue) error
 	InitiateReveal(client *ethclient.Client, config types.Configurations, account types.Account, epoch uint32, staker bindings.StructsStaker, rogueData types.Rogue) error
-	InitiatePropose(client *ethclient.Client, config types.Configurations, account types.Account, epoch uint32, staker bindings.StructsStaker, blockNumber *big.Int, rogueData types.Rogue) error
+	InitiatePropose(ctx context.Context, client *ethclient.Client, config types.Configurations, account types.Account, epoch uint32, staker bindings.StructsStaker, blockNumber *big.Int, rogueData types.Rogue) error
 	GetBountyIdFromEvents(client *ethclient.Client, blockNumber *big.Int, bountyHunter string) (uint32, error)
 	HandleClaimBounty(client *ethclient.Client, config types.Configurations, account types.Account) error
 	ExecuteContractAddresses(flagSet *pflag.FlagSet)
@@ -0,0 +1,78 @@
+//Package cmd provides all functions related to command line
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"razor/utils"
+	"strconv"
+
+	"github.com/olekukonko/tablewriter"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+var jobHealthCmd = &cobra.Command{
+	Use:   "jobHealth",
+	Short: "jobHealth reports how reliable the jobs of each collection have been while voting",
+	Long: `Reports for every job fetched while voting in how many epochs it was fetched and failed, how far its values were from the confirmed medians on average and the factor its weight is multiplied by. Jobs of collections with a reputation in assets.json are down-weighted or excluded by their health.
+
+Example:
+  ./razor jobHealth
+`,
+	Run: initialiseJobHealth,
+}
+
+//This function initialises the ExecuteJobHealth function
+func initialiseJobHealth(cmd *cobra.Command, args []string) {
+	cmdUtils.ExecuteJobHealth(cmd.Flags())
+}
+
+//This function sets the flags appropriately and executes the GetJobHealth function
+func (*UtilsStruct) ExecuteJobHealth(flagSet *pflag.FlagSet) {
+	razorUtils.AssignLogFile(flagSet)
+
+	err := cmdUtils.GetJobHealth()
+	utils.CheckError("Error in getting health of jobs: ", err)
+}
+
+//This function prints the health of every job kept from voting
+func (*UtilsStruct) GetJobHealth() error {
+	report, err := razorUtils.ReadJobHealth()
+	if err != nil {
+		return err
+	}
+	if len(report) == 0 {
+		log.Info("No health of jobs has been recorded yet, it is recorded while voting")
+		return nil
+	}
+
+	table := tablewriter.NewWriter(os.Stdout)
+	table.SetHeader([]string{"Collection", "Job", "URL", "Epochs", "Failures", "Failure Rate", "Samples", "Deviation", "Weight Factor", "Status", "Reason"})
+	for _, health := range report {
+		status := health.Status
+		if status == "" {
+			// Jobs of collections without a reputation are only tracked
+			status = "tracked"
+		}
+		table.Append([]string{
+			health.Collection,
+			health.Job,
+			health.Url,
+			strconv.Itoa(int(health.Epochs)),
+			strconv.Itoa(int(health.Failures)),
+			fmt.Sprintf("%.1f%%", health.FailureRate),
+			strconv.Itoa(int(health.Samples)),
+			fmt.Sprintf("%.2f%%", health.Deviation),
+			fmt.Sprintf("%.2f", health.WeightFactor),
+			status,
+			health.Reason,
+		})
+	}
+	table.Render()
+	return nil
+}
+
+func init() {
+	rootCmd.AddCommand(jobHealthCmd)
+}
@@ -0,0 +1,107 @@
+package cmd
+
+import (
+	"errors"
+	"razor/cmd/mocks"
+	"razor/core/types"
+	"testing"
+
+	"github.com/spf13/pflag"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestGetJobHealth(t *testing.T) {
+	report := []types.JobHealth{
+		{Collection: "ethCollectionMean", Job: "#1", Url: "https://api.gemini.com/v1/pubticker/ethusd", Epochs: 20, Samples: 19, Deviation: 0.4, WeightFactor: 1, Status: "ok"},
+		{Collection: "ethCollectionMean", Job: "eth_kraken", Url: "https://api.kraken.com/0/public/Ticker?pair=ETHUSD", Epochs: 20, Failures: 12, FailureRate: 61.2, WeightFactor: 0, Status: "excluded", Reason: "fails 61.2% of epochs"},
+		{Collection: "btcCollectionMean", Job: "#4", Url: "https://api.gemini.com/v1/pubticker/btcusd", Epochs: 3, WeightFactor: 1},
+	}
+
+	type args struct {
+		report    []types.JobHealth
+		reportErr error
+	}
+	tests := []struct {
+		name    string
+		args    args
+		wantErr bool
+	}{
+		{
+			name: "Test 1: When health of jobs is printed",
+			args: args{
+				report: report,
+			},
+			wantErr: false,
+		},
+		{
+			name:    "Test 2: When no health of jobs has been recorded",
+			args:    args{},
+			wantErr: false,
+		},
+		{
+			name: "Test 3: When there is an error in reading health of jobs",
+			args: args{
+				reportErr: errors.New("invalid job health file"),
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			utilsMock := new(mocks.UtilsInterface)
+			razorUtils = utilsMock
+
+			utilsMock.On("ReadJobHealth").Return(tt.args.report, tt.args.reportErr)
+
+			utils := &UtilsStruct{}
+			err := utils.GetJobHealth()
+			if (err != nil) != tt.wantErr {
+				t.Errorf("GetJobHealth() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestExecuteJobHealth(t *testing.T) {
+	var flagSet *pflag.FlagSet
+
+	tests := []struct {
+		name          string
+		jobHealthErr  error
+		expectedFatal bool
+	}{
+		{
+			name:          "Test 1: When ExecuteJobHealth function executes successfully",
+			expectedFatal: false,
+		},
+		{
+			name:          "Test 2: When there is an error in getting health of jobs",
+			jobHealthErr:  errors.New("invalid job health file"),
+			expectedFatal: true,
+		},
+	}
+	defer func() { log.ExitFunc = nil }()
+	var fatal bool
+	log.ExitFunc = func(int) { fatal = true }
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			utilsMock := new(mocks.UtilsInterface)
+			cmdUtilsMock := new(mocks.UtilsCmdInterface)
+
+			razorUtils = utilsMock
+			cmdUtils = cmdUtilsMock
+
+			utilsMock.On("AssignLogFile", mock.AnythingOfType("*pflag.FlagSet"))
+			cmdUtilsMock.On("GetJobHealth").Return(tt.jobHealthErr)
+
+			utils := &UtilsStruct{}
+			fatal = false
+
+			utils.ExecuteJobHealth(flagSet)
+			if fatal != tt.expectedFatal {
+				t.Error("The ExecuteJobHealth function didn't execute as expected")
+			}
+		})
+	}
+}
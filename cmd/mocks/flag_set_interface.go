@@ -33,6 +33,69 @@ func (_m *FlagSetInterface) GetBoolAutoVote(flagSet *pflag.FlagSet) (bool, error
 	return r0, r1
 }
 
+// GetBoolEncryptDataFiles provides a mock function with given fields: flagSet
+func (_m *FlagSetInterface) GetBoolEncryptDataFiles(flagSet *pflag.FlagSet) (bool, error) {
+	ret := _m.Called(flagSet)
+
+	var r0 bool
+	if rf, ok := ret.Get(0).(func(*pflag.FlagSet) bool); ok {
+		r0 = rf(flagSet)
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(*pflag.FlagSet) error); ok {
+		r1 = rf(flagSet)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetBoolPrune provides a mock function with given fields: flagSet
+func (_m *FlagSetInterface) GetBoolPrune(flagSet *pflag.FlagSet) (bool, error) {
+	ret := _m.Called(flagSet)
+
+	var r0 bool
+	if rf, ok := ret.Get(0).(func(*pflag.FlagSet) bool); ok {
+		r0 = rf(flagSet)
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(*pflag.FlagSet) error); ok {
+		r1 = rf(flagSet)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetBoolRecordResponses provides a mock function with given fields: flagSet
+func (_m *FlagSetInterface) GetBoolRecordResponses(flagSet *pflag.FlagSet) (bool, error) {
+	ret := _m.Called(flagSet)
+
+	var r0 bool
+	if rf, ok := ret.Get(0).(func(*pflag.FlagSet) bool); ok {
+		r0 = rf(flagSet)
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(*pflag.FlagSet) error); ok {
+		r1 = rf(flagSet)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // GetBoolRogue provides a mock function with given fields: flagSet
 func (_m *FlagSetInterface) GetBoolRogue(flagSet *pflag.FlagSet) (bool, error) {
 	ret := _m.Called(flagSet)
@@ -411,6 +474,27 @@ func (_m *FlagSetInterface) GetStringExposeMetrics(flagSet *pflag.FlagSet) (stri
 	return r0, r1
 }
 
+// GetStringFormat provides a mock function with given fields: flagSet
+func (_m *FlagSetInterface) GetStringFormat(flagSet *pflag.FlagSet) (string, error) {
+	ret := _m.Called(flagSet)
+
+	var r0 string
+	if rf, ok := ret.Get(0).(func(*pflag.FlagSet) string); ok {
+		r0 = rf(flagSet)
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(*pflag.FlagSet) error); ok {
+		r1 = rf(flagSet)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // GetStringFrom provides a mock function with given fields: flagSet
 func (_m *FlagSetInterface) GetStringFrom(flagSet *pflag.FlagSet) (string, error) {
 	ret := _m.Called(flagSet)
@@ -474,6 +558,27 @@ func (_m *FlagSetInterface) GetStringName(flagSet *pflag.FlagSet) (string, error
 	return r0, r1
 }
 
+// GetStringOutput provides a mock function with given fields: flagSet
+func (_m *FlagSetInterface) GetStringOutput(flagSet *pflag.FlagSet) (string, error) {
+	ret := _m.Called(flagSet)
+
+	var r0 string
+	if rf, ok := ret.Get(0).(func(*pflag.FlagSet) string); ok {
+		r0 = rf(flagSet)
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(*pflag.FlagSet) error); ok {
+		r1 = rf(flagSet)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // GetStringPow provides a mock function with given fields: flagSet
 func (_m *FlagSetInterface) GetStringPow(flagSet *pflag.FlagSet) (string, error) {
 	ret := _m.Called(flagSet)
@@ -749,6 +854,69 @@ func (_m *FlagSetInterface) GetUint32BountyId(flagSet *pflag.FlagSet) (uint32, e
 	return r0, r1
 }
 
+// GetUint32DataFilesRetention provides a mock function with given fields: flagSet
+func (_m *FlagSetInterface) GetUint32DataFilesRetention(flagSet *pflag.FlagSet) (uint32, error) {
+	ret := _m.Called(flagSet)
+
+	var r0 uint32
+	if rf, ok := ret.Get(0).(func(*pflag.FlagSet) uint32); ok {
+		r0 = rf(flagSet)
+	} else {
+		r0 = ret.Get(0).(uint32)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(*pflag.FlagSet) error); ok {
+		r1 = rf(flagSet)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetUint32Epoch provides a mock function with given fields: flagSet
+func (_m *FlagSetInterface) GetUint32Epoch(flagSet *pflag.FlagSet) (uint32, error) {
+	ret := _m.Called(flagSet)
+
+	var r0 uint32
+	if rf, ok := ret.Get(0).(func(*pflag.FlagSet) uint32); ok {
+		r0 = rf(flagSet)
+	} else {
+		r0 = ret.Get(0).(uint32)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(*pflag.FlagSet) error); ok {
+		r1 = rf(flagSet)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetUint32FromEpoch provides a mock function with given fields: flagSet
+func (_m *FlagSetInterface) GetUint32FromEpoch(flagSet *pflag.FlagSet) (uint32, error) {
+	ret := _m.Called(flagSet)
+
+	var r0 uint32
+	if rf, ok := ret.Get(0).(func(*pflag.FlagSet) uint32); ok {
+		r0 = rf(flagSet)
+	} else {
+		r0 = ret.Get(0).(uint32)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(*pflag.FlagSet) error); ok {
+		r1 = rf(flagSet)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // GetUint32StakerId provides a mock function with given fields: flagSet
 func (_m *FlagSetInterface) GetUint32StakerId(flagSet *pflag.FlagSet) (uint32, error) {
 	ret := _m.Called(flagSet)
@@ -770,6 +938,27 @@ func (_m *FlagSetInterface) GetUint32StakerId(flagSet *pflag.FlagSet) (uint32, e
 	return r0, r1
 }
 
+// GetUint32ToEpoch provides a mock function with given fields: flagSet
+func (_m *FlagSetInterface) GetUint32ToEpoch(flagSet *pflag.FlagSet) (uint32, error) {
+	ret := _m.Called(flagSet)
+
+	var r0 uint32
+	if rf, ok := ret.Get(0).(func(*pflag.FlagSet) uint32); ok {
+		r0 = rf(flagSet)
+	} else {
+		r0 = ret.Get(0).(uint32)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(*pflag.FlagSet) error); ok {
+		r1 = rf(flagSet)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // GetUint32Tolerance provides a mock function with given fields: flagSet
 func (_m *FlagSetInterface) GetUint32Tolerance(flagSet *pflag.FlagSet) (uint32, error) {
 	ret := _m.Called(flagSet)
@@ -19,6 +19,8 @@ import (
 
 	ethclient "github.com/ethereum/go-ethereum/ethclient"
 
+	io "io"
+
 	mock "github.com/stretchr/testify/mock"
 
 	pflag "github.com/spf13/pflag"
@@ -399,6 +401,11 @@ func (_m *UtilsCmdInterface) ExecuteCreateJob(flagSet *pflag.FlagSet) {
 	_m.Called(flagSet)
 }
 
+// ExecuteDataFiles provides a mock function with given fields: flagSet
+func (_m *UtilsCmdInterface) ExecuteDataFiles(flagSet *pflag.FlagSet) {
+	_m.Called(flagSet)
+}
+
 // ExecuteDelegate provides a mock function with given fields: flagSet
 func (_m *UtilsCmdInterface) ExecuteDelegate(flagSet *pflag.FlagSet) {
 	_m.Called(flagSet)
@@ -409,6 +416,11 @@ func (_m *UtilsCmdInterface) ExecuteExtendLock(flagSet *pflag.FlagSet) {
 	_m.Called(flagSet)
 }
 
+// ExecuteHistory provides a mock function with given fields: flagSet
+func (_m *UtilsCmdInterface) ExecuteHistory(flagSet *pflag.FlagSet) {
+	_m.Called(flagSet)
+}
+
 // ExecuteImport provides a mock function with given fields: flagSet
 func (_m *UtilsCmdInterface) ExecuteImport(flagSet *pflag.FlagSet) {
 	_m.Called(flagSet)
@@ -419,6 +431,11 @@ func (_m *UtilsCmdInterface) ExecuteInitiateWithdraw(flagSet *pflag.FlagSet) {
 	_m.Called(flagSet)
 }
 
+// ExecuteJobHealth provides a mock function with given fields: flagSet
+func (_m *UtilsCmdInterface) ExecuteJobHealth(flagSet *pflag.FlagSet) {
+	_m.Called(flagSet)
+}
+
 // ExecuteJobList provides a mock function with given fields: flagSet
 func (_m *UtilsCmdInterface) ExecuteJobList(flagSet *pflag.FlagSet) {
 	_m.Called(flagSet)
@@ -434,6 +451,11 @@ func (_m *UtilsCmdInterface) ExecuteModifyCollectionStatus(flagSet *pflag.FlagSe
 	_m.Called(flagSet)
 }
 
+// ExecuteReplayCommit provides a mock function with given fields: flagSet
+func (_m *UtilsCmdInterface) ExecuteReplayCommit(flagSet *pflag.FlagSet) {
+	_m.Called(flagSet)
+}
+
 // ExecuteSetDelegation provides a mock function with given fields: flagSet
 func (_m *UtilsCmdInterface) ExecuteSetDelegation(flagSet *pflag.FlagSet) {
 	_m.Called(flagSet)
@@ -449,6 +471,16 @@ func (_m *UtilsCmdInterface) ExecuteStakerinfo(flagSet *pflag.FlagSet) {
 	_m.Called(flagSet)
 }
 
+// ExecuteTestCollection provides a mock function with given fields: flagSet
+func (_m *UtilsCmdInterface) ExecuteTestCollection(flagSet *pflag.FlagSet) {
+	_m.Called(flagSet)
+}
+
+// ExecuteTestJob provides a mock function with given fields: flagSet
+func (_m *UtilsCmdInterface) ExecuteTestJob(flagSet *pflag.FlagSet) {
+	_m.Called(flagSet)
+}
+
 // ExecuteTransfer provides a mock function with given fields: flagSet
 func (_m *UtilsCmdInterface) ExecuteTransfer(flagSet *pflag.FlagSet) {
 	_m.Called(flagSet)
@@ -479,6 +511,16 @@ func (_m *UtilsCmdInterface) ExecuteUpdateJob(flagSet *pflag.FlagSet) {
 	_m.Called(flagSet)
 }
 
+// ExecuteValidateAssets provides a mock function with given fields: flagSet
+func (_m *UtilsCmdInterface) ExecuteValidateAssets(flagSet *pflag.FlagSet) {
+	_m.Called(flagSet)
+}
+
+// ExecuteVerifyReveal provides a mock function with given fields: flagSet
+func (_m *UtilsCmdInterface) ExecuteVerifyReveal(flagSet *pflag.FlagSet) {
+	_m.Called(flagSet)
+}
+
 // ExecuteVote provides a mock function with given fields: flagSet
 func (_m *UtilsCmdInterface) ExecuteVote(flagSet *pflag.FlagSet) {
 	_m.Called(flagSet)
@@ -712,13 +754,13 @@ func (_m *UtilsCmdInterface) GetGasPrice() (int32, error) {
 	return r0, r1
 }
 
-// GetIteration provides a mock function with given fields: client, proposer, bufferPercent
-func (_m *UtilsCmdInterface) GetIteration(client *ethclient.Client, proposer types.ElectedProposer, bufferPercent int32) int {
-	ret := _m.Called(client, proposer, bufferPercent)
+// GetIteration provides a mock function with given fields: ctx, client, proposer, bufferPercent
+func (_m *UtilsCmdInterface) GetIteration(ctx context.Context, client *ethclient.Client, proposer types.ElectedProposer, bufferPercent int32) int {
+	ret := _m.Called(ctx, client, proposer, bufferPercent)
 
 	var r0 int
-	if rf, ok := ret.Get(0).(func(*ethclient.Client, types.ElectedProposer, int32) int); ok {
-		r0 = rf(client, proposer, bufferPercent)
+	if rf, ok := ret.Get(0).(func(context.Context, *ethclient.Client, types.ElectedProposer, int32) int); ok {
+		r0 = rf(ctx, client, proposer, bufferPercent)
 	} else {
 		r0 = ret.Get(0).(int)
 	}
@@ -726,6 +768,20 @@ func (_m *UtilsCmdInterface) GetIteration(client *ethclient.Client, proposer typ
 	return r0
 }
 
+// GetJobHealth provides a mock function with given fields:
+func (_m *UtilsCmdInterface) GetJobHealth() error {
+	ret := _m.Called()
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func() error); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // GetJobList provides a mock function with given fields: client
 func (_m *UtilsCmdInterface) GetJobList(client *ethclient.Client) error {
 	ret := _m.Called(client)
@@ -951,9 +1007,9 @@ func (_m *UtilsCmdInterface) GiveSorted(client *ethclient.Client, blockManager *
 	_m.Called(client, blockManager, txnOpts, epoch, assetId, sortedStakers)
 }
 
-// HandleBlock provides a mock function with given fields: client, account, blockNumber, config, rogueData
-func (_m *UtilsCmdInterface) HandleBlock(client *ethclient.Client, account types.Account, blockNumber *big.Int, config types.Configurations, rogueData types.Rogue) {
-	_m.Called(client, account, blockNumber, config, rogueData)
+// HandleBlock provides a mock function with given fields: ctx, client, account, blockNumber, config, rogueData
+func (_m *UtilsCmdInterface) HandleBlock(ctx context.Context, client *ethclient.Client, account types.Account, blockNumber *big.Int, config types.Configurations, rogueData types.Rogue) {
+	_m.Called(ctx, client, account, blockNumber, config, rogueData)
 }
 
 // HandleClaimBounty provides a mock function with given fields: client, config, account
@@ -1070,6 +1126,20 @@ func (_m *UtilsCmdInterface) HandleWithdrawLock(client *ethclient.Client, accoun
 	return r0, r1
 }
 
+// History provides a mock function with given fields: collectionId, fromEpoch, toEpoch, format, writer
+func (_m *UtilsCmdInterface) History(collectionId uint16, fromEpoch uint32, toEpoch uint32, format string, writer io.Writer) error {
+	ret := _m.Called(collectionId, fromEpoch, toEpoch, format, writer)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(uint16, uint32, uint32, string, io.Writer) error); ok {
+		r0 = rf(collectionId, fromEpoch, toEpoch, format, writer)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // ImportAccount provides a mock function with given fields:
 func (_m *UtilsCmdInterface) ImportAccount() (accounts.Account, error) {
 	ret := _m.Called()
@@ -1144,13 +1214,13 @@ func (_m *UtilsCmdInterface) InitiateCommit(client *ethclient.Client, config typ
 	return r0
 }
 
-// InitiatePropose provides a mock function with given fields: client, config, account, epoch, staker, blockNumber, rogueData
-func (_m *UtilsCmdInterface) InitiatePropose(client *ethclient.Client, config types.Configurations, account types.Account, epoch uint32, staker bindings.StructsStaker, blockNumber *big.Int, rogueData types.Rogue) error {
-	ret := _m.Called(client, config, account, epoch, staker, blockNumber, rogueData)
+// InitiatePropose provides a mock function with given fields: ctx, client, config, account, epoch, staker, blockNumber, rogueData
+func (_m *UtilsCmdInterface) InitiatePropose(ctx context.Context, client *ethclient.Client, config types.Configurations, account types.Account, epoch uint32, staker bindings.StructsStaker, blockNumber *big.Int, rogueData types.Rogue) error {
+	ret := _m.Called(ctx, client, config, account, epoch, staker, blockNumber, rogueData)
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(*ethclient.Client, types.Configurations, types.Account, uint32, bindings.StructsStaker, *big.Int, types.Rogue) error); ok {
-		r0 = rf(client, config, account, epoch, staker, blockNumber, rogueData)
+	if rf, ok := ret.Get(0).(func(context.Context, *ethclient.Client, types.Configurations, types.Account, uint32, bindings.StructsStaker, *big.Int, types.Rogue) error); ok {
+		r0 = rf(ctx, client, config, account, epoch, staker, blockNumber, rogueData)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -1195,6 +1265,20 @@ func (_m *UtilsCmdInterface) InitiateWithdraw(client *ethclient.Client, txnOpts
 	return r0, r1
 }
 
+// InspectDataFiles provides a mock function with given fields: address, epoch, writer
+func (_m *UtilsCmdInterface) InspectDataFiles(address string, epoch uint32, writer io.Writer) error {
+	ret := _m.Called(address, epoch, writer)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string, uint32, io.Writer) error); ok {
+		r0 = rf(address, epoch, writer)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // IsElectedProposer provides a mock function with given fields: proposer, currentStakerStake
 func (_m *UtilsCmdInterface) IsElectedProposer(proposer types.ElectedProposer, currentStakerStake *big.Int) bool {
 	ret := _m.Called(proposer, currentStakerStake)
@@ -1232,6 +1316,20 @@ func (_m *UtilsCmdInterface) ListAccounts() ([]accounts.Account, error) {
 	return r0, r1
 }
 
+// ListDataFiles provides a mock function with given fields: address, writer
+func (_m *UtilsCmdInterface) ListDataFiles(address string, writer io.Writer) error {
+	ret := _m.Called(address, writer)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string, io.Writer) error); ok {
+		r0 = rf(address, writer)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // MakeBlock provides a mock function with given fields: client, blockNumber, epoch, rogueData
 func (_m *UtilsCmdInterface) MakeBlock(client *ethclient.Client, blockNumber *big.Int, epoch uint32, rogueData types.Rogue) ([]*big.Int, []uint16, *types.RevealedDataMaps, error) {
 	ret := _m.Called(client, blockNumber, epoch, rogueData)
@@ -1296,22 +1394,20 @@ func (_m *UtilsCmdInterface) ModifyCollectionStatus(client *ethclient.Client, co
 	return r0, r1
 }
 
-// Propose provides a mock function with given fields: client, config, account, staker, epoch, blockNumber, rogueData
-func (_m *UtilsCmdInterface) Propose(client *ethclient.Client, config types.Configurations, account types.Account, staker bindings.StructsStaker, epoch uint32, blockNumber *big.Int, rogueData types.Rogue) (common.Hash, error) {
-	ret := _m.Called(client, config, account, staker, epoch, blockNumber, rogueData)
+// Propose provides a mock function with given fields: ctx, client, config, account, staker, epoch, blockNumber, rogueData
+func (_m *UtilsCmdInterface) Propose(ctx context.Context, client *ethclient.Client, config types.Configurations, account types.Account, staker bindings.StructsStaker, epoch uint32, blockNumber *big.Int, rogueData types.Rogue) (common.Hash, error) {
+	ret := _m.Called(ctx, client, config, account, staker, epoch, blockNumber, rogueData)
 
 	var r0 common.Hash
-	if rf, ok := ret.Get(0).(func(*ethclient.Client, types.Configurations, types.Account, bindings.StructsStaker, uint32, *big.Int, types.Rogue) common.Hash); ok {
-		r0 = rf(client, config, account, staker, epoch, blockNumber, rogueData)
+	if rf, ok := ret.Get(0).(func(context.Context, *ethclient.Client, types.Configurations, types.Account, bindings.StructsStaker, uint32, *big.Int, types.Rogue) common.Hash); ok {
+		r0 = rf(ctx, client, config, account, staker, epoch, blockNumber, rogueData)
 	} else {
-		if ret.Get(0) != nil {
-			r0 = ret.Get(0).(common.Hash)
-		}
+		r0 = ret.Get(0).(common.Hash)
 	}
 
 	var r1 error
-	if rf, ok := ret.Get(1).(func(*ethclient.Client, types.Configurations, types.Account, bindings.StructsStaker, uint32, *big.Int, types.Rogue) error); ok {
-		r1 = rf(client, config, account, staker, epoch, blockNumber, rogueData)
+	if rf, ok := ret.Get(1).(func(context.Context, *ethclient.Client, types.Configurations, types.Account, bindings.StructsStaker, uint32, *big.Int, types.Rogue) error); ok {
+		r1 = rf(ctx, client, config, account, staker, epoch, blockNumber, rogueData)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -1319,6 +1415,48 @@ func (_m *UtilsCmdInterface) Propose(client *ethclient.Client, config types.Conf
 	return r0, r1
 }
 
+// RecoverCommitData provides a mock function with given fields: client, account, epoch, secret
+func (_m *UtilsCmdInterface) RecoverCommitData(client *ethclient.Client, account types.Account, epoch uint32, secret []byte) (types.CommitData, string, error) {
+	ret := _m.Called(client, account, epoch, secret)
+
+	var r0 types.CommitData
+	if rf, ok := ret.Get(0).(func(*ethclient.Client, types.Account, uint32, []byte) types.CommitData); ok {
+		r0 = rf(client, account, epoch, secret)
+	} else {
+		r0 = ret.Get(0).(types.CommitData)
+	}
+
+	var r1 string
+	if rf, ok := ret.Get(1).(func(*ethclient.Client, types.Account, uint32, []byte) string); ok {
+		r1 = rf(client, account, epoch, secret)
+	} else {
+		r1 = ret.Get(1).(string)
+	}
+
+	var r2 error
+	if rf, ok := ret.Get(2).(func(*ethclient.Client, types.Account, uint32, []byte) error); ok {
+		r2 = rf(client, account, epoch, secret)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
+// ReplayCommit provides a mock function with given fields: address, epoch
+func (_m *UtilsCmdInterface) ReplayCommit(address string, epoch uint32) error {
+	ret := _m.Called(address, epoch)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string, uint32) error); ok {
+		r0 = rf(address, epoch)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // ResetUnstakeLock provides a mock function with given fields: client, config, extendLockInput
 func (_m *UtilsCmdInterface) ResetUnstakeLock(client *ethclient.Client, config types.Configurations, extendLockInput types.ExtendLockInput) (common.Hash, error) {
 	ret := _m.Called(client, config, extendLockInput)
@@ -1439,6 +1577,34 @@ func (_m *UtilsCmdInterface) StoreBountyId(client *ethclient.Client, account typ
 	return r0
 }
 
+// TestCollection provides a mock function with given fields: client, collectionId, epoch
+func (_m *UtilsCmdInterface) TestCollection(client *ethclient.Client, collectionId uint16, epoch uint32) error {
+	ret := _m.Called(client, collectionId, epoch)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(*ethclient.Client, uint16, uint32) error); ok {
+		r0 = rf(client, collectionId, epoch)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// TestJob provides a mock function with given fields: client, job, epoch
+func (_m *UtilsCmdInterface) TestJob(client *ethclient.Client, job types.AssetJob, epoch uint32) error {
+	ret := _m.Called(client, job, epoch)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(*ethclient.Client, types.AssetJob, uint32) error); ok {
+		r0 = rf(client, job, epoch)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // Transfer provides a mock function with given fields: client, config, transferInput
 func (_m *UtilsCmdInterface) Transfer(client *ethclient.Client, config types.Configurations, transferInput types.TransferInput) (common.Hash, error) {
 	ret := _m.Called(client, config, transferInput)
@@ -1568,6 +1734,34 @@ func (_m *UtilsCmdInterface) UpdateJob(client *ethclient.Client, config types.Co
 	return r0, r1
 }
 
+// ValidateAssets provides a mock function with given fields: client
+func (_m *UtilsCmdInterface) ValidateAssets(client *ethclient.Client) error {
+	ret := _m.Called(client)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(*ethclient.Client) error); ok {
+		r0 = rf(client)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// VerifyReveal provides a mock function with given fields: client, account, epoch, commitData, secret
+func (_m *UtilsCmdInterface) VerifyReveal(client *ethclient.Client, account types.Account, epoch uint32, commitData types.CommitData, secret []byte) error {
+	ret := _m.Called(client, account, epoch, commitData, secret)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(*ethclient.Client, types.Account, uint32, types.CommitData, []byte) error); ok {
+		r0 = rf(client, account, epoch, commitData, secret)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // Vote provides a mock function with given fields: ctx, config, client, rogueData, account
 func (_m *UtilsCmdInterface) Vote(ctx context.Context, config types.Configurations, client *ethclient.Client, rogueData types.Rogue, account types.Account) error {
 	ret := _m.Called(ctx, config, client, rogueData, account)
@@ -274,6 +274,27 @@ func (_m *UtilsInterface) FetchBalance(client *ethclient.Client, accountAddress
 	return r0, r1
 }
 
+// GetActiveCollection provides a mock function with given fields: client, collectionId
+func (_m *UtilsInterface) GetActiveCollection(client *ethclient.Client, collectionId uint16) (bindings.StructsCollection, error) {
+	ret := _m.Called(client, collectionId)
+
+	var r0 bindings.StructsCollection
+	if rf, ok := ret.Get(0).(func(*ethclient.Client, uint16) bindings.StructsCollection); ok {
+		r0 = rf(client, collectionId)
+	} else {
+		r0 = ret.Get(0).(bindings.StructsCollection)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(*ethclient.Client, uint16) error); ok {
+		r1 = rf(client, collectionId)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // GetActiveCollections provides a mock function with given fields: client
 func (_m *UtilsInterface) GetActiveCollections(client *ethclient.Client) ([]uint16, error) {
 	ret := _m.Called(client)
@@ -297,6 +318,27 @@ func (_m *UtilsInterface) GetActiveCollections(client *ethclient.Client) ([]uint
 	return r0, r1
 }
 
+// GetActiveJob provides a mock function with given fields: client, jobId
+func (_m *UtilsInterface) GetActiveJob(client *ethclient.Client, jobId uint16) (bindings.StructsJob, error) {
+	ret := _m.Called(client, jobId)
+
+	var r0 bindings.StructsJob
+	if rf, ok := ret.Get(0).(func(*ethclient.Client, uint16) bindings.StructsJob); ok {
+		r0 = rf(client, jobId)
+	} else {
+		r0 = ret.Get(0).(bindings.StructsJob)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(*ethclient.Client, uint16) error); ok {
+		r1 = rf(client, jobId)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // GetAggregatedDataOfCollection provides a mock function with given fields: client, collectionId, epoch
 func (_m *UtilsInterface) GetAggregatedDataOfCollection(client *ethclient.Client, collectionId uint16, epoch uint32) (*big.Int, error) {
 	ret := _m.Called(client, collectionId, epoch)
@@ -320,6 +362,27 @@ func (_m *UtilsInterface) GetAggregatedDataOfCollection(client *ethclient.Client
 	return r0, r1
 }
 
+// GetAggregationReport provides a mock function with given fields: collectionId
+func (_m *UtilsInterface) GetAggregationReport(collectionId uint16) (types.AggregationReport, bool) {
+	ret := _m.Called(collectionId)
+
+	var r0 types.AggregationReport
+	if rf, ok := ret.Get(0).(func(uint16) types.AggregationReport); ok {
+		r0 = rf(collectionId)
+	} else {
+		r0 = ret.Get(0).(types.AggregationReport)
+	}
+
+	var r1 bool
+	if rf, ok := ret.Get(1).(func(uint16) bool); ok {
+		r1 = rf(collectionId)
+	} else {
+		r1 = ret.Get(1).(bool)
+	}
+
+	return r0, r1
+}
+
 // GetAmountInDecimal provides a mock function with given fields: amountInWei
 func (_m *UtilsInterface) GetAmountInDecimal(amountInWei *big.Int) *big.Float {
 	ret := _m.Called(amountInWei)
@@ -368,6 +431,29 @@ func (_m *UtilsInterface) GetBlockManager(client *ethclient.Client) *bindings.Bl
 	return r0
 }
 
+// GetCollectionJobs provides a mock function with given fields: client, collection
+func (_m *UtilsInterface) GetCollectionJobs(client *ethclient.Client, collection bindings.StructsCollection) ([]types.AssetJob, error) {
+	ret := _m.Called(client, collection)
+
+	var r0 []types.AssetJob
+	if rf, ok := ret.Get(0).(func(*ethclient.Client, bindings.StructsCollection) []types.AssetJob); ok {
+		r0 = rf(client, collection)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]types.AssetJob)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(*ethclient.Client, bindings.StructsCollection) error); ok {
+		r1 = rf(client, collection)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // GetCollections provides a mock function with given fields: client
 func (_m *UtilsInterface) GetCollections(client *ethclient.Client) ([]bindings.StructsCollection, error) {
 	ret := _m.Called(client)
@@ -391,20 +477,20 @@ func (_m *UtilsInterface) GetCollections(client *ethclient.Client) ([]bindings.S
 	return r0, r1
 }
 
-// GetCommitDataFileName provides a mock function with given fields: address
-func (_m *UtilsInterface) GetCommitDataFileName(address string) (string, error) {
-	ret := _m.Called(address)
+// GetCommitDataFileName provides a mock function with given fields: address, epoch
+func (_m *UtilsInterface) GetCommitDataFileName(address string, epoch uint32) (string, error) {
+	ret := _m.Called(address, epoch)
 
 	var r0 string
-	if rf, ok := ret.Get(0).(func(string) string); ok {
-		r0 = rf(address)
+	if rf, ok := ret.Get(0).(func(string, uint32) string); ok {
+		r0 = rf(address, epoch)
 	} else {
 		r0 = ret.Get(0).(string)
 	}
 
 	var r1 error
-	if rf, ok := ret.Get(1).(func(string) error); ok {
-		r1 = rf(address)
+	if rf, ok := ret.Get(1).(func(string, uint32) error); ok {
+		r1 = rf(address, epoch)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -456,6 +542,75 @@ func (_m *UtilsInterface) GetConfigFilePath() (string, error) {
 	return r0, r1
 }
 
+// GetDataFromAPI provides a mock function with given fields: url, request
+func (_m *UtilsInterface) GetDataFromAPI(url string, request types.JobRequest) ([]byte, error) {
+	ret := _m.Called(url, request)
+
+	var r0 []byte
+	if rf, ok := ret.Get(0).(func(string, types.JobRequest) []byte); ok {
+		r0 = rf(url, request)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]byte)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(string, types.JobRequest) error); ok {
+		r1 = rf(url, request)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetDataToCommitFromJob provides a mock function with given fields: job
+func (_m *UtilsInterface) GetDataToCommitFromJob(job types.AssetJob) (*big.Int, error) {
+	ret := _m.Called(job)
+
+	var r0 *big.Int
+	if rf, ok := ret.Get(0).(func(types.AssetJob) *big.Int); ok {
+		r0 = rf(job)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*big.Int)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(types.AssetJob) error); ok {
+		r1 = rf(job)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetDatumFromJob provides a mock function with given fields: job
+func (_m *UtilsInterface) GetDatumFromJob(job types.AssetJob) (*big.Rat, error) {
+	ret := _m.Called(job)
+
+	var r0 *big.Rat
+	if rf, ok := ret.Get(0).(func(types.AssetJob) *big.Rat); ok {
+		r0 = rf(job)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*big.Rat)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(types.AssetJob) error); ok {
+		r1 = rf(job)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // GetDefaultPath provides a mock function with given fields:
 func (_m *UtilsInterface) GetDefaultPath() (string, error) {
 	ret := _m.Called()
@@ -693,6 +848,48 @@ func (_m *UtilsInterface) GetJobs(client *ethclient.Client) ([]bindings.StructsJ
 	return r0, r1
 }
 
+// GetLegacyCommitDataFileName provides a mock function with given fields: address
+func (_m *UtilsInterface) GetLegacyCommitDataFileName(address string) (string, error) {
+	ret := _m.Called(address)
+
+	var r0 string
+	if rf, ok := ret.Get(0).(func(string) string); ok {
+		r0 = rf(address)
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(address)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetLegacyProposeDataFileName provides a mock function with given fields: address
+func (_m *UtilsInterface) GetLegacyProposeDataFileName(address string) (string, error) {
+	ret := _m.Called(address)
+
+	var r0 string
+	if rf, ok := ret.Get(0).(func(string) string); ok {
+		r0 = rf(address)
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(address)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // GetLock provides a mock function with given fields: client, address, stakerId, lockType
 func (_m *UtilsInterface) GetLock(client *ethclient.Client, address string, stakerId uint32, lockType uint8) (types.Locks, error) {
 	ret := _m.Called(client, address, stakerId, lockType)
@@ -833,20 +1030,20 @@ func (_m *UtilsInterface) GetOptions() bind.CallOpts {
 	return r0
 }
 
-// GetProposeDataFileName provides a mock function with given fields: address
-func (_m *UtilsInterface) GetProposeDataFileName(address string) (string, error) {
-	ret := _m.Called(address)
+// GetProposeDataFileName provides a mock function with given fields: address, epoch
+func (_m *UtilsInterface) GetProposeDataFileName(address string, epoch uint32) (string, error) {
+	ret := _m.Called(address, epoch)
 
 	var r0 string
-	if rf, ok := ret.Get(0).(func(string) string); ok {
-		r0 = rf(address)
+	if rf, ok := ret.Get(0).(func(string, uint32) string); ok {
+		r0 = rf(address, epoch)
 	} else {
 		r0 = ret.Get(0).(string)
 	}
 
 	var r1 error
-	if rf, ok := ret.Get(1).(func(string) error); ok {
-		r1 = rf(address)
+	if rf, ok := ret.Get(1).(func(string, uint32) error); ok {
+		r1 = rf(address, epoch)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -1215,6 +1412,71 @@ func (_m *UtilsInterface) IsFlagPassed(name string) bool {
 	return r0
 }
 
+// ListDataFiles provides a mock function with given fields:
+func (_m *UtilsInterface) ListDataFiles() ([]types.DataFileEntry, error) {
+	ret := _m.Called()
+
+	var r0 []types.DataFileEntry
+	if rf, ok := ret.Get(0).(func() []types.DataFileEntry); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]types.DataFileEntry)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func() error); ok {
+		r1 = rf()
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// LoadHTTPConfig provides a mock function with given fields:
+func (_m *UtilsInterface) LoadHTTPConfig() error {
+	ret := _m.Called()
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func() error); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// LoadJobHealth provides a mock function with given fields:
+func (_m *UtilsInterface) LoadJobHealth() error {
+	ret := _m.Called()
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func() error); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// OpenHistory provides a mock function with given fields:
+func (_m *UtilsInterface) OpenHistory() error {
+	ret := _m.Called()
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func() error); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // PasswordPrompt provides a mock function with given fields:
 func (_m *UtilsInterface) PasswordPrompt() string {
 	ret := _m.Called()
@@ -1243,6 +1505,29 @@ func (_m *UtilsInterface) PrivateKeyPrompt() string {
 	return r0
 }
 
+// PruneDataFiles provides a mock function with given fields: epoch, retention
+func (_m *UtilsInterface) PruneDataFiles(epoch uint32, retention uint32) ([]uint32, error) {
+	ret := _m.Called(epoch, retention)
+
+	var r0 []uint32
+	if rf, ok := ret.Get(0).(func(uint32, uint32) []uint32); ok {
+		r0 = rf(epoch, retention)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]uint32)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(uint32, uint32) error); ok {
+		r1 = rf(epoch, retention)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // ReadFromCommitJsonFile provides a mock function with given fields: filePath
 func (_m *UtilsInterface) ReadFromCommitJsonFile(filePath string) (types.CommitFileData, error) {
 	ret := _m.Called(filePath)
@@ -1306,6 +1591,90 @@ func (_m *UtilsInterface) ReadFromProposeJsonFile(filePath string) (types.Propos
 	return r0, r1
 }
 
+// ReadHistory provides a mock function with given fields: collectionId, fromEpoch, toEpoch
+func (_m *UtilsInterface) ReadHistory(collectionId uint16, fromEpoch uint32, toEpoch uint32) ([]types.HistoryRecord, error) {
+	ret := _m.Called(collectionId, fromEpoch, toEpoch)
+
+	var r0 []types.HistoryRecord
+	if rf, ok := ret.Get(0).(func(uint16, uint32, uint32) []types.HistoryRecord); ok {
+		r0 = rf(collectionId, fromEpoch, toEpoch)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]types.HistoryRecord)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(uint16, uint32, uint32) error); ok {
+		r1 = rf(collectionId, fromEpoch, toEpoch)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ReadJobHealth provides a mock function with given fields:
+func (_m *UtilsInterface) ReadJobHealth() ([]types.JobHealth, error) {
+	ret := _m.Called()
+
+	var r0 []types.JobHealth
+	if rf, ok := ret.Get(0).(func() []types.JobHealth); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]types.JobHealth)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func() error); ok {
+		r1 = rf()
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// RecordLeavesHistory provides a mock function with given fields: client, epoch, kind, commitData
+func (_m *UtilsInterface) RecordLeavesHistory(client *ethclient.Client, epoch uint32, kind string, commitData types.CommitData) {
+	_m.Called(client, epoch, kind, commitData)
+}
+
+// RecordMediansHistory provides a mock function with given fields: client, epoch
+func (_m *UtilsInterface) RecordMediansHistory(client *ethclient.Client, epoch uint32) {
+	_m.Called(client, epoch)
+}
+
+// RecordResponses provides a mock function with given fields: retention
+func (_m *UtilsInterface) RecordResponses(retention uint32) error {
+	ret := _m.Called(retention)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(uint32) error); ok {
+		r0 = rf(retention)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// ReplayResponses provides a mock function with given fields: epoch
+func (_m *UtilsInterface) ReplayResponses(epoch uint32) error {
+	ret := _m.Called(epoch)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(uint32) error); ok {
+		r0 = rf(epoch)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // SaveDataToCommitJsonFile provides a mock function with given fields: flePath, epoch, commitFileData
 func (_m *UtilsInterface) SaveDataToCommitJsonFile(flePath string, epoch uint32, commitFileData types.CommitData) error {
 	ret := _m.Called(flePath, epoch, commitFileData)
@@ -1362,6 +1731,48 @@ func (_m *UtilsInterface) SecondsToReadableTime(time int) string {
 	return r0
 }
 
+// SetDataFilesPassword provides a mock function with given fields: password, encrypt
+func (_m *UtilsInterface) SetDataFilesPassword(password string, encrypt bool) error {
+	ret := _m.Called(password, encrypt)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string, bool) error); ok {
+		r0 = rf(password, encrypt)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// SetResponseCacheEpoch provides a mock function with given fields: epoch
+func (_m *UtilsInterface) SetResponseCacheEpoch(epoch uint32) {
+	_m.Called(epoch)
+}
+
+// ValidateAssetsFile provides a mock function with given fields: client
+func (_m *UtilsInterface) ValidateAssetsFile(client *ethclient.Client) ([]error, error) {
+	ret := _m.Called(client)
+
+	var r0 []error
+	if rf, ok := ret.Get(0).(func(*ethclient.Client) []error); ok {
+		r0 = rf(client)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]error)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(*ethclient.Client) error); ok {
+		r1 = rf(client)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // WaitForBlockCompletion provides a mock function with given fields: client, hashToRead
 func (_m *UtilsInterface) WaitForBlockCompletion(client *ethclient.Client, hashToRead string) int {
 	ret := _m.Called(client, hashToRead)
@@ -1380,3 +1791,17 @@ func (_m *UtilsInterface) WaitForBlockCompletion(client *ethclient.Client, hashT
 func (_m *UtilsInterface) WaitTillNextNSecs(seconds int32) {
 	_m.Called(seconds)
 }
+
+// WatchAssetsFile provides a mock function with given fields: client
+func (_m *UtilsInterface) WatchAssetsFile(client *ethclient.Client) error {
+	ret := _m.Called(client)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(*ethclient.Client) error); ok {
+		r0 = rf(client)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
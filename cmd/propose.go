@@ -2,6 +2,7 @@
 package cmd
 
 import (
+	"context"
 	"encoding/hex"
 	"errors"
 	"github.com/ethereum/go-ethereum/common"
@@ -14,8 +15,10 @@ import (
 	"razor/core/types"
 	"razor/pkg/bindings"
 	"razor/utils"
+	"runtime"
 	"sort"
 	"sync"
+	"sync/atomic"
 	"time"
 )
 
@@ -23,6 +26,7 @@ var (
 	_mediansData           []*big.Int
 	_revealedCollectionIds []uint16
 	_revealedDataMaps      *types.RevealedDataMaps
+	_mediansDataEpoch      uint32
 	//iterations             []int
 )
 
@@ -35,7 +39,7 @@ var (
 // Find iteration using salt as seed
 
 //This functions handles the propose state
-func (*UtilsStruct) Propose(client *ethclient.Client, config types.Configurations, account types.Account, staker bindings.StructsStaker, epoch uint32, blockNumber *big.Int, rogueData types.Rogue) (common.Hash, error) {
+func (*UtilsStruct) Propose(ctx context.Context, client *ethclient.Client, config types.Configurations, account types.Account, staker bindings.StructsStaker, epoch uint32, blockNumber *big.Int, rogueData types.Rogue) (common.Hash, error) {
 	if state, err := razorUtils.GetDelayedState(client, config.BufferPercent); err != nil || state != 2 {
 		log.Error("Not propose state")
 		return core.NilHash, err
@@ -75,7 +79,7 @@ func (*UtilsStruct) Propose(client *ethclient.Client, config types.Configuration
 	if err != nil {
 		return core.NilHash, err
 	}
-	iteration := cmdUtils.GetIteration(client, types.ElectedProposer{
+	iteration := cmdUtils.GetIteration(ctx, client, types.ElectedProposer{
 		Stake:           staker.Stake,
 		StakerId:        staker.Id,
 		BiggestStake:    biggestStake,
@@ -124,9 +128,10 @@ func (*UtilsStruct) Propose(client *ethclient.Client, config types.Configuration
 	_mediansData = medians
 	_revealedCollectionIds = ids
 	_revealedDataMaps = revealedDataMaps
+	_mediansDataEpoch = epoch
 
 	log.Debug("Saving proposed data for recovery")
-	fileName, err := razorUtils.GetProposeDataFileName(account.Address)
+	fileName, err := razorUtils.GetProposeDataFileName(account.Address, epoch)
 	if err != nil {
 		log.Error("Error in getting file name to save median data: ", err)
 		return core.NilHash, nil
@@ -214,7 +219,7 @@ loop:
 }
 
 //This function returns the iteration of the proposer if he is elected
-func (*UtilsStruct) GetIteration(client *ethclient.Client, proposer types.ElectedProposer, bufferPercent int32) int {
+func (*UtilsStruct) GetIteration(ctx context.Context, client *ethclient.Client, proposer types.ElectedProposer, bufferPercent int32) int {
 	stake, err := razorUtils.GetStakeSnapshot(client, proposer.StakerId, proposer.Epoch)
 	if err != nil {
 		log.Error("Error in fetching influence of staker: ", err)
@@ -225,67 +230,90 @@ func (*UtilsStruct) GetIteration(client *ethclient.Client, proposer types.Electe
 	if err != nil {
 		return -1
 	}
-	stateTimeout := time.NewTimer(time.Second * time.Duration(stateRemainingTime))
-	wg := &sync.WaitGroup{}
-	wg.Add(core.NumRoutines)
-	done := make(chan bool, 10)
-	iterationResult := make(chan int, 10)
-	quit := make(chan bool, 10)
+	// A block can only be proposed until the state ends, so the search stops with it or when the context of the caller is done
+	ctx, cancel := context.WithTimeout(ctx, time.Second*time.Duration(stateRemainingTime))
+	defer cancel()
 
-	for routine := 0; routine < core.NumRoutines; routine++ {
-		go getIterationConcurrently(proposer, currentStakerStake, routine, wg, done, iterationResult, quit, stateTimeout)
+	numWorkers := runtime.NumCPU()
+	log.Debugf("Searching iterations with %d workers", numWorkers)
+	iteration, err := getIteration(ctx, proposer, currentStakerStake, numWorkers)
+	if err != nil {
+		log.Error("State timeout! Error in getting iteration: ", err)
+		return -1
 	}
-
-	log.Debug("Waiting for all the goroutines to finish")
-	wg.Wait()
-	log.Debug("Done")
-
-	close(done)
-	close(quit)
-	close(iterationResult)
-
-	var iterations []int
-
-	for iteration := range iterationResult {
-		iterations = append(iterations, iteration)
+	if iteration == -1 {
+		log.Debug("IsElected is never true for this staker")
 	}
-
-	sort.Ints(iterations)
-	return iterations[0]
+	return iteration
 }
 
-func getIterationConcurrently(proposer types.ElectedProposer, currentStake *big.Int, routine int, wg *sync.WaitGroup, done chan bool, iterationResult chan int, quit chan bool, stateTimeout *time.Timer) {
-	//PARALLEL IMPLEMENTATION WITH BATCHES
-
-	defer wg.Done()
-	batchSize := core.BatchSize                  //1000
-	NumBatches := core.MaxIterations / batchSize //10000000/1000 = 10000
-	// Batch 0th - [0,1000)
-	// Batch 1th - [1000,2000)
-	for batch := 0; batch < NumBatches; batch++ {
-		for iteration := (batch * batchSize) + routine; iteration < (batch*batchSize)+batchSize; iteration = iteration + core.NumRoutines {
-			select {
-			case <-stateTimeout.C:
-				log.Error("State timeout!")
-				iterationResult <- -1
-				quit <- true
-				return
-			default:
-				proposer.Iteration = iteration
-				if len(done) >= 1 || len(quit) >= 1 {
+//This function returns the smallest iteration in which the proposer is elected, or -1 if it is elected in none, and an error if the context is done before any is found.
+//Workers take batches of iterations in increasing order and stop taking them once an iteration is found before them, so every iteration before the one returned is checked
+func getIteration(ctx context.Context, proposer types.ElectedProposer, currentStake *big.Int, numWorkers int) (int, error) {
+	if numWorkers < 1 {
+		numWorkers = 1
+	}
+	batchSize := core.BatchSize
+	numBatches := (core.MaxIterations + batchSize - 1) / batchSize
+
+	var (
+		nextBatch    int64 = -1
+		minIteration int64 = math.MaxInt64
+		cancelled    int32
+	)
+	wg := &sync.WaitGroup{}
+	wg.Add(numWorkers)
+	for worker := 0; worker < numWorkers; worker++ {
+		go func(proposer types.ElectedProposer) {
+			defer wg.Done()
+			for {
+				batch := int(atomic.AddInt64(&nextBatch, 1))
+				if batch >= numBatches || int64(batch*batchSize) >= atomic.LoadInt64(&minIteration) {
 					return
 				}
-				isElected := cmdUtils.IsElectedProposer(proposer, currentStake)
-				if isElected {
-					iterationResult <- iteration
-					done <- true
-					return
+				end := (batch + 1) * batchSize
+				if end > core.MaxIterations {
+					end = core.MaxIterations
+				}
+				for iteration := batch * batchSize; iteration < end && int64(iteration) < atomic.LoadInt64(&minIteration); iteration++ {
+					select {
+					case <-ctx.Done():
+						atomic.StoreInt32(&cancelled, 1)
+						return
+					default:
+					}
+					proposer.Iteration = iteration
+					if cmdUtils.IsElectedProposer(proposer, currentStake) {
+						storeMinIteration(&minIteration, int64(iteration))
+						break
+					}
 				}
 			}
+		}(proposer)
+	}
+	wg.Wait()
+
+	if minIteration == math.MaxInt64 {
+		if atomic.LoadInt32(&cancelled) == 1 {
+			return -1, ctx.Err()
+		}
+		return -1, nil
+	}
+	if atomic.LoadInt32(&cancelled) == 1 {
+		// An iteration in which the proposer is elected can still be proposed with, though a smaller one may not have been checked
+		log.Debugf("Search of iterations stopped before it was complete, using iteration %d", minIteration)
+	}
+	return int(minIteration), nil
+}
+
+//This function stores the iteration if it is smaller than the smallest iteration stored
+func storeMinIteration(minIteration *int64, iteration int64) {
+	for {
+		stored := atomic.LoadInt64(minIteration)
+		if iteration >= stored || atomic.CompareAndSwapInt64(minIteration, stored, iteration) {
+			return
 		}
 	}
-	iterationResult <- -1
-	log.Debug("IsElected is never true for this batch")
 }
 
 //This function returns if the elected staker is proposer or not
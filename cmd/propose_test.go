@@ -1,12 +1,14 @@
 package cmd
 
 import (
+	"context"
 	"crypto/ecdsa"
 	"crypto/elliptic"
 	"crypto/rand"
 	"errors"
 	"fmt"
 	"github.com/stretchr/testify/mock"
+	"math"
 	"math/big"
 	"razor/cmd/mocks"
 	"razor/core"
@@ -15,6 +17,7 @@ import (
 	"razor/utils"
 	Mocks "razor/utils/mocks"
 	"reflect"
+	"runtime"
 	"sort"
 	"testing"
 	"time"
@@ -483,7 +486,7 @@ func TestPropose(t *testing.T) {
 		utilsMock.On("GetProposedBlock", mock.AnythingOfType("*ethclient.Client"), mock.AnythingOfType("uint32"), mock.AnythingOfType("uint32")).Return(tt.args.lastProposedBlockStruct, tt.args.lastProposedBlockStructErr)
 		cmdUtilsMock.On("MakeBlock", mock.AnythingOfType("*ethclient.Client"), mock.Anything, mock.Anything, mock.Anything).Return(tt.args.medians, tt.args.ids, tt.args.revealDataMaps, tt.args.mediansErr)
 		utilsMock.On("ConvertUint32ArrayToBigIntArray", mock.Anything).Return(tt.args.mediansBigInt)
-		utilsMock.On("GetProposeDataFileName", mock.AnythingOfType("string")).Return(tt.args.fileName, tt.args.fileNameErr)
+		utilsMock.On("GetProposeDataFileName", mock.AnythingOfType("string"), mock.AnythingOfType("uint32")).Return(tt.args.fileName, tt.args.fileNameErr)
 		utilsMock.On("SaveDataToProposeJsonFile", mock.Anything, mock.Anything, mock.Anything).Return(tt.args.saveDataErr)
 		utilsMock.On("GetTxnOpts", mock.AnythingOfType("types.TransactionOptions")).Return(txnOpts)
 		blockManagerUtilsMock.On("Propose", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(tt.args.proposeTxn, tt.args.proposeErr)
@@ -492,7 +495,7 @@ func TestPropose(t *testing.T) {
 
 		utils := &UtilsStruct{}
 		t.Run(tt.name, func(t *testing.T) {
-			got, err := utils.Propose(client, config, account, staker, epoch, blockNumber, tt.args.rogueData)
+			got, err := utils.Propose(context.Background(), client, config, account, staker, epoch, blockNumber, tt.args.rogueData)
 			if got != tt.want {
 				t.Errorf("Txn hash for Propose function, got = %v, want %v", got, tt.want)
 			}
@@ -678,6 +681,7 @@ func TestGetIteration(t *testing.T) {
 		stakeSnapshotErr error
 		remainingTime    int64
 		remainingTimeErr error
+		cancelled        bool
 	}
 	tests := []struct {
 		name string
@@ -716,6 +720,15 @@ func TestGetIteration(t *testing.T) {
 			},
 			want: -1,
 		},
+		{
+			name: "Test 5: When the context of the caller is cancelled before an iteration is found",
+			args: args{
+				stakeSnapshot: big.NewInt(1000),
+				remainingTime: 10,
+				cancelled:     true,
+			},
+			want: -1,
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -729,13 +742,168 @@ func TestGetIteration(t *testing.T) {
 			utilsMock.On("GetStakeSnapshot", mock.AnythingOfType("*ethclient.Client"), mock.AnythingOfType("uint32"), mock.AnythingOfType("uint32")).Return(big.NewInt(1).Mul(tt.args.stakeSnapshot, big.NewInt(1e18)), tt.args.stakeSnapshotErr)
 			utilsPkgMock.On("GetRemainingTimeOfCurrentState", mock.Anything, mock.Anything).Return(tt.args.remainingTime, tt.args.remainingTimeErr)
 
-			if got := cmdUtils.GetIteration(client, proposer, bufferPercent); got != tt.want {
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+			if tt.args.cancelled {
+				cancel()
+			}
+			if got := cmdUtils.GetIteration(ctx, client, proposer, bufferPercent); got != tt.want {
 				t.Errorf("getIteration() = %v, want %v", got, tt.want)
 			}
 		})
 	}
 }
 
+func Test_getIteration(t *testing.T) {
+	batchSize, maxIterations := core.BatchSize, core.MaxIterations
+	defer func() {
+		core.BatchSize, core.MaxIterations = batchSize, maxIterations
+	}()
+	core.BatchSize = 10
+
+	type args struct {
+		electedIterations []int
+		maxIterations     int
+		numWorkers        int
+		cancelled         bool
+		cancelledOnFound  bool
+	}
+	tests := []struct {
+		name    string
+		args    args
+		want    int
+		wantErr bool
+	}{
+		{
+			name: "Test 1: When proposer is elected in iterations of different batches",
+			args: args{
+				electedIterations: []int{3, 12, 2500},
+				maxIterations:     5000,
+				numWorkers:        4,
+			},
+			want: 3,
+		},
+		{
+			name: "Test 2: When proposer is elected in iterations searched by different workers",
+			args: args{
+				electedIterations: []int{2500, 2501, 4999},
+				maxIterations:     5000,
+				numWorkers:        3,
+			},
+			want: 2500,
+		},
+		{
+			name: "Test 3: When proposer is elected only in the last iteration of an incomplete batch",
+			args: args{
+				electedIterations: []int{5004},
+				maxIterations:     5005,
+				numWorkers:        4,
+			},
+			want: 5004,
+		},
+		{
+			name: "Test 4: When proposer is never elected",
+			args: args{
+				maxIterations: 5000,
+				numWorkers:    2,
+			},
+			want: -1,
+		},
+		{
+			name: "Test 5: When number of workers is 0",
+			args: args{
+				electedIterations: []int{3, 12, 2500},
+				maxIterations:     5000,
+				numWorkers:        0,
+			},
+			want: 3,
+		},
+		{
+			name: "Test 6: When context is cancelled before the search",
+			args: args{
+				electedIterations: []int{3},
+				maxIterations:     5000,
+				numWorkers:        4,
+				cancelled:         true,
+			},
+			want:    -1,
+			wantErr: true,
+		},
+		{
+			name: "Test 7: When context is cancelled after an iteration is found",
+			args: args{
+				electedIterations: []int{15},
+				maxIterations:     5000,
+				numWorkers:        2,
+				cancelledOnFound:  true,
+			},
+			want: 15,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cmdUtilsMock := new(mocks.UtilsCmdInterface)
+			cmdUtils = cmdUtilsMock
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+
+			elected := make(map[int]bool)
+			for _, iteration := range tt.args.electedIterations {
+				elected[iteration] = true
+			}
+			cmdUtilsMock.On("IsElectedProposer", mock.Anything, mock.Anything).Return(func(proposer types.ElectedProposer, currentStakerStake *big.Int) bool {
+				if tt.args.cancelledOnFound {
+					// The first batch is still being searched when the context is cancelled after an iteration of the second batch is found
+					if proposer.Iteration == 5 {
+						<-ctx.Done()
+					}
+					if elected[proposer.Iteration] {
+						cancel()
+					}
+				}
+				return elected[proposer.Iteration]
+			})
+
+			core.MaxIterations = tt.args.maxIterations
+			if tt.args.cancelled {
+				cancel()
+			}
+
+			got, err := getIteration(ctx, types.ElectedProposer{}, big.NewInt(1), tt.args.numWorkers)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("getIteration() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("getIteration() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGetIterationWithWorkers(t *testing.T) {
+	salt := []byte{142, 170, 157, 83, 109, 43, 34, 152, 21, 154, 159, 12, 195, 119, 50, 186, 218, 57, 39, 173, 228, 135, 20, 100, 149, 27, 169, 158, 34, 113, 66, 64}
+	saltBytes32 := [32]byte{}
+	copy(saltBytes32[:], salt)
+
+	proposer := types.ElectedProposer{
+		BiggestStake:    big.NewInt(1).Mul(big.NewInt(10000000), big.NewInt(1e18)),
+		StakerId:        2,
+		NumberOfStakers: 10,
+		Salt:            saltBytes32,
+	}
+	stake := big.NewInt(1).Mul(big.NewInt(1000), big.NewInt(1e18))
+	currentStakerStake := big.NewInt(1).Mul(stake, big.NewInt(int64(math.Exp2(32))))
+	cmdUtils = &UtilsStruct{}
+
+	// Every number of workers has to return the smallest iteration found by a sequential search
+	for _, numWorkers := range []int{1, 3, runtime.NumCPU()} {
+		got, err := getIteration(context.Background(), proposer, currentStakerStake, numWorkers)
+		if err != nil || got != 70183 {
+			t.Errorf("getIteration() with %d workers = %v, %v, want 70183", numWorkers, got, err)
+		}
+	}
+}
+
 func TestInfluencedMedian(t *testing.T) {
 	type args struct {
 		sortedVotes            []*big.Int
@@ -982,7 +1150,7 @@ func BenchmarkGetIteration(b *testing.B) {
 				utilsPkgMock.On("GetRemainingTimeOfCurrentState", mock.Anything, mock.Anything).Return(int64(100), nil)
 
 				core.BatchSize = v.batchSize
-				cmdUtils.GetIteration(client, proposer, bufferPercent)
+				cmdUtils.GetIteration(context.Background(), client, proposer, bufferPercent)
 
 				timeElapsed := time.Since(start).Microseconds()
 				timeRecorded = append(timeRecorded, timeElapsed)
@@ -993,6 +1161,46 @@ func BenchmarkGetIteration(b *testing.B) {
 	}
 }
 
+func BenchmarkGetIterationWorkers(b *testing.B) {
+	salt := []byte{142, 170, 157, 83, 109, 43, 34, 152, 21, 154, 159, 12, 195, 119, 50, 186, 218, 57, 39, 173, 228, 135, 20, 100, 149, 27, 169, 158, 34, 113, 66, 64}
+	saltBytes32 := [32]byte{}
+	copy(saltBytes32[:], salt)
+
+	proposer := types.ElectedProposer{
+		BiggestStake:    big.NewInt(1).Mul(big.NewInt(10000000), big.NewInt(1e18)),
+		StakerId:        2,
+		NumberOfStakers: 10,
+		Salt:            saltBytes32,
+	}
+	cmdUtils = &UtilsStruct{}
+
+	var table = []struct {
+		stakeSnapshot *big.Int
+		numWorkers    int
+	}{
+		{stakeSnapshot: big.NewInt(1000), numWorkers: 1},
+		{stakeSnapshot: big.NewInt(1000), numWorkers: 2},
+		{stakeSnapshot: big.NewInt(1000), numWorkers: 4},
+		{stakeSnapshot: big.NewInt(1000), numWorkers: runtime.NumCPU()},
+		{stakeSnapshot: big.NewInt(10000000), numWorkers: 1},
+		{stakeSnapshot: big.NewInt(10000000), numWorkers: 2},
+		{stakeSnapshot: big.NewInt(10000000), numWorkers: 4},
+		{stakeSnapshot: big.NewInt(10000000), numWorkers: runtime.NumCPU()},
+	}
+
+	for _, v := range table {
+		stake := big.NewInt(1).Mul(v.stakeSnapshot, big.NewInt(1e18))
+		currentStakerStake := big.NewInt(1).Mul(stake, big.NewInt(int64(math.Exp2(32))))
+		b.Run(fmt.Sprintf("Stakers_Stake_%d, Workers_%d", v.stakeSnapshot, v.numWorkers), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				if _, err := getIteration(context.Background(), proposer, currentStakerStake, v.numWorkers); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
+
 func CalculateMedian(arr []int64) int64 {
 	var median int64
 
@@ -0,0 +1,124 @@
+//Package cmd provides all functions related to command line
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"razor/utils"
+	"sort"
+	"strconv"
+
+	"github.com/olekukonko/tablewriter"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+var replayCommitCmd = &cobra.Command{
+	Use:   "replayCommit",
+	Short: "replayCommit recalculates the values committed in an epoch from its recorded responses",
+	Long: `Recalculates the value of every collection committed in an epoch from the responses recorded while voting with --recordResponses and the assets.json recorded with them, without fetching any source or reading the chain: the collections, their jobs, the confirmed medians used for fallbacks and the health of the jobs are recorded along with the responses. The values are compared with the leaves saved in the commit data file of the staker.
+
+Example:
+  ./razor replayCommit --address 0x5a0b54d5dc17e0aadc383d2db43b0a0d3e029c4c --epoch 1200
+`,
+	Run: initialiseReplayCommit,
+}
+
+//This function initialises the ExecuteReplayCommit function
+func initialiseReplayCommit(cmd *cobra.Command, args []string) {
+	cmdUtils.ExecuteReplayCommit(cmd.Flags())
+}
+
+//This function sets the flags appropriately and executes the ReplayCommit function
+func (*UtilsStruct) ExecuteReplayCommit(flagSet *pflag.FlagSet) {
+	razorUtils.AssignLogFile(flagSet)
+
+	address, err := flagSetUtils.GetStringAddress(flagSet)
+	utils.CheckError("Error in getting address: ", err)
+
+	epoch, err := flagSetUtils.GetUint32Epoch(flagSet)
+	utils.CheckError("Error in getting epoch: ", err)
+
+	// Commit data files written with --encryptDataFiles are read with the password of the staker
+	password := razorUtils.AssignPassword(flagSet)
+	err = razorUtils.SetDataFilesPassword(password, false)
+	utils.CheckError("Error in setting password of data files: ", err)
+
+	err = cmdUtils.ReplayCommit(address, epoch)
+	utils.CheckError("Error in replaying commit: ", err)
+}
+
+//This function recalculates the leaves committed in the epoch from its recording and compares them with the saved leaves, nothing is read from the chain
+func (*UtilsStruct) ReplayCommit(address string, epoch uint32) error {
+	committedData, err := readCommitDataFile(address, epoch)
+	if err != nil {
+		return err
+	}
+	if err := razorUtils.ReplayResponses(epoch); err != nil {
+		return err
+	}
+
+	var assignedIndexes []int
+	for index, assigned := range committedData.AssignedCollections {
+		if assigned {
+			assignedIndexes = append(assignedIndexes, index)
+		}
+	}
+	sort.Ints(assignedIndexes)
+
+	table := tablewriter.NewWriter(os.Stdout)
+	table.SetHeader([]string{"Index", "Collection Id", "Committed", "Replayed", "Match"})
+	mismatches := 0
+	for _, index := range assignedIndexes {
+		if index >= len(committedData.Leaves) {
+			return errors.New("commit data file has fewer leaves than assigned collections")
+		}
+		committed := committedData.Leaves[index]
+		// Values read from the chain are served from the recording, so no client is needed
+		result := getCollectionResult(nil, epoch, index)
+		replayed := ""
+		match := result.Err == nil && result.Data != nil && committed != nil && result.Data.Cmp(committed) == 0
+		if result.Err != nil {
+			replayed = result.Err.Error()
+		} else if result.Data != nil {
+			replayed = result.Data.String()
+		}
+		if !match {
+			mismatches++
+		}
+		table.Append([]string{
+			strconv.Itoa(index),
+			strconv.Itoa(int(result.CollectionId)),
+			committed.String(),
+			replayed,
+			strconv.FormatBool(match),
+		})
+	}
+	table.Render()
+
+	if mismatches != 0 {
+		return fmt.Errorf("%d of %d values replayed for epoch %d differ from the committed ones", mismatches, len(assignedIndexes), epoch)
+	}
+	log.Infof("Every value committed in epoch %d is replayed from its recorded responses", epoch)
+	return nil
+}
+
+func init() {
+	rootCmd.AddCommand(replayCommitCmd)
+
+	var (
+		Address  string
+		Epoch    uint32
+		Password string
+	)
+
+	replayCommitCmd.Flags().StringVarP(&Address, "address", "a", "", "address of the staker")
+	replayCommitCmd.Flags().Uint32VarP(&Epoch, "epoch", "", 0, "epoch to replay")
+	replayCommitCmd.Flags().StringVarP(&Password, "password", "", "", "password path of staker to read encrypted data files")
+
+	addrErr := replayCommitCmd.MarkFlagRequired("address")
+	utils.CheckError("Address error: ", addrErr)
+	epochErr := replayCommitCmd.MarkFlagRequired("epoch")
+	utils.CheckError("Epoch error: ", epochErr)
+}
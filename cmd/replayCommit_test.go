@@ -0,0 +1,195 @@
+package cmd
+
+import (
+	"errors"
+	"math/big"
+	"razor/cmd/mocks"
+	"razor/core/types"
+	"razor/utils"
+	mocks2 "razor/utils/mocks"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/spf13/pflag"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestReplayCommit(t *testing.T) {
+	address := "0x000000000000000000000000000000000000dea1"
+	commitData := types.CommitFileData{
+		Epoch:               5,
+		AssignedCollections: map[int]bool{1: true, 2: true},
+		Leaves:              []*big.Int{big.NewInt(0), big.NewInt(295050), big.NewInt(4500000)},
+	}
+
+	type args struct {
+		commitData      types.CommitFileData
+		commitDataErr   error
+		replayErr       error
+		collectionData  map[uint16]*big.Int
+		collectionError error
+	}
+	tests := []struct {
+		name    string
+		epoch   uint32
+		args    args
+		wantErr bool
+	}{
+		{
+			name:  "Test 1: When replayed values are the committed ones",
+			epoch: 5,
+			args: args{
+				commitData:     commitData,
+				collectionData: map[uint16]*big.Int{1: big.NewInt(295050), 2: big.NewInt(4500000)},
+			},
+			wantErr: false,
+		},
+		{
+			name:  "Test 2: When a replayed value differs from the committed one",
+			epoch: 5,
+			args: args{
+				commitData:     commitData,
+				collectionData: map[uint16]*big.Int{1: big.NewInt(295050), 2: big.NewInt(4600000)},
+			},
+			wantErr: true,
+		},
+		{
+			name:  "Test 3: When a collection cannot be replayed",
+			epoch: 5,
+			args: args{
+				commitData:      commitData,
+				collectionError: errors.New("no recorded response for GET https://api.gemini.com/v1/pubticker/ethusd"),
+			},
+			wantErr: true,
+		},
+		{
+			name:  "Test 4: When commit data file has the leaves of another epoch",
+			epoch: 4,
+			args: args{
+				commitData: commitData,
+			},
+			wantErr: true,
+		},
+		{
+			name:  "Test 5: When epoch has no recording",
+			epoch: 5,
+			args: args{
+				commitData: commitData,
+				replayErr:  errors.New("no recording of epoch 5"),
+			},
+			wantErr: true,
+		},
+		{
+			name:  "Test 6: When commit data file cannot be read",
+			epoch: 5,
+			args: args{
+				commitDataErr: errors.New("no such file"),
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			utilsMock := new(mocks.UtilsInterface)
+			utilsPkgMock := new(mocks2.Utils)
+
+			razorUtils = utilsMock
+			utils.UtilsInterface = utilsPkgMock
+
+			utilsMock.On("GetCommitDataFileName", address, tt.epoch).Return("/home/data_files/5/"+address+"_CommitData.json", nil)
+			utilsMock.On("GetLegacyCommitDataFileName", address).Return("/home/data_files/"+address+"_CommitData.json", nil)
+			utilsMock.On("ReadFromCommitJsonFile", mock.AnythingOfType("string")).Return(tt.args.commitData, tt.args.commitDataErr)
+			utilsMock.On("ReplayResponses", tt.epoch).Return(tt.args.replayErr)
+			utilsPkgMock.On("GetCollectionIdFromIndex", mock.AnythingOfType("*ethclient.Client"), mock.AnythingOfType("uint16")).Return(func(client *ethclient.Client, index uint16) uint16 { return index }, nil)
+			utilsPkgMock.On("GetAggregatedDataOfCollection", mock.AnythingOfType("*ethclient.Client"), mock.AnythingOfType("uint16"), tt.epoch).Return(func(client *ethclient.Client, collectionId uint16, epoch uint32) *big.Int {
+				return tt.args.collectionData[collectionId]
+			}, tt.args.collectionError)
+			utilsPkgMock.On("CheckSanityBounds", mock.AnythingOfType("*ethclient.Client"), mock.AnythingOfType("uint16"), tt.epoch, mock.Anything).Return(func(client *ethclient.Client, collectionId uint16, epoch uint32, value *big.Int) *big.Int {
+				return value
+			}, nil)
+
+			utils := &UtilsStruct{}
+			err := utils.ReplayCommit(address, tt.epoch)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ReplayCommit() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestExecuteReplayCommit(t *testing.T) {
+	var flagSet *pflag.FlagSet
+
+	type args struct {
+		epoch       uint32
+		epochErr    error
+		passwordErr error
+		replayErr   error
+	}
+	tests := []struct {
+		name          string
+		args          args
+		expectedFatal bool
+	}{
+		{
+			name: "Test 1: When ExecuteReplayCommit function executes successfully",
+			args: args{
+				epoch: 5,
+			},
+			expectedFatal: false,
+		},
+		{
+			name: "Test 2: When there is an error in getting epoch",
+			args: args{
+				epochErr: errors.New("epoch error"),
+			},
+			expectedFatal: true,
+		},
+		{
+			name: "Test 3: When there is an error in setting password of data files",
+			args: args{
+				epoch:       5,
+				passwordErr: errors.New("password error"),
+			},
+			expectedFatal: true,
+		},
+		{
+			name: "Test 4: When replayed values differ from the committed ones",
+			args: args{
+				epoch:     5,
+				replayErr: errors.New("1 of 2 values replayed for epoch 5 differ from the committed ones"),
+			},
+			expectedFatal: true,
+		},
+	}
+	defer func() { log.ExitFunc = nil }()
+	var fatal bool
+	log.ExitFunc = func(int) { fatal = true }
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			utilsMock := new(mocks.UtilsInterface)
+			cmdUtilsMock := new(mocks.UtilsCmdInterface)
+			flagSetUtilsMock := new(mocks.FlagSetInterface)
+
+			razorUtils = utilsMock
+			cmdUtils = cmdUtilsMock
+			flagSetUtils = flagSetUtilsMock
+
+			utilsMock.On("AssignLogFile", mock.AnythingOfType("*pflag.FlagSet"))
+			flagSetUtilsMock.On("GetStringAddress", mock.AnythingOfType("*pflag.FlagSet")).Return("0x000000000000000000000000000000000000dea1", nil)
+			flagSetUtilsMock.On("GetUint32Epoch", mock.AnythingOfType("*pflag.FlagSet")).Return(tt.args.epoch, tt.args.epochErr)
+			utilsMock.On("AssignPassword", mock.AnythingOfType("*pflag.FlagSet")).Return("test")
+			utilsMock.On("SetDataFilesPassword", "test", false).Return(tt.args.passwordErr)
+			cmdUtilsMock.On("ReplayCommit", mock.AnythingOfType("string"), tt.args.epoch).Return(tt.args.replayErr)
+
+			utils := &UtilsStruct{}
+			fatal = false
+
+			utils.ExecuteReplayCommit(flagSet)
+			if fatal != tt.expectedFatal {
+				t.Error("The ExecuteReplayCommit function didn't execute as expected")
+			}
+		})
+	}
+}
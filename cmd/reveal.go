@@ -3,6 +3,7 @@ package cmd
 
 import (
 	"errors"
+	"fmt"
 	"math/big"
 	"razor/core"
 	"razor/core/types"
@@ -70,6 +71,90 @@ func (*UtilsStruct) Reveal(client *ethclient.Client, config types.Configurations
 	return transactionUtils.Hash(txn), nil
 }
 
+//This function returns the committed data of the epoch which matches the commitment on chain from the commit data file, or else rebuilds it from the committed values kept in the history, along with where it was recovered from
+func (*UtilsStruct) RecoverCommitData(client *ethclient.Client, account types.Account, epoch uint32, secret []byte) (types.CommitData, string, error) {
+	var recoverErrs []string
+	commitData, err := getCommitDataFromFile(account.Address, epoch)
+	if err == nil {
+		err = cmdUtils.VerifyReveal(client, account, epoch, commitData, secret)
+	}
+	if err == nil {
+		return commitData, "commit data file", nil
+	}
+	recoverErrs = append(recoverErrs, "commit data file: "+err.Error())
+
+	commitData, err = getCommitDataFromHistory(client, epoch, secret)
+	if err == nil {
+		err = cmdUtils.VerifyReveal(client, account, epoch, commitData, secret)
+	}
+	if err == nil {
+		return commitData, "history", nil
+	}
+	recoverErrs = append(recoverErrs, "history: "+err.Error())
+	return types.CommitData{}, "", errors.New(strings.Join(recoverErrs, "; "))
+}
+
+//This function returns the committed data of the epoch saved in the commit data file of the address
+func getCommitDataFromFile(address string, epoch uint32) (types.CommitData, error) {
+	committedData, err := readCommitDataFile(address, epoch)
+	if err != nil {
+		return types.CommitData{}, err
+	}
+	return types.CommitData{
+		AssignedCollections:    committedData.AssignedCollections,
+		SeqAllottedCollections: committedData.SeqAllottedCollections,
+		Leaves:                 committedData.Leaves,
+	}, nil
+}
+
+//This function rebuilds the committed data of the epoch from the collections assigned by the seed and their committed values kept in the history
+func getCommitDataFromHistory(client *ethclient.Client, epoch uint32, secret []byte) (types.CommitData, error) {
+	salt, err := cmdUtils.GetSalt(client, epoch)
+	if err != nil {
+		return types.CommitData{}, err
+	}
+	seed := calculateSeed(salt, secret)
+	numActiveCollections, err := utils.UtilsInterface.GetNumActiveCollections(client)
+	if err != nil {
+		return types.CommitData{}, err
+	}
+	assignedCollections, seqAllottedCollections, err := utils.UtilsInterface.GetAssignedCollections(client, numActiveCollections, seed)
+	if err != nil {
+		return types.CommitData{}, err
+	}
+
+	leaves := make([]*big.Int, numActiveCollections)
+	for index := 0; index < int(numActiveCollections); index++ {
+		leaves[index] = big.NewInt(0)
+		if !assignedCollections[index] {
+			continue
+		}
+		collectionId, err := utils.UtilsInterface.GetCollectionIdFromIndex(client, uint16(index))
+		if err != nil {
+			return types.CommitData{}, err
+		}
+		records, err := razorUtils.ReadHistory(collectionId, epoch, epoch)
+		if err != nil {
+			return types.CommitData{}, err
+		}
+		var committed *big.Int
+		for _, record := range records {
+			if record.Kind == "committed" {
+				committed = record.Value
+			}
+		}
+		if committed == nil {
+			return types.CommitData{}, fmt.Errorf("no committed value of collection %d is kept", collectionId)
+		}
+		leaves[index] = committed
+	}
+	return types.CommitData{
+		AssignedCollections:    assignedCollections,
+		SeqAllottedCollections: seqAllottedCollections,
+		Leaves:                 leaves,
+	}, nil
+}
+
 //This function generates the tree reveal data
 func (*UtilsStruct) GenerateTreeRevealData(merkleTree [][][]byte, commitData types.CommitData) bindings.StructsMerkleTree {
 	if merkleTree == nil || commitData.SeqAllottedCollections == nil || commitData.Leaves == nil {
@@ -377,3 +377,95 @@ func getDummySeqAllottedCollection(numOfAllottedCollections int) []*big.Int {
 	}
 	return result
 }
+
+func TestRecoverCommitData(t *testing.T) {
+	var client *ethclient.Client
+	account := types.Account{Address: "0x000000000000000000000000000000000000dea1"}
+	committedLeaves := []*big.Int{big.NewInt(0), big.NewInt(295050)}
+
+	type args struct {
+		committedDataFromFile types.CommitFileData
+		records               []types.HistoryRecord
+		historyErr            error
+	}
+	tests := []struct {
+		name              string
+		args              args
+		wantLeaves        []*big.Int
+		wantRecoveredFrom string
+		wantErr           bool
+	}{
+		{
+			name: "Test 1: When commit data file matches the commitment on chain",
+			args: args{
+				committedDataFromFile: types.CommitFileData{Epoch: 5, SeqAllottedCollections: []*big.Int{big.NewInt(1)}, Leaves: committedLeaves},
+			},
+			wantLeaves:        committedLeaves,
+			wantRecoveredFrom: "commit data file",
+		},
+		{
+			name: "Test 2: When commit data file is stale and the committed values are kept in the history",
+			args: args{
+				committedDataFromFile: types.CommitFileData{Epoch: 4, SeqAllottedCollections: []*big.Int{big.NewInt(1)}, Leaves: committedLeaves},
+				records: []types.HistoryRecord{
+					{CollectionId: 3, Epoch: 5, Kind: "aggregated", Value: big.NewInt(295000)},
+					{CollectionId: 3, Epoch: 5, Kind: "committed", Value: big.NewInt(295050)},
+				},
+			},
+			wantLeaves:        committedLeaves,
+			wantRecoveredFrom: "history",
+		},
+		{
+			name: "Test 3: When commit data file is stale and the committed values are not kept",
+			args: args{
+				committedDataFromFile: types.CommitFileData{Epoch: 4},
+				records:               []types.HistoryRecord{{CollectionId: 3, Epoch: 5, Kind: "aggregated", Value: big.NewInt(295000)}},
+			},
+			wantErr: true,
+		},
+		{
+			name: "Test 4: When commit data file does not match the commitment on chain and history cannot be read",
+			args: args{
+				committedDataFromFile: types.CommitFileData{Epoch: 5, SeqAllottedCollections: []*big.Int{big.NewInt(1)}, Leaves: []*big.Int{big.NewInt(0), big.NewInt(1)}},
+				historyErr:            errors.New("no history has been kept yet, it is kept while voting"),
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			utilsMock := new(mocks.UtilsInterface)
+			cmdUtilsMock := new(mocks.UtilsCmdInterface)
+			utilsPkgMock := new(mocks2.Utils)
+
+			razorUtils = utilsMock
+			cmdUtils = cmdUtilsMock
+			utils2.UtilsInterface = utilsPkgMock
+
+			utilsMock.On("GetCommitDataFileName", account.Address, uint32(5)).Return("/home/data_files/5/"+account.Address+"_CommitData.json", nil)
+			utilsMock.On("GetLegacyCommitDataFileName", account.Address).Return("/home/data_files/"+account.Address+"_CommitData.json", nil)
+			utilsMock.On("ReadFromCommitJsonFile", mock.AnythingOfType("string")).Return(tt.args.committedDataFromFile, nil)
+			cmdUtilsMock.On("GetSalt", mock.AnythingOfType("*ethclient.Client"), uint32(5)).Return([32]byte{1}, nil)
+			utilsPkgMock.On("GetNumActiveCollections", mock.AnythingOfType("*ethclient.Client")).Return(uint16(2), nil)
+			utilsPkgMock.On("GetAssignedCollections", mock.AnythingOfType("*ethclient.Client"), uint16(2), mock.Anything).Return(map[int]bool{1: true}, []*big.Int{big.NewInt(1)}, nil)
+			utilsPkgMock.On("GetCollectionIdFromIndex", mock.AnythingOfType("*ethclient.Client"), uint16(1)).Return(uint16(3), nil)
+			utilsMock.On("ReadHistory", uint16(3), uint32(5), uint32(5)).Return(tt.args.records, tt.args.historyErr)
+			cmdUtilsMock.On("VerifyReveal", mock.AnythingOfType("*ethclient.Client"), account, uint32(5), mock.Anything, mock.Anything).Return(func(client *ethclient.Client, account types.Account, epoch uint32, commitData types.CommitData, secret []byte) error {
+				if !reflect.DeepEqual(commitData.Leaves, committedLeaves) {
+					return errors.New("commitment of the commit data does not match the commitment on chain")
+				}
+				return nil
+			})
+
+			ut := &UtilsStruct{}
+			got, recoveredFrom, err := ut.RecoverCommitData(client, account, 5, []byte{2})
+			if (err != nil) != tt.wantErr {
+				t.Errorf("RecoverCommitData() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got.Leaves, tt.wantLeaves) || recoveredFrom != tt.wantRecoveredFrom {
+				t.Errorf("RecoverCommitData() = %v from %s, want %v from %s", got.Leaves, recoveredFrom, tt.wantLeaves, tt.wantRecoveredFrom)
+			}
+		})
+	}
+}
@@ -411,14 +411,24 @@ func (u Utils) ReadFromDisputeJsonFile(filePath string) (types.DisputeFileData,
 	return utilsInterface.ReadFromDisputeJsonFile(filePath)
 }
 
-//This function returns the proposed data JSON file
-func (u Utils) GetProposeDataFileName(address string) (string, error) {
-	return path.PathUtilsInterface.GetProposeDataFileName(address)
+//This function returns the proposed data JSON file of the epoch
+func (u Utils) GetProposeDataFileName(address string, epoch uint32) (string, error) {
+	return path.PathUtilsInterface.GetProposeDataFileName(address, epoch)
 }
 
-//This function returns the commit data file name
-func (u Utils) GetCommitDataFileName(address string) (string, error) {
-	return path.PathUtilsInterface.GetCommitDataFileName(address)
+//This function returns the commit data file name of the epoch
+func (u Utils) GetCommitDataFileName(address string, epoch uint32) (string, error) {
+	return path.PathUtilsInterface.GetCommitDataFileName(address, epoch)
+}
+
+//This function returns the legacy commit data file name which is not kept per epoch
+func (u Utils) GetLegacyCommitDataFileName(address string) (string, error) {
+	return path.PathUtilsInterface.GetLegacyCommitDataFileName(address)
+}
+
+//This function returns the legacy proposed data JSON file which is not kept per epoch
+func (u Utils) GetLegacyProposeDataFileName(address string) (string, error) {
+	return path.PathUtilsInterface.GetLegacyProposeDataFileName(address)
 }
 
 //This function returns the dispute data file name
@@ -426,6 +436,116 @@ func (u Utils) GetDisputeDataFileName(address string) (string, error) {
 	return path.PathUtilsInterface.GetDisputeDataFileName(address)
 }
 
+//This function returns the active job
+func (u Utils) GetActiveJob(client *ethclient.Client, jobId uint16) (bindings.StructsJob, error) {
+	return utilsInterface.GetActiveJob(client, jobId)
+}
+
+//This function returns the active collection
+func (u Utils) GetActiveCollection(client *ethclient.Client, collectionId uint16) (bindings.StructsCollection, error) {
+	return utilsInterface.GetActiveCollection(client, collectionId)
+}
+
+//This function returns the jobs of the collection including the jobs from assets.json
+func (u Utils) GetCollectionJobs(client *ethclient.Client, collection bindings.StructsCollection) ([]types.AssetJob, error) {
+	return utilsInterface.GetCollectionJobs(client, collection)
+}
+
+//This function returns how the value of the collection was last aggregated, if it was
+func (u Utils) GetAggregationReport(collectionId uint16) (types.AggregationReport, bool) {
+	return utilsInterface.GetAggregationReport(collectionId)
+}
+
+//This function returns the response of the job request
+func (u Utils) GetDataFromAPI(url string, request types.JobRequest) ([]byte, error) {
+	return utilsInterface.GetDataFromAPI(url, request)
+}
+
+//This function returns the value of job before its power is applied
+func (u Utils) GetDatumFromJob(job types.AssetJob) (*big.Rat, error) {
+	return utilsInterface.GetDatumFromJob(job)
+}
+
+//This function returns the data which is used for commit from job
+func (u Utils) GetDataToCommitFromJob(job types.AssetJob) (*big.Int, error) {
+	return utilsInterface.GetDataToCommitFromJob(job)
+}
+
+//This function sets the epoch of the response cache shared by the jobs
+func (u Utils) SetResponseCacheEpoch(epoch uint32) {
+	utils.SetResponseCacheEpoch(epoch)
+}
+
+//This function applies the http settings of assets.json to the requests of jobs
+func (u Utils) LoadHTTPConfig() error {
+	return utils.LoadHTTPConfig()
+}
+
+//This function loads the health of jobs kept from previous runs
+func (u Utils) LoadJobHealth() error {
+	return utils.LoadJobHealth()
+}
+
+//This function returns the health of every job kept from voting
+func (u Utils) ReadJobHealth() ([]types.JobHealth, error) {
+	return utils.ReadJobHealth()
+}
+
+//This function records the responses of job requests of every epoch
+func (u Utils) RecordResponses(retention uint32) error {
+	return utils.RecordResponses(retention)
+}
+
+//This function sets the password data files are read with, and encrypted with if encrypt is set
+func (u Utils) SetDataFilesPassword(password string, encrypt bool) error {
+	return utils.SetDataFilesPassword(password, encrypt)
+}
+
+//This function returns the data files of every epoch
+func (u Utils) ListDataFiles() ([]types.DataFileEntry, error) {
+	return utils.ListDataFiles()
+}
+
+//This function removes the data files of the epochs which are not among the last retention epochs up to the epoch
+func (u Utils) PruneDataFiles(epoch uint32, retention uint32) ([]uint32, error) {
+	return utils.PruneDataFiles(epoch, retention)
+}
+
+//This function replays the responses recorded in the epoch instead of fetching them
+func (u Utils) ReplayResponses(epoch uint32) error {
+	return utils.ReplayResponses(epoch)
+}
+
+//This function keeps the values fetched, committed and revealed while voting in the history database
+func (u Utils) OpenHistory() error {
+	return utils.OpenHistory()
+}
+
+//This function keeps the committed or revealed leaves of the assigned collections in the history database
+func (u Utils) RecordLeavesHistory(client *ethclient.Client, epoch uint32, kind string, commitData types.CommitData) {
+	utils.RecordLeavesHistory(client, epoch, kind, commitData)
+}
+
+//This function keeps the confirmed medians of every collection in the epoch in the history database
+func (u Utils) RecordMediansHistory(client *ethclient.Client, epoch uint32) {
+	utils.RecordMediansHistory(client, epoch)
+}
+
+//This function returns the values of the collection kept in the history database between the epochs
+func (u Utils) ReadHistory(collectionId uint16, fromEpoch uint32, toEpoch uint32) ([]types.HistoryRecord, error) {
+	return utils.ReadHistory(collectionId, fromEpoch, toEpoch)
+}
+
+//This function returns every problem in assets.json
+func (u Utils) ValidateAssetsFile(client *ethclient.Client) ([]error, error) {
+	return utilsInterface.ValidateAssetsFile(client)
+}
+
+//This function watches assets.json and reloads it when it changes
+func (u Utils) WatchAssetsFile(client *ethclient.Client) error {
+	return utilsInterface.WatchAssetsFile(client)
+}
+
 //This function returns the hash
 func (transactionUtils TransactionUtils) Hash(txn *Types.Transaction) common.Hash {
 	return txn.Hash()
@@ -949,6 +1069,51 @@ func (flagSetUtils FLagSetUtils) GetStringSliceRogueMode(flagSet *pflag.FlagSet)
 	return flagSet.GetStringSlice("rogueMode")
 }
 
+//This function is used to check if recordResponses is passed or not
+func (flagSetUtils FLagSetUtils) GetBoolRecordResponses(flagSet *pflag.FlagSet) (bool, error) {
+	return flagSet.GetBool("recordResponses")
+}
+
+//This function is used to check if encryptDataFiles is passed or not
+func (flagSetUtils FLagSetUtils) GetBoolEncryptDataFiles(flagSet *pflag.FlagSet) (bool, error) {
+	return flagSet.GetBool("encryptDataFiles")
+}
+
+//This function returns the number of epochs data files are kept for in Uint32
+func (flagSetUtils FLagSetUtils) GetUint32DataFilesRetention(flagSet *pflag.FlagSet) (uint32, error) {
+	return flagSet.GetUint32("dataFilesRetention")
+}
+
+//This function is used to check if prune is passed or not
+func (flagSetUtils FLagSetUtils) GetBoolPrune(flagSet *pflag.FlagSet) (bool, error) {
+	return flagSet.GetBool("prune")
+}
+
+//This function returns the epoch in Uint32
+func (flagSetUtils FLagSetUtils) GetUint32Epoch(flagSet *pflag.FlagSet) (uint32, error) {
+	return flagSet.GetUint32("epoch")
+}
+
+//This function returns the from epoch in Uint32
+func (flagSetUtils FLagSetUtils) GetUint32FromEpoch(flagSet *pflag.FlagSet) (uint32, error) {
+	return flagSet.GetUint32("fromEpoch")
+}
+
+//This function returns the to epoch in Uint32
+func (flagSetUtils FLagSetUtils) GetUint32ToEpoch(flagSet *pflag.FlagSet) (uint32, error) {
+	return flagSet.GetUint32("toEpoch")
+}
+
+//This function returns the format in string
+func (flagSetUtils FLagSetUtils) GetStringFormat(flagSet *pflag.FlagSet) (string, error) {
+	return flagSet.GetString("format")
+}
+
+//This function returns the output in string
+func (flagSetUtils FLagSetUtils) GetStringOutput(flagSet *pflag.FlagSet) (string, error) {
+	return flagSet.GetString("output")
+}
+
 //This function is used to check if exposeMetrics is passed or not
 func (flagSetUtils FLagSetUtils) GetStringExposeMetrics(flagSet *pflag.FlagSet) (string, error) {
 	return flagSet.GetString("exposeMetrics")
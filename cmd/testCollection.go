@@ -0,0 +1,122 @@
+//Package cmd provides all functions related to command line
+package cmd
+
+import (
+	"os"
+	"razor/core/types"
+	"razor/utils"
+	"strconv"
+
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/olekukonko/tablewriter"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+var testCollectionCmd = &cobra.Command{
+	Use:   "testCollection",
+	Short: "testCollection aggregates a collection the same way it is done during commit without voting",
+	Long: `Aggregates the value of a collection exactly as it is done during commit, including the jobs from assets.json, without voting. The response, value, powered value and weight of every job of the collection are shown together with the aggregated value and the power and aggregation method it is aggregated with.
+
+Example:
+  ./razor testCollection --collectionId 1
+`,
+	Run: initialiseTestCollection,
+}
+
+//This function initialises the ExecuteTestCollection function
+func initialiseTestCollection(cmd *cobra.Command, args []string) {
+	cmdUtils.ExecuteTestCollection(cmd.Flags())
+}
+
+//This function sets the flags appropriately and executes the TestCollection function
+func (*UtilsStruct) ExecuteTestCollection(flagSet *pflag.FlagSet) {
+	razorUtils.AssignLogFile(flagSet)
+
+	config, err := cmdUtils.GetConfigData()
+	utils.CheckError("Error in getting config: ", err)
+
+	client := razorUtils.ConnectToClient(config.Provider)
+
+	collectionId, err := flagSetUtils.GetUint16CollectionId(flagSet)
+	utils.CheckError("Error in getting collectionId: ", err)
+
+	epoch, err := razorUtils.GetEpoch(client)
+	utils.CheckError("Error in getting epoch: ", err)
+
+	err = cmdUtils.TestCollection(client, collectionId, epoch)
+	utils.CheckError("Error in testing collection: ", err)
+}
+
+//This function shows the details of every job of the collection and the aggregated value of the collection
+func (*UtilsStruct) TestCollection(client *ethclient.Client, collectionId uint16, epoch uint32) error {
+	collection, err := razorUtils.GetActiveCollection(client, collectionId)
+	if err != nil {
+		return err
+	}
+	jobs, err := razorUtils.GetCollectionJobs(client, collection)
+	if err != nil {
+		return err
+	}
+
+	// Aggregating first sets the response cache of the epoch, so the jobs below show the values selected from the same responses
+	aggregatedValue, aggregationErr := razorUtils.GetAggregatedDataOfCollection(client, collectionId, epoch)
+	report, reported := razorUtils.GetAggregationReport(collectionId)
+
+	jobsTable := tablewriter.NewWriter(os.Stdout)
+	jobsTable.SetHeader(jobTestHeader)
+	for _, job := range jobs {
+		// Jobs whose value cannot be fetched are shown with their error and are left out of the aggregation
+		row, _ := getJobTestResult(job)
+		if aggregationErr == nil && reported {
+			// The weight is the one the job is aggregated with, after the reputation and outlier filter of the collection
+			row[len(row)-1] = getAggregatedWeight(job, report)
+		}
+		jobsTable.Append(row)
+	}
+	jobsTable.Render()
+
+	if aggregationErr != nil {
+		return aggregationErr
+	}
+	power, method, source := strconv.Itoa(int(collection.Power)), strconv.Itoa(int(collection.AggregationMethod)), ""
+	if reported {
+		power, method, source = strconv.Itoa(int(report.Power)), report.Method, report.Source
+	}
+	collectionTable := tablewriter.NewWriter(os.Stdout)
+	collectionTable.SetHeader([]string{"Collection Id", "Name", "Power", "Aggregation Method", "Aggregated From", "Aggregated Value"})
+	collectionTable.Append([]string{
+		strconv.Itoa(int(collection.Id)),
+		collection.Name,
+		power,
+		method,
+		source,
+		aggregatedValue.String(),
+	})
+	collectionTable.Render()
+	return nil
+}
+
+//This function returns the weight the job is aggregated with in the collection, jobs which are not aggregated have none
+func getAggregatedWeight(job types.AssetJob, report types.AggregationReport) string {
+	key := utils.GetJobKey(job)
+	for _, jobData := range report.JobsData {
+		if utils.GetJobKey(jobData.Job) == key {
+			return strconv.Itoa(int(jobData.Job.Weight))
+		}
+	}
+	return "not aggregated"
+}
+
+func init() {
+	rootCmd.AddCommand(testCollectionCmd)
+
+	var (
+		CollectionId uint16
+	)
+
+	testCollectionCmd.Flags().Uint16VarP(&CollectionId, "collectionId", "", 0, "id of the collection to test")
+
+	collectionIdErr := testCollectionCmd.MarkFlagRequired("collectionId")
+	utils.CheckError("Collection Id error: ", collectionIdErr)
+}
@@ -0,0 +1,238 @@
+package cmd
+
+import (
+	"errors"
+	"math/big"
+	"razor/cmd/mocks"
+	"razor/core/types"
+	"razor/pkg/bindings"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/spf13/pflag"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestTestCollection(t *testing.T) {
+	var client *ethclient.Client
+	var epoch uint32 = 5
+
+	collection := bindings.StructsCollection{Active: true, Id: 1, Power: 2,
+		AggregationMethod: 2, JobIDs: []uint16{1, 2}, Name: "ethCollectionMean",
+	}
+	jobs := []types.AssetJob{
+		{StructsJob: bindings.StructsJob{Id: 1, Weight: 100, Power: 2, Name: "ethusd_gemini", Selector: "last", Url: "https://api.gemini.com/v1/pubticker/ethusd"}},
+		{StructsJob: bindings.StructsJob{Id: 2, Weight: 100, Power: 2, Name: "ethusd_kraken", Selector: "result.XETHZUSD.c[0]", Url: "https://api.kraken.com/0/public/Ticker?pair=ETHUSD"}},
+	}
+
+	type args struct {
+		collection         bindings.StructsCollection
+		collectionErr      error
+		jobs               []types.AssetJob
+		jobsErr            error
+		aggregatedValue    *big.Int
+		aggregatedValueErr error
+		report             types.AggregationReport
+		reported           bool
+		datumErr           error
+	}
+	tests := []struct {
+		name    string
+		args    args
+		wantErr bool
+	}{
+		{
+			name: "Test 1: When TestCollection() executes successfully",
+			args: args{
+				collection:      collection,
+				jobs:            jobs,
+				aggregatedValue: big.NewInt(300012),
+			},
+			wantErr: false,
+		},
+		{
+			name: "Test 2: When value of a job cannot be fetched",
+			args: args{
+				collection:      collection,
+				jobs:            jobs,
+				aggregatedValue: big.NewInt(300012),
+				datumErr:        errors.New("unable to reach API"),
+			},
+			wantErr: false,
+		},
+		{
+			name: "Test 3: When the collection is aggregated with the method, power and weights from assets.json",
+			args: args{
+				collection:      collection,
+				jobs:            jobs,
+				aggregatedValue: big.NewInt(300012),
+				report:          types.AggregationReport{Source: "jobs", Method: "trimmed mean", Power: 3, JobsData: []types.JobData{{Job: types.AssetJob{StructsJob: bindings.StructsJob{Id: 1, Weight: 50}}, Value: big.NewInt(300012)}}},
+				reported:        true,
+			},
+			wantErr: false,
+		},
+		{
+			name: "Test 4: When there is an error in getting collection",
+			args: args{
+				collectionErr: errors.New("collection inactive"),
+			},
+			wantErr: true,
+		},
+		{
+			name: "Test 5: When there is an error in getting jobs of collection",
+			args: args{
+				collection: collection,
+				jobsErr:    errors.New("error in reading assets.json"),
+			},
+			wantErr: true,
+		},
+		{
+			name: "Test 6: When there is an error in aggregating collection",
+			args: args{
+				collection:         collection,
+				jobs:               jobs,
+				aggregatedValueErr: errors.New("no jobs present in the collection"),
+				datumErr:           errors.New("unable to reach API"),
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			utilsMock := new(mocks.UtilsInterface)
+			razorUtils = utilsMock
+
+			utilsMock.On("GetActiveCollection", mock.AnythingOfType("*ethclient.Client"), mock.AnythingOfType("uint16")).Return(tt.args.collection, tt.args.collectionErr)
+			utilsMock.On("GetCollectionJobs", mock.AnythingOfType("*ethclient.Client"), mock.Anything).Return(tt.args.jobs, tt.args.jobsErr)
+			utilsMock.On("GetAggregatedDataOfCollection", mock.AnythingOfType("*ethclient.Client"), mock.AnythingOfType("uint16"), epoch).Return(tt.args.aggregatedValue, tt.args.aggregatedValueErr)
+			utilsMock.On("GetAggregationReport", mock.AnythingOfType("uint16")).Return(tt.args.report, tt.args.reported)
+			utilsMock.On("GetDataFromAPI", mock.AnythingOfType("string"), mock.Anything).Return([]byte(`{"last":"3000.12"}`), nil)
+			utilsMock.On("GetDatumFromJob", mock.Anything).Return(big.NewRat(300012, 100), tt.args.datumErr)
+			utilsMock.On("GetDataToCommitFromJob", mock.Anything).Return(big.NewInt(300012), nil)
+
+			utils := &UtilsStruct{}
+			err := utils.TestCollection(client, 1, epoch)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("TestCollection() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestGetAggregatedWeight(t *testing.T) {
+	report := types.AggregationReport{JobsData: []types.JobData{
+		{Job: types.AssetJob{StructsJob: bindings.StructsJob{Id: 1, Weight: 30}}},
+		{Job: types.AssetJob{StructsJob: bindings.StructsJob{Name: "ethusd_local", Weight: 1}}},
+	}}
+	tests := []struct {
+		name string
+		job  types.AssetJob
+		want string
+	}{
+		{
+			name: "Test 1: When the job is aggregated with a reduced weight",
+			job:  types.AssetJob{StructsJob: bindings.StructsJob{Id: 1, Weight: 100}},
+			want: "30",
+		},
+		{
+			name: "Test 2: When a custom job is aggregated",
+			job:  types.AssetJob{StructsJob: bindings.StructsJob{Name: "ethusd_local", Weight: 1}},
+			want: "1",
+		},
+		{
+			name: "Test 3: When the job is not aggregated",
+			job:  types.AssetJob{StructsJob: bindings.StructsJob{Id: 2, Weight: 100}},
+			want: "not aggregated",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := getAggregatedWeight(tt.job, report); got != tt.want {
+				t.Errorf("getAggregatedWeight() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestExecuteTestCollection(t *testing.T) {
+	var config types.Configurations
+	var client *ethclient.Client
+	var flagSet *pflag.FlagSet
+
+	type args struct {
+		configErr         error
+		collectionIdErr   error
+		epochErr          error
+		testCollectionErr error
+	}
+	tests := []struct {
+		name          string
+		args          args
+		expectedFatal bool
+	}{
+		{
+			name:          "Test 1: When ExecuteTestCollection() executes successfully",
+			args:          args{},
+			expectedFatal: false,
+		},
+		{
+			name: "Test 2: When there is an error in getting config",
+			args: args{
+				configErr: errors.New("config error"),
+			},
+			expectedFatal: true,
+		},
+		{
+			name: "Test 3: When there is an error in getting collectionId",
+			args: args{
+				collectionIdErr: errors.New("collectionId error"),
+			},
+			expectedFatal: true,
+		},
+		{
+			name: "Test 4: When there is an error in getting epoch",
+			args: args{
+				epochErr: errors.New("epoch error"),
+			},
+			expectedFatal: true,
+		},
+		{
+			name: "Test 5: When there is an error in testing collection",
+			args: args{
+				testCollectionErr: errors.New("collection inactive"),
+			},
+			expectedFatal: true,
+		},
+	}
+
+	defer func() { log.ExitFunc = nil }()
+	var fatal bool
+	log.ExitFunc = func(int) { fatal = true }
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			utilsMock := new(mocks.UtilsInterface)
+			flagsetUtilsMock := new(mocks.FlagSetInterface)
+			cmdUtilsMock := new(mocks.UtilsCmdInterface)
+
+			razorUtils = utilsMock
+			flagSetUtils = flagsetUtilsMock
+			cmdUtils = cmdUtilsMock
+
+			utilsMock.On("AssignLogFile", mock.AnythingOfType("*pflag.FlagSet"))
+			cmdUtilsMock.On("GetConfigData").Return(config, tt.args.configErr)
+			utilsMock.On("ConnectToClient", mock.AnythingOfType("string")).Return(client)
+			flagsetUtilsMock.On("GetUint16CollectionId", flagSet).Return(uint16(1), tt.args.collectionIdErr)
+			utilsMock.On("GetEpoch", mock.AnythingOfType("*ethclient.Client")).Return(uint32(5), tt.args.epochErr)
+			cmdUtilsMock.On("TestCollection", mock.AnythingOfType("*ethclient.Client"), mock.AnythingOfType("uint16"), mock.AnythingOfType("uint32")).Return(tt.args.testCollectionErr)
+
+			utils := &UtilsStruct{}
+			fatal = false
+
+			utils.ExecuteTestCollection(flagSet)
+			if fatal != tt.expectedFatal {
+				t.Error("The ExecuteTestCollection function didn't execute as expected")
+			}
+		})
+	}
+}
@@ -0,0 +1,192 @@
+//Package cmd provides all functions related to command line
+package cmd
+
+import (
+	"errors"
+	"math/big"
+	"os"
+	"razor/core"
+	"razor/core/types"
+	"razor/pkg/bindings"
+	"razor/utils"
+	"strconv"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/olekukonko/tablewriter"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+var testJobCmd = &cobra.Command{
+	Use:   "testJob",
+	Short: "testJob runs a job the same way it is run during commit without voting",
+	Long: `Fetches the response of a job, selects its value and applies its power exactly as it is done during commit, without voting. Either the id of a job or an ad-hoc url and selector can be given.
+
+Example:
+  ./razor testJob --jobId 1
+  ./razor testJob -u https://api.gemini.com/v1/pubticker/ethusd -s last --power 2
+`,
+	Run: initialiseTestJob,
+}
+
+var jobTestHeader = []string{"Job Id", "Name", "Url", "Selector", "Response", "Value", "Power", "Powered Value", "Weight"}
+
+//This function initialises the ExecuteTestJob function
+func initialiseTestJob(cmd *cobra.Command, args []string) {
+	cmdUtils.ExecuteTestJob(cmd.Flags())
+}
+
+//This function sets the flags appropriately and executes the TestJob function
+func (*UtilsStruct) ExecuteTestJob(flagSet *pflag.FlagSet) {
+	razorUtils.AssignLogFile(flagSet)
+
+	config, err := cmdUtils.GetConfigData()
+	utils.CheckError("Error in getting config: ", err)
+
+	client := razorUtils.ConnectToClient(config.Provider)
+
+	epoch, err := razorUtils.GetEpoch(client)
+	utils.CheckError("Error in getting epoch: ", err)
+
+	var job types.AssetJob
+	if razorUtils.IsFlagPassed("jobId") {
+		jobId, err := flagSetUtils.GetUint16JobId(flagSet)
+		utils.CheckError("Error in getting jobId: ", err)
+
+		activeJob, err := razorUtils.GetActiveJob(client, jobId)
+		utils.CheckError("Error in getting job: ", err)
+		job = types.AssetJob{StructsJob: activeJob}
+	} else {
+		url, err := flagSetUtils.GetStringUrl(flagSet)
+		utils.CheckError("Error in getting url: ", err)
+
+		selector, err := flagSetUtils.GetStringSelector(flagSet)
+		utils.CheckError("Error in getting selector: ", err)
+
+		if url == "" || selector == "" {
+			utils.CheckError("Error in getting job: ", errors.New("either jobId or url and selector are required"))
+		}
+
+		selectorType, err := flagSetUtils.GetUint8SelectorType(flagSet)
+		utils.CheckError("Error in getting selectorType: ", err)
+
+		power, err := flagSetUtils.GetInt8Power(flagSet)
+		utils.CheckError("Error in getting power: ", err)
+
+		weight, err := flagSetUtils.GetUint8Weight(flagSet)
+		utils.CheckError("Error in getting weight: ", err)
+
+		job = types.AssetJob{StructsJob: bindings.StructsJob{
+			Url:          url,
+			Selector:     selector,
+			SelectorType: selectorType,
+			Power:        power,
+			Weight:       weight,
+		}}
+	}
+
+	err = cmdUtils.TestJob(client, job, epoch)
+	utils.CheckError("Error in testing job: ", err)
+}
+
+//This function shows the response, the selected value and the powered value of the job
+func (*UtilsStruct) TestJob(client *ethclient.Client, job types.AssetJob, epoch uint32) error {
+	// The response is fetched only once in the epoch, so the value of a job which is not scraped is selected from the same response that is shown
+	razorUtils.SetResponseCacheEpoch(epoch)
+	// Jobs given by their id or URL are not read from assets.json, but they are fetched with its http settings too
+	if err := razorUtils.LoadHTTPConfig(); err != nil {
+		return err
+	}
+
+	row, err := getJobTestResult(job)
+	table := tablewriter.NewWriter(os.Stdout)
+	table.SetHeader(jobTestHeader)
+	table.Append(row)
+	table.Render()
+	return err
+}
+
+//This function returns the row of the job test table and the error in getting the value of the job
+func getJobTestResult(job types.AssetJob) ([]string, error) {
+	source := job.Url
+	response := ""
+	if job.Expression != "" {
+		source = "= " + job.Expression
+	} else if job.Plugin == nil {
+		body, err := razorUtils.GetDataFromAPI(job.Url, job.Request)
+		if err != nil {
+			response = "error: " + err.Error()
+		} else {
+			response = getResponseExcerpt(body)
+		}
+		if job.SelectorType == core.XHTMLSelectorType || job.SelectorType == core.CSSSelectorType {
+			// The value is scraped with a request of its own, whose headers differ, so it can be selected from another response than the one shown
+			response = "separate fetch: " + response
+		}
+	}
+
+	value, poweredValue := "", ""
+	datum, err := razorUtils.GetDatumFromJob(job)
+	if err != nil {
+		value = "error: " + err.Error()
+	} else {
+		value = formatDatum(datum)
+		dataToCommit, err := razorUtils.GetDataToCommitFromJob(job)
+		if err != nil {
+			poweredValue = "error: " + err.Error()
+		} else {
+			poweredValue = dataToCommit.String()
+		}
+	}
+
+	return []string{
+		strconv.Itoa(int(job.Id)),
+		job.Name,
+		source,
+		job.Selector,
+		response,
+		value,
+		strconv.Itoa(int(job.Power)),
+		poweredValue,
+		strconv.Itoa(int(job.Weight)),
+	}, err
+}
+
+//This function returns the response in a single line, cut to the length of an excerpt
+func getResponseExcerpt(response []byte) string {
+	excerpt := strings.Join(strings.Fields(string(response)), " ")
+	if len(excerpt) > core.ResponseExcerptLength {
+		excerpt = excerpt[:core.ResponseExcerptLength] + "..."
+	}
+	return excerpt
+}
+
+//This function returns the exact decimal representation of the value, up to 18 decimals
+func formatDatum(datum *big.Rat) string {
+	if datum.IsInt() {
+		return datum.Num().String()
+	}
+	value := strings.TrimRight(datum.FloatString(18), "0")
+	return strings.TrimSuffix(value, ".")
+}
+
+func init() {
+	rootCmd.AddCommand(testJobCmd)
+
+	var (
+		JobId        uint16
+		URL          string
+		Selector     string
+		SelectorType uint8
+		Power        int8
+		Weight       uint8
+	)
+
+	testJobCmd.Flags().Uint16VarP(&JobId, "jobId", "", 0, "id of the job to test")
+	testJobCmd.Flags().StringVarP(&URL, "url", "u", "", "url of ad-hoc job")
+	testJobCmd.Flags().StringVarP(&Selector, "selector", "s", "", "selector of ad-hoc job")
+	testJobCmd.Flags().Uint8VarP(&SelectorType, "selectorType", "", 0, "selector type of ad-hoc job (0 for json, 1 for XHTML, 2 for css, 3 for regex, 4 for csv, 5 for gjson)")
+	testJobCmd.Flags().Int8VarP(&Power, "power", "", 0, "power of ad-hoc job")
+	testJobCmd.Flags().Uint8VarP(&Weight, "weight", "", 1, "weight of ad-hoc job")
+}
@@ -0,0 +1,295 @@
+package cmd
+
+import (
+	"errors"
+	"math/big"
+	"razor/cmd/mocks"
+	"razor/core/types"
+	"razor/pkg/bindings"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/spf13/pflag"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestTestJob(t *testing.T) {
+	var client *ethclient.Client
+	var epoch uint32 = 5
+
+	job := types.AssetJob{StructsJob: bindings.StructsJob{Id: 1, Weight: 100,
+		Power: 2, Name: "ethusd_gemini", Selector: "last",
+		Url: "https://api.gemini.com/v1/pubticker/ethusd",
+	}}
+	scrapedJob := types.AssetJob{StructsJob: bindings.StructsJob{Id: 2, Weight: 100,
+		Power: 2, Name: "ethusd_coinmarketcap", Selector: `//span[@class="priceValue"]`, SelectorType: 1,
+		Url: "https://coinmarketcap.com/currencies/ethereum/",
+	}}
+	derivedJob := types.AssetJob{
+		StructsJob: bindings.StructsJob{Name: "ethbtc", Power: 6, Weight: 1},
+		Expression: "ethusd / btcusd",
+	}
+
+	type args struct {
+		job             types.AssetJob
+		response        []byte
+		responseErr     error
+		datum           *big.Rat
+		datumErr        error
+		dataToCommit    *big.Int
+		dataToCommitErr error
+		httpConfigErr   error
+	}
+	tests := []struct {
+		name    string
+		args    args
+		wantRow []string
+		wantErr bool
+	}{
+		{
+			name: "Test 1: When TestJob() executes successfully",
+			args: args{
+				job:          job,
+				response:     []byte(`{"bid":"3000.1","ask":"3000.2","last":"3000.12"}`),
+				datum:        big.NewRat(300012, 100),
+				dataToCommit: big.NewInt(300012),
+			},
+			wantRow: []string{"1", "ethusd_gemini", "https://api.gemini.com/v1/pubticker/ethusd", "last", `{"bid":"3000.1","ask":"3000.2","last":"3000.12"}`, "3000.12", "2", "300012", "100"},
+		},
+		{
+			name: "Test 2: When job is a derived job",
+			args: args{
+				job:          derivedJob,
+				datum:        big.NewRat(1, 4),
+				dataToCommit: big.NewInt(250000),
+			},
+			wantRow: []string{"0", "ethbtc", "= ethusd / btcusd", "", "", "0.25", "6", "250000", "1"},
+		},
+		{
+			name: "Test 3: When there is an error in fetching the response",
+			args: args{
+				job:         job,
+				responseErr: errors.New("unable to reach API"),
+				datumErr:    errors.New("unable to reach API"),
+			},
+			wantRow: []string{"1", "ethusd_gemini", "https://api.gemini.com/v1/pubticker/ethusd", "last", "error: unable to reach API", "error: unable to reach API", "2", "", "100"},
+			wantErr: true,
+		},
+		{
+			name: "Test 4: When http settings of assets.json cannot be applied",
+			args: args{
+				job:           job,
+				response:      []byte(`{"bid":"3000.1","ask":"3000.2","last":"3000.12"}`),
+				datum:         big.NewRat(300012, 100),
+				dataToCommit:  big.NewInt(300012),
+				httpConfigErr: errors.New("error in reading CA file"),
+			},
+			wantRow: []string{"1", "ethusd_gemini", "https://api.gemini.com/v1/pubticker/ethusd", "last", `{"bid":"3000.1","ask":"3000.2","last":"3000.12"}`, "3000.12", "2", "300012", "100"},
+			wantErr: true,
+		},
+		{
+			name: "Test 5: When job is scraped with a request of its own",
+			args: args{
+				job:          scrapedJob,
+				response:     []byte(`<span class="priceValue">3000.12</span>`),
+				datum:        big.NewRat(300012, 100),
+				dataToCommit: big.NewInt(300012),
+			},
+			wantRow: []string{"2", "ethusd_coinmarketcap", "https://coinmarketcap.com/currencies/ethereum/", `//span[@class="priceValue"]`, `separate fetch: <span class="priceValue">3000.12</span>`, "3000.12", "2", "300012", "100"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			utilsMock := new(mocks.UtilsInterface)
+			razorUtils = utilsMock
+
+			utilsMock.On("SetResponseCacheEpoch", epoch)
+			utilsMock.On("LoadHTTPConfig").Return(tt.args.httpConfigErr)
+			utilsMock.On("GetDataFromAPI", mock.AnythingOfType("string"), mock.Anything).Return(tt.args.response, tt.args.responseErr)
+			utilsMock.On("GetDatumFromJob", mock.Anything).Return(tt.args.datum, tt.args.datumErr)
+			utilsMock.On("GetDataToCommitFromJob", mock.Anything).Return(tt.args.dataToCommit, tt.args.dataToCommitErr)
+
+			utils := &UtilsStruct{}
+			err := utils.TestJob(client, tt.args.job, epoch)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("TestJob() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			utilsMock.AssertCalled(t, "SetResponseCacheEpoch", epoch)
+
+			row, _ := getJobTestResult(tt.args.job)
+			if !reflect.DeepEqual(row, tt.wantRow) {
+				t.Errorf("getJobTestResult() got = %q, want %q", row, tt.wantRow)
+			}
+		})
+	}
+}
+
+func TestGetResponseExcerpt(t *testing.T) {
+	tests := []struct {
+		name     string
+		response []byte
+		want     string
+	}{
+		{
+			name:     "Test 1: When response spans multiple lines",
+			response: []byte("{\n  \"last\": \"3000.12\",\n  \"volume\": \"120\"\n}\n"),
+			want:     `{ "last": "3000.12", "volume": "120" }`,
+		},
+		{
+			name:     "Test 2: When response is longer than an excerpt",
+			response: []byte(strings.Repeat("a", 200)),
+			want:     strings.Repeat("a", 120) + "...",
+		},
+		{
+			name:     "Test 3: When response is empty",
+			response: nil,
+			want:     "",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := getResponseExcerpt(tt.response); got != tt.want {
+				t.Errorf("getResponseExcerpt() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFormatDatum(t *testing.T) {
+	tests := []struct {
+		name  string
+		datum *big.Rat
+		want  string
+	}{
+		{name: "Test 1: When value is an integer", datum: big.NewRat(3000, 1), want: "3000"},
+		{name: "Test 2: When value is a decimal", datum: big.NewRat(300012, 100), want: "3000.12"},
+		{name: "Test 3: When value has more than 18 decimals", datum: big.NewRat(1, 3), want: "0.333333333333333333"},
+		{name: "Test 4: When value is negative", datum: big.NewRat(-5, 2), want: "-2.5"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := formatDatum(tt.datum); got != tt.want {
+				t.Errorf("formatDatum() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestExecuteTestJob(t *testing.T) {
+	var config types.Configurations
+	var client *ethclient.Client
+	var flagSet *pflag.FlagSet
+
+	type args struct {
+		configErr     error
+		epochErr      error
+		isJobIdPassed bool
+		jobId         uint16
+		jobIdErr      error
+		activeJobErr  error
+		url           string
+		selector      string
+		testJobErr    error
+	}
+	tests := []struct {
+		name          string
+		args          args
+		expectedFatal bool
+	}{
+		{
+			name: "Test 1: When ExecuteTestJob() executes successfully with jobId",
+			args: args{
+				isJobIdPassed: true,
+				jobId:         1,
+			},
+			expectedFatal: false,
+		},
+		{
+			name: "Test 2: When ExecuteTestJob() executes successfully with url and selector",
+			args: args{
+				url:      "https://api.gemini.com/v1/pubticker/ethusd",
+				selector: "last",
+			},
+			expectedFatal: false,
+		},
+		{
+			name: "Test 3: When there is an error in getting config",
+			args: args{
+				configErr:     errors.New("config error"),
+				isJobIdPassed: true,
+			},
+			expectedFatal: true,
+		},
+		{
+			name: "Test 4: When there is an error in getting epoch",
+			args: args{
+				epochErr:      errors.New("epoch error"),
+				isJobIdPassed: true,
+			},
+			expectedFatal: true,
+		},
+		{
+			name: "Test 5: When there is an error in getting job",
+			args: args{
+				isJobIdPassed: true,
+				activeJobErr:  errors.New("job error"),
+			},
+			expectedFatal: true,
+		},
+		{
+			name: "Test 6: When neither jobId nor url and selector are passed",
+			args: args{
+				selector: "last",
+			},
+			expectedFatal: true,
+		},
+		{
+			name: "Test 7: When there is an error in testing job",
+			args: args{
+				isJobIdPassed: true,
+				testJobErr:    errors.New("unable to reach API"),
+			},
+			expectedFatal: true,
+		},
+	}
+
+	defer func() { log.ExitFunc = nil }()
+	var fatal bool
+	log.ExitFunc = func(int) { fatal = true }
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			utilsMock := new(mocks.UtilsInterface)
+			flagsetUtilsMock := new(mocks.FlagSetInterface)
+			cmdUtilsMock := new(mocks.UtilsCmdInterface)
+
+			razorUtils = utilsMock
+			flagSetUtils = flagsetUtilsMock
+			cmdUtils = cmdUtilsMock
+
+			utilsMock.On("AssignLogFile", mock.AnythingOfType("*pflag.FlagSet"))
+			cmdUtilsMock.On("GetConfigData").Return(config, tt.args.configErr)
+			utilsMock.On("ConnectToClient", mock.AnythingOfType("string")).Return(client)
+			utilsMock.On("GetEpoch", mock.AnythingOfType("*ethclient.Client")).Return(uint32(5), tt.args.epochErr)
+			utilsMock.On("IsFlagPassed", "jobId").Return(tt.args.isJobIdPassed)
+			flagsetUtilsMock.On("GetUint16JobId", flagSet).Return(tt.args.jobId, tt.args.jobIdErr)
+			utilsMock.On("GetActiveJob", mock.AnythingOfType("*ethclient.Client"), mock.AnythingOfType("uint16")).Return(bindings.StructsJob{Id: tt.args.jobId}, tt.args.activeJobErr)
+			flagsetUtilsMock.On("GetStringUrl", flagSet).Return(tt.args.url, nil)
+			flagsetUtilsMock.On("GetStringSelector", flagSet).Return(tt.args.selector, nil)
+			flagsetUtilsMock.On("GetUint8SelectorType", flagSet).Return(uint8(0), nil)
+			flagsetUtilsMock.On("GetInt8Power", flagSet).Return(int8(2), nil)
+			flagsetUtilsMock.On("GetUint8Weight", flagSet).Return(uint8(1), nil)
+			cmdUtilsMock.On("TestJob", mock.AnythingOfType("*ethclient.Client"), mock.Anything, mock.AnythingOfType("uint32")).Return(tt.args.testJobErr)
+
+			utils := &UtilsStruct{}
+			fatal = false
+
+			utils.ExecuteTestJob(flagSet)
+			if fatal != tt.expectedFatal {
+				t.Error("The ExecuteTestJob function didn't execute as expected")
+			}
+		})
+	}
+}
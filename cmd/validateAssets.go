@@ -0,0 +1,60 @@
+//Package cmd provides all functions related to command line
+package cmd
+
+import (
+	"fmt"
+	"razor/utils"
+
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+var validateAssetsCmd = &cobra.Command{
+	Use:   "validateAssets",
+	Short: "validateAssets reports every problem in assets.json",
+	Long: `Checks assets.json against its file format and the collections on chain. Unknown keys, unknown collections, official jobs which are not part of their collection, weights and powers out of range, invalid selectors, expressions and aggregation settings are reported. vote refuses to start if assets.json has any problem.
+
+Example:
+  ./razor validateAssets
+`,
+	Run: initialiseValidateAssets,
+}
+
+//This function initialises the ExecuteValidateAssets function
+func initialiseValidateAssets(cmd *cobra.Command, args []string) {
+	cmdUtils.ExecuteValidateAssets(cmd.Flags())
+}
+
+//This function sets the flags appropriately and executes the ValidateAssets function
+func (*UtilsStruct) ExecuteValidateAssets(flagSet *pflag.FlagSet) {
+	razorUtils.AssignLogFile(flagSet)
+
+	config, err := cmdUtils.GetConfigData()
+	utils.CheckError("Error in getting config: ", err)
+
+	client := razorUtils.ConnectToClient(config.Provider)
+
+	err = cmdUtils.ValidateAssets(client)
+	utils.CheckError("Error in validating assets.json: ", err)
+}
+
+//This function logs every problem in assets.json and returns an error if there is any
+func (*UtilsStruct) ValidateAssets(client *ethclient.Client) error {
+	problems, err := razorUtils.ValidateAssetsFile(client)
+	if err != nil {
+		return err
+	}
+	if len(problems) == 0 {
+		log.Info("assets.json is valid")
+		return nil
+	}
+	for _, problem := range problems {
+		log.Error(problem)
+	}
+	return fmt.Errorf("found %d problems in assets.json", len(problems))
+}
+
+func init() {
+	rootCmd.AddCommand(validateAssetsCmd)
+}
@@ -0,0 +1,126 @@
+package cmd
+
+import (
+	"errors"
+	"razor/cmd/mocks"
+	"razor/core/types"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/spf13/pflag"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestValidateAssets(t *testing.T) {
+	var client *ethclient.Client
+
+	type args struct {
+		problems    []error
+		validateErr error
+	}
+	tests := []struct {
+		name    string
+		args    args
+		wantErr bool
+	}{
+		{
+			name:    "Test 1: When assets.json has no problems",
+			args:    args{},
+			wantErr: false,
+		},
+		{
+			name: "Test 2: When assets.json has problems",
+			args: args{
+				problems: []error{
+					errors.New(`assets.collection.ethCollectionMean.custom jobs[0]: unknown key "selectr"`),
+					errors.New("assets.collection.ethCollectionMean.custom jobs[0].weight: must be between 1 and 255"),
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "Test 3: When there is an error in reading assets.json",
+			args: args{
+				validateErr: errors.New("error in fetching collections"),
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			utilsMock := new(mocks.UtilsInterface)
+			razorUtils = utilsMock
+
+			utilsMock.On("ValidateAssetsFile", mock.AnythingOfType("*ethclient.Client")).Return(tt.args.problems, tt.args.validateErr)
+
+			utils := &UtilsStruct{}
+			err := utils.ValidateAssets(client)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ValidateAssets() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestExecuteValidateAssets(t *testing.T) {
+	var config types.Configurations
+	var client *ethclient.Client
+	var flagSet *pflag.FlagSet
+
+	type args struct {
+		configErr   error
+		validateErr error
+	}
+	tests := []struct {
+		name          string
+		args          args
+		expectedFatal bool
+	}{
+		{
+			name:          "Test 1: When ExecuteValidateAssets() executes successfully",
+			args:          args{},
+			expectedFatal: false,
+		},
+		{
+			name: "Test 2: When there is an error in getting config",
+			args: args{
+				configErr: errors.New("config error"),
+			},
+			expectedFatal: true,
+		},
+		{
+			name: "Test 3: When assets.json is invalid",
+			args: args{
+				validateErr: errors.New("found 2 problems in assets.json"),
+			},
+			expectedFatal: true,
+		},
+	}
+
+	defer func() { log.ExitFunc = nil }()
+	var fatal bool
+	log.ExitFunc = func(int) { fatal = true }
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			utilsMock := new(mocks.UtilsInterface)
+			cmdUtilsMock := new(mocks.UtilsCmdInterface)
+
+			razorUtils = utilsMock
+			cmdUtils = cmdUtilsMock
+
+			utilsMock.On("AssignLogFile", mock.AnythingOfType("*pflag.FlagSet"))
+			cmdUtilsMock.On("GetConfigData").Return(config, tt.args.configErr)
+			utilsMock.On("ConnectToClient", mock.AnythingOfType("string")).Return(client)
+			cmdUtilsMock.On("ValidateAssets", mock.AnythingOfType("*ethclient.Client")).Return(tt.args.validateErr)
+
+			utils := &UtilsStruct{}
+			fatal = false
+
+			utils.ExecuteValidateAssets(flagSet)
+			if fatal != tt.expectedFatal {
+				t.Error("The ExecuteValidateAssets function didn't execute as expected")
+			}
+		})
+	}
+}
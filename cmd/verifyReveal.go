@@ -0,0 +1,115 @@
+//Package cmd provides all functions related to command line
+package cmd
+
+import (
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"razor/core/types"
+	"razor/utils"
+
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+var verifyRevealCmd = &cobra.Command{
+	Use:   "verifyReveal",
+	Short: "verifyReveal checks that the reveal of the current epoch will pass before it is sent",
+	Long: `Rebuilds the merkle tree of the values saved in the commit data file of the staker, and checks that its root and the seed derived from the secret match the commitment on chain and that the proof of every assigned value is valid, as the contract checks them when revealing.
+
+Example:
+  ./razor verifyReveal --address 0x5a0b54d5dc17e0aadc383d2db43b0a0d3e029c4c
+`,
+	Run: initialiseVerifyReveal,
+}
+
+// The reveal is not checked when the commitment on chain cannot be read, which is not a mismatch of the commit data
+var errCommitmentNotRead = errors.New("commitment on chain could not be read")
+
+//This function initialises the ExecuteVerifyReveal function
+func initialiseVerifyReveal(cmd *cobra.Command, args []string) {
+	cmdUtils.ExecuteVerifyReveal(cmd.Flags())
+}
+
+//This function sets the flags appropriately and executes the VerifyReveal function
+func (*UtilsStruct) ExecuteVerifyReveal(flagSet *pflag.FlagSet) {
+	razorUtils.AssignLogFile(flagSet)
+
+	address, err := flagSetUtils.GetStringAddress(flagSet)
+	utils.CheckError("Error in getting address: ", err)
+
+	config, err := cmdUtils.GetConfigData()
+	utils.CheckError("Error in getting config: ", err)
+
+	password := razorUtils.AssignPassword(flagSet)
+	account := types.Account{Address: address, Password: password}
+
+	err = razorUtils.SetDataFilesPassword(password, false)
+	utils.CheckError("Error in setting password of data files: ", err)
+
+	client := razorUtils.ConnectToClient(config.Provider)
+
+	epoch, err := razorUtils.GetEpoch(client)
+	utils.CheckError("Error in getting epoch: ", err)
+
+	commitData, err := getCommitDataFromFile(address, epoch)
+	utils.CheckError("Error in getting committed data of the current epoch: ", err)
+
+	secret, err := cmdUtils.CalculateSecret(account, epoch)
+	utils.CheckError("Error in calculating secret: ", err)
+
+	err = cmdUtils.VerifyReveal(client, account, epoch, commitData, secret)
+	utils.CheckError("Reveal would fail: ", err)
+	log.Infof("Reveal of epoch %d matches the commitment on chain", epoch)
+}
+
+//This function checks that the commit data and the seed derived from the secret match the commitment on chain and that the proof of every assigned value is valid
+func (*UtilsStruct) VerifyReveal(client *ethclient.Client, account types.Account, epoch uint32, commitData types.CommitData, secret []byte) error {
+	if len(commitData.Leaves) == 0 || len(commitData.SeqAllottedCollections) == 0 {
+		return errors.New("commit data has no values to reveal")
+	}
+	salt, err := cmdUtils.GetSalt(client, epoch)
+	if err != nil {
+		return fmt.Errorf("%w: %s", errCommitmentNotRead, err)
+	}
+	seed := calculateSeed(salt, secret)
+
+	merkleTree := utils.MerkleInterface.CreateMerkle(commitData.Leaves)
+	root := utils.MerkleInterface.GetMerkleRoot(merkleTree)
+	commitment := utils.CalculateCommitment(root, seed)
+	commitmentOnChain, err := razorUtils.GetCommitments(client, account.Address)
+	if err != nil {
+		return fmt.Errorf("%w: %s", errCommitmentNotRead, err)
+	}
+	if commitment != commitmentOnChain {
+		return fmt.Errorf("commitment 0x%s of the commit data does not match the commitment 0x%s on chain", hex.EncodeToString(commitment[:]), hex.EncodeToString(commitmentOnChain[:]))
+	}
+
+	treeRevealData := cmdUtils.GenerateTreeRevealData(merkleTree, commitData)
+	if len(treeRevealData.Values) != len(treeRevealData.Proofs) {
+		return errors.New("every revealed value needs a proof")
+	}
+	for i, value := range treeRevealData.Values {
+		if !utils.MerkleInterface.VerifyProofPath(treeRevealData.Root, value.Value, value.LeafId, len(commitData.Leaves), treeRevealData.Proofs[i]) {
+			return fmt.Errorf("proof of value %s of leaf %d is invalid", value.Value, value.LeafId)
+		}
+	}
+	log.Debugf("Verified commitment 0x%s and the proofs of %d values", hex.EncodeToString(commitment[:]), len(treeRevealData.Values))
+	return nil
+}
+
+func init() {
+	rootCmd.AddCommand(verifyRevealCmd)
+
+	var (
+		Address  string
+		Password string
+	)
+
+	verifyRevealCmd.Flags().StringVarP(&Address, "address", "a", "", "address of the staker")
+	verifyRevealCmd.Flags().StringVarP(&Password, "password", "", "", "password path of staker to protect the keystore")
+
+	addrErr := verifyRevealCmd.MarkFlagRequired("address")
+	utils.CheckError("Address error: ", addrErr)
+}
@@ -0,0 +1,249 @@
+package cmd
+
+import (
+	"encoding/hex"
+	"errors"
+	"math/big"
+	"razor/cmd/mocks"
+	"razor/core/types"
+	"razor/pkg/bindings"
+	"razor/utils"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/ethclient"
+	solsha3 "github.com/miguelmota/go-solidity-sha3"
+	"github.com/spf13/pflag"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestVerifyReveal(t *testing.T) {
+	var client *ethclient.Client
+	account := types.Account{Address: "0x000000000000000000000000000000000000dea1"}
+	salt := [32]byte{1, 2, 3}
+	secret := []byte{4, 5, 6}
+	commitData := types.CommitData{
+		AssignedCollections:    map[int]bool{1: true, 2: true},
+		SeqAllottedCollections: []*big.Int{big.NewInt(1), big.NewInt(2)},
+		Leaves:                 []*big.Int{big.NewInt(0), big.NewInt(295050), big.NewInt(4500000)},
+	}
+	utils.MerkleInterface = &utils.MerkleTreeStruct{}
+	seed := solsha3.SoliditySHA3([]string{"bytes32", "bytes32"}, []interface{}{"0x" + hex.EncodeToString(salt[:]), "0x" + hex.EncodeToString(secret)})
+	commitment := utils.CalculateCommitment(utils.MerkleInterface.GetMerkleRoot(utils.MerkleInterface.CreateMerkle(commitData.Leaves)), seed)
+
+	type args struct {
+		commitData    types.CommitData
+		saltErr       error
+		commitment    [32]byte
+		commitmentErr error
+		proofs        [][][32]byte
+	}
+	tests := []struct {
+		name        string
+		args        args
+		wantErr     bool
+		wantNotRead bool
+	}{
+		{
+			name: "Test 1: When commit data matches the commitment on chain",
+			args: args{
+				commitData: commitData,
+				commitment: commitment,
+			},
+			wantErr: false,
+		},
+		{
+			name: "Test 2: When commit data does not match the commitment on chain",
+			args: args{
+				commitData: types.CommitData{
+					AssignedCollections:    commitData.AssignedCollections,
+					SeqAllottedCollections: commitData.SeqAllottedCollections,
+					Leaves:                 []*big.Int{big.NewInt(0), big.NewInt(295051), big.NewInt(4500000)},
+				},
+				commitment: commitment,
+			},
+			wantErr: true,
+		},
+		{
+			name: "Test 3: When a proof is invalid",
+			args: args{
+				commitData: commitData,
+				commitment: commitment,
+				proofs:     [][][32]byte{{{1}}, {{2}}},
+			},
+			wantErr: true,
+		},
+		{
+			name: "Test 4: When there is an error in getting salt",
+			args: args{
+				commitData: commitData,
+				saltErr:    errors.New("salt error"),
+			},
+			wantErr:     true,
+			wantNotRead: true,
+		},
+		{
+			name: "Test 5: When there is an error in getting the commitment on chain",
+			args: args{
+				commitData:    commitData,
+				commitmentErr: errors.New("commitment error"),
+			},
+			wantErr:     true,
+			wantNotRead: true,
+		},
+		{
+			name: "Test 6: When commit data has no values",
+			args: args{
+				commitment: commitment,
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			utilsMock := new(mocks.UtilsInterface)
+			cmdUtilsMock := new(mocks.UtilsCmdInterface)
+
+			razorUtils = utilsMock
+			cmdUtils = cmdUtilsMock
+
+			cmdUtilsMock.On("GetSalt", mock.AnythingOfType("*ethclient.Client"), uint32(5)).Return(salt, tt.args.saltErr)
+			utilsMock.On("GetCommitments", mock.AnythingOfType("*ethclient.Client"), account.Address).Return(tt.args.commitment, tt.args.commitmentErr)
+			cmdUtilsMock.On("GenerateTreeRevealData", mock.Anything, mock.Anything).Return(func(merkleTree [][][]byte, commitData types.CommitData) bindings.StructsMerkleTree {
+				treeRevealData := (&UtilsStruct{}).GenerateTreeRevealData(merkleTree, commitData)
+				if tt.args.proofs != nil {
+					treeRevealData.Proofs = tt.args.proofs
+				}
+				return treeRevealData
+			})
+
+			ut := &UtilsStruct{}
+			err := ut.VerifyReveal(client, account, 5, tt.args.commitData, secret)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("VerifyReveal() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			// A commitment which cannot be read is not reported as a mismatch with a zero commitment
+			if errors.Is(err, errCommitmentNotRead) != tt.wantNotRead {
+				t.Errorf("VerifyReveal() error = %v, want commitment not read %v", err, tt.wantNotRead)
+			}
+		})
+	}
+}
+
+func TestExecuteVerifyReveal(t *testing.T) {
+	var client *ethclient.Client
+	var flagSet *pflag.FlagSet
+	committedData := types.CommitFileData{
+		Epoch:                  5,
+		AssignedCollections:    map[int]bool{1: true},
+		SeqAllottedCollections: []*big.Int{big.NewInt(1)},
+		Leaves:                 []*big.Int{big.NewInt(0), big.NewInt(295050)},
+	}
+
+	type args struct {
+		configErr     error
+		epoch         uint32
+		epochErr      error
+		committedData types.CommitFileData
+		commitDataErr error
+		secretErr     error
+		verifyErr     error
+	}
+	tests := []struct {
+		name          string
+		args          args
+		expectedFatal bool
+	}{
+		{
+			name: "Test 1: When ExecuteVerifyReveal function executes successfully",
+			args: args{
+				epoch:         5,
+				committedData: committedData,
+			},
+			expectedFatal: false,
+		},
+		{
+			name: "Test 2: When there is an error in getting config",
+			args: args{
+				configErr: errors.New("config error"),
+			},
+			expectedFatal: true,
+		},
+		{
+			name: "Test 3: When there is an error in getting epoch",
+			args: args{
+				epochErr: errors.New("epoch error"),
+			},
+			expectedFatal: true,
+		},
+		{
+			name: "Test 4: When commit data file has the values of another epoch",
+			args: args{
+				epoch:         6,
+				committedData: committedData,
+			},
+			expectedFatal: true,
+		},
+		{
+			name: "Test 5: When commit data file cannot be read",
+			args: args{
+				epoch:         5,
+				commitDataErr: errors.New("no such file"),
+			},
+			expectedFatal: true,
+		},
+		{
+			name: "Test 6: When there is an error in calculating secret",
+			args: args{
+				epoch:         5,
+				committedData: committedData,
+				secretErr:     errors.New("secret error"),
+			},
+			expectedFatal: true,
+		},
+		{
+			name: "Test 7: When reveal would fail",
+			args: args{
+				epoch:         5,
+				committedData: committedData,
+				verifyErr:     errors.New("commitment of the commit data does not match the commitment on chain"),
+			},
+			expectedFatal: true,
+		},
+	}
+	defer func() { log.ExitFunc = nil }()
+	var fatal bool
+	log.ExitFunc = func(int) { fatal = true }
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			utilsMock := new(mocks.UtilsInterface)
+			cmdUtilsMock := new(mocks.UtilsCmdInterface)
+			flagSetUtilsMock := new(mocks.FlagSetInterface)
+
+			razorUtils = utilsMock
+			cmdUtils = cmdUtilsMock
+			flagSetUtils = flagSetUtilsMock
+
+			utilsMock.On("AssignLogFile", mock.AnythingOfType("*pflag.FlagSet"))
+			flagSetUtilsMock.On("GetStringAddress", mock.AnythingOfType("*pflag.FlagSet")).Return("0x000000000000000000000000000000000000dea1", nil)
+			cmdUtilsMock.On("GetConfigData").Return(types.Configurations{}, tt.args.configErr)
+			utilsMock.On("AssignPassword", mock.AnythingOfType("*pflag.FlagSet")).Return("test")
+			utilsMock.On("SetDataFilesPassword", mock.AnythingOfType("string"), false).Return(nil)
+			utilsMock.On("ConnectToClient", mock.AnythingOfType("string")).Return(client)
+			utilsMock.On("GetEpoch", mock.AnythingOfType("*ethclient.Client")).Return(tt.args.epoch, tt.args.epochErr)
+			utilsMock.On("GetCommitDataFileName", mock.AnythingOfType("string"), mock.AnythingOfType("uint32")).Return("/home/data_files/5/0x000000000000000000000000000000000000dea1_CommitData.json", nil)
+			utilsMock.On("GetLegacyCommitDataFileName", mock.AnythingOfType("string")).Return("/home/data_files/0x000000000000000000000000000000000000dea1_CommitData.json", nil)
+			utilsMock.On("ReadFromCommitJsonFile", mock.AnythingOfType("string")).Return(tt.args.committedData, tt.args.commitDataErr)
+			cmdUtilsMock.On("CalculateSecret", mock.Anything, mock.AnythingOfType("uint32")).Return([]byte{1}, tt.args.secretErr)
+			cmdUtilsMock.On("VerifyReveal", mock.AnythingOfType("*ethclient.Client"), mock.Anything, mock.AnythingOfType("uint32"), mock.Anything, mock.Anything).Return(tt.args.verifyErr)
+
+			ut := &UtilsStruct{}
+			fatal = false
+
+			ut.ExecuteVerifyReveal(flagSet)
+			if fatal != tt.expectedFatal {
+				t.Error("The ExecuteVerifyReveal function didn't execute as expected")
+			}
+		})
+	}
+}
@@ -14,6 +14,7 @@ import (
 	"razor/core"
 	"razor/core/types"
 	"razor/logger"
+	"razor/metrics"
 	"razor/pkg/bindings"
 	"razor/utils"
 	"strings"
@@ -67,6 +68,36 @@ func (*UtilsStruct) ExecuteVote(flagSet *pflag.FlagSet) {
 	}
 	client := razorUtils.ConnectToClient(config.Provider)
 
+	// Values are committed from assets.json, so voting does not start with a file that would be partly ignored, the content which is validated is the one that is applied
+	err = razorUtils.WatchAssetsFile(client)
+	utils.CheckError("Error in loading assets.json, run validateAssets to see every problem: ", err)
+
+	// Jobs are still down-weighted by the health they get from now on if their past health cannot be loaded
+	if err := razorUtils.LoadJobHealth(); err != nil {
+		log.Error("Error in loading health of jobs: ", err)
+	}
+	// Voting goes on without an audit trail if the history database cannot be opened
+	if err := razorUtils.OpenHistory(); err != nil {
+		log.Error("Error in opening history: ", err)
+	}
+
+	dataFilesRetention, err = flagSetUtils.GetUint32DataFilesRetention(flagSet)
+	utils.CheckError("Error in getting data files retention: ", err)
+
+	// Recordings are kept for as many epochs as data files, as they are replayed against them
+	recordResponses, err := flagSetUtils.GetBoolRecordResponses(flagSet)
+	utils.CheckError("Error in getting record responses status: ", err)
+	if recordResponses {
+		err = razorUtils.RecordResponses(dataFilesRetention)
+		utils.CheckError("Error in recording responses: ", err)
+	}
+
+	// Data files are read with the password of the account even when they are not encrypted from now on, in case they were before
+	encryptDataFiles, err := flagSetUtils.GetBoolEncryptDataFiles(flagSet)
+	utils.CheckError("Error in getting encrypt data files status: ", err)
+	err = razorUtils.SetDataFilesPassword(password, encryptDataFiles)
+	utils.CheckError("Error in setting password of data files: ", err)
+
 	account := types.Account{Address: address, Password: password}
 
 	cmdUtils.HandleExit()
@@ -116,21 +147,24 @@ func (*UtilsStruct) Vote(ctx context.Context, config types.Configurations, clien
 			}
 			if latestHeader.Number.Cmp(header.Number) != 0 {
 				header = latestHeader
-				cmdUtils.HandleBlock(client, account, latestHeader.Number, config, rogueData)
+				cmdUtils.HandleBlock(ctx, client, account, latestHeader.Number, config, rogueData)
 			}
 		}
 	}
 }
 
 var (
-	_commitData      types.CommitData
-	lastVerification uint32
-	blockConfirmed   uint32
-	disputeData      types.DisputeFileData
+	_commitData        types.CommitData
+	_commitDataEpoch   uint32
+	lastVerification   uint32
+	blockConfirmed     uint32
+	disputeData        types.DisputeFileData
+	dataFilesRetention uint32
+	lastPrunedEpoch    uint32
 )
 
 //This function handles the block
-func (*UtilsStruct) HandleBlock(client *ethclient.Client, account types.Account, blockNumber *big.Int, config types.Configurations, rogueData types.Rogue) {
+func (*UtilsStruct) HandleBlock(ctx context.Context, client *ethclient.Client, account types.Account, blockNumber *big.Int, config types.Configurations, rogueData types.Rogue) {
 	state, err := razorUtils.GetDelayedState(client, config.BufferPercent)
 	if err != nil {
 		log.Error("Error in getting state: ", err)
@@ -215,6 +249,24 @@ func (*UtilsStruct) HandleBlock(client *ethclient.Client, account types.Account,
 		osUtils.Exit(0)
 	}
 
+	// Medians of the previous epoch are kept whether or not this staker committed to any of their collections
+	if epoch > 0 {
+		razorUtils.RecordMediansHistory(client, epoch-1)
+	}
+
+	// Data files are pruned once an epoch whichever states the staker takes part in
+	if lastPrunedEpoch < epoch {
+		prunedEpochs, err := razorUtils.PruneDataFiles(epoch, dataFilesRetention)
+		if err != nil {
+			log.Error("Error in pruning data files: ", err)
+		} else {
+			lastPrunedEpoch = epoch
+			if len(prunedEpochs) > 0 {
+				log.Debugf("Removed the data files of epochs %v", prunedEpochs)
+			}
+		}
+	}
+
 	switch state {
 	case 0:
 		err := cmdUtils.InitiateCommit(client, config, account, epoch, stakerId, rogueData)
@@ -229,7 +281,7 @@ func (*UtilsStruct) HandleBlock(client *ethclient.Client, account types.Account,
 			break
 		}
 	case 2:
-		err := cmdUtils.InitiatePropose(client, config, account, epoch, staker, blockNumber, rogueData)
+		err := cmdUtils.InitiatePropose(ctx, client, config, account, epoch, staker, blockNumber, rogueData)
 		if err != nil {
 			log.Error(err)
 			break
@@ -310,7 +362,7 @@ func (*UtilsStruct) InitiateCommit(client *ethclient.Client, config types.Config
 		return err
 	}
 
-	seed := solsha3.SoliditySHA3([]string{"bytes32", "bytes32"}, []interface{}{"0x" + hex.EncodeToString(salt[:]), "0x" + hex.EncodeToString(secret)})
+	seed := calculateSeed(salt, secret)
 
 	commitData, err := cmdUtils.HandleCommitState(client, epoch, seed, rogueData)
 	if err != nil {
@@ -318,6 +370,7 @@ func (*UtilsStruct) InitiateCommit(client *ethclient.Client, config types.Config
 	}
 
 	_commitData = commitData
+	_commitDataEpoch = epoch
 
 	merkleTree := utils.MerkleInterface.CreateMerkle(commitData.Leaves)
 	commitTxn, err := cmdUtils.Commit(client, config, account, epoch, seed, utils.MerkleInterface.GetMerkleRoot(merkleTree))
@@ -332,7 +385,7 @@ func (*UtilsStruct) InitiateCommit(client *ethclient.Client, config types.Config
 	}
 
 	log.Debug("Saving committed data for recovery")
-	fileName, err := razorUtils.GetCommitDataFileName(account.Address)
+	fileName, err := razorUtils.GetCommitDataFileName(account.Address, epoch)
 	if err != nil {
 		return errors.New("Error in getting file name to save committed data: " + err.Error())
 	}
@@ -342,6 +395,7 @@ func (*UtilsStruct) InitiateCommit(client *ethclient.Client, config types.Config
 		return errors.New("Error in saving data to file" + fileName + ": " + err.Error())
 	}
 	log.Debug("Data saved!")
+	razorUtils.RecordLeavesHistory(client, epoch, "committed", commitData)
 	return nil
 }
 
@@ -362,36 +416,45 @@ func (*UtilsStruct) InitiateReveal(client *ethclient.Client, config types.Config
 	}
 	log.Debug("Epoch last revealed: ", lastReveal)
 
-	if _commitData.AssignedCollections == nil && _commitData.SeqAllottedCollections == nil && _commitData.Leaves == nil {
-		fileName, err := razorUtils.GetCommitDataFileName(account.Address)
+	// Committed data kept in memory is only revealed in the epoch it was committed in
+	if _commitDataEpoch != epoch || (_commitData.AssignedCollections == nil && _commitData.SeqAllottedCollections == nil && _commitData.Leaves == nil) {
+		committedDataFromFile, err := readCommitDataFile(account.Address, epoch)
 		if err != nil {
-			log.Error("Error in getting file name to save committed data: ", err)
+			log.Error("Error in getting committed data from file: ", err)
 			return err
 		}
-		committedDataFromFile, err := razorUtils.ReadFromCommitJsonFile(fileName)
-		if err != nil {
-			log.Errorf("Error in getting committed data from file %s: %t", fileName, err)
-			return err
-		}
-		if committedDataFromFile.Epoch != epoch {
-			log.Errorf("File %s doesn't contain latest committed data: %t", fileName, err)
-			return errors.New("commit data file doesn't contain latest committed data")
-		}
 		_commitData.AssignedCollections = committedDataFromFile.AssignedCollections
 		_commitData.SeqAllottedCollections = committedDataFromFile.SeqAllottedCollections
 		_commitData.Leaves = committedDataFromFile.Leaves
+		_commitDataEpoch = epoch
+	}
+
+	secret, err := cmdUtils.CalculateSecret(account, epoch)
+	if err != nil {
+		return err
 	}
+
 	if rogueData.IsRogue && utils.Contains(rogueData.RogueMode, "reveal") {
 		var rogueCommittedData []*big.Int
 		for i := 0; i < len(_commitData.Leaves); i++ {
 			rogueCommittedData = append(rogueCommittedData, razorUtils.GetRogueRandomValue(10000000))
 		}
 		_commitData.Leaves = rogueCommittedData
-	}
-
-	secret, err := cmdUtils.CalculateSecret(account, epoch)
-	if err != nil {
-		return err
+	} else if err := cmdUtils.VerifyReveal(client, account, epoch, _commitData, secret); errors.Is(err, errCommitmentNotRead) {
+		// The committed data is not recovered as it may match, the reveal is checked again on the next block
+		return errors.New("Reveal self-check error: " + err.Error())
+	} else if err != nil {
+		// A reveal which does not match the commitment on chain reverts, so the committed data is recovered or nothing is sent
+		log.Error("Committed data does not match the commitment on chain: ", err)
+		commitData, recoveredFrom, recoverErr := cmdUtils.RecoverCommitData(client, account, epoch, secret)
+		if recoverErr != nil {
+			metrics.RevealSelfCheckFailuresMetric.WithLabelValues("none").Inc()
+			log.Errorf("ALERT: Not revealing in epoch %d as no committed data matches the commitment on chain: %s", epoch, recoverErr)
+			return errors.New("Reveal self-check error: " + err.Error())
+		}
+		metrics.RevealSelfCheckFailuresMetric.WithLabelValues(recoveredFrom).Inc()
+		log.Warnf("Recovered the committed data of epoch %d from the %s", epoch, recoveredFrom)
+		_commitData = commitData
 	}
 	revealTxn, err := cmdUtils.Reveal(client, config, account, epoch, _commitData, secret)
 	if err != nil {
@@ -399,12 +462,13 @@ func (*UtilsStruct) InitiateReveal(client *ethclient.Client, config types.Config
 	}
 	if revealTxn != core.NilHash {
 		razorUtils.WaitForBlockCompletion(client, revealTxn.String())
+		razorUtils.RecordLeavesHistory(client, epoch, "revealed", _commitData)
 	}
 	return nil
 }
 
 //This function initiates the propose
-func (*UtilsStruct) InitiatePropose(client *ethclient.Client, config types.Configurations, account types.Account, epoch uint32, staker bindings.StructsStaker, blockNumber *big.Int, rogueData types.Rogue) error {
+func (*UtilsStruct) InitiatePropose(ctx context.Context, client *ethclient.Client, config types.Configurations, account types.Account, epoch uint32, staker bindings.StructsStaker, blockNumber *big.Int, rogueData types.Rogue) error {
 	lastProposal, err := cmdUtils.GetLastProposedEpoch(client, blockNumber, staker.Id)
 	if err != nil {
 		return errors.New("Error in fetching last proposal: " + err.Error())
@@ -422,7 +486,7 @@ func (*UtilsStruct) InitiatePropose(client *ethclient.Client, config types.Confi
 		return nil
 	}
 
-	proposeTxn, err := cmdUtils.Propose(client, config, account, staker, epoch, blockNumber, rogueData)
+	proposeTxn, err := cmdUtils.Propose(ctx, client, config, account, staker, epoch, blockNumber, rogueData)
 	if err != nil {
 		return errors.New("Propose error: " + err.Error())
 	}
@@ -537,11 +601,14 @@ func init() {
 	rootCmd.AddCommand(voteCmd)
 
 	var (
-		Address         string
-		Rogue           bool
-		RogueMode       []string
-		Password        string
-		AutoClaimBounty bool
+		Address            string
+		Rogue              bool
+		RogueMode          []string
+		Password           string
+		AutoClaimBounty    bool
+		RecordResponses    bool
+		EncryptDataFiles   bool
+		DataFilesRetention uint32
 	)
 
 	voteCmd.Flags().StringVarP(&Address, "address", "a", "", "address of the staker")
@@ -549,6 +616,9 @@ func init() {
 	voteCmd.Flags().StringSliceVarP(&RogueMode, "rogueMode", "", []string{}, "type of rogue mode")
 	voteCmd.Flags().StringVarP(&Password, "password", "", "", "password path of the staker to protect the keystore")
 	voteCmd.Flags().BoolVarP(&AutoClaimBounty, "autoClaimBounty", "", false, "auto claim bounty")
+	voteCmd.Flags().BoolVarP(&RecordResponses, "recordResponses", "", false, "record the responses of jobs of every epoch to replay them with replayCommit")
+	voteCmd.Flags().BoolVarP(&EncryptDataFiles, "encryptDataFiles", "", false, "encrypt the committed and proposed data saved for recovery with the password of the staker")
+	voteCmd.Flags().Uint32VarP(&DataFilesRetention, "dataFilesRetention", "", core.DataFilesRetention, "number of the latest epochs to keep the committed and proposed data and the recorded responses of, 0 keeps the data of every epoch")
 
 	addrErr := voteCmd.MarkFlagRequired("address")
 	utils.CheckError("Address error: ", addrErr)
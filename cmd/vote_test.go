@@ -1,8 +1,10 @@
 package cmd
 
 import (
+	"context"
 	"encoding/hex"
 	"errors"
+	"fmt"
 	"github.com/ethereum/go-ethereum/accounts/abi"
 	"github.com/ethereum/go-ethereum/common"
 	Types "github.com/ethereum/go-ethereum/core/types"
@@ -37,6 +39,13 @@ func TestExecuteVote(t *testing.T) {
 		rogueModeErr error
 		address      string
 		addressErr   error
+		watchErr     error
+		healthErr    error
+		historyErr   error
+		record       bool
+		recordErr    error
+		encrypt      bool
+		dataFilesErr error
 		voteErr      error
 	}
 	tests := []struct {
@@ -118,6 +127,91 @@ func TestExecuteVote(t *testing.T) {
 			},
 			expectedFatal: false,
 		},
+		{
+			name: "Test 7: When assets.json is invalid",
+			args: args{
+				config:      config,
+				password:    "test",
+				address:     "0x000000000000000000000000000000000000dea1",
+				rogueStatus: true,
+				rogueMode:   []string{"propose", "commit"},
+				watchErr:    errors.New("found 2 problems in assets.json"),
+			},
+			expectedFatal: true,
+		},
+		{
+			name: "Test 8: When there is an error in watching assets.json",
+			args: args{
+				config:      config,
+				password:    "test",
+				address:     "0x000000000000000000000000000000000000dea1",
+				rogueStatus: true,
+				rogueMode:   []string{"propose", "commit"},
+				watchErr:    errors.New("too many open files"),
+			},
+			expectedFatal: true,
+		},
+		{
+			name: "Test 9: When the health of jobs cannot be loaded",
+			args: args{
+				config:      config,
+				password:    "test",
+				address:     "0x000000000000000000000000000000000000dea1",
+				rogueStatus: true,
+				rogueMode:   []string{"propose", "commit"},
+				healthErr:   errors.New("invalid job health file"),
+			},
+			expectedFatal: false,
+		},
+		{
+			name: "Test 10: When responses are recorded",
+			args: args{
+				config:      config,
+				password:    "test",
+				address:     "0x000000000000000000000000000000000000dea1",
+				rogueStatus: true,
+				rogueMode:   []string{"propose", "commit"},
+				record:      true,
+			},
+			expectedFatal: false,
+		},
+		{
+			name: "Test 11: When responses cannot be recorded",
+			args: args{
+				config:      config,
+				password:    "test",
+				address:     "0x000000000000000000000000000000000000dea1",
+				rogueStatus: true,
+				rogueMode:   []string{"propose", "commit"},
+				record:      true,
+				recordErr:   errors.New("permission denied"),
+			},
+			expectedFatal: true,
+		},
+		{
+			name: "Test 12: When history cannot be opened",
+			args: args{
+				config:      config,
+				password:    "test",
+				address:     "0x000000000000000000000000000000000000dea1",
+				rogueStatus: true,
+				rogueMode:   []string{"propose", "commit"},
+				historyErr:  errors.New("resource temporarily unavailable"),
+			},
+			expectedFatal: false,
+		},
+		{
+			name: "Test 13: When data files are to be encrypted without a password",
+			args: args{
+				config:       config,
+				address:      "0x000000000000000000000000000000000000dea1",
+				rogueStatus:  true,
+				rogueMode:    []string{"propose", "commit"},
+				encrypt:      true,
+				dataFilesErr: errors.New("data files cannot be encrypted without a password"),
+			},
+			expectedFatal: true,
+		},
 	}
 
 	defer func() { log.ExitFunc = nil }()
@@ -144,6 +238,14 @@ func TestExecuteVote(t *testing.T) {
 			utilsMock.On("ConnectToClient", mock.AnythingOfType("string")).Return(client)
 			flagSetUtilsMock.On("GetBoolRogue", mock.AnythingOfType("*pflag.FlagSet")).Return(tt.args.rogueStatus, tt.args.rogueErr)
 			flagSetUtilsMock.On("GetStringSliceRogueMode", mock.AnythingOfType("*pflag.FlagSet")).Return(tt.args.rogueMode, tt.args.rogueModeErr)
+			utilsMock.On("WatchAssetsFile", mock.AnythingOfType("*ethclient.Client")).Return(tt.args.watchErr)
+			utilsMock.On("LoadJobHealth").Return(tt.args.healthErr)
+			utilsMock.On("OpenHistory").Return(tt.args.historyErr)
+			flagSetUtilsMock.On("GetBoolRecordResponses", mock.AnythingOfType("*pflag.FlagSet")).Return(tt.args.record, nil)
+			utilsMock.On("RecordResponses", mock.AnythingOfType("uint32")).Return(tt.args.recordErr)
+			flagSetUtilsMock.On("GetBoolEncryptDataFiles", mock.AnythingOfType("*pflag.FlagSet")).Return(tt.args.encrypt, nil)
+			flagSetUtilsMock.On("GetUint32DataFilesRetention", mock.AnythingOfType("*pflag.FlagSet")).Return(uint32(100), nil)
+			utilsMock.On("SetDataFilesPassword", mock.AnythingOfType("string"), mock.AnythingOfType("bool")).Return(tt.args.dataFilesErr)
 			cmdUtilsMock.On("HandleExit").Return()
 			cmdUtilsMock.On("Vote", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(tt.args.voteErr)
 			osMock.On("Exit", mock.AnythingOfType("int")).Return()
@@ -662,8 +764,9 @@ func TestInitiateCommit(t *testing.T) {
 			merkleInterface.On("GetMerkleRoot", mock.Anything).Return(tt.args.merkleRoot)
 			cmdUtilsMock.On("Commit", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(tt.args.commitTxn, tt.args.commitTxnErr)
 			utilsMock.On("WaitForBlockCompletion", mock.AnythingOfType("*ethclient.Client"), mock.AnythingOfType("string")).Return(tt.args.status)
-			utilsMock.On("GetCommitDataFileName", mock.AnythingOfType("string")).Return(tt.args.fileName, tt.args.fileNameErr)
+			utilsMock.On("GetCommitDataFileName", mock.AnythingOfType("string"), mock.AnythingOfType("uint32")).Return(tt.args.fileName, tt.args.fileNameErr)
 			utilsMock.On("SaveDataToCommitJsonFile", mock.Anything, mock.Anything, mock.Anything).Return(tt.args.saveErr)
+			utilsMock.On("RecordLeavesHistory", mock.AnythingOfType("*ethclient.Client"), mock.AnythingOfType("uint32"), "committed", mock.Anything)
 			ut := &UtilsStruct{}
 			if err := ut.InitiateCommit(client, config, account, tt.args.epoch, stakerId, rogueData); (err != nil) != tt.wantErr {
 				t.Errorf("InitiateCommit() error = %v, wantErr %v", err, tt.wantErr)
@@ -696,6 +799,11 @@ func TestInitiateReveal(t *testing.T) {
 		revealTxn                common.Hash
 		revealTxnErr             error
 		rogueData                types.Rogue
+		verifyErr                error
+		recoveredData            types.CommitData
+		recoverErr               error
+		commitDataInMemory       types.CommitData
+		commitDataEpoch          uint32
 	}
 	tests := []struct {
 		name    string
@@ -810,6 +918,61 @@ func TestInitiateReveal(t *testing.T) {
 			},
 			wantErr: false,
 		},
+		{
+			name: "Test 11: When committed data does not match the commitment on chain and is recovered",
+			args: args{
+				epoch:                 5,
+				lastReveal:            2,
+				fileName:              "",
+				committedDataFromFile: types.CommitFileData{Epoch: 5},
+				secret:                []byte{},
+				verifyErr:             errors.New("commitment of the commit data does not match the commitment on chain"),
+				recoveredData:         types.CommitData{Leaves: []*big.Int{big.NewInt(1)}},
+				revealTxn:             common.BigToHash(big.NewInt(1)),
+			},
+			wantErr: false,
+		},
+		{
+			name: "Test 12: When committed data does not match the commitment on chain and cannot be recovered",
+			args: args{
+				epoch:                 5,
+				lastReveal:            2,
+				fileName:              "",
+				committedDataFromFile: types.CommitFileData{Epoch: 5},
+				secret:                []byte{},
+				verifyErr:             errors.New("commitment of the commit data does not match the commitment on chain"),
+				recoverErr:            errors.New("no committed value of collection 1 is kept"),
+				revealTxn:             common.BigToHash(big.NewInt(1)),
+			},
+			wantErr: true,
+		},
+		{
+			name: "Test 14: When the commitment on chain cannot be read, the committed data is not recovered",
+			args: args{
+				epoch:                 5,
+				lastReveal:            2,
+				fileName:              "",
+				committedDataFromFile: types.CommitFileData{Epoch: 5},
+				secret:                []byte{},
+				verifyErr:             fmt.Errorf("%w: %s", errCommitmentNotRead, "connection refused"),
+				recoveredData:         types.CommitData{Leaves: []*big.Int{big.NewInt(1)}},
+				revealTxn:             common.BigToHash(big.NewInt(1)),
+			},
+			wantErr: true,
+		},
+		{
+			name: "Test 13: When committed data in memory is of the previous epoch and the commit data file of the epoch cannot be read",
+			args: args{
+				epoch:                    5,
+				lastReveal:               2,
+				commitDataInMemory:       types.CommitData{Leaves: []*big.Int{big.NewInt(1)}},
+				commitDataEpoch:          4,
+				committedDataFromFileErr: errors.New("no such file or directory"),
+				secret:                   []byte{},
+				revealTxn:                common.BigToHash(big.NewInt(1)),
+			},
+			wantErr: true,
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -819,14 +982,23 @@ func TestInitiateReveal(t *testing.T) {
 			razorUtils = utilsMock
 			cmdUtils = cmdUtilsMock
 
+			if tt.args.commitDataEpoch != 0 {
+				_commitData = tt.args.commitDataInMemory
+				_commitDataEpoch = tt.args.commitDataEpoch
+			}
+
 			utilsMock.On("GetEpochLastRevealed", mock.AnythingOfType("*ethclient.Client"), mock.AnythingOfType("uint32")).Return(tt.args.lastReveal, tt.args.lastRevealErr)
 			cmdUtilsMock.On("HandleRevealState", mock.AnythingOfType("*ethclient.Client"), mock.Anything, mock.AnythingOfType("uint32")).Return(tt.args.revealStateErr)
-			utilsMock.On("GetCommitDataFileName", mock.AnythingOfType("string")).Return(tt.args.fileName, tt.args.fileNameErr)
+			utilsMock.On("GetCommitDataFileName", mock.AnythingOfType("string"), mock.AnythingOfType("uint32")).Return(tt.args.fileName, tt.args.fileNameErr)
 			utilsMock.On("ReadFromCommitJsonFile", mock.Anything).Return(tt.args.committedDataFromFile, tt.args.committedDataFromFileErr)
+			utilsMock.On("GetLegacyCommitDataFileName", mock.AnythingOfType("string")).Return("/home/data_files/0x000000000000000000000000000000000000dea1_CommitData.json", nil)
 			utilsMock.On("GetRogueRandomValue", mock.AnythingOfType("int")).Return(randomNum)
 			cmdUtilsMock.On("CalculateSecret", mock.Anything, mock.Anything).Return(tt.args.secret, tt.args.secretErr)
+			cmdUtilsMock.On("VerifyReveal", mock.AnythingOfType("*ethclient.Client"), mock.Anything, tt.args.epoch, mock.Anything, mock.Anything).Return(tt.args.verifyErr)
+			cmdUtilsMock.On("RecoverCommitData", mock.AnythingOfType("*ethclient.Client"), mock.Anything, tt.args.epoch, mock.Anything).Return(tt.args.recoveredData, "commit data file", tt.args.recoverErr)
 			cmdUtilsMock.On("Reveal", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(tt.args.revealTxn, tt.args.revealTxnErr)
 			utilsMock.On("WaitForBlockCompletion", mock.AnythingOfType("*ethclient.Client"), mock.AnythingOfType("string")).Return(1)
+			utilsMock.On("RecordLeavesHistory", mock.AnythingOfType("*ethclient.Client"), mock.AnythingOfType("uint32"), "revealed", mock.Anything)
 			ut := &UtilsStruct{}
 			if err := ut.InitiateReveal(client, config, account, tt.args.epoch, staker, tt.args.rogueData); (err != nil) != tt.wantErr {
 				t.Errorf("InitiateReveal() error = %v, wantErr %v", err, tt.wantErr)
@@ -922,10 +1094,10 @@ func TestInitiatePropose(t *testing.T) {
 			cmdUtils = cmdUtilsMock
 			cmdUtilsMock.On("GetLastProposedEpoch", mock.AnythingOfType("*ethclient.Client"), mock.AnythingOfType("*big.Int"), mock.AnythingOfType("uint32")).Return(tt.args.lastProposal, tt.args.lastProposalErr)
 			utilsMock.On("GetEpochLastRevealed", mock.AnythingOfType("*ethclient.Client"), mock.AnythingOfType("uint32")).Return(tt.args.lastReveal, tt.args.lastRevealErr)
-			cmdUtilsMock.On("Propose", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(tt.args.proposeTxn, tt.args.proposeTxnErr)
+			cmdUtilsMock.On("Propose", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(tt.args.proposeTxn, tt.args.proposeTxnErr)
 			utilsMock.On("WaitForBlockCompletion", mock.AnythingOfType("*ethclient.Client"), mock.AnythingOfType("string")).Return(1)
 			ut := &UtilsStruct{}
-			if err := ut.InitiatePropose(client, config, account, tt.args.epoch, staker, blockNumber, rogueData); (err != nil) != tt.wantErr {
+			if err := ut.InitiatePropose(context.Background(), client, config, account, tt.args.epoch, staker, blockNumber, rogueData); (err != nil) != tt.wantErr {
 				t.Errorf("InitiatePropose() error = %v, wantErr %v", err, tt.wantErr)
 			}
 		})
@@ -970,6 +1142,8 @@ func TestHandleBlock(t *testing.T) {
 		lastVerification     uint32
 		isFlagPassed         bool
 		handleClaimBountyErr error
+		lastPrunedEpoch      uint32
+		pruneErr             error
 	}
 	tests := []struct {
 		name string
@@ -1305,6 +1479,40 @@ func TestHandleBlock(t *testing.T) {
 				config:           types.Configurations{WaitTime: 6},
 			},
 		},
+		{
+			name: "Test 24: When data files of old epochs cannot be pruned in propose state",
+			args: args{
+				state:          2,
+				epoch:          5,
+				stateName:      "propose",
+				stakerId:       1,
+				staker:         bindings.StructsStaker{Id: 1, Stake: big.NewInt(10000)},
+				ethBalance:     big.NewInt(1000),
+				minStakeAmount: big.NewInt(100),
+				actualStake:    big.NewFloat(10000),
+				actualBalance:  big.NewFloat(1000),
+				sRZRBalance:    big.NewInt(10000),
+				sRZRInEth:      big.NewFloat(100),
+				pruneErr:       errors.New("permission denied"),
+			},
+		},
+		{
+			name: "Test 25: When data files are already pruned in the epoch in dispute state",
+			args: args{
+				state:           3,
+				epoch:           5,
+				lastPrunedEpoch: 5,
+				stateName:       "dispute",
+				stakerId:        1,
+				staker:          bindings.StructsStaker{Id: 1, Stake: big.NewInt(10000)},
+				ethBalance:      big.NewInt(1000),
+				minStakeAmount:  big.NewInt(100),
+				actualStake:     big.NewFloat(10000),
+				actualBalance:   big.NewFloat(1000),
+				sRZRBalance:     big.NewInt(10000),
+				sRZRInEth:       big.NewFloat(100),
+			},
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -1329,12 +1537,14 @@ func TestHandleBlock(t *testing.T) {
 			utilsPkgMock.On("GetMinStakeAmount", mock.AnythingOfType("*ethclient.Client")).Return(tt.args.minStakeAmount, tt.args.minStakeAmountErr)
 			utilsMock.On("ConvertWeiToEth", mock.AnythingOfType("*big.Int")).Return(tt.args.actualStake, tt.args.actualStakeErr)
 			utilsMock.On("GetStakerSRZRBalance", mock.Anything, mock.Anything).Return(tt.args.sRZRBalance, tt.args.sRZRBalanceErr)
+			utilsMock.On("RecordMediansHistory", mock.AnythingOfType("*ethclient.Client"), mock.AnythingOfType("uint32"))
+			utilsMock.On("PruneDataFiles", tt.args.epoch, mock.AnythingOfType("uint32")).Return(nil, tt.args.pruneErr)
 			utilsPkgMock.On("GetStateName", mock.AnythingOfType("int64")).Return(tt.args.stateName)
 			cmdUtilsMock.On("AutoUnstakeAndWithdraw", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
 			osMock.On("Exit", mock.AnythingOfType("int")).Return()
 			cmdUtilsMock.On("InitiateCommit", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(tt.args.initiateCommitErr)
 			cmdUtilsMock.On("InitiateReveal", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(tt.args.initiateRevealErr)
-			cmdUtilsMock.On("InitiatePropose", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(tt.args.initiateProposeErr)
+			cmdUtilsMock.On("InitiatePropose", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(tt.args.initiateProposeErr)
 			cmdUtilsMock.On("HandleDispute", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(tt.args.handleDisputeErr)
 			utilsPkgMock.On("IsFlagPassed", mock.AnythingOfType("string")).Return(tt.args.isFlagPassed)
 			cmdUtilsMock.On("HandleClaimBounty", mock.Anything, mock.Anything, mock.Anything).Return(tt.args.handleClaimBountyErr)
@@ -1343,8 +1553,9 @@ func TestHandleBlock(t *testing.T) {
 			timeMock.On("Sleep", mock.Anything).Return()
 			utilsMock.On("WaitTillNextNSecs", mock.AnythingOfType("int32")).Return()
 			lastVerification = tt.args.lastVerification
+			lastPrunedEpoch = tt.args.lastPrunedEpoch
 			ut := &UtilsStruct{}
-			ut.HandleBlock(client, account, blockNumber, tt.args.config, rogueData)
+			ut.HandleBlock(context.Background(), client, account, blockNumber, tt.args.config, rogueData)
 		})
 	}
 }
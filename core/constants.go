@@ -2,6 +2,8 @@
 package core
 
 import (
+	"time"
+
 	"github.com/ethereum/go-ethereum/common"
 )
 
@@ -13,5 +15,53 @@ var MaxRetries uint = 8
 var NilHash = common.Hash{0x00}
 var BlockCompletionTimeout = 30
 var BatchSize = 1000
-var NumRoutines = 10
 var MaxIterations = 10000000
+var NumFetchRoutines = 16
+var MaxRequestsPerHost = 4
+var DefaultMADThreshold float64 = 3
+var DefaultDeviationPercentage float64 = 10
+var ResponseExcerptLength = 120
+var AssetsReloadDelay = 500 * time.Millisecond
+var DefaultPluginTimeout = 5 * time.Second
+var MaxPluginTimeout = 30 * time.Second
+var MaxPluginOutputSize = 1 << 20
+var PluginGRPCMethod = "/razor.plugin.v1.DataSource/Fetch"
+var DefaultJobTimeout = 60 * time.Second
+var MaxJobTimeout = 60 * time.Second
+var DefaultMaxResponseSize int64 = 10 << 20
+
+// Weight given to the latest epoch in the moving averages of the health of jobs
+var JobHealthDecay = 0.1
+var DefaultReputationMaxDeviation float64 = 5
+var DefaultReputationMaxFailureRate float64 = 50
+var DefaultReputationMinWeight float64 = 25
+var DefaultReputationMinJobs = 2
+var DefaultReputationMinSamples = 5
+
+// Version of the format data files are written in
+var DataFileVersion = 1
+
+// Cost parameters of scrypt, which derives the key of encrypted data files from the password of the account
+var DataFileScryptN = 1 << 15
+var DataFileScryptR = 8
+var DataFileScryptP = 1
+
+// Number of the latest epochs data files are kept for by default
+var DataFilesRetention uint32 = 100
+
+// Number of epochs of history the staleness of a collection without max staleness epochs is looked up in
+var StalenessLookbackEpochs uint32 = 10
+
+// Number of times per epoch the block of the previous epoch is fetched to keep its confirmed medians, until it is confirmed
+var MediansHistoryAttempts = 5
+
+// Time kept after the fetch deadline for aggregating the data of collections before the commit state timeout
+var FetchDeadlineReserve = 3 * time.Second
+
+// Selector types 0 and 1 are the ones supported by the contracts, others can only be used by jobs in assets.json
+var JSONSelectorType uint8 = 0
+var XHTMLSelectorType uint8 = 1
+var CSSSelectorType uint8 = 2
+var RegexSelectorType uint8 = 3
+var CSVSelectorType uint8 = 4
+var GJSONSelectorType uint8 = 5
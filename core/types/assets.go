@@ -2,8 +2,10 @@
 package types
 
 import (
+	"encoding/json"
 	"math/big"
 	"razor/pkg/bindings"
+	"time"
 )
 
 type Job struct {
@@ -51,8 +53,188 @@ type AssignedAsset struct {
 }
 
 type CustomJob struct {
-	URL      string `json:"URL"`
-	Selector string `json:"selector"`
-	Power    int8   `json:"power"`
-	Weight   uint8  `json:"weight"`
+	Name         string `json:"name"`
+	URL          string `json:"URL"`
+	Selector     string `json:"selector"`
+	SelectorType uint8  `json:"-"` // set from the name of selector type in assets.json
+	Power        int8   `json:"power"`
+	Weight       uint8  `json:"weight"`
+}
+
+type JobAuth struct {
+	Env    string `json:"env"`
+	File   string `json:"file"`
+	Header string `json:"header"`
+	Prefix string `json:"prefix"`
+	Query  string `json:"query"`
+}
+
+type JobRequest struct {
+	Method   string
+	Headers  map[string]string
+	Body     string
+	Auth     *JobAuth
+	Timeout  time.Duration // timeout of each attempt, core.DefaultJobTimeout is used if it is 0
+	Attempts uint          // core.MaxRetries is used if it is 0
+
+	// Proxy and client certificate override the ones of the http settings in assets.json
+	Proxy             string
+	ClientCertificate *ClientCertificate
+}
+
+type ClientCertificate struct {
+	CertFile string `json:"cert file"`
+	KeyFile  string `json:"key file"`
+}
+
+type AssetJob struct {
+	bindings.StructsJob
+	Request        JobRequest
+	VolumeSelector string
+	Expression     string              // set only for derived jobs
+	Inputs         map[string]AssetJob // jobs the expression of a derived job refers to, by name
+	Plugin         *PluginSource       // set only for jobs whose value comes from a plugin
+	Freshness      *JobFreshness       // set only for jobs whose responses have a timestamp
+}
+
+type JobFreshness struct {
+	TimestampSelector string
+	MaxAge            time.Duration
+}
+
+type PluginSource struct {
+	Type    string
+	Command string
+	Args    []string
+	Address string
+	URL     string
+	Timeout time.Duration
+	Params  map[string]interface{}
+}
+
+type JobData struct {
+	Job    AssetJob
+	Value  *big.Int
+	Volume *big.Rat
+}
+
+type AggregationReport struct {
+	Source   string    // jobs, secondary jobs or previous value
+	Method   string    // aggregation method the value is aggregated with
+	Power    int8      // power of the collection, which is the one from assets.json if it is set there
+	JobsData []JobData // data of the jobs the value is aggregated from, with the weights they are aggregated with
+}
+
+type OutlierFilter struct {
+	Method    string  `json:"method"`
+	Threshold float64 `json:"threshold"`
+	MinQuorum int     `json:"min quorum"`
+}
+
+// Bounds within which jobs of a collection are down-weighted or excluded by their health, percentages are between 0 and 100
+type Reputation struct {
+	MaxDeviation   float64  `json:"max deviation"`
+	MaxFailureRate float64  `json:"max failure rate"`
+	MinWeight      *float64 `json:"min weight"` // nil if it is not set, as 0 is a valid min weight
+	MinJobs        int      `json:"min jobs"`
+	MinSamples     int      `json:"min samples"`
+}
+
+// Bounds the value of a collection is checked against before it is committed, min and max are compared with the value as it is committed
+type SanityBounds struct {
+	Min       *float64 `json:"min"`
+	Max       *float64 `json:"max"`
+	MaxChange float64  `json:"max change"` // percentage of the previous median
+	Policy    string   `json:"policy"`
+}
+
+type CollectionAggregation struct {
+	Method         string  `json:"method"`
+	TrimPercentage float64 `json:"trim percentage"`
+}
+
+type CollectionFallback struct {
+	Chain              []string
+	MaxStalenessEpochs uint32
+	SecondaryJobs      []AssetJob
+}
+
+type AssetsConfig struct {
+	Assets AssetsCollections `json:"assets"`
+	HTTP   *HTTPConfig       `json:"http"`
+}
+
+type HTTPConfig struct {
+	Proxy             string             `json:"proxy"`
+	CAFiles           []string           `json:"ca files"`
+	ClientCertificate *ClientCertificate `json:"client certificate"`
+	MaxResponseSize   int64              `json:"max response size"`
+	ContentTypes      []string           `json:"content types"`
+}
+
+type AssetsCollections struct {
+	Collection map[string]CollectionConfig `json:"collection"`
+}
+
+// Integer fields are wider than the fields they are copied into, so that values out of range are reported by validation
+type CollectionConfig struct {
+	Power         int64                  `json:"power"`
+	OfficialJobs  map[string]JobConfig   `json:"official jobs"`
+	CustomJobs    []JobConfig            `json:"custom jobs"`
+	DerivedJobs   []DerivedJobConfig     `json:"derived jobs"`
+	OutlierFilter *OutlierFilter         `json:"outlier filter"`
+	Aggregation   *CollectionAggregation `json:"aggregation"`
+	Fallback      *FallbackConfig        `json:"fallback"`
+	Reputation    *Reputation            `json:"reputation"`
+	SanityBounds  *SanityBounds          `json:"sanity bounds"`
+}
+
+type JobConfig struct {
+	Name           string            `json:"name"`
+	URL            string            `json:"URL"`
+	Selector       string            `json:"selector"`
+	SelectorType   string            `json:"selector type"`
+	VolumeSelector string            `json:"volume selector"`
+	Power          int64             `json:"power"`
+	Weight         int64             `json:"weight"`
+	Method         string            `json:"method"`
+	Headers        map[string]string `json:"headers"`
+	Body           json.RawMessage   `json:"body"`
+	Auth           *JobAuth          `json:"auth"`
+	Plugin         *PluginConfig     `json:"plugin"`
+	Freshness      *FreshnessConfig  `json:"freshness"`
+	Timeout        float64           `json:"timeout"`
+	Retries        *int64            `json:"retries"`
+
+	Proxy             string             `json:"proxy"`
+	ClientCertificate *ClientCertificate `json:"client certificate"`
+}
+
+type DerivedJobConfig struct {
+	Name       string               `json:"name"`
+	Expression string               `json:"expression"`
+	Power      int64                `json:"power"`
+	Weight     int64                `json:"weight"`
+	Inputs     map[string]JobConfig `json:"inputs"`
+}
+
+type FallbackConfig struct {
+	Chain              []string    `json:"chain"`
+	MaxStalenessEpochs int64       `json:"max staleness epochs"`
+	SecondaryJobs      []JobConfig `json:"secondary jobs"`
+}
+
+type FreshnessConfig struct {
+	TimestampSelector string  `json:"timestamp selector"`
+	MaxAge            float64 `json:"max age"`
+}
+
+type PluginConfig struct {
+	Type    string                 `json:"type"`
+	Command string                 `json:"command"`
+	Args    []string               `json:"args"`
+	Address string                 `json:"address"`
+	URL     string                 `json:"URL"`
+	Timeout float64                `json:"timeout"`
+	Params  map[string]interface{} `json:"params"`
 }
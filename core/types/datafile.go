@@ -0,0 +1,38 @@
+//Package types include the different user defined items of possible different types in a single type
+package types
+
+import (
+	"encoding/json"
+	"time"
+)
+
+type DataFile struct {
+	Version    int                 `json:"version"`
+	Checksum   string              `json:"checksum,omitempty"`
+	Data       json.RawMessage     `json:"data,omitempty"`
+	Encryption *DataFileEncryption `json:"encryption,omitempty"`
+}
+
+type DataFileEncryption struct {
+	Cipher     string `json:"cipher"`
+	Kdf        string `json:"kdf"`
+	Salt       []byte `json:"salt"`
+	Nonce      []byte `json:"nonce"`
+	Ciphertext []byte `json:"ciphertext"`
+}
+
+type DataFileEntry struct {
+	Epoch   uint32    `json:"epoch,omitempty"`
+	Address string    `json:"address"`
+	Kind    string    `json:"kind"`
+	Path    string    `json:"path"`
+	Size    int64     `json:"size"`
+	ModTime time.Time `json:"modTime"`
+}
+
+type DataFileContent struct {
+	Epoch   uint32      `json:"epoch"`
+	Address string      `json:"address"`
+	Kind    string      `json:"kind"`
+	Data    interface{} `json:"data"`
+}
@@ -0,0 +1,17 @@
+//Package types include the different user defined items of possible different types in a single type
+package types
+
+type JobHealth struct {
+	Collection   string  `json:"collection"`
+	Job          string  `json:"job"`
+	Url          string  `json:"url"`
+	Epochs       uint32  `json:"epochs"`
+	Failures     uint32  `json:"failures"`
+	FailureRate  float64 `json:"failureRate"` // moving average over the epochs, in percent
+	Samples      uint32  `json:"samples"`
+	Deviation    float64 `json:"deviation"` // moving average of the deviation from the confirmed median, in percent
+	LastEpoch    uint32  `json:"lastEpoch"`
+	WeightFactor float64 `json:"weightFactor"`
+	Status       string  `json:"status"`
+	Reason       string  `json:"reason"`
+}
@@ -0,0 +1,12 @@
+//Package types include the different user defined items of possible different types in a single type
+package types
+
+import "math/big"
+
+type HistoryRecord struct {
+	CollectionId uint16   `json:"collectionId"`
+	Epoch        uint32   `json:"epoch"`
+	Kind         string   `json:"kind"` // job, aggregated, committed, revealed or median
+	Job          string   `json:"job,omitempty"`
+	Value        *big.Int `json:"value"`
+}
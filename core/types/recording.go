@@ -0,0 +1,33 @@
+//Package types include the different user defined items of possible different types in a single type
+package types
+
+import (
+	"math/big"
+	"net/http"
+	"razor/pkg/bindings"
+	"time"
+)
+
+type RecordedResponse struct {
+	Time       time.Time   `json:"time"`
+	Method     string      `json:"method"`
+	Url        string      `json:"url"`
+	Key        string      `json:"key"` // hash of the key of the request in the response cache
+	StatusCode int         `json:"statusCode"`
+	Status     string      `json:"status"`
+	Header     http.Header `json:"header"`
+	Body       []byte      `json:"body"`
+}
+
+type RecordedChain struct {
+	CollectionIds  map[uint16]uint16                     `json:"collectionIds"`  // ids of the collections by their index
+	Collections    map[uint16]bindings.StructsCollection `json:"collections"`    // collections by their id
+	Jobs           map[uint16]bindings.StructsJob        `json:"jobs"`           // jobs by their id
+	PreviousValues map[string]RecordedValue              `json:"previousValues"` // confirmed medians by epoch and collection id
+	JobHealth      map[string]JobHealth                  `json:"jobHealth"`      // health of the jobs of every collection before it was aggregated
+}
+
+type RecordedValue struct {
+	Value *big.Int `json:"value"`
+	Err   string   `json:"err,omitempty"`
+}
@@ -63,3 +63,10 @@ type ProposeFileData struct {
 	RevealedCollectionIds []uint16
 	RevealedDataMaps      *RevealedDataMaps
 }
+
+type CollectionResult struct {
+	Index        int
+	CollectionId uint16
+	Data         *big.Int
+	Err          error
+}
@@ -24,10 +24,82 @@ var (
 			"go_version":       runtime.Version(),
 		},
 	})
+
+	ResponseCacheHitsMetric = promauto.NewCounter(prometheus.CounterOpts{
+		Name: "response_cache_hits_total",
+		Help: "Number of job requests served from the per-epoch response cache",
+	})
+
+	ResponseCacheMissesMetric = promauto.NewCounter(prometheus.CounterOpts{
+		Name: "response_cache_misses_total",
+		Help: "Number of job requests fetched from the source",
+	})
+
+	AssetsReloadsMetric = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "assets_reloads_total",
+		Help: "Number of reloads of assets.json by result",
+	}, []string{"result"})
+
+	AssetsAppliedEpochMetric = promauto.NewGauge(prometheus.GaugeOpts{
+		Name: "assets_applied_epoch",
+		Help: "Epoch from which the current assets.json is used",
+	})
+
+	CollectionFallbacksMetric = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "collection_fallbacks_total",
+		Help: "Number of times a collection whose jobs have failed used a step of its fallback chain",
+	}, []string{"collection", "fallback"})
+
+	CollectionStaleEpochsMetric = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "collection_stale_epochs",
+		Help: "Number of epochs for which a collection has had no value from its jobs",
+	}, []string{"collection"})
+
+	CollectionStalenessExceededMetric = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "collection_staleness_exceeded_total",
+		Help: "Number of times the previous value of a collection was not used as it was staler than its max staleness",
+	}, []string{"collection"})
+
+	StaleResponsesMetric = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "stale_responses_total",
+		Help: "Number of responses of jobs which were not used as their timestamp was older than the max age of the job",
+	}, []string{"job"})
+
+	JobWeightFactorMetric = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "job_weight_factor",
+		Help: "Factor applied to the weight of a job by the reputation of its collection, 0 if the job is excluded",
+	}, []string{"collection", "job"})
+
+	JobDeviationMetric = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "job_deviation_percent",
+		Help: "Moving average of the deviation of the values of a job from the confirmed median of its collection",
+	}, []string{"collection", "job"})
+
+	SanityCheckFailuresMetric = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "sanity_check_failures_total",
+		Help: "Number of values of a collection which were outside its sanity bounds, by the policy which was applied",
+	}, []string{"collection", "policy"})
+
+	RevealSelfCheckFailuresMetric = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "reveal_self_check_failures_total",
+		Help: "Number of reveals whose committed data did not match the commitment on chain, by where the matching data was recovered from",
+	}, []string{"recovered_from"})
 )
 
 func init() {
 	//create a registry
 	RazorRegistry = prometheus.NewRegistry()
 	RazorRegistry.MustRegister(ClientMetric)
+	RazorRegistry.MustRegister(ResponseCacheHitsMetric)
+	RazorRegistry.MustRegister(ResponseCacheMissesMetric)
+	RazorRegistry.MustRegister(AssetsReloadsMetric)
+	RazorRegistry.MustRegister(AssetsAppliedEpochMetric)
+	RazorRegistry.MustRegister(CollectionFallbacksMetric)
+	RazorRegistry.MustRegister(CollectionStaleEpochsMetric)
+	RazorRegistry.MustRegister(CollectionStalenessExceededMetric)
+	RazorRegistry.MustRegister(StaleResponsesMetric)
+	RazorRegistry.MustRegister(JobWeightFactorMetric)
+	RazorRegistry.MustRegister(JobDeviationMetric)
+	RazorRegistry.MustRegister(SanityCheckFailuresMetric)
+	RazorRegistry.MustRegister(RevealSelfCheckFailuresMetric)
 }
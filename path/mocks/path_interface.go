@@ -9,20 +9,20 @@ type PathInterface struct {
 	mock.Mock
 }
 
-// GetCommitDataFileName provides a mock function with given fields: address
-func (_m *PathInterface) GetCommitDataFileName(address string) (string, error) {
-	ret := _m.Called(address)
+// GetCommitDataFileName provides a mock function with given fields: address, epoch
+func (_m *PathInterface) GetCommitDataFileName(address string, epoch uint32) (string, error) {
+	ret := _m.Called(address, epoch)
 
 	var r0 string
-	if rf, ok := ret.Get(0).(func(string) string); ok {
-		r0 = rf(address)
+	if rf, ok := ret.Get(0).(func(string, uint32) string); ok {
+		r0 = rf(address, epoch)
 	} else {
 		r0 = ret.Get(0).(string)
 	}
 
 	var r1 error
-	if rf, ok := ret.Get(1).(func(string) error); ok {
-		r1 = rf(address)
+	if rf, ok := ret.Get(1).(func(string, uint32) error); ok {
+		r1 = rf(address, epoch)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -51,6 +51,27 @@ func (_m *PathInterface) GetConfigFilePath() (string, error) {
 	return r0, r1
 }
 
+// GetDataFilesDir provides a mock function with given fields:
+func (_m *PathInterface) GetDataFilesDir() (string, error) {
+	ret := _m.Called()
+
+	var r0 string
+	if rf, ok := ret.Get(0).(func() string); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func() error); ok {
+		r1 = rf()
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // GetDefaultPath provides a mock function with given fields:
 func (_m *PathInterface) GetDefaultPath() (string, error) {
 	ret := _m.Called()
@@ -114,20 +135,20 @@ func (_m *PathInterface) GetJobFilePath() (string, error) {
 	return r0, r1
 }
 
-// GetLogFilePath provides a mock function with given fields: fileName
-func (_m *PathInterface) GetLogFilePath(fileName string) (string, error) {
-	ret := _m.Called(fileName)
+// GetLegacyCommitDataFileName provides a mock function with given fields: address
+func (_m *PathInterface) GetLegacyCommitDataFileName(address string) (string, error) {
+	ret := _m.Called(address)
 
 	var r0 string
 	if rf, ok := ret.Get(0).(func(string) string); ok {
-		r0 = rf(fileName)
+		r0 = rf(address)
 	} else {
 		r0 = ret.Get(0).(string)
 	}
 
 	var r1 error
 	if rf, ok := ret.Get(1).(func(string) error); ok {
-		r1 = rf(fileName)
+		r1 = rf(address)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -135,8 +156,8 @@ func (_m *PathInterface) GetLogFilePath(fileName string) (string, error) {
 	return r0, r1
 }
 
-// GetProposeDataFileName provides a mock function with given fields: address
-func (_m *PathInterface) GetProposeDataFileName(address string) (string, error) {
+// GetLegacyProposeDataFileName provides a mock function with given fields: address
+func (_m *PathInterface) GetLegacyProposeDataFileName(address string) (string, error) {
 	ret := _m.Called(address)
 
 	var r0 string
@@ -155,3 +176,45 @@ func (_m *PathInterface) GetProposeDataFileName(address string) (string, error)
 
 	return r0, r1
 }
+
+// GetLogFilePath provides a mock function with given fields: fileName
+func (_m *PathInterface) GetLogFilePath(fileName string) (string, error) {
+	ret := _m.Called(fileName)
+
+	var r0 string
+	if rf, ok := ret.Get(0).(func(string) string); ok {
+		r0 = rf(fileName)
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(fileName)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetProposeDataFileName provides a mock function with given fields: address, epoch
+func (_m *PathInterface) GetProposeDataFileName(address string, epoch uint32) (string, error) {
+	ret := _m.Called(address, epoch)
+
+	var r0 string
+	if rf, ok := ret.Get(0).(func(string, uint32) string); ok {
+		r0 = rf(address, epoch)
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(string, uint32) error); ok {
+		r1 = rf(address, epoch)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
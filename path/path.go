@@ -4,6 +4,7 @@ package path
 import (
 	"os"
 	pathPkg "path"
+	"strconv"
 )
 
 //This function returns the default path
@@ -56,8 +57,8 @@ func (PathUtils) GetJobFilePath() (string, error) {
 	return filePath, nil
 }
 
-//This function returns the file name of commit data file
-func (PathUtils) GetCommitDataFileName(address string) (string, error) {
+//This function returns the directory of data files
+func (PathUtils) GetDataFilesDir() (string, error) {
 	razorDir, err := PathUtilsInterface.GetDefaultPath()
 	if err != nil {
 		return "", err
@@ -69,38 +70,59 @@ func (PathUtils) GetCommitDataFileName(address string) (string, error) {
 			return "", mkdirErr
 		}
 	}
+	return dataFileDir, nil
+}
 
-	return pathPkg.Join(dataFileDir, address+"_CommitData.json"), nil
+//This function returns the directory of data files of the epoch, it is only created when a data file of the epoch is saved
+func getEpochDataFilesDir(epoch uint32) (string, error) {
+	dataFileDir, err := PathUtilsInterface.GetDataFilesDir()
+	if err != nil {
+		return "", err
+	}
+	return pathPkg.Join(dataFileDir, strconv.FormatUint(uint64(epoch), 10)), nil
 }
 
-//This function returns the file name of propose data file
-func (PathUtils) GetProposeDataFileName(address string) (string, error) {
-	razorDir, err := PathUtilsInterface.GetDefaultPath()
+//This function returns the file name of commit data file of the epoch
+func (PathUtils) GetCommitDataFileName(address string, epoch uint32) (string, error) {
+	epochDataFileDir, err := getEpochDataFilesDir(epoch)
 	if err != nil {
 		return "", err
 	}
-	dataFileDir := pathPkg.Join(razorDir, "data_files")
-	if _, err := OSUtilsInterface.Stat(dataFileDir); OSUtilsInterface.IsNotExist(err) {
-		mkdirErr := OSUtilsInterface.Mkdir(dataFileDir, 0700)
-		if mkdirErr != nil {
-			return "", mkdirErr
-		}
+	return pathPkg.Join(epochDataFileDir, address+"_CommitData.json"), nil
+}
+
+//This function returns the file name of propose data file of the epoch
+func (PathUtils) GetProposeDataFileName(address string, epoch uint32) (string, error) {
+	epochDataFileDir, err := getEpochDataFilesDir(epoch)
+	if err != nil {
+		return "", err
+	}
+	return pathPkg.Join(epochDataFileDir, address+"_proposedData.json"), nil
+}
+
+//This function returns the file name of commit data file kept in the directory of data files before the files were kept per epoch
+func (PathUtils) GetLegacyCommitDataFileName(address string) (string, error) {
+	dataFileDir, err := PathUtilsInterface.GetDataFilesDir()
+	if err != nil {
+		return "", err
+	}
+	return pathPkg.Join(dataFileDir, address+"_CommitData.json"), nil
+}
+
+//This function returns the file name of propose data file kept in the directory of data files before the files were kept per epoch
+func (PathUtils) GetLegacyProposeDataFileName(address string) (string, error) {
+	dataFileDir, err := PathUtilsInterface.GetDataFilesDir()
+	if err != nil {
+		return "", err
 	}
 	return pathPkg.Join(dataFileDir, address+"_proposedData.json"), nil
 }
 
 //This function returns the file name of dispute data file
 func (PathUtils) GetDisputeDataFileName(address string) (string, error) {
-	razorDir, err := PathUtilsInterface.GetDefaultPath()
+	dataFileDir, err := PathUtilsInterface.GetDataFilesDir()
 	if err != nil {
 		return "", err
 	}
-	dataFileDir := pathPkg.Join(razorDir, "data_files")
-	if _, err := OSUtilsInterface.Stat(dataFileDir); OSUtilsInterface.IsNotExist(err) {
-		mkdirErr := OSUtilsInterface.Mkdir(dataFileDir, 0700)
-		if mkdirErr != nil {
-			return "", mkdirErr
-		}
-	}
 	return pathPkg.Join(dataFileDir, address+"_disputeData.json"), nil
 }
@@ -17,8 +17,11 @@ type PathInterface interface {
 	GetLogFilePath(fileName string) (string, error)
 	GetConfigFilePath() (string, error)
 	GetJobFilePath() (string, error)
-	GetCommitDataFileName(address string) (string, error)
-	GetProposeDataFileName(address string) (string, error)
+	GetDataFilesDir() (string, error)
+	GetCommitDataFileName(address string, epoch uint32) (string, error)
+	GetProposeDataFileName(address string, epoch uint32) (string, error)
+	GetLegacyCommitDataFileName(address string) (string, error)
+	GetLegacyProposeDataFileName(address string) (string, error)
 	GetDisputeDataFileName(address string) (string, error)
 }
 
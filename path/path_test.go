@@ -268,11 +268,10 @@ func TestGetJobFilePath(t *testing.T) {
 	}
 }
 
-func TestGetCommitDataFileName(t *testing.T) {
+func TestGetDataFilesDir(t *testing.T) {
 	var fileInfo fs.FileInfo
 
 	type args struct {
-		address    string
 		path       string
 		pathErr    error
 		statErr    error
@@ -286,18 +285,16 @@ func TestGetCommitDataFileName(t *testing.T) {
 		wantErr error
 	}{
 		{
-			name: "Test 1: When GetCommitDataFileName() executes successfully",
+			name: "Test 1: When GetDataFilesDir() executes successfully",
 			args: args{
-				address: "0x000000000000000000000000000000000000dead",
-				path:    "/home",
+				path: "/home",
 			},
-			want:    "/home/data_files/0x000000000000000000000000000000000000dead_CommitData.json",
+			want:    "/home/data_files",
 			wantErr: nil,
 		},
 		{
 			name: "Test 2: When there is an error in getting path",
 			args: args{
-				address: "0x000000000000000000000000000000000000dead",
 				pathErr: errors.New("path error"),
 			},
 			want:    "",
@@ -306,18 +303,16 @@ func TestGetCommitDataFileName(t *testing.T) {
 		{
 			name: "Test 3: When data_files directory is not present and mkdir creates it",
 			args: args{
-				address:    "0x000000000000000000000000000000000000dead",
 				path:       "/home",
 				statErr:    errors.New("not exists"),
 				isNotExist: true,
 			},
-			want:    "/home/data_files/0x000000000000000000000000000000000000dead_CommitData.json",
+			want:    "/home/data_files",
 			wantErr: nil,
 		},
 		{
 			name: "Test 4: When data_files directory is not present and there is an error in creating new one",
 			args: args{
-				address:    "0x000000000000000000000000000000000000dead",
 				path:       "/home",
 				statErr:    errors.New("not exists"),
 				isNotExist: true,
@@ -342,33 +337,29 @@ func TestGetCommitDataFileName(t *testing.T) {
 			osMock.On("Mkdir", mock.Anything, mock.Anything).Return(tt.args.mkdirErr)
 
 			pa := &PathUtils{}
-			got, err := pa.GetCommitDataFileName(tt.args.address)
+			got, err := pa.GetDataFilesDir()
 			if got != tt.want {
-				t.Errorf("GetCommitDataFileName() got = %v, want %v", got, tt.want)
+				t.Errorf("GetDataFilesDir() got = %v, want %v", got, tt.want)
 			}
 			if err == nil || tt.wantErr == nil {
 				if err != tt.wantErr {
-					t.Errorf("Error for GetCommitDataFileName(), got = %v, want = %v", err, tt.wantErr)
+					t.Errorf("Error for GetDataFilesDir(), got = %v, want = %v", err, tt.wantErr)
 				}
 			} else {
 				if err.Error() != tt.wantErr.Error() {
-					t.Errorf("Error for GetCommitDataFileName(), got = %v, want = %v", err, tt.wantErr)
+					t.Errorf("Error for GetDataFilesDir(), got = %v, want = %v", err, tt.wantErr)
 				}
 			}
 		})
 	}
 }
 
-func TestGetProposeDataFileName(t *testing.T) {
-	var fileInfo fs.FileInfo
-
+func TestGetCommitDataFileName(t *testing.T) {
 	type args struct {
-		address    string
-		path       string
-		pathErr    error
-		statErr    error
-		isNotExist bool
-		mkdirErr   error
+		address string
+		epoch   uint32
+		path    string
+		pathErr error
 	}
 	tests := []struct {
 		name    string
@@ -377,63 +368,98 @@ func TestGetProposeDataFileName(t *testing.T) {
 		wantErr error
 	}{
 		{
-			name: "Test 1: When GetProposeDataFileName() executes successfully",
+			name: "Test 1: When GetCommitDataFileName() executes successfully",
 			args: args{
 				address: "0x000000000000000000000000000000000000dead",
-				path:    "/home",
+				epoch:   5,
+				path:    "/home/data_files",
 			},
-			want:    "/home/data_files/0x000000000000000000000000000000000000dead_proposedData.json",
+			want:    "/home/data_files/5/0x000000000000000000000000000000000000dead_CommitData.json",
 			wantErr: nil,
 		},
 		{
-			name: "Test 2: When there is an error in getting path",
+			name: "Test 2: When there is an error in getting the directory of data files",
 			args: args{
 				address: "0x000000000000000000000000000000000000dead",
+				epoch:   5,
 				pathErr: errors.New("path error"),
 			},
 			want:    "",
 			wantErr: errors.New("path error"),
 		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+
+			pathMock := new(mocks.PathInterface)
+
+			PathUtilsInterface = pathMock
+
+			pathMock.On("GetDataFilesDir").Return(tt.args.path, tt.args.pathErr)
+
+			pa := &PathUtils{}
+			got, err := pa.GetCommitDataFileName(tt.args.address, tt.args.epoch)
+			if got != tt.want {
+				t.Errorf("GetCommitDataFileName() got = %v, want %v", got, tt.want)
+			}
+			if err == nil || tt.wantErr == nil {
+				if err != tt.wantErr {
+					t.Errorf("Error for GetCommitDataFileName(), got = %v, want = %v", err, tt.wantErr)
+				}
+			} else {
+				if err.Error() != tt.wantErr.Error() {
+					t.Errorf("Error for GetCommitDataFileName(), got = %v, want = %v", err, tt.wantErr)
+				}
+			}
+		})
+	}
+}
+
+func TestGetProposeDataFileName(t *testing.T) {
+	type args struct {
+		address string
+		epoch   uint32
+		path    string
+		pathErr error
+	}
+	tests := []struct {
+		name    string
+		args    args
+		want    string
+		wantErr error
+	}{
 		{
-			name: "Test 3: When data_files directory is not present and mkdir creates it",
+			name: "Test 1: When GetProposeDataFileName() executes successfully",
 			args: args{
-				address:    "0x000000000000000000000000000000000000dead",
-				path:       "/home",
-				statErr:    errors.New("not exists"),
-				isNotExist: true,
+				address: "0x000000000000000000000000000000000000dead",
+				epoch:   5,
+				path:    "/home/data_files",
 			},
-			want:    "/home/data_files/0x000000000000000000000000000000000000dead_proposedData.json",
+			want:    "/home/data_files/5/0x000000000000000000000000000000000000dead_proposedData.json",
 			wantErr: nil,
 		},
 		{
-			name: "Test 4: When data_files directory is not present and there is an error in creating new one",
+			name: "Test 2: When there is an error in getting the directory of data files",
 			args: args{
-				address:    "0x000000000000000000000000000000000000dead",
-				path:       "/home",
-				statErr:    errors.New("not exists"),
-				isNotExist: true,
-				mkdirErr:   errors.New("mkdir error"),
+				address: "0x000000000000000000000000000000000000dead",
+				epoch:   5,
+				pathErr: errors.New("path error"),
 			},
 			want:    "",
-			wantErr: errors.New("mkdir error"),
+			wantErr: errors.New("path error"),
 		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 
 			pathMock := new(mocks.PathInterface)
-			osMock := new(mocks.OSInterface)
 
-			OSUtilsInterface = osMock
 			PathUtilsInterface = pathMock
 
-			pathMock.On("GetDefaultPath").Return(tt.args.path, tt.args.pathErr)
-			osMock.On("Stat", mock.AnythingOfType("string")).Return(fileInfo, tt.args.statErr)
-			osMock.On("IsNotExist", mock.Anything).Return(tt.args.isNotExist)
-			osMock.On("Mkdir", mock.Anything, mock.Anything).Return(tt.args.mkdirErr)
+			pathMock.On("GetDataFilesDir").Return(tt.args.path, tt.args.pathErr)
 
 			pa := &PathUtils{}
-			got, err := pa.GetProposeDataFileName(tt.args.address)
+			got, err := pa.GetProposeDataFileName(tt.args.address, tt.args.epoch)
 			if got != tt.want {
 				t.Errorf("GetProposeDataFileName() got = %v, want %v", got, tt.want)
 			}
@@ -451,15 +477,10 @@ func TestGetProposeDataFileName(t *testing.T) {
 }
 
 func TestGetDisputeDataFileName(t *testing.T) {
-	var fileInfo fs.FileInfo
-
 	type args struct {
-		address    string
-		path       string
-		pathErr    error
-		statErr    error
-		isNotExist bool
-		mkdirErr   error
+		address string
+		path    string
+		pathErr error
 	}
 	tests := []struct {
 		name    string
@@ -471,13 +492,13 @@ func TestGetDisputeDataFileName(t *testing.T) {
 			name: "Test 1: When GetDisputeDataFileName executes successfully",
 			args: args{
 				address: "0x000000000000000000000000000000000000dead",
-				path:    "/home",
+				path:    "/home/data_files",
 			},
 			want:    "/home/data_files/0x000000000000000000000000000000000000dead_disputeData.json",
 			wantErr: nil,
 		},
 		{
-			name: "Test 2: When there is an error in getting path",
+			name: "Test 2: When there is an error in getting the directory of data files",
 			args: args{
 				address: "0x000000000000000000000000000000000000dead",
 				pathErr: errors.New("path error"),
@@ -485,56 +506,141 @@ func TestGetDisputeDataFileName(t *testing.T) {
 			want:    "",
 			wantErr: errors.New("path error"),
 		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+
+			pathMock := new(mocks.PathInterface)
+			PathUtilsInterface = pathMock
+
+			pathMock.On("GetDataFilesDir").Return(tt.args.path, tt.args.pathErr)
+
+			pa := &PathUtils{}
+			got, err := pa.GetDisputeDataFileName(tt.args.address)
+			if got != tt.want {
+				t.Errorf("GetDisputeDataFileName got = %v, want %v", got, tt.want)
+			}
+			if err == nil || tt.wantErr == nil {
+				if err != tt.wantErr {
+					t.Errorf("Error for GetDisputeDataFileName, got = %v, want = %v", err, tt.wantErr)
+				}
+			} else {
+				if err.Error() != tt.wantErr.Error() {
+					t.Errorf("Error for GetDisputeDataFileName, got = %v, want = %v", err, tt.wantErr)
+				}
+			}
+		})
+	}
+}
+
+func TestGetLegacyCommitDataFileName(t *testing.T) {
+	type args struct {
+		address string
+		path    string
+		pathErr error
+	}
+	tests := []struct {
+		name    string
+		args    args
+		want    string
+		wantErr error
+	}{
 		{
-			name: "Test 3: When data_files directory is not present and mkdir creates it",
+			name: "Test 1: When GetLegacyCommitDataFileName executes successfully",
 			args: args{
-				address:    "0x000000000000000000000000000000000000dead",
-				path:       "/home",
-				statErr:    errors.New("not exists"),
-				isNotExist: true,
+				address: "0x000000000000000000000000000000000000dead",
+				path:    "/home/data_files",
 			},
-			want:    "/home/data_files/0x000000000000000000000000000000000000dead_disputeData.json",
+			want:    "/home/data_files/0x000000000000000000000000000000000000dead_CommitData.json",
 			wantErr: nil,
 		},
 		{
-			name: "Test 4: When data_files directory is not present and there is an error in creating new one",
+			name: "Test 2: When there is an error in getting the directory of data files",
 			args: args{
-				address:    "0x000000000000000000000000000000000000dead",
-				path:       "/home",
-				statErr:    errors.New("not exists"),
-				isNotExist: true,
-				mkdirErr:   errors.New("mkdir error"),
+				address: "0x000000000000000000000000000000000000dead",
+				pathErr: errors.New("path error"),
 			},
 			want:    "",
-			wantErr: errors.New("mkdir error"),
+			wantErr: errors.New("path error"),
 		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 
 			pathMock := new(mocks.PathInterface)
-			osMock := new(mocks.OSInterface)
+			PathUtilsInterface = pathMock
 
-			OSUtilsInterface = osMock
+			pathMock.On("GetDataFilesDir").Return(tt.args.path, tt.args.pathErr)
+
+			pa := &PathUtils{}
+			got, err := pa.GetLegacyCommitDataFileName(tt.args.address)
+			if got != tt.want {
+				t.Errorf("GetLegacyCommitDataFileName got = %v, want %v", got, tt.want)
+			}
+			if err == nil || tt.wantErr == nil {
+				if err != tt.wantErr {
+					t.Errorf("Error for GetLegacyCommitDataFileName, got = %v, want = %v", err, tt.wantErr)
+				}
+			} else {
+				if err.Error() != tt.wantErr.Error() {
+					t.Errorf("Error for GetLegacyCommitDataFileName, got = %v, want = %v", err, tt.wantErr)
+				}
+			}
+		})
+	}
+}
+
+func TestGetLegacyProposeDataFileName(t *testing.T) {
+	type args struct {
+		address string
+		path    string
+		pathErr error
+	}
+	tests := []struct {
+		name    string
+		args    args
+		want    string
+		wantErr error
+	}{
+		{
+			name: "Test 1: When GetLegacyProposeDataFileName executes successfully",
+			args: args{
+				address: "0x000000000000000000000000000000000000dead",
+				path:    "/home/data_files",
+			},
+			want:    "/home/data_files/0x000000000000000000000000000000000000dead_proposedData.json",
+			wantErr: nil,
+		},
+		{
+			name: "Test 2: When there is an error in getting the directory of data files",
+			args: args{
+				address: "0x000000000000000000000000000000000000dead",
+				pathErr: errors.New("path error"),
+			},
+			want:    "",
+			wantErr: errors.New("path error"),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+
+			pathMock := new(mocks.PathInterface)
 			PathUtilsInterface = pathMock
 
-			pathMock.On("GetDefaultPath").Return(tt.args.path, tt.args.pathErr)
-			osMock.On("Stat", mock.AnythingOfType("string")).Return(fileInfo, tt.args.statErr)
-			osMock.On("IsNotExist", mock.Anything).Return(tt.args.isNotExist)
-			osMock.On("Mkdir", mock.Anything, mock.Anything).Return(tt.args.mkdirErr)
+			pathMock.On("GetDataFilesDir").Return(tt.args.path, tt.args.pathErr)
 
 			pa := &PathUtils{}
-			got, err := pa.GetDisputeDataFileName(tt.args.address)
+			got, err := pa.GetLegacyProposeDataFileName(tt.args.address)
 			if got != tt.want {
-				t.Errorf("GetDisputeDataFileName got = %v, want %v", got, tt.want)
+				t.Errorf("GetLegacyProposeDataFileName got = %v, want %v", got, tt.want)
 			}
 			if err == nil || tt.wantErr == nil {
 				if err != tt.wantErr {
-					t.Errorf("Error for GetDisputeDataFileName, got = %v, want = %v", err, tt.wantErr)
+					t.Errorf("Error for GetLegacyProposeDataFileName, got = %v, want = %v", err, tt.wantErr)
 				}
 			} else {
 				if err.Error() != tt.wantErr.Error() {
-					t.Errorf("Error for GetDisputeDataFileName, got = %v, want = %v", err, tt.wantErr)
+					t.Errorf("Error for GetLegacyProposeDataFileName, got = %v, want = %v", err, tt.wantErr)
 				}
 			}
 		})
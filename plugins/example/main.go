@@ -0,0 +1,125 @@
+//Package main is an example of a plugin data source for custom jobs in assets.json
+//
+//The plugin returns the value and volume given in the params of the job. It can be run as an exec plugin,
+//which reads the request from stdin and writes its output to stdout, or serve the request as an HTTP or gRPC plugin:
+//
+//	go build -o example-plugin ./plugins/example
+//	echo '{"name": "eth", "params": {"value": "1234.56"}}' | ./example-plugin
+//	./example-plugin --http 127.0.0.1:8080
+//	./example-plugin --grpc 127.0.0.1:50051
+package main
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"flag"
+	"io"
+	"log"
+	"net/http"
+	"os"
+
+	"golang.org/x/net/http2"
+	"golang.org/x/net/http2/h2c"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/structpb"
+)
+
+const grpcMethod = "/razor.plugin.v1.DataSource/Fetch"
+
+func main() {
+	httpAddress := flag.String("http", "", "serve requests as an HTTP plugin on this address")
+	grpcAddress := flag.String("grpc", "", "serve requests as a gRPC plugin on this address")
+	flag.Parse()
+
+	switch {
+	case *httpAddress != "":
+		log.Fatal(http.ListenAndServe(*httpAddress, http.HandlerFunc(serveHTTP)))
+	case *grpcAddress != "":
+		mux := http.NewServeMux()
+		mux.HandleFunc(grpcMethod, serveGRPC)
+		// gRPC plugins are called over HTTP/2 without TLS
+		log.Fatal(http.ListenAndServe(*grpcAddress, h2c.NewHandler(mux, &http2.Server{})))
+	default:
+		var request map[string]interface{}
+		if err := json.NewDecoder(os.Stdin).Decode(&request); err != nil {
+			log.Fatal(err)
+		}
+		if err := json.NewEncoder(os.Stdout).Encode(fetch(request)); err != nil {
+			log.Fatal(err)
+		}
+	}
+}
+
+//This function returns the output for the request, the values are strings so that every digit is kept
+func fetch(request map[string]interface{}) map[string]interface{} {
+	params, _ := request["params"].(map[string]interface{})
+	value, ok := params["value"].(string)
+	if !ok {
+		return map[string]interface{}{"error": "value param is required"}
+	}
+	output := map[string]interface{}{"value": value}
+	if volume, ok := params["volume"].(string); ok {
+		output["volume"] = volume
+	}
+	return output
+}
+
+//This function answers the JSON request posted to the HTTP plugin
+func serveHTTP(w http.ResponseWriter, r *http.Request) {
+	var request map[string]interface{}
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(fetch(request))
+}
+
+//This function answers the google.protobuf.Struct request of the gRPC plugin
+func serveGRPC(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/grpc")
+	w.Header().Set("Trailer", "Grpc-Status, Grpc-Message")
+
+	request, err := readGRPCMessage(r.Body)
+	if err != nil {
+		w.Header().Set("Grpc-Status", "3")
+		w.Header().Set("Grpc-Message", err.Error())
+		return
+	}
+	output, err := structpb.NewStruct(fetch(request.AsMap()))
+	if err == nil {
+		var payload []byte
+		payload, err = proto.Marshal(output)
+		if err == nil {
+			// Every gRPC message is prefixed by a compression flag and its length
+			frame := make([]byte, 5+len(payload))
+			binary.BigEndian.PutUint32(frame[1:5], uint32(len(payload)))
+			copy(frame[5:], payload)
+			w.Write(frame)
+		}
+	}
+	if err != nil {
+		w.Header().Set("Grpc-Status", "13")
+		w.Header().Set("Grpc-Message", err.Error())
+		return
+	}
+	w.Header().Set("Grpc-Status", "0")
+}
+
+//This function reads a single uncompressed gRPC message
+func readGRPCMessage(body io.Reader) (*structpb.Struct, error) {
+	header := make([]byte, 5)
+	if _, err := io.ReadFull(body, header); err != nil {
+		return nil, err
+	}
+	if header[0] != 0 {
+		return nil, errors.New("compressed messages are not supported")
+	}
+	payload := make([]byte, binary.BigEndian.Uint32(header[1:]))
+	if _, err := io.ReadFull(body, payload); err != nil {
+		return nil, err
+	}
+	message := &structpb.Struct{}
+	return message, proto.Unmarshal(payload, message)
+}
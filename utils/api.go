@@ -2,9 +2,16 @@
 package utils
 
 import (
+	"context"
 	"errors"
+	"io"
 	"net/http"
+	"net/url"
+	"os"
 	"razor/core"
+	"razor/core/types"
+	"strings"
+	"sync"
 	"time"
 
 	"github.com/PaesslerAG/jsonpath"
@@ -12,54 +19,227 @@ import (
 	"github.com/gocolly/colly"
 )
 
-//This function returns the data from API
-func (*UtilsStruct) GetDataFromAPI(url string) ([]byte, error) {
-	client := http.Client{
-		Timeout: 60 * time.Second,
+var (
+	fetchSlots     = make(chan struct{}, core.NumFetchRoutines)
+	hostSlots      = make(map[string]chan struct{})
+	hostSlotsMutex sync.Mutex
+)
+
+//This function blocks until a request to the host of given url is allowed or the context is done and returns the function releasing it
+func AcquireFetchSlot(ctx context.Context, rawUrl string) (func(), error) {
+	host := rawUrl
+	if parsedUrl, err := url.Parse(rawUrl); err == nil && parsedUrl.Host != "" {
+		host = parsedUrl.Host
 	}
-	var body []byte
-	err := retry.Do(
-		func() error {
-			response, err := client.Get(url)
-			if err != nil {
-				return err
-			}
-			defer response.Body.Close()
-			if response.StatusCode != 200 {
-				return errors.New("unable to reach API")
-			}
-			body, err = IoutilInterface.ReadAll(response.Body)
-			if err != nil {
-				return err
+
+	hostSlotsMutex.Lock()
+	hostSlot, ok := hostSlots[host]
+	if !ok {
+		hostSlot = make(chan struct{}, core.MaxRequestsPerHost)
+		hostSlots[host] = hostSlot
+	}
+	hostSlotsMutex.Unlock()
+
+	select {
+	case hostSlot <- struct{}{}:
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+	select {
+	case fetchSlots <- struct{}{}:
+	case <-ctx.Done():
+		<-hostSlot
+		return nil, ctx.Err()
+	}
+	return func() {
+		<-fetchSlots
+		<-hostSlot
+	}, nil
+}
+
+//This function returns the secret of a job from the environment variable or file it references
+func GetJobAuthSecret(auth *types.JobAuth) (string, error) {
+	var secret string
+	if auth.Env != "" {
+		secret = os.Getenv(auth.Env)
+	} else if auth.File != "" {
+		data, err := os.ReadFile(auth.File)
+		if err != nil {
+			return "", err
+		}
+		secret = string(data)
+	} else {
+		return "", errors.New("auth requires either env or file to read the secret from")
+	}
+	secret = strings.TrimSpace(secret)
+	if secret == "" {
+		return "", errors.New("secret for job auth is empty")
+	}
+	return secret, nil
+}
+
+//This function builds the http request for a job using its method, headers, body and auth
+func BuildJobRequest(rawUrl string, request types.JobRequest) (*http.Request, error) {
+	method := strings.ToUpper(request.Method)
+	if method == "" {
+		method = http.MethodGet
+	}
+	var body io.Reader
+	if request.Body != "" {
+		body = strings.NewReader(request.Body)
+	}
+	httpRequest, err := http.NewRequest(method, rawUrl, body)
+	if err != nil {
+		return nil, err
+	}
+	if request.Body != "" {
+		httpRequest.Header.Set("Content-Type", "application/json")
+	}
+	for key, value := range request.Headers {
+		httpRequest.Header.Set(key, value)
+	}
+	if request.Auth != nil {
+		secret, err := GetJobAuthSecret(request.Auth)
+		if err != nil {
+			return nil, err
+		}
+		if request.Auth.Query != "" {
+			query := httpRequest.URL.Query()
+			query.Set(request.Auth.Query, secret)
+			httpRequest.URL.RawQuery = query.Encode()
+		} else {
+			header := request.Auth.Header
+			if header == "" {
+				header = "Authorization"
 			}
-			return nil
-		}, RetryInterface.RetryAttempts(core.MaxRetries))
+			httpRequest.Header.Set(header, request.Auth.Prefix+secret)
+		}
+	}
+	return httpRequest, nil
+}
+
+//This function returns the data from API
+func (*UtilsStruct) GetDataFromAPI(url string, request types.JobRequest) ([]byte, error) {
+	var body []byte
+	err := fetchWithRetry(request, func(ctx context.Context, timeout time.Duration) error {
+		client := http.Client{
+			Timeout:   timeout,
+			Transport: getJobTransport(request),
+		}
+		httpRequest, err := BuildJobRequest(url, request)
+		if err != nil {
+			return retry.Unrecoverable(err)
+		}
+		response, err := client.Do(httpRequest.WithContext(ctx))
+		if err != nil {
+			return redactRequestError(err, httpRequest.URL)
+		}
+		defer response.Body.Close()
+		if response.StatusCode != 200 {
+			return errors.New("unable to reach API")
+		}
+		body, err = IoutilInterface.ReadAll(response.Body)
+		if err != nil {
+			return err
+		}
+		return nil
+	})
 	if err != nil {
 		return nil, err
 	}
 	return body, nil
 }
 
+//This function replaces the url in the error of a failed request with its redacted url, as query values can hold API keys
+func redactRequestError(err error, requestURL *url.URL) error {
+	var urlErr *url.Error
+	if errors.As(err, &urlErr) {
+		return &url.Error{Op: urlErr.Op, URL: getRedactedURL(requestURL), Err: urlErr.Err}
+	}
+	return err
+}
+
+//This function calls fetch with the timeout of the job request until it succeeds or its attempts are used, no attempt is made after the fetch deadline
+func fetchWithRetry(request types.JobRequest, fetch func(ctx context.Context, timeout time.Duration) error) error {
+	timeout := request.Timeout
+	if timeout <= 0 {
+		timeout = core.DefaultJobTimeout
+	}
+	attempts := request.Attempts
+	if attempts == 0 {
+		attempts = core.MaxRetries
+	}
+	ctx := UtilsInterface.GetFetchContext()
+	return retry.Do(
+		func() error {
+			attemptTimeout, err := UtilsInterface.GetFetchAttemptTimeout(timeout)
+			if err != nil {
+				return retry.Unrecoverable(err)
+			}
+			return fetch(ctx, attemptTimeout)
+		}, RetryInterface.RetryAttempts(attempts), retry.Context(ctx))
+}
+
 //This function returns data from JSON file
 func (*UtilsStruct) GetDataFromJSON(jsonObject map[string]interface{}, selector string) (interface{}, error) {
+	return jsonpath.Get(getJSONPath(selector), jsonObject)
+}
+
+//This function returns the JSON path of the selector of a job
+func getJSONPath(selector string) string {
 	if selector[0] == '[' {
-		selector = "$" + selector
-	} else {
-		selector = "$." + selector
+		return "$" + selector
 	}
-	return jsonpath.Get(selector, jsonObject)
+	return "$." + selector
 }
 
-//This function returns data from XHTML
-func (*UtilsStruct) GetDataFromXHTML(url string, selector string) (string, error) {
-	c := colly.NewCollector()
-	var priceData string
-	c.OnXML(selector, func(e *colly.XMLElement) {
-		priceData = e.Text
+//This function returns data from XHTML, all selectors are selected from a single response
+func (*UtilsStruct) GetDataFromXHTML(url string, selectors []string, request types.JobRequest) ([]string, error) {
+	httpRequest, err := BuildJobRequest(url, request)
+	if err != nil {
+		return nil, err
+	}
+	var priceData []string
+	err = fetchWithRetry(request, func(ctx context.Context, timeout time.Duration) error {
+		select {
+		case <-ctx.Done():
+			return retry.Unrecoverable(ctx.Err())
+		default:
+		}
+		// Every attempt scrapes with a new collector, so that a failed attempt leaves no data behind
+		data := make([]string, len(selectors))
+		c := colly.NewCollector()
+		c.WithTransport(contextTransport{ctx: ctx, base: getJobTransport(request)})
+		c.SetRequestTimeout(timeout)
+		for i := range selectors {
+			index := i
+			c.OnXML(selectors[index], func(e *colly.XMLElement) {
+				data[index] = e.Text
+			})
+		}
+		var body io.Reader
+		if request.Body != "" {
+			body = strings.NewReader(request.Body)
+		}
+		if err := c.Request(httpRequest.Method, httpRequest.URL.String(), body, nil, httpRequest.Header); err != nil {
+			return redactRequestError(err, httpRequest.URL)
+		}
+		priceData = data
+		return nil
 	})
-	err := c.Visit(url)
 	if err != nil {
-		return "", err
+		return nil, err
 	}
 	return priceData, nil
 }
+
+//contextTransport sends the requests of a collector with the context of the fetch, as colly does not take one
+type contextTransport struct {
+	ctx  context.Context
+	base http.RoundTripper
+}
+
+//This function sends the request with the context of the transport
+func (transport contextTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	return transport.base.RoundTrip(req.WithContext(transport.ctx))
+}
@@ -1,14 +1,29 @@
 package utils
 
 import (
+	"context"
 	"errors"
+	"io"
+	"os"
+	"path/filepath"
 	"github.com/avast/retry-go"
 	"github.com/stretchr/testify/mock"
+	"razor/core"
+	"razor/core/types"
 	"razor/utils/mocks"
 	"reflect"
+	"sync"
+	"sync/atomic"
 	"testing"
+	"time"
 )
 
+//This function mocks a fetch deadline which is never reached, so sources are fetched with their own timeout
+func mockFetchDeadline(utilsMock *mocks.Utils) {
+	utilsMock.On("GetFetchContext").Return(context.Background())
+	utilsMock.On("GetFetchAttemptTimeout", mock.AnythingOfType("time.Duration")).Return(func(timeout time.Duration) time.Duration { return timeout }, nil)
+}
+
 func getAPIByteArray(index int) []byte {
 	apiData := [][]byte{
 		[]byte(`{
@@ -101,8 +116,9 @@ func TestGetDataFromAPI(t *testing.T) {
 
 			ioutilMock.On("ReadAll", mock.Anything).Return(tt.args.body, tt.args.bodyErr)
 			retryMock.On("RetryAttempts", mock.AnythingOfType("uint")).Return(retry.Attempts(1))
+			mockFetchDeadline(utilsMock)
 
-			got, err := utils.GetDataFromAPI(tt.args.url)
+			got, err := utils.GetDataFromAPI(tt.args.url, types.JobRequest{})
 			if (err != nil) != tt.wantErr {
 				t.Errorf("GetDataFromAPI() error = %v, wantErr %v", err, tt.wantErr)
 				return
@@ -249,21 +265,277 @@ func TestGetDataFromHTML(t *testing.T) {
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
+			retryMock := new(mocks.RetryUtils)
 			utilsMock := new(mocks.Utils)
 
 			optionsPackageStruct := OptionsPackageStruct{
+				RetryInterface: retryMock,
 				UtilsInterface: utilsMock,
 			}
 			utils := StartRazor(optionsPackageStruct)
 
-			got, err := utils.GetDataFromXHTML(tt.args.url, tt.args.selector)
+			retryMock.On("RetryAttempts", mock.AnythingOfType("uint")).Return(retry.Attempts(1))
+			mockFetchDeadline(utilsMock)
+
+			got, err := utils.GetDataFromXHTML(tt.args.url, []string{tt.args.selector}, types.JobRequest{})
 			if (err != nil) != tt.wantErr {
 				t.Errorf("GetDataFromHTML() error = %v, wantErr %v", err, tt.wantErr)
 				return
 			}
-			if got != tt.want {
+			if err == nil && got[0] != tt.want {
 				t.Errorf("GetDataFromHTML() got = %v, want %v", got, tt.want)
 			}
 		})
 	}
 }
+
+func TestAcquireFetchSlot(t *testing.T) {
+	tests := []struct {
+		name    string
+		urls    []string
+		wantMax int
+	}{
+		{
+			name:    "Test 1: When all the requests are made to the same host",
+			urls:    []string{"https://api.gemini.com/v1/pubticker/ethusd", "https://api.gemini.com/v1/pubticker/btcusd"},
+			wantMax: core.MaxRequestsPerHost,
+		},
+		{
+			name:    "Test 2: When the url cannot be parsed",
+			urls:    []string{"://api.gemini.com"},
+			wantMax: core.MaxRequestsPerHost,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var (
+				inFlight    int32
+				maxInFlight int32
+			)
+			wg := &sync.WaitGroup{}
+			for i := 0; i < 5*core.MaxRequestsPerHost; i++ {
+				wg.Add(1)
+				go func(index int) {
+					defer wg.Done()
+					release, err := AcquireFetchSlot(context.Background(), tt.urls[index%len(tt.urls)])
+					if err != nil {
+						t.Errorf("AcquireFetchSlot() error = %v", err)
+						return
+					}
+					defer release()
+					current := atomic.AddInt32(&inFlight, 1)
+					for {
+						max := atomic.LoadInt32(&maxInFlight)
+						if current <= max || atomic.CompareAndSwapInt32(&maxInFlight, max, current) {
+							break
+						}
+					}
+					time.Sleep(5 * time.Millisecond)
+					atomic.AddInt32(&inFlight, -1)
+				}(i)
+			}
+			wg.Wait()
+			if int(maxInFlight) > tt.wantMax {
+				t.Errorf("AcquireFetchSlot() allowed %d requests to the same host, want at most %d", maxInFlight, tt.wantMax)
+			}
+		})
+	}
+}
+
+func TestAcquireFetchSlotWithContextDone(t *testing.T) {
+	rawUrl := "https://api.kraken.com/0/public/Ticker?pair=ETHUSD"
+	var releases []func()
+	for i := 0; i < core.MaxRequestsPerHost; i++ {
+		release, err := AcquireFetchSlot(context.Background(), rawUrl)
+		if err != nil {
+			t.Fatalf("AcquireFetchSlot() error = %v", err)
+		}
+		releases = append(releases, release)
+	}
+
+	// The host is saturated, so the request waits until its context is done
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	if _, err := AcquireFetchSlot(ctx, rawUrl); err != context.DeadlineExceeded {
+		t.Errorf("AcquireFetchSlot() error = %v, want %v", err, context.DeadlineExceeded)
+	}
+
+	for _, release := range releases {
+		release()
+	}
+	release, err := AcquireFetchSlot(context.Background(), rawUrl)
+	if err != nil {
+		t.Fatalf("AcquireFetchSlot() error = %v after the host slots are released", err)
+	}
+	release()
+}
+
+func TestGetJobAuthSecret(t *testing.T) {
+	secretFile := filepath.Join(t.TempDir(), "secret")
+	if err := os.WriteFile(secretFile, []byte("file-secret\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	emptyFile := filepath.Join(t.TempDir(), "empty")
+	if err := os.WriteFile(emptyFile, []byte(" \n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("RAZOR_TEST_API_KEY", "env-secret")
+
+	tests := []struct {
+		name    string
+		auth    *types.JobAuth
+		want    string
+		wantErr bool
+	}{
+		{
+			name: "Test 1: When secret is read from environment variable",
+			auth: &types.JobAuth{Env: "RAZOR_TEST_API_KEY"},
+			want: "env-secret",
+		},
+		{
+			name: "Test 2: When secret is read from file",
+			auth: &types.JobAuth{File: secretFile},
+			want: "file-secret",
+		},
+		{
+			name:    "Test 3: When environment variable is not set",
+			auth:    &types.JobAuth{Env: "RAZOR_TEST_UNSET_API_KEY"},
+			wantErr: true,
+		},
+		{
+			name:    "Test 4: When secret file does not exist",
+			auth:    &types.JobAuth{File: filepath.Join(t.TempDir(), "missing")},
+			wantErr: true,
+		},
+		{
+			name:    "Test 5: When secret file is empty",
+			auth:    &types.JobAuth{File: emptyFile},
+			wantErr: true,
+		},
+		{
+			name:    "Test 6: When neither env nor file is provided",
+			auth:    &types.JobAuth{Header: "X-API-KEY"},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := GetJobAuthSecret(tt.auth)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("GetJobAuthSecret() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if got != tt.want {
+				t.Errorf("GetJobAuthSecret() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestBuildJobRequest(t *testing.T) {
+	t.Setenv("RAZOR_TEST_API_KEY", "secret")
+
+	tests := []struct {
+		name        string
+		url         string
+		request     types.JobRequest
+		wantMethod  string
+		wantURL     string
+		wantHeaders map[string]string
+		wantBody    string
+		wantErr     bool
+	}{
+		{
+			name:       "Test 1: When request is not customised",
+			url:        "https://api.gemini.com/v1/pubticker/ethusd",
+			wantMethod: "GET",
+			wantURL:    "https://api.gemini.com/v1/pubticker/ethusd",
+		},
+		{
+			name: "Test 2: When method, headers and body are provided",
+			url:  "https://api.example.com/price",
+			request: types.JobRequest{
+				Method:  "post",
+				Headers: map[string]string{"Accept": "application/json"},
+				Body:    `{"symbol":"ETH"}`,
+			},
+			wantMethod:  "POST",
+			wantURL:     "https://api.example.com/price",
+			wantHeaders: map[string]string{"Accept": "application/json", "Content-Type": "application/json"},
+			wantBody:    `{"symbol":"ETH"}`,
+		},
+		{
+			name: "Test 3: When auth secret is sent in default header with prefix",
+			url:  "https://api.example.com/price",
+			request: types.JobRequest{
+				Auth: &types.JobAuth{Env: "RAZOR_TEST_API_KEY", Prefix: "Bearer "},
+			},
+			wantMethod:  "GET",
+			wantURL:     "https://api.example.com/price",
+			wantHeaders: map[string]string{"Authorization": "Bearer secret"},
+		},
+		{
+			name: "Test 4: When auth secret is sent in custom header",
+			url:  "https://api.example.com/price",
+			request: types.JobRequest{
+				Auth: &types.JobAuth{Env: "RAZOR_TEST_API_KEY", Header: "X-API-KEY"},
+			},
+			wantMethod:  "GET",
+			wantURL:     "https://api.example.com/price",
+			wantHeaders: map[string]string{"X-API-KEY": "secret"},
+		},
+		{
+			name: "Test 5: When auth secret is sent as query parameter",
+			url:  "https://api.example.com/price?symbol=ETH",
+			request: types.JobRequest{
+				Auth: &types.JobAuth{Env: "RAZOR_TEST_API_KEY", Query: "apikey"},
+			},
+			wantMethod: "GET",
+			wantURL:    "https://api.example.com/price?apikey=secret&symbol=ETH",
+		},
+		{
+			name: "Test 6: When auth secret cannot be resolved",
+			url:  "https://api.example.com/price",
+			request: types.JobRequest{
+				Auth: &types.JobAuth{Env: "RAZOR_TEST_UNSET_API_KEY"},
+			},
+			wantErr: true,
+		},
+		{
+			name:    "Test 7: When method is invalid",
+			url:     "https://api.example.com/price",
+			request: types.JobRequest{Method: "GET POST"},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := BuildJobRequest(tt.url, tt.request)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("BuildJobRequest() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if err != nil {
+				return
+			}
+			if got.Method != tt.wantMethod {
+				t.Errorf("BuildJobRequest() method = %v, want %v", got.Method, tt.wantMethod)
+			}
+			if got.URL.String() != tt.wantURL {
+				t.Errorf("BuildJobRequest() url = %v, want %v", got.URL.String(), tt.wantURL)
+			}
+			for key, value := range tt.wantHeaders {
+				if got.Header.Get(key) != value {
+					t.Errorf("BuildJobRequest() header %s = %v, want %v", key, got.Header.Get(key), value)
+				}
+			}
+			var body []byte
+			if got.Body != nil {
+				body, _ = io.ReadAll(got.Body)
+			}
+			if string(body) != tt.wantBody {
+				t.Errorf("BuildJobRequest() body = %s, want %s", body, tt.wantBody)
+			}
+		})
+	}
+}
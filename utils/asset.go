@@ -2,22 +2,29 @@
 package utils
 
 import (
+	"bytes"
 	"encoding/hex"
 	"encoding/json"
 	"errors"
+	"fmt"
+	"math"
 	"math/big"
+	"net/url"
 	"os"
 	"razor/core"
 	"razor/core/types"
+	"razor/metrics"
 	"razor/path"
 	"razor/pkg/bindings"
 	"regexp"
 	"strconv"
+	"strings"
+	"sync"
+	"time"
 
 	"github.com/avast/retry-go"
 	"github.com/ethereum/go-ethereum/accounts/abi/bind"
 	"github.com/ethereum/go-ethereum/ethclient"
-	"github.com/tidwall/gjson"
 
 	solsha3 "github.com/miguelmota/go-solidity-sha3"
 )
@@ -147,6 +154,7 @@ func (*UtilsStruct) GetActiveCollectionIds(client *ethclient.Client) ([]uint16,
 
 //This function returns the aggregate data of collection
 func (*UtilsStruct) GetAggregatedDataOfCollection(client *ethclient.Client, collectionId uint16, epoch uint32) (*big.Int, error) {
+	setAggregationEpoch(epoch)
 	activeCollection, err := UtilsInterface.GetActiveCollection(client, collectionId)
 	if err != nil {
 		log.Error(err)
@@ -157,45 +165,284 @@ func (*UtilsStruct) GetAggregatedDataOfCollection(client *ethclient.Client, coll
 	if aggregationError != nil {
 		return nil, aggregationError
 	}
+	UtilsInterface.WriteHistory([]types.HistoryRecord{{CollectionId: collectionId, Epoch: epoch, Kind: "aggregated", Value: collectionData}})
 	return collectionData, nil
 }
 
+//This function sets the epoch the collections are aggregated in, so that the collections of an epoch are aggregated and recorded together
+func (*UtilsStruct) SetAggregationEpoch(epoch uint32) {
+	setAggregationEpoch(epoch)
+}
+
+//This function sets the epoch of everything which is kept per epoch while collections are aggregated
+func setAggregationEpoch(epoch uint32) {
+	// Responses of job requests are shared by all the collections aggregated in the same epoch
+	responseCache.SetEpoch(epoch)
+	// The health of jobs is written once per epoch, with the changes of the previous epoch
+	jobHealth.SetEpoch(epoch)
+	// A reloaded assets.json is only applied between epochs so every collection of an epoch uses the same file
+	assetsFile.SetEpoch(epoch)
+	responseArchive.SetEpoch(epoch, readAssetsData)
+}
+
 //This function aggregates the override jobs
 func (*UtilsStruct) Aggregate(client *ethclient.Client, previousEpoch uint32, collection bindings.StructsCollection) (*big.Int, error) {
-	var jobs []bindings.StructsJob
-	var overriddenJobIds []uint16
-
-	// Checks if assets.JSON file exists
-	assetsFilePath, err := path.PathUtilsInterface.GetJobFilePath()
+	assets, err := readAssetsFile()
 	if err != nil {
 		return nil, err
 	}
-	if _, err := path.OSUtilsInterface.Stat(assetsFilePath); !errors.Is(err, os.ErrNotExist) {
-		jsonFile, err := path.OSUtilsInterface.Open(assetsFilePath)
-		if err != nil {
-			return nil, err
+	// A collection which is not in assets.json has an empty config, so it is aggregated as it is on chain
+	collectionConfig, _ := assets.Collection(collection.Name)
+	if collectionConfig.Power != 0 {
+		if collectionConfig.Power < math.MinInt8 || collectionConfig.Power > math.MaxInt8 {
+			return nil, fmt.Errorf("power %d of collection %s is not between %d and %d", collectionConfig.Power, collection.Name, math.MinInt8, math.MaxInt8)
 		}
-		defer jsonFile.Close()
+		collection.Power = int8(collectionConfig.Power)
+	}
+	fallback := GetFallbackFromConfig(collection.Name, collectionConfig.Fallback)
 
-		data, err := IoutilInterface.ReadAll(jsonFile)
+	jobs := getJobsOfCollection(client, collection, assets)
+	if len(jobs) == 0 {
+		return nil, errors.New("no jobs present in the collection")
+	}
+
+	if chain := UtilsInterface.GetReplayedChain(); chain != nil {
+		// The pending values of jobs were compared with their median when the epoch was recorded
+		restoreCollectionState(chain, collection)
+	} else {
+		UtilsInterface.SettleJobHealth(client, previousEpoch, collection.Name, collection.Id)
+		UtilsInterface.RecordCollectionState(collection)
+	}
+	jobsData, err := getJobsDataOfCollection(collection, previousEpoch+1, jobs, collectionConfig.OutlierFilter, collectionConfig.Reputation)
+	if err != nil || len(jobsData) == 0 {
+		return aggregateWithFallback(client, previousEpoch, collection, fallback, collectionConfig.OutlierFilter, collectionConfig.Reputation, collectionConfig.Aggregation)
+	}
+	metrics.CollectionStaleEpochsMetric.WithLabelValues(collection.Name).Set(0)
+	return aggregateJobsData(collection, "jobs", jobsData, collectionConfig.Aggregation)
+}
+
+//This function returns the data of the jobs which is left after the reputation and outlier filter of the collection, the health and values of the jobs are recorded for the epoch
+func getJobsDataOfCollection(collection bindings.StructsCollection, epoch uint32, jobs []types.AssetJob, outlierFilter *types.OutlierFilter, reputation *types.Reputation) ([]types.JobData, error) {
+	jobsData, err := UtilsInterface.GetDataToCommitFromJobs(jobs)
+	if err != nil {
+		return jobsData, err
+	}
+	UtilsInterface.RecordJobHealth(collection.Name, epoch, jobs, jobsData)
+	recordJobValuesHistory(collection.Id, epoch, jobsData)
+	if reputation != nil {
+		jobsData = UtilsInterface.ApplyJobReputation(collection.Name, jobsData, *reputation)
+	}
+	if outlierFilter != nil {
+		jobsData, err = FilterOutliers(collection.Name, jobsData, *outlierFilter)
 		if err != nil {
-			return nil, err
+			log.Error(err)
 		}
-		dataString := string(data)
+	}
+	return jobsData, err
+}
 
-		powerFromJSONFile := gjson.Get(dataString, "assets.collection."+collection.Name+".power").Int()
-		if powerFromJSONFile != 0 {
-			collection.Power = int8(powerFromJSONFile)
-		}
+//This function aggregates the data of the jobs with the aggregation method from assets.json, or else the one of the collection, and reports how the collection is aggregated
+func aggregateJobsData(collection bindings.StructsCollection, source string, jobsData []types.JobData, aggregation *types.CollectionAggregation) (*big.Int, error) {
+	var (
+		value  *big.Int
+		err    error
+		method string
+	)
+	// Aggregation method from assets.json overrides the one of the collection on chain for the values we commit
+	if aggregation != nil {
+		method = strings.ToLower(aggregation.Method)
+		value, err = performLocalAggregation(jobsData, *aggregation)
+	} else {
+		method = getAggregationMethodName(collection.AggregationMethod)
+		dataToCommit, weight := GetValuesAndWeightsFromJobsData(jobsData)
+		value, err = performAggregation(dataToCommit, weight, collection.AggregationMethod)
+	}
+	if err != nil {
+		return nil, err
+	}
+	aggregationReports.Set(collection.Id, types.AggregationReport{Source: source, Method: method, Power: collection.Power, JobsData: jobsData})
+	return value, nil
+}
+
+//This function returns the name of the aggregation method of a collection on chain
+func getAggregationMethodName(aggregationMethod uint32) string {
+	// convention is 1 for median and 2 for mean
+	switch aggregationMethod {
+	case 1:
+		return "median"
+	case 2:
+		return "mean"
+	}
+	return strconv.Itoa(int(aggregationMethod))
+}
+
+//AggregationReports keeps how the value of every collection was last aggregated
+type AggregationReports struct {
+	mutex   sync.Mutex
+	reports map[uint16]types.AggregationReport
+}
+
+var aggregationReports = &AggregationReports{reports: make(map[uint16]types.AggregationReport)}
+
+//This function sets how the value of the collection was last aggregated
+func (reports *AggregationReports) Set(collectionId uint16, report types.AggregationReport) {
+	reports.mutex.Lock()
+	defer reports.mutex.Unlock()
+	reports.reports[collectionId] = report
+}
+
+//This function returns how the value of the collection was last aggregated, if it was
+func (*UtilsStruct) GetAggregationReport(collectionId uint16) (types.AggregationReport, bool) {
+	aggregationReports.mutex.Lock()
+	defer aggregationReports.mutex.Unlock()
+	report, ok := aggregationReports.reports[collectionId]
+	return report, ok
+}
+
+//This function returns the key the job is identified by in its collection
+func GetJobKey(job types.AssetJob) string {
+	return getJobHealthKey(job)
+}
+
+//This function returns the job as it can be logged, the values of the query of its URL are masked as they can be API keys
+func getJobLogName(job types.AssetJob) string {
+	if job.Id != 0 || job.Name != "" {
+		return getJobHealthKey(job)
+	}
+	jobURL, err := url.Parse(job.Url)
+	if err != nil {
+		return getSelectorTypeName(job.SelectorType) + " " + job.Selector
+	}
+	return getRedactedURL(jobURL) + " " + getSelectorTypeName(job.SelectorType) + " " + job.Selector
+}
+
+//This function returns the jobs of the collection which are used to aggregate its value, including the jobs from assets.json
+func (*UtilsStruct) GetCollectionJobs(client *ethclient.Client, collection bindings.StructsCollection) ([]types.AssetJob, error) {
+	assets, err := readAssetsFile()
+	if err != nil {
+		return nil, err
+	}
+	return getJobsOfCollection(client, collection, assets), nil
+}
+
+//assetsContent is the content of assets.json along with its decoded config, which is nil for an empty content
+type assetsContent struct {
+	Data   string
+	Config *types.AssetsConfig
+}
+
+// Content of assets.json last read from disk while it is not watched, it is only decoded again once it changes
+var assetsFromDisk struct {
+	mutex  sync.Mutex
+	loaded bool
+	assets assetsContent
+}
+
+//This function decodes the content of assets.json
+func decodeAssets(dataString string) (assetsContent, error) {
+	config, err := DecodeAssetsConfig(dataString)
+	if err != nil {
+		return assetsContent{}, err
+	}
+	return assetsContent{Data: dataString, Config: config}, nil
+}
+
+//This function returns the config of the collection in assets.json and whether it is there
+func (assets assetsContent) Collection(name string) (types.CollectionConfig, bool) {
+	if assets.Config == nil {
+		return types.CollectionConfig{}, false
+	}
+	collectionConfig, ok := assets.Config.Assets.Collection[name]
+	return collectionConfig, ok
+}
 
+//This function applies the http settings of the content, jobs are fetched with the http settings of the assets.json they are read from
+func (assets assetsContent) applyHTTPConfig() {
+	var config *types.HTTPConfig
+	if assets.Config != nil {
+		config = assets.Config.HTTP
+	}
+	if err := fetchTransport.Configure(config); err != nil {
+		log.Error("Error in applying http settings: ", err)
+	}
+}
+
+//This function returns the content of assets.json, while it is watched the content applied in the current epoch is returned
+func readAssetsFile() (assetsContent, error) {
+	if dataString, config, ok := UtilsInterface.GetAppliedAssets(); ok {
+		return assetsContent{Data: dataString, Config: config}, nil
+	}
+	dataString, err := readAssetsFileFromDisk()
+	if err != nil {
+		return assetsContent{}, err
+	}
+	assetsFromDisk.mutex.Lock()
+	defer assetsFromDisk.mutex.Unlock()
+	if assetsFromDisk.loaded && assetsFromDisk.assets.Data == dataString {
+		return assetsFromDisk.assets, nil
+	}
+	assets, err := decodeAssets(dataString)
+	if err != nil {
+		return assetsContent{}, err
+	}
+	assets.applyHTTPConfig()
+	assetsFromDisk.assets = assets
+	assetsFromDisk.loaded = true
+	return assets, nil
+}
+
+//This function returns the raw content of assets.json which jobs are read from
+func readAssetsData() (string, error) {
+	assets, err := readAssetsFile()
+	return assets.Data, err
+}
+
+//This function returns the content of assets.json, or an empty string if it does not exist
+func readAssetsFileFromDisk() (string, error) {
+	assetsFilePath, err := path.PathUtilsInterface.GetJobFilePath()
+	if err != nil {
+		return "", err
+	}
+	if _, err := path.OSUtilsInterface.Stat(assetsFilePath); errors.Is(err, os.ErrNotExist) {
+		return "", nil
+	}
+	jsonFile, err := path.OSUtilsInterface.Open(assetsFilePath)
+	if err != nil {
+		return "", err
+	}
+	defer jsonFile.Close()
+
+	data, err := IoutilInterface.ReadAll(jsonFile)
+	if err != nil {
+		return "", err
+	}
+	return string(data), nil
+}
+
+//This function returns the official jobs of the collection, overridden by assets.json, and the custom and derived jobs of the collection in assets.json
+func getJobsOfCollection(client *ethclient.Client, collection bindings.StructsCollection, assets assetsContent) []types.AssetJob {
+	var jobs []types.AssetJob
+	var overriddenJobIds []uint16
+	var derivedJobs []types.AssetJob
+
+	if collectionConfig, ok := assets.Collection(collection.Name); ok {
 		// Overriding the jobs from contracts with official jobs present in asset.go
-		overrideJobs, overriddenJobIdsFromJSONfile := UtilsInterface.HandleOfficialJobsFromJSONFile(client, collection, dataString)
+		overrideJobs, overriddenJobIdsFromJSONfile := UtilsInterface.HandleOfficialJobsFromJSONFile(client, collection, collectionConfig)
 		jobs = append(jobs, overrideJobs...)
 		overriddenJobIds = append(overriddenJobIds, overriddenJobIdsFromJSONfile...)
 
 		// Also adding custom jobs to jobs array
-		customJobs := GetCustomJobsFromJSONFile(collection.Name, dataString)
+		customJobs := GetCustomJobsFromConfig(collection.Name, collectionConfig)
 		jobs = append(jobs, customJobs...)
+
+		// Only vwap weighs the values of jobs with their volume, so the volume of a job is neither fetched nor required otherwise
+		if collectionConfig.Aggregation == nil || !strings.EqualFold(collectionConfig.Aggregation.Method, "vwap") {
+			for i := range jobs {
+				jobs[i].VolumeSelector = ""
+			}
+		}
+
+		derivedJobs = GetDerivedJobsFromConfig(collection.Name, collectionConfig)
 	}
 
 	for _, id := range collection.JobIDs {
@@ -207,27 +454,26 @@ func (*UtilsStruct) Aggregate(client *ethclient.Client, previousEpoch uint32, co
 				log.Errorf("Error in fetching job %d: %s", id, err)
 				continue
 			}
-			jobs = append(jobs, job)
+			jobs = append(jobs, types.AssetJob{StructsJob: job})
 		}
 	}
 
-	if len(jobs) == 0 {
-		return nil, errors.New("no jobs present in the collection")
+	// Derived jobs can refer to any other job of the collection by name, so they are resolved once all the jobs are known
+	if len(derivedJobs) != 0 {
+		jobs = append(jobs, ResolveDerivedJobs(collection.Name, derivedJobs, jobs)...)
 	}
-
-	dataToCommit, weight, err := UtilsInterface.GetDataToCommitFromJobs(jobs)
-	if err != nil || len(dataToCommit) == 0 {
-		prevCommitmentData, err := UtilsInterface.FetchPreviousValue(client, previousEpoch, collection.Id)
-		if err != nil {
-			return nil, err
-		}
-		return prevCommitmentData, nil
-	}
-	return performAggregation(dataToCommit, weight, collection.AggregationMethod)
+	return jobs
 }
 
 //This function returns the active job
 func (*UtilsStruct) GetActiveJob(client *ethclient.Client, jobId uint16) (bindings.StructsJob, error) {
+	if chain := UtilsInterface.GetReplayedChain(); chain != nil {
+		job, ok := chain.Jobs[jobId]
+		if !ok {
+			return bindings.StructsJob{}, fmt.Errorf("job %d is not recorded", jobId)
+		}
+		return job, nil
+	}
 	var (
 		job bindings.StructsJob
 		err error
@@ -244,14 +490,26 @@ func (*UtilsStruct) GetActiveJob(client *ethclient.Client, jobId uint16) (bindin
 	if err != nil {
 		return bindings.StructsJob{}, err
 	}
+	UtilsInterface.RecordChain(func(chain *types.RecordedChain) { chain.Jobs[jobId] = job })
 	return job, nil
 }
 
 //This function returns the active collection
 func (*UtilsStruct) GetActiveCollection(client *ethclient.Client, collectionId uint16) (bindings.StructsCollection, error) {
-	collection, err := UtilsInterface.GetCollection(client, collectionId)
-	if err != nil {
-		return bindings.StructsCollection{}, err
+	var collection bindings.StructsCollection
+	if chain := UtilsInterface.GetReplayedChain(); chain != nil {
+		recorded, ok := chain.Collections[collectionId]
+		if !ok {
+			return bindings.StructsCollection{}, fmt.Errorf("collection %d is not recorded", collectionId)
+		}
+		collection = recorded
+	} else {
+		var err error
+		collection, err = UtilsInterface.GetCollection(client, collectionId)
+		if err != nil {
+			return bindings.StructsCollection{}, err
+		}
+		UtilsInterface.RecordChain(func(chain *types.RecordedChain) { chain.Collections[collectionId] = collection })
 	}
 	if !collection.Active {
 		return bindings.StructsCollection{}, errors.New("collection inactive")
@@ -260,71 +518,227 @@ func (*UtilsStruct) GetActiveCollection(client *ethclient.Client, collectionId u
 }
 
 //This function returns the data which is used for commit from jobs
-func (*UtilsStruct) GetDataToCommitFromJobs(jobs []bindings.StructsJob) ([]*big.Int, []uint8, error) {
-	var (
-		data   []*big.Int
-		weight []uint8
-	)
-	for _, job := range jobs {
-		dataToAppend, err := UtilsInterface.GetDataToCommitFromJob(job)
-		if err != nil {
+func (*UtilsStruct) GetDataToCommitFromJobs(jobs []types.AssetJob) ([]types.JobData, error) {
+	var data []types.JobData
+	// Jobs are fetched concurrently, results are kept in the order of jobs so that aggregation stays deterministic
+	jobsData := make([]types.JobData, len(jobs))
+	routines := make(chan struct{}, core.NumFetchRoutines)
+	wg := &sync.WaitGroup{}
+	ctx := UtilsInterface.GetFetchContext()
+	for i := range jobs {
+		select {
+		case routines <- struct{}{}:
+		case <-ctx.Done():
+		}
+		// Jobs which are not started by the fetch deadline, or by the end of the state they are fetched for, are skipped
+		if ctx.Err() != nil {
+			log.Warnf("Skipping %d jobs which are not fetched by the fetch deadline", len(jobs)-i)
+			break
+		}
+		wg.Add(1)
+		go func(index int) {
+			defer func() {
+				<-routines
+				wg.Done()
+			}()
+			dataToAppend, volume, err := UtilsInterface.GetDataAndVolumeToCommitFromJob(jobs[index])
+			if err != nil {
+				return
+			}
+			jobsData[index] = types.JobData{Job: jobs[index], Value: dataToAppend, Volume: volume}
+		}(i)
+	}
+	wg.Wait()
+
+	for i := range jobsData {
+		if jobsData[i].Value == nil {
 			continue
 		}
-		data = append(data, dataToAppend)
-		weight = append(weight, job.Weight)
+		data = append(data, jobsData[i])
 	}
-	return data, weight, nil
+	return data, nil
 }
 
-//This function returns the data which is used for commit from job
-func (*UtilsStruct) GetDataToCommitFromJob(job bindings.StructsJob) (*big.Int, error) {
-	var parsedJSON map[string]interface{}
+//This function returns the values and weights of the jobs data
+func GetValuesAndWeightsFromJobsData(jobsData []types.JobData) ([]*big.Int, []uint8) {
 	var (
-		response []byte
-		apiErr   error
+		values  []*big.Int
+		weights []uint8
 	)
+	for _, jobData := range jobsData {
+		values = append(values, jobData.Value)
+		weights = append(weights, jobData.Job.Weight)
+	}
+	return values, weights
+}
 
-	// Fetch data from API with retry mechanism
-	var parsedData interface{}
-	if job.SelectorType == 0 {
-		apiErr = retry.Do(
-			func() error {
-				response, apiErr = UtilsInterface.GetDataFromAPI(job.Url)
-				if apiErr != nil {
-					log.Error("Error in fetching data from API: ", apiErr)
-					return apiErr
-				}
-				return nil
-			}, RetryInterface.RetryAttempts(core.MaxRetries))
-
-		err := json.Unmarshal(response, &parsedJSON)
+//This function returns the data which is used for commit from job
+func (*UtilsStruct) GetDataToCommitFromJob(job types.AssetJob) (*big.Int, error) {
+	datum, err := getDatum(job)
+	if err != nil {
+		return nil, err
+	}
+	return MultiplyWithPower(datum, job.Power), nil
+}
+
+//This function returns the data which is used for commit from job and the volume of job, which is nil if the job has no volume selector, a job whose volume is not found fails
+func (*UtilsStruct) GetDataAndVolumeToCommitFromJob(job types.AssetJob) (*big.Int, *big.Rat, error) {
+	datum, volume, err := getDatumAndVolume(job, true)
+	if err != nil {
+		return nil, nil, err
+	}
+	return MultiplyWithPower(datum, job.Power), volume, nil
+}
+
+//This function returns the value of job before its power is applied
+func (*UtilsStruct) GetDatumFromJob(job types.AssetJob) (*big.Rat, error) {
+	return getDatum(job)
+}
+
+//This function returns the value of job, computed from its expression for derived jobs or selected from its response otherwise
+func getDatum(job types.AssetJob) (*big.Rat, error) {
+	datum, _, err := getDatumAndVolume(job, false)
+	return datum, err
+}
+
+//This function returns the value of job and its volume if asked for, the value, volume and timestamp of a job are selected from a single response of the job
+func getDatumAndVolume(job types.AssetJob, withVolume bool) (*big.Rat, *big.Rat, error) {
+	if job.Expression != "" {
+		datum, err := getDatumFromDerivedJob(job)
+		return datum, nil, err
+	}
+	withVolume = withVolume && job.VolumeSelector != ""
+	selectors := []string{job.Selector}
+	if withVolume {
+		selectors = append(selectors, job.VolumeSelector)
+	}
+	if job.Freshness != nil {
+		selectors = append(selectors, job.Freshness.TimestampSelector)
+	}
+	dataPoints, err := getDataPointsFromJob(job, selectors)
+	if err != nil {
+		return nil, nil, err
+	}
+	if job.Freshness != nil {
+		if err := CheckJobFreshness(job, dataPoints[len(dataPoints)-1]); err != nil {
+			return nil, nil, err
+		}
+	}
+	datum, err := getDatumFromDataPoint(job, dataPoints[0])
+	if err != nil {
+		return nil, nil, err
+	}
+	if !withVolume {
+		return datum, nil, nil
+	}
+	// A job whose volume cannot be read is not used, as its value would be weighted without it
+	volume, err := getDatumFromDataPoint(job, dataPoints[1])
+	if err != nil {
+		log.Errorf("Error in fetching volume of job %s: %s", getJobLogName(job), err)
+		return nil, nil, err
+	}
+	return datum, volume, nil
+}
+
+//This function returns the response of job, which is fetched with the timeout and retries of the job
+func getResponseFromJob(job types.AssetJob) ([]byte, error) {
+	response, err := UtilsInterface.GetDataFromAPI(job.Url, job.Request)
+	if err != nil {
+		log.Error("Error in fetching data from API: ", err)
+		return nil, err
+	}
+	return response, nil
+}
+
+//This function returns the number of a value selected from the response of job
+func getDatumFromDataPoint(job types.AssetJob, parsedData interface{}) (*big.Rat, error) {
+	if dataPoint, ok := parsedData.(string); ok && job.Plugin == nil && job.SelectorType != core.JSONSelectorType && job.SelectorType != core.GJSONSelectorType {
+		// remove "," and currency symbols from text values
+		parsedData = regexp.MustCompile(`[\p{Sc},]`).ReplaceAllString(dataPoint, "")
+	}
+
+	datum, err := UtilsInterface.ConvertToNumber(parsedData)
+	if err != nil {
+		log.Error("Result is not a number")
+		return nil, err
+	}
+	return datum, nil
+}
+
+//This function returns the values selected by selectors from a single response of job as they are in the response
+func getDataPointsFromJob(job types.AssetJob, selectors []string) ([]interface{}, error) {
+	if job.Plugin != nil {
+		return getDataPointsFromPlugin(job, selectors)
+	}
+	var parsedJSON map[string]interface{}
+	dataPoints := make([]interface{}, len(selectors))
+
+	switch job.SelectorType {
+	case core.JSONSelectorType:
+		response, err := getResponseFromJob(job)
 		if err != nil {
-			log.Error("Error in parsing data from API: ", err)
 			return nil, err
 		}
-		parsedData, err = UtilsInterface.GetDataFromJSON(parsedJSON, job.Selector)
+
+		// Numbers are kept as json.Number so that they are converted without losing precision
+		decoder := json.NewDecoder(bytes.NewReader(response))
+		decoder.UseNumber()
+		err = decoder.Decode(&parsedJSON)
 		if err != nil {
-			log.Error("Error in fetching value from parsed data: ", err)
+			log.Error("Error in parsing data from API: ", err)
 			return nil, err
 		}
-	} else {
-		//TODO: Add retry here.
-		dataPoint, err := UtilsInterface.GetDataFromXHTML(job.Url, job.Selector)
+		for index, selector := range selectors {
+			parsedData, err := UtilsInterface.GetDataFromJSON(parsedJSON, selector)
+			if err != nil {
+				log.Error("Error in fetching value from parsed data: ", err)
+				return nil, err
+			}
+			dataPoints[index] = parsedData
+		}
+		return dataPoints, nil
+	case core.XHTMLSelectorType, core.CSSSelectorType:
+		var (
+			data []string
+			err  error
+		)
+		if job.SelectorType == core.XHTMLSelectorType {
+			data, err = UtilsInterface.GetDataFromXHTML(job.Url, selectors, job.Request)
+		} else {
+			data, err = UtilsInterface.GetDataFromHTML(job.Url, selectors, job.Request)
+		}
 		if err != nil {
 			log.Error("Error in fetching value from parsed XHTML: ", err)
 			return nil, err
 		}
-		// remove "," and currency symbols
-		parsedData = regexp.MustCompile(`[\p{Sc},]`).ReplaceAllString(dataPoint, "")
-	}
-
-	datum, err := UtilsInterface.ConvertToNumber(parsedData)
-	if err != nil {
-		log.Error("Result is not a number")
-		return nil, err
+		for index := range selectors {
+			dataPoints[index] = data[index]
+		}
+		return dataPoints, nil
+	case core.RegexSelectorType, core.CSVSelectorType, core.GJSONSelectorType:
+		response, err := getResponseFromJob(job)
+		if err != nil {
+			return nil, err
+		}
+		for index, selector := range selectors {
+			var dataPoint string
+			switch job.SelectorType {
+			case core.RegexSelectorType:
+				dataPoint, err = GetDataFromRegex(response, selector)
+			case core.CSVSelectorType:
+				dataPoint, err = GetDataFromCSV(response, selector)
+			default:
+				dataPoint, err = GetDataFromGJSON(response, selector)
+			}
+			if err != nil {
+				log.Error("Error in fetching value from response: ", err)
+				return nil, err
+			}
+			dataPoints[index] = dataPoint
+		}
+		return dataPoints, nil
 	}
-
-	return MultiplyWithPower(datum, job.Power), err
+	return nil, errors.New("invalid selector type " + strconv.Itoa(int(job.SelectorType)))
 }
 
 //This function returns the assigned collection
@@ -366,6 +780,13 @@ func (*UtilsStruct) GetLeafIdOfACollection(client *ethclient.Client, collectionI
 
 //This function returns the collection Id from index
 func (*UtilsStruct) GetCollectionIdFromIndex(client *ethclient.Client, medianIndex uint16) (uint16, error) {
+	if chain := UtilsInterface.GetReplayedChain(); chain != nil {
+		collectionId, ok := chain.CollectionIds[medianIndex]
+		if !ok {
+			return 0, fmt.Errorf("no collection at index %d is recorded", medianIndex)
+		}
+		return collectionId, nil
+	}
 	var (
 		collectionId uint16
 		err          error
@@ -382,6 +803,7 @@ func (*UtilsStruct) GetCollectionIdFromIndex(client *ethclient.Client, medianInd
 	if err != nil {
 		return 0, err
 	}
+	UtilsInterface.RecordChain(func(chain *types.RecordedChain) { chain.CollectionIds[medianIndex] = collectionId })
 	return collectionId, nil
 }
 
@@ -406,70 +828,249 @@ func (*UtilsStruct) GetCollectionIdFromLeafId(client *ethclient.Client, leafId u
 	return collectionId, nil
 }
 
-//This function returns the custom jobs from JSON file
-func GetCustomJobsFromJSONFile(collection string, jsonFileData string) []bindings.StructsJob {
-	var collectionCustomJobs []bindings.StructsJob
+//This function returns the custom jobs of the collection from its config in assets.json, jobs which cannot be built are skipped
+func GetCustomJobsFromConfig(collection string, collectionConfig types.CollectionConfig) []types.AssetJob {
+	var collectionCustomJobs []types.AssetJob
 
-	collectionCustomJobsPath := "assets.collection." + collection + ".custom jobs"
-	customJobs := gjson.Get(jsonFileData, collectionCustomJobsPath).Array()
-	if len(customJobs) == 0 {
-		return nil
+	for i, jobConfig := range collectionConfig.CustomJobs {
+		job, err := GetJobFromConfig(jobConfig)
+		if err != nil {
+			log.Errorf("Skipping custom job %d of collection %s: %s", i, collection, err)
+			continue
+		}
+		collectionCustomJobs = append(collectionCustomJobs, job)
+	}
+
+	return collectionCustomJobs
+}
+
+//This function returns the job from its config in assets.json
+func GetJobFromConfig(jobConfig types.JobConfig) (types.AssetJob, error) {
+	url := jobConfig.URL
+	selectorType := core.JSONSelectorType
+	if jobConfig.SelectorType != "" {
+		var err error
+		selectorType, err = GetSelectorTypeFromName(jobConfig.SelectorType)
+		if err != nil {
+			return types.AssetJob{}, err
+		}
+	}
+	power, weight, err := getPowerAndWeight(jobConfig.Power, jobConfig.Weight)
+	if err != nil {
+		return types.AssetJob{}, err
+	}
+	var plugin *types.PluginSource
+	if jobConfig.Plugin != nil {
+		plugin, err = GetPluginSourceFromConfig(*jobConfig.Plugin)
+		if err != nil {
+			return types.AssetJob{}, err
+		}
+		// Plugin jobs have no URL, their source is shown in its place
+		url = getPluginSourceName(*plugin)
+	}
+	request, err := GetJobRequestFromConfig(jobConfig)
+	if err != nil {
+		return types.AssetJob{}, err
+	}
+	job := ConvertCustomJobToStructJob(types.CustomJob{
+		Name:         jobConfig.Name,
+		URL:          url,
+		Power:        power,
+		Selector:     jobConfig.Selector,
+		SelectorType: selectorType,
+		Weight:       weight,
+	})
+	return types.AssetJob{
+		StructsJob:     job,
+		Request:        request,
+		VolumeSelector: jobConfig.VolumeSelector,
+		Plugin:         plugin,
+		Freshness:      GetJobFreshnessFromConfig(jobConfig.Freshness),
+	}, nil
+}
+
+//This function returns the power and weight of a job, an error is returned if they do not fit in the fields of a job
+func getPowerAndWeight(power int64, weight int64) (int8, uint8, error) {
+	if power < math.MinInt8 || power > math.MaxInt8 {
+		return 0, 0, fmt.Errorf("power %d is not between %d and %d", power, math.MinInt8, math.MaxInt8)
+	}
+	if weight < 0 || weight > math.MaxUint8 {
+		return 0, 0, fmt.Errorf("weight %d is not between 0 and %d", weight, math.MaxUint8)
 	}
+	return int8(power), uint8(weight), nil
+}
 
-	for i := 0; i < len(customJobs); i++ {
-		customJobsData := customJobs[i].String()
-		url := gjson.Get(customJobsData, "URL").String()
-		selector := gjson.Get(customJobsData, "selector").String()
-		power := int8(gjson.Get(customJobsData, "power").Int())
-		weight := uint8(gjson.Get(customJobsData, "weight").Int())
-		job := ConvertCustomJobToStructJob(types.CustomJob{
-			URL:      url,
-			Power:    power,
-			Selector: selector,
-			Weight:   weight,
+//This function returns the plugin source of a custom job from its config in assets.json
+func GetPluginSourceFromConfig(pluginConfig types.PluginConfig) (*types.PluginSource, error) {
+	pluginType := strings.ToLower(pluginConfig.Type)
+	if !Contains(pluginTypes, pluginType) {
+		return nil, fmt.Errorf("invalid plugin type %s", pluginConfig.Type)
+	}
+	return &types.PluginSource{
+		Type:    pluginType,
+		Command: pluginConfig.Command,
+		Args:    pluginConfig.Args,
+		Address: pluginConfig.Address,
+		URL:     pluginConfig.URL,
+		Timeout: time.Duration(pluginConfig.Timeout * float64(time.Second)),
+		Params:  pluginConfig.Params,
+	}, nil
+}
+
+//This function returns the derived jobs of the collection from its config in assets.json, their expressions are resolved against the other jobs of the collection by ResolveDerivedJobs
+func GetDerivedJobsFromConfig(collection string, collectionConfig types.CollectionConfig) []types.AssetJob {
+	var collectionDerivedJobs []types.AssetJob
+
+	for i, derivedJobConfig := range collectionConfig.DerivedJobs {
+		name := derivedJobConfig.Name
+		if name == "" || derivedJobConfig.Expression == "" {
+			log.Errorf("Skipping derived job %d of collection %s: name and expression are required", i, collection)
+			continue
+		}
+		power, weight, err := getPowerAndWeight(derivedJobConfig.Power, derivedJobConfig.Weight)
+		if err != nil {
+			log.Errorf("Skipping derived job %s of collection %s: %s", name, collection, err)
+			continue
+		}
+		inputs := make(map[string]types.AssetJob)
+		for _, inputName := range sortedKeys(derivedJobConfig.Inputs) {
+			var input types.AssetJob
+			input, err = GetJobFromConfig(derivedJobConfig.Inputs[inputName])
+			if err != nil {
+				err = fmt.Errorf("input %s: %s", inputName, err)
+				break
+			}
+			input.Name = inputName
+			inputs[inputName] = input
+		}
+		if err != nil {
+			log.Errorf("Skipping derived job %s of collection %s: %s", name, collection, err)
+			continue
+		}
+		collectionDerivedJobs = append(collectionDerivedJobs, types.AssetJob{
+			StructsJob: bindings.StructsJob{
+				Name:   name,
+				Power:  power,
+				Weight: weight,
+			},
+			Expression: derivedJobConfig.Expression,
+			Inputs:     inputs,
 		})
-		collectionCustomJobs = append(collectionCustomJobs, job)
 	}
 
-	return collectionCustomJobs
+	return collectionDerivedJobs
 }
 
 //This function converts custom Job to struct job
 func ConvertCustomJobToStructJob(customJob types.CustomJob) bindings.StructsJob {
 	return bindings.StructsJob{
-		Url:      customJob.URL,
-		Selector: customJob.Selector,
-		Power:    customJob.Power,
-		Weight:   customJob.Weight,
+		Name:         customJob.Name,
+		Url:          customJob.URL,
+		Selector:     customJob.Selector,
+		SelectorType: customJob.SelectorType,
+		Power:        customJob.Power,
+		Weight:       customJob.Weight,
+	}
+}
+
+//This function returns the request customisations (method, headers, body and auth) of a job from its config in assets.json
+func GetJobRequestFromConfig(jobConfig types.JobConfig) (types.JobRequest, error) {
+	request := types.JobRequest{
+		Method:  jobConfig.Method,
+		Timeout: time.Duration(jobConfig.Timeout * float64(time.Second)),
+		Proxy:   jobConfig.Proxy,
+	}
+	if len(jobConfig.Headers) != 0 {
+		request.Headers = make(map[string]string)
+		for key, value := range jobConfig.Headers {
+			request.Headers[key] = value
+		}
+	}
+	body, err := getJobRequestBody(jobConfig.Body)
+	if err != nil {
+		return types.JobRequest{}, err
+	}
+	request.Body = body
+	if jobConfig.Auth != nil {
+		auth := *jobConfig.Auth
+		request.Auth = &auth
+	}
+	if jobConfig.ClientCertificate != nil {
+		clientCertificate := *jobConfig.ClientCertificate
+		request.ClientCertificate = &clientCertificate
+	}
+	// Retries are the attempts made after the first one
+	if jobConfig.Retries != nil {
+		if *jobConfig.Retries < 0 {
+			return types.JobRequest{}, fmt.Errorf("retries %d must not be negative", *jobConfig.Retries)
+		}
+		request.Attempts = uint(*jobConfig.Retries) + 1
+	}
+	return request, nil
+}
+
+//This function returns the body of a job request, a JSON string is sent as its text and any other JSON value as it is
+func getJobRequestBody(body json.RawMessage) (string, error) {
+	body = bytes.TrimSpace(body)
+	if len(body) == 0 || string(body) == "null" {
+		return "", nil
+	}
+	if body[0] != '"' {
+		return string(body), nil
+	}
+	var text string
+	if err := json.Unmarshal(body, &text); err != nil {
+		return "", fmt.Errorf("invalid body: %s", err)
 	}
+	return text, nil
 }
 
-//This function handles official jobs from JSON file
-func (*UtilsStruct) HandleOfficialJobsFromJSONFile(client *ethclient.Client, collection bindings.StructsCollection, dataString string) ([]bindings.StructsJob, []uint16) {
-	var overrideJobs []bindings.StructsJob
+//This function handles official jobs from the config of the collection in assets.json
+func (*UtilsStruct) HandleOfficialJobsFromJSONFile(client *ethclient.Client, collection bindings.StructsCollection, collectionConfig types.CollectionConfig) ([]types.AssetJob, []uint16) {
+	var overrideJobs []types.AssetJob
 	var overriddenJobIds []uint16
 
 	collectionName := collection.Name
 	jobIds := collection.JobIDs
 
 	for i := 0; i < len(jobIds); i++ {
-		officialJobsPath := "assets.collection." + collectionName + ".official jobs." + strconv.Itoa(int(jobIds[i]))
-		officialJobs := gjson.Get(dataString, officialJobsPath).String()
-		if officialJobs != "" {
-			job, err := UtilsInterface.GetActiveJob(client, jobIds[i])
+		officialJob, ok := collectionConfig.OfficialJobs[strconv.Itoa(int(jobIds[i]))]
+		if !ok {
+			continue
+		}
+		job, err := UtilsInterface.GetActiveJob(client, jobIds[i])
+		if err != nil {
+			continue
+		}
+		power, weight, err := getPowerAndWeight(officialJob.Power, officialJob.Weight)
+		if err != nil {
+			log.Errorf("Not overriding job %d of collection %s: %s", jobIds[i], collectionName, err)
+			continue
+		}
+		if officialJob.SelectorType != "" {
+			job.SelectorType, err = GetSelectorTypeFromName(officialJob.SelectorType)
 			if err != nil {
+				log.Errorf("Not overriding job %d of collection %s: %s", jobIds[i], collectionName, err)
 				continue
 			}
-			job.Url = gjson.Get(officialJobs, "URL").String()
-			job.Selector = gjson.Get(officialJobs, "selector").String()
-			job.Weight = uint8(gjson.Get(officialJobs, "weight").Int())
-			job.Power = int8(gjson.Get(officialJobs, "power").Int())
-
-			overrideJobs = append(overrideJobs, job)
-			overriddenJobIds = append(overriddenJobIds, jobIds[i])
-		} else {
+		}
+		request, err := GetJobRequestFromConfig(officialJob)
+		if err != nil {
+			log.Errorf("Not overriding job %d of collection %s: %s", jobIds[i], collectionName, err)
 			continue
 		}
+		job.Url = officialJob.URL
+		job.Selector = officialJob.Selector
+		job.Weight = weight
+		job.Power = power
+
+		overrideJobs = append(overrideJobs, types.AssetJob{
+			StructsJob:     job,
+			Request:        request,
+			VolumeSelector: officialJob.VolumeSelector,
+			Freshness:      GetJobFreshnessFromConfig(officialJob.Freshness),
+		})
+		overriddenJobIds = append(overriddenJobIds, jobIds[i])
 	}
 
 	return overrideJobs, overriddenJobIds
@@ -0,0 +1,545 @@
+//Package utils provides the utils functions
+package utils
+
+import (
+	"bytes"
+	"crypto/tls"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"mime"
+	"net"
+	"net/url"
+	"os/exec"
+	"razor/core"
+	"razor/core/types"
+	"razor/pkg/bindings"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/ethclient"
+)
+
+var jobRequestMethods = []string{"GET", "HEAD", "POST", "PUT", "PATCH", "DELETE", "OPTIONS"}
+
+var outlierFilterMethods = []string{"mad", "percentage"}
+
+var aggregationMethods = []string{"median", "mean", "trimmed mean", "vwap", "min", "max", "mode"}
+
+//This function reads assets.json and returns every problem in it, the file is valid if it does not exist
+func (*UtilsStruct) ValidateAssetsFile(client *ethclient.Client) ([]error, error) {
+	dataString, err := readAssetsFileFromDisk()
+	if err != nil {
+		return nil, err
+	}
+	return getAssetsProblems(client, dataString)
+}
+
+//This function returns every problem in the content of assets.json, an empty content is valid
+func getAssetsProblems(client *ethclient.Client, dataString string) ([]error, error) {
+	if dataString == "" {
+		return nil, nil
+	}
+	collections, err := UtilsInterface.GetAllCollections(client)
+	if err != nil {
+		return nil, err
+	}
+	config, problems := ParseAssetsConfig([]byte(dataString))
+	if config == nil {
+		return problems, nil
+	}
+	return append(problems, ValidateAssetsConfig(*config, collections)...), nil
+}
+
+//This function decodes assets.json strictly, every key which is not part of the file format is returned as a problem
+func ParseAssetsConfig(data []byte) (*types.AssetsConfig, []error) {
+	// Trailing commas are tolerated as assets.json has always been read leniently
+	data = removeTrailingCommas(data)
+
+	var document interface{}
+	if err := json.Unmarshal(data, &document); err != nil {
+		return nil, []error{fmt.Errorf("invalid JSON: %s", err)}
+	}
+	problems := findUnknownKeys(document, reflect.TypeOf(types.AssetsConfig{}), "")
+
+	var config types.AssetsConfig
+	if err := json.Unmarshal(data, &config); err != nil {
+		var typeErr *json.UnmarshalTypeError
+		if errors.As(err, &typeErr) {
+			return &config, append(problems, fmt.Errorf("%s: expected %s but got %s", typeErr.Field, typeErr.Type, typeErr.Value))
+		}
+		return nil, append(problems, err)
+	}
+	return &config, problems
+}
+
+//This function decodes the content of assets.json which jobs are read from, any key which is not part of the file format is an error, an empty content has no config
+func DecodeAssetsConfig(dataString string) (*types.AssetsConfig, error) {
+	if dataString == "" {
+		return nil, nil
+	}
+	decoder := json.NewDecoder(bytes.NewReader(removeTrailingCommas([]byte(dataString))))
+	decoder.DisallowUnknownFields()
+	var config types.AssetsConfig
+	if err := decoder.Decode(&config); err != nil {
+		return nil, fmt.Errorf("invalid assets.json: %s", err)
+	}
+	return &config, nil
+}
+
+//This function returns the problems in the assets config, the names of its collections are checked against the collections on chain
+func ValidateAssetsConfig(config types.AssetsConfig, collections []bindings.StructsCollection) []error {
+	var problems []error
+	if config.HTTP != nil {
+		problems = append(problems, validateHTTPConfig("http", *config.HTTP)...)
+	}
+	collectionsByName := make(map[string]bindings.StructsCollection)
+	for _, collection := range collections {
+		collectionsByName[collection.Name] = collection
+	}
+
+	for _, name := range sortedKeys(config.Assets.Collection) {
+		path := "assets.collection." + name
+		collectionConfig := config.Assets.Collection[name]
+		collection, ok := collectionsByName[name]
+		if !ok {
+			problems = append(problems, fmt.Errorf("%s: unknown collection", path))
+		}
+		if collectionConfig.Power < math.MinInt8 || collectionConfig.Power > math.MaxInt8 {
+			problems = append(problems, fmt.Errorf("%s.power: must be between %d and %d", path, math.MinInt8, math.MaxInt8))
+		}
+
+		for _, jobId := range sortedKeys(collectionConfig.OfficialJobs) {
+			jobPath := path + ".official jobs." + jobId
+			id, err := strconv.ParseUint(jobId, 10, 16)
+			if err != nil {
+				problems = append(problems, fmt.Errorf("%s: job id must be a number", jobPath))
+			} else if ok && !Contains(collection.JobIDs, uint16(id)) {
+				problems = append(problems, fmt.Errorf("%s: job is not part of the collection", jobPath))
+			}
+			problems = append(problems, validateJobConfig(jobPath, collectionConfig.OfficialJobs[jobId], true)...)
+		}
+
+		names := make(map[string]bool)
+		for i, job := range collectionConfig.CustomJobs {
+			jobPath := fmt.Sprintf("%s.custom jobs[%d]", path, i)
+			problems = append(problems, validateJobConfig(jobPath, job, true)...)
+			if job.Name != "" {
+				if names[job.Name] {
+					problems = append(problems, fmt.Errorf("%s.name: %s is used by another job", jobPath, job.Name))
+				}
+				names[job.Name] = true
+			}
+		}
+		for i, derivedJob := range collectionConfig.DerivedJobs {
+			jobPath := fmt.Sprintf("%s.derived jobs[%d]", path, i)
+			problems = append(problems, validateDerivedJobConfig(jobPath, derivedJob)...)
+			if derivedJob.Name != "" {
+				if names[derivedJob.Name] {
+					problems = append(problems, fmt.Errorf("%s.name: %s is used by another job", jobPath, derivedJob.Name))
+				}
+				names[derivedJob.Name] = true
+			}
+		}
+
+		if filter := collectionConfig.OutlierFilter; filter != nil {
+			if !Contains(outlierFilterMethods, strings.ToLower(filter.Method)) {
+				problems = append(problems, fmt.Errorf("%s.outlier filter.method: must be one of MAD, percentage", path))
+			}
+			if filter.Threshold < 0 {
+				problems = append(problems, fmt.Errorf("%s.outlier filter.threshold: must not be negative", path))
+			}
+			if filter.MinQuorum < 0 {
+				problems = append(problems, fmt.Errorf("%s.outlier filter.min quorum: must not be negative", path))
+			}
+		}
+		if aggregation := collectionConfig.Aggregation; aggregation != nil {
+			method := strings.ToLower(aggregation.Method)
+			if !Contains(aggregationMethods, method) {
+				problems = append(problems, fmt.Errorf("%s.aggregation.method: must be one of %s", path, strings.Join(aggregationMethods, ", ")))
+			}
+			if method == "trimmed mean" && (aggregation.TrimPercentage < 0 || aggregation.TrimPercentage >= 50) {
+				problems = append(problems, fmt.Errorf("%s.aggregation.trim percentage: must be at least 0 and less than 50", path))
+			}
+		}
+		if fallback := collectionConfig.Fallback; fallback != nil {
+			problems = append(problems, validateFallbackConfig(path+".fallback", *fallback)...)
+		}
+		if reputation := collectionConfig.Reputation; reputation != nil {
+			problems = append(problems, validateReputationConfig(path+".reputation", *reputation)...)
+		}
+		if bounds := collectionConfig.SanityBounds; bounds != nil {
+			problems = append(problems, validateSanityBounds(path+".sanity bounds", *bounds)...)
+		}
+	}
+	return problems
+}
+
+//This function returns the problems in the job, weight is only checked for jobs which are aggregated
+func validateJobConfig(path string, job types.JobConfig, isAggregated bool) []error {
+	var problems []error
+	if job.Plugin != nil {
+		// The selectors of a plugin job are names of fields in the output of the plugin
+		problems = append(problems, validatePluginConfig(path+".plugin", *job.Plugin)...)
+		if job.URL != "" {
+			problems = append(problems, fmt.Errorf("%s.URL: must not be set for a plugin job", path))
+		}
+		if job.Timeout != 0 || job.Retries != nil {
+			problems = append(problems, fmt.Errorf("%s: timeout and retries of a plugin job are set in its plugin", path))
+		}
+		if job.Proxy != "" || job.ClientCertificate != nil {
+			problems = append(problems, fmt.Errorf("%s: proxy and client certificate must not be set for a plugin job", path))
+		}
+	} else {
+		problems = append(problems, validateJobSource(path, job)...)
+		if job.Timeout < 0 || job.Timeout > core.MaxJobTimeout.Seconds() {
+			problems = append(problems, fmt.Errorf("%s.timeout: must be between 0 and %g seconds", path, core.MaxJobTimeout.Seconds()))
+		}
+		if job.Retries != nil && (*job.Retries < 0 || *job.Retries >= int64(core.MaxRetries)) {
+			problems = append(problems, fmt.Errorf("%s.retries: must be between 0 and %d", path, core.MaxRetries-1))
+		}
+		if job.Proxy != "" {
+			if _, err := ParseProxyURL(job.Proxy); err != nil {
+				problems = append(problems, fmt.Errorf("%s.proxy: %s", path, err))
+			}
+		}
+		if job.ClientCertificate != nil {
+			problems = append(problems, validateClientCertificate(path+".client certificate", *job.ClientCertificate)...)
+		}
+	}
+	if job.Freshness != nil {
+		problems = append(problems, validateFreshnessConfig(path+".freshness", *job.Freshness)...)
+	}
+
+	problems = append(problems, validatePowerAndWeight(path, job.Power, job.Weight, isAggregated)...)
+	if job.Method != "" && !Contains(jobRequestMethods, strings.ToUpper(job.Method)) {
+		problems = append(problems, fmt.Errorf("%s.method: must be one of %s", path, strings.Join(jobRequestMethods, ", ")))
+	}
+	if job.Auth != nil {
+		if job.Auth.Env == "" && job.Auth.File == "" {
+			problems = append(problems, fmt.Errorf("%s.auth: env or file is required", path))
+		}
+		if job.Auth.Header != "" && job.Auth.Query != "" {
+			problems = append(problems, fmt.Errorf("%s.auth: only one of header and query can be set", path))
+		}
+	}
+	return problems
+}
+
+//This function returns the problems in the URL and selectors of a job whose value is selected from a response
+func validateJobSource(path string, job types.JobConfig) []error {
+	var problems []error
+	if job.URL == "" {
+		problems = append(problems, fmt.Errorf("%s.URL: is required", path))
+	} else if jobUrl, err := url.Parse(job.URL); err != nil || (jobUrl.Scheme != "http" && jobUrl.Scheme != "https") || jobUrl.Host == "" {
+		problems = append(problems, fmt.Errorf("%s.URL: must be an absolute http or https URL", path))
+	}
+
+	selectorType := core.JSONSelectorType
+	var err error
+	if job.SelectorType != "" {
+		selectorType, err = GetSelectorTypeFromName(job.SelectorType)
+	}
+	if err != nil {
+		problems = append(problems, fmt.Errorf("%s.selector type: %s", path, err))
+	} else {
+		if err := ValidateSelector(selectorType, job.Selector); err != nil {
+			problems = append(problems, fmt.Errorf("%s.selector: %s", path, err))
+		}
+		if job.VolumeSelector != "" {
+			if err := ValidateSelector(selectorType, job.VolumeSelector); err != nil {
+				problems = append(problems, fmt.Errorf("%s.volume selector: %s", path, err))
+			}
+		}
+		if job.Freshness != nil && job.Freshness.TimestampSelector != "" {
+			if err := ValidateSelector(selectorType, job.Freshness.TimestampSelector); err != nil {
+				problems = append(problems, fmt.Errorf("%s.freshness.timestamp selector: %s", path, err))
+			}
+		}
+	}
+	return problems
+}
+
+//This function returns the problems in the http settings, the files they refer to are loaded as they are when jobs are fetched
+func validateHTTPConfig(path string, config types.HTTPConfig) []error {
+	var problems []error
+	if config.Proxy != "" {
+		if _, err := ParseProxyURL(config.Proxy); err != nil {
+			problems = append(problems, fmt.Errorf("%s.proxy: %s", path, err))
+		}
+	}
+	for i, caFile := range config.CAFiles {
+		if _, err := loadRootCAs([]string{caFile}); err != nil {
+			problems = append(problems, fmt.Errorf("%s.ca files[%d]: %s", path, i, err))
+		}
+	}
+	if config.ClientCertificate != nil {
+		problems = append(problems, validateClientCertificate(path+".client certificate", *config.ClientCertificate)...)
+	}
+	if config.MaxResponseSize < 0 {
+		problems = append(problems, fmt.Errorf("%s.max response size: must not be negative", path))
+	}
+	for i, contentType := range config.ContentTypes {
+		if _, _, err := mime.ParseMediaType(contentType); err != nil || !strings.Contains(contentType, "/") {
+			problems = append(problems, fmt.Errorf("%s.content types[%d]: invalid content type %s", path, i, contentType))
+		}
+	}
+	return problems
+}
+
+//This function checks that the client certificate and its key can be loaded
+func validateClientCertificate(path string, certificate types.ClientCertificate) []error {
+	if certificate.CertFile == "" || certificate.KeyFile == "" {
+		return []error{fmt.Errorf("%s: cert file and key file are required", path)}
+	}
+	if _, err := tls.LoadX509KeyPair(certificate.CertFile, certificate.KeyFile); err != nil {
+		return []error{fmt.Errorf("%s: %s", path, err)}
+	}
+	return nil
+}
+
+//This function checks that the freshness of a job has a timestamp selector and a positive max age
+func validateFreshnessConfig(path string, freshness types.FreshnessConfig) []error {
+	var problems []error
+	if freshness.TimestampSelector == "" {
+		problems = append(problems, fmt.Errorf("%s.timestamp selector: is required", path))
+	}
+	if freshness.MaxAge <= 0 {
+		problems = append(problems, fmt.Errorf("%s.max age: must be greater than 0", path))
+	}
+	return problems
+}
+
+//This function returns the problems in the plugin of a job, gRPC and HTTP plugins have to run on the same machine
+func validatePluginConfig(path string, plugin types.PluginConfig) []error {
+	var problems []error
+	switch strings.ToLower(plugin.Type) {
+	case "exec":
+		if plugin.Command == "" {
+			problems = append(problems, fmt.Errorf("%s.command: is required", path))
+		} else if _, err := exec.LookPath(plugin.Command); err != nil {
+			problems = append(problems, fmt.Errorf("%s.command: %s", path, err))
+		}
+	case "grpc":
+		if host, _, err := net.SplitHostPort(plugin.Address); err != nil {
+			problems = append(problems, fmt.Errorf("%s.address: must be host:port", path))
+		} else if !isLocalHost(host) {
+			problems = append(problems, fmt.Errorf("%s.address: must be a local address", path))
+		}
+	case "http":
+		if pluginUrl, err := url.Parse(plugin.URL); err != nil || (pluginUrl.Scheme != "http" && pluginUrl.Scheme != "https") || pluginUrl.Host == "" {
+			problems = append(problems, fmt.Errorf("%s.URL: must be an absolute http or https URL", path))
+		} else if !isLocalHost(pluginUrl.Hostname()) {
+			problems = append(problems, fmt.Errorf("%s.URL: must be a local address", path))
+		}
+	default:
+		problems = append(problems, fmt.Errorf("%s.type: must be one of %s", path, strings.Join(pluginTypes, ", ")))
+	}
+	if plugin.Timeout < 0 || plugin.Timeout > core.MaxPluginTimeout.Seconds() {
+		problems = append(problems, fmt.Errorf("%s.timeout: must be between 0 and %g seconds", path, core.MaxPluginTimeout.Seconds()))
+	}
+	return problems
+}
+
+//This function checks if the host is this machine
+func isLocalHost(host string) bool {
+	if host == "localhost" {
+		return true
+	}
+	ip := net.ParseIP(host)
+	return ip != nil && ip.IsLoopback()
+}
+
+//This function returns the problems in the derived job and its inputs
+func validateDerivedJobConfig(path string, derivedJob types.DerivedJobConfig) []error {
+	var problems []error
+	if derivedJob.Name == "" {
+		problems = append(problems, fmt.Errorf("%s.name: is required", path))
+	}
+	if derivedJob.Expression == "" {
+		problems = append(problems, fmt.Errorf("%s.expression: is required", path))
+	} else if _, err := ParseExpression(derivedJob.Expression); err != nil {
+		problems = append(problems, fmt.Errorf("%s.expression: %s", path, err))
+	}
+	problems = append(problems, validatePowerAndWeight(path, derivedJob.Power, derivedJob.Weight, true)...)
+	for _, name := range sortedKeys(derivedJob.Inputs) {
+		problems = append(problems, validateJobConfig(path+".inputs."+name, derivedJob.Inputs[name], false)...)
+	}
+	return problems
+}
+
+//This function returns the problems in the fallback chain and secondary jobs of a collection
+func validateFallbackConfig(path string, fallback types.FallbackConfig) []error {
+	var problems []error
+	for i, step := range fallback.Chain {
+		if !Contains(fallbackSteps, strings.ToLower(step)) {
+			problems = append(problems, fmt.Errorf("%s.chain[%d]: must be one of %s", path, i, strings.Join(fallbackSteps, ", ")))
+		} else if strings.ToLower(step) == "secondary jobs" && len(fallback.SecondaryJobs) == 0 {
+			problems = append(problems, fmt.Errorf("%s.chain[%d]: secondary jobs are required", path, i))
+		}
+	}
+	if fallback.MaxStalenessEpochs < 0 || fallback.MaxStalenessEpochs > math.MaxUint32 {
+		problems = append(problems, fmt.Errorf("%s.max staleness epochs: must be between 0 and %d", path, uint32(math.MaxUint32)))
+	}
+	for i, job := range fallback.SecondaryJobs {
+		problems = append(problems, validateJobConfig(fmt.Sprintf("%s.secondary jobs[%d]", path, i), job, true)...)
+	}
+	return problems
+}
+
+//This function checks that the bounds of the reputation of a collection are percentages and its counts are not negative
+func validateReputationConfig(path string, reputation types.Reputation) []error {
+	var problems []error
+	percentages := []struct {
+		name  string
+		value float64
+	}{
+		{"max deviation", reputation.MaxDeviation},
+		{"max failure rate", reputation.MaxFailureRate},
+	}
+	if reputation.MinWeight != nil {
+		percentages = append(percentages, struct {
+			name  string
+			value float64
+		}{"min weight", *reputation.MinWeight})
+	}
+	for _, percentage := range percentages {
+		if percentage.value < 0 || percentage.value > 100 {
+			problems = append(problems, fmt.Errorf("%s.%s: must be between 0 and 100", path, percentage.name))
+		}
+	}
+	if reputation.MinJobs < 0 {
+		problems = append(problems, fmt.Errorf("%s.min jobs: must not be negative", path))
+	}
+	if reputation.MinSamples < 0 {
+		problems = append(problems, fmt.Errorf("%s.min samples: must not be negative", path))
+	}
+	return problems
+}
+
+//This function checks that the min of the sanity bounds of a collection is not more than their max and that their policy is known
+func validateSanityBounds(path string, bounds types.SanityBounds) []error {
+	var problems []error
+	if bounds.Min != nil && bounds.Max != nil && *bounds.Min > *bounds.Max {
+		problems = append(problems, fmt.Errorf("%s.min: must not be more than max", path))
+	}
+	if bounds.MaxChange < 0 {
+		problems = append(problems, fmt.Errorf("%s.max change: must not be negative", path))
+	}
+	if bounds.Policy != "" && !Contains(sanityPolicies, strings.ToLower(bounds.Policy)) {
+		problems = append(problems, fmt.Errorf("%s.policy: must be one of %s", path, strings.Join(sanityPolicies, ", ")))
+	}
+	if bounds.Min == nil && bounds.Max == nil && bounds.MaxChange == 0 {
+		problems = append(problems, fmt.Errorf("%s: at least one of min, max and max change is required", path))
+	}
+	return problems
+}
+
+//This function checks that power and weight fit the job fields and that an aggregated job has a weight
+func validatePowerAndWeight(path string, power int64, weight int64, isAggregated bool) []error {
+	var problems []error
+	if power < math.MinInt8 || power > math.MaxInt8 {
+		problems = append(problems, fmt.Errorf("%s.power: must be between %d and %d", path, math.MinInt8, math.MaxInt8))
+	}
+	if isAggregated && (weight <= 0 || weight > math.MaxUint8) {
+		problems = append(problems, fmt.Errorf("%s.weight: must be between 1 and %d", path, math.MaxUint8))
+	}
+	return problems
+}
+
+//This function returns a problem for every key of the JSON document which has no field in the schema
+func findUnknownKeys(document interface{}, schema reflect.Type, path string) []error {
+	var problems []error
+	for schema.Kind() == reflect.Ptr {
+		schema = schema.Elem()
+	}
+	switch schema.Kind() {
+	case reflect.Struct:
+		object, ok := document.(map[string]interface{})
+		if !ok {
+			return nil
+		}
+		fields := make(map[string]reflect.Type)
+		for i := 0; i < schema.NumField(); i++ {
+			key := strings.Split(schema.Field(i).Tag.Get("json"), ",")[0]
+			if key != "" && key != "-" {
+				fields[strings.ToLower(key)] = schema.Field(i).Type
+			}
+		}
+		for _, key := range sortedKeys(object) {
+			// Keys are matched regardless of case, as encoding/json decodes them
+			fieldType, ok := fields[strings.ToLower(key)]
+			if !ok {
+				problems = append(problems, fmt.Errorf("%s: unknown key %q", strings.TrimPrefix(path+"."+key, "."), key))
+				continue
+			}
+			problems = append(problems, findUnknownKeys(object[key], fieldType, strings.TrimPrefix(path+"."+key, "."))...)
+		}
+	case reflect.Map:
+		object, ok := document.(map[string]interface{})
+		if !ok {
+			return nil
+		}
+		for _, key := range sortedKeys(object) {
+			problems = append(problems, findUnknownKeys(object[key], schema.Elem(), path+"."+key)...)
+		}
+	case reflect.Slice:
+		array, ok := document.([]interface{})
+		if !ok {
+			return nil
+		}
+		for i, element := range array {
+			problems = append(problems, findUnknownKeys(element, schema.Elem(), fmt.Sprintf("%s[%d]", path, i))...)
+		}
+	}
+	return problems
+}
+
+//This function removes the commas before closing brackets and braces which are outside of strings
+func removeTrailingCommas(data []byte) []byte {
+	result := make([]byte, 0, len(data))
+	inString, escaped := false, false
+	for i := 0; i < len(data); i++ {
+		char := data[i]
+		if inString {
+			switch {
+			case escaped:
+				escaped = false
+			case char == '\\':
+				escaped = true
+			case char == '"':
+				inString = false
+			}
+			result = append(result, char)
+			continue
+		}
+		if char == '"' {
+			inString = true
+		}
+		if char == ',' {
+			next := i + 1
+			for next < len(data) && strings.ContainsRune(" \t\r\n", rune(data[next])) {
+				next++
+			}
+			if next < len(data) && (data[next] == '}' || data[next] == ']') {
+				continue
+			}
+		}
+		result = append(result, char)
+	}
+	return result
+}
+
+//This function returns the keys of the map with string keys in sorted order
+func sortedKeys(object interface{}) []string {
+	mapKeys := reflect.ValueOf(object).MapKeys()
+	keys := make([]string, 0, len(mapKeys))
+	for _, key := range mapKeys {
+		keys = append(keys, key.String())
+	}
+	sort.Strings(keys)
+	return keys
+}
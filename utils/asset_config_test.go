@@ -0,0 +1,394 @@
+package utils
+
+import (
+	"razor/core/types"
+	"razor/pkg/bindings"
+	"reflect"
+	"testing"
+)
+
+var assetsConfigFixture = `{
+  "assets": {
+    "collection": {
+      "ethCollectionMean": {
+        "power": 2,
+        "official jobs": {
+          "1": {
+            "URL": "https://api.gemini.com/v1/pubticker/ethusd",
+            "selector": "last",
+            "power": 3,
+            "weight": 2
+          },
+        },
+        "custom jobs": [
+          {
+            "name": "eth1",
+            "URL": "https://api.kucoin.com/api/v1/prices?currencies=ETH",
+            "selector": "data.ETH",
+            "power": 3,
+            "weight": 2
+          },
+        ],
+        "derived jobs": [
+          {
+            "name": "ethinverse",
+            "expression": "1 / eth",
+            "power": 8,
+            "weight": 1,
+            "inputs": {
+              "eth": {
+                "URL": "https://api.gemini.com/v1/pubticker/ethusd",
+                "selector": "last"
+              }
+            }
+          }
+        ],
+        "aggregation": {
+          "method": "trimmed mean",
+          "trim percentage": 10
+        }
+      }
+    }
+  }
+}`
+
+func TestParseAssetsConfig(t *testing.T) {
+	tests := []struct {
+		name         string
+		data         string
+		wantConfig   bool
+		wantProblems []string
+	}{
+		{
+			name:       "Test 1: When assets.json is valid and has trailing commas",
+			data:       assetsConfigFixture,
+			wantConfig: true,
+		},
+		{
+			name:       "Test 2: When assets.json has unknown keys",
+			data:       `{"assets": {"collection": {"ethCollectionMean": {"power": 2, "custom jobs": [{"URL": "https://api.gemini.com/v1/pubticker/ethusd", "selectr": "last", "weight": 1}], "outlier filter": {"method": "MAD", "treshold": 3}}}}, "version": 1}`,
+			wantConfig: true,
+			wantProblems: []string{
+				`assets.collection.ethCollectionMean.custom jobs[0].selectr: unknown key "selectr"`,
+				`assets.collection.ethCollectionMean.outlier filter.treshold: unknown key "treshold"`,
+				`version: unknown key "version"`,
+			},
+		},
+		{
+			name:       "Test 3: When a key has the wrong type",
+			data:       `{"assets": {"collection": ["ethCollectionMean"]}}`,
+			wantConfig: true,
+			wantProblems: []string{
+				"assets.collection: expected map[string]types.CollectionConfig but got array",
+			},
+		},
+		{
+			name:       "Test 4: When keys differ in case from the file format",
+			data:       `{"Assets": {"collection": {"ethCollectionMean": {"Power": 2, "Custom Jobs": [{"Url": "https://api.gemini.com/v1/pubticker/ethusd", "SELECTOR": "last", "Selectr": "last"}], "Outlier Filter": {"Method": "MAD"}}}}}`,
+			wantConfig: true,
+			wantProblems: []string{
+				`Assets.collection.ethCollectionMean.Custom Jobs[0].Selectr: unknown key "Selectr"`,
+			},
+		},
+		{
+			name:       "Test 5: When assets.json is not JSON",
+			data:       `{"assets": {"collection": `,
+			wantConfig: false,
+			wantProblems: []string{
+				"invalid JSON: unexpected end of JSON input",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config, problems := ParseAssetsConfig([]byte(tt.data))
+			if (config != nil) != tt.wantConfig {
+				t.Errorf("ParseAssetsConfig() config = %v, wantConfig %v", config, tt.wantConfig)
+			}
+			if !reflect.DeepEqual(errorStrings(problems), tt.wantProblems) {
+				t.Errorf("ParseAssetsConfig() problems = %v, want %v", errorStrings(problems), tt.wantProblems)
+			}
+		})
+	}
+}
+
+func TestValidateAssetsConfig(t *testing.T) {
+	collections := []bindings.StructsCollection{
+		{Id: 1, Name: "ethCollectionMean", JobIDs: []uint16{1, 2}},
+	}
+	validJob := types.JobConfig{URL: "https://api.gemini.com/v1/pubticker/ethusd", Selector: "last", Power: 3, Weight: 1}
+	var retries, tooManyRetries int64 = 2, 8
+	negativeMinWeight := -10.0
+	var min, max float64 = 100000, 500000
+
+	tests := []struct {
+		name         string
+		config       types.CollectionConfig
+		collection   string
+		wantProblems []string
+	}{
+		{
+			name:       "Test 1: When collection config is valid",
+			config:     types.CollectionConfig{Power: 2, OfficialJobs: map[string]types.JobConfig{"1": validJob}, CustomJobs: []types.JobConfig{validJob}},
+			collection: "ethCollectionMean",
+		},
+		{
+			name:       "Test 2: When collection does not exist",
+			config:     types.CollectionConfig{Power: 200},
+			collection: "btcCollectionMean",
+			wantProblems: []string{
+				"assets.collection.btcCollectionMean: unknown collection",
+				"assets.collection.btcCollectionMean.power: must be between -128 and 127",
+			},
+		},
+		{
+			name:       "Test 3: When official jobs are not part of the collection",
+			config:     types.CollectionConfig{OfficialJobs: map[string]types.JobConfig{"3": validJob, "eth": validJob}},
+			collection: "ethCollectionMean",
+			wantProblems: []string{
+				"assets.collection.ethCollectionMean.official jobs.3: job is not part of the collection",
+				"assets.collection.ethCollectionMean.official jobs.eth: job id must be a number",
+			},
+		},
+		{
+			name: "Test 4: When custom job is invalid",
+			config: types.CollectionConfig{CustomJobs: []types.JobConfig{
+				{URL: "api.gemini.com", SelectorType: "xpath", Selector: "//span[", Power: 300, Method: "FETCH", Auth: &types.JobAuth{Header: "Authorization", Query: "key"}},
+			}},
+			collection: "ethCollectionMean",
+			wantProblems: []string{
+				"assets.collection.ethCollectionMean.custom jobs[0].URL: must be an absolute http or https URL",
+				`assets.collection.ethCollectionMean.custom jobs[0].selector: invalid selector "//span[": expression must evaluate to a node-set`,
+				"assets.collection.ethCollectionMean.custom jobs[0].power: must be between -128 and 127",
+				"assets.collection.ethCollectionMean.custom jobs[0].weight: must be between 1 and 255",
+				"assets.collection.ethCollectionMean.custom jobs[0].method: must be one of GET, HEAD, POST, PUT, PATCH, DELETE, OPTIONS",
+				"assets.collection.ethCollectionMean.custom jobs[0].auth: env or file is required",
+				"assets.collection.ethCollectionMean.custom jobs[0].auth: only one of header and query can be set",
+			},
+		},
+		{
+			name: "Test 5: When derived jobs are invalid",
+			config: types.CollectionConfig{
+				CustomJobs: []types.JobConfig{{Name: "eth", URL: validJob.URL, Selector: "last", Weight: 1}},
+				DerivedJobs: []types.DerivedJobConfig{
+					{Name: "eth", Expression: "1 / (eth", Weight: 1},
+					{Expression: "eth * 2", Inputs: map[string]types.JobConfig{"eth": {URL: validJob.URL, SelectorType: "yaml", Selector: "last"}}},
+				},
+			},
+			collection: "ethCollectionMean",
+			wantProblems: []string{
+				"assets.collection.ethCollectionMean.derived jobs[0].expression: missing ) at position 8 in expression",
+				"assets.collection.ethCollectionMean.derived jobs[0].name: eth is used by another job",
+				"assets.collection.ethCollectionMean.derived jobs[1].name: is required",
+				"assets.collection.ethCollectionMean.derived jobs[1].weight: must be between 1 and 255",
+				"assets.collection.ethCollectionMean.derived jobs[1].inputs.eth.selector type: invalid selector type yaml",
+			},
+		},
+		{
+			name: "Test 6: When outlier filter and aggregation are invalid",
+			config: types.CollectionConfig{
+				OutlierFilter: &types.OutlierFilter{Method: "zscore", Threshold: -1, MinQuorum: -1},
+				Aggregation:   &types.CollectionAggregation{Method: "Trimmed Mean", TrimPercentage: 50},
+			},
+			collection: "ethCollectionMean",
+			wantProblems: []string{
+				"assets.collection.ethCollectionMean.outlier filter.method: must be one of MAD, percentage",
+				"assets.collection.ethCollectionMean.outlier filter.threshold: must not be negative",
+				"assets.collection.ethCollectionMean.outlier filter.min quorum: must not be negative",
+				"assets.collection.ethCollectionMean.aggregation.trim percentage: must be at least 0 and less than 50",
+			},
+		},
+		{
+			name: "Test 7: When fallback is invalid",
+			config: types.CollectionConfig{
+				Fallback: &types.FallbackConfig{
+					Chain:              []string{"secondary jobs", "last value", "skip"},
+					MaxStalenessEpochs: -1,
+				},
+			},
+			collection: "ethCollectionMean",
+			wantProblems: []string{
+				"assets.collection.ethCollectionMean.fallback.chain[0]: secondary jobs are required",
+				"assets.collection.ethCollectionMean.fallback.chain[1]: must be one of secondary jobs, previous value, skip",
+				"assets.collection.ethCollectionMean.fallback.max staleness epochs: must be between 0 and 4294967295",
+			},
+		},
+		{
+			name: "Test 8: When fallback has an invalid secondary job",
+			config: types.CollectionConfig{
+				Fallback: &types.FallbackConfig{
+					Chain:         []string{"Secondary Jobs", "previous value"},
+					SecondaryJobs: []types.JobConfig{validJob, {URL: validJob.URL, Selector: "last"}},
+				},
+			},
+			collection: "ethCollectionMean",
+			wantProblems: []string{
+				"assets.collection.ethCollectionMean.fallback.secondary jobs[1].weight: must be between 1 and 255",
+			},
+		},
+		{
+			name: "Test 9: When plugin jobs are invalid",
+			config: types.CollectionConfig{CustomJobs: []types.JobConfig{
+				{Name: "eth_exec", Weight: 1, Plugin: &types.PluginConfig{Type: "exec", Command: "/bin/sh", Timeout: 2}},
+				{Name: "eth_grpc", Weight: 1, URL: validJob.URL, Plugin: &types.PluginConfig{Type: "grpc", Address: "10.0.0.5:50051"}},
+				{Name: "eth_http", Weight: 1, Plugin: &types.PluginConfig{Type: "http", URL: "http://localhost:8080/eth", Timeout: 60}},
+				{Name: "eth_ws", Weight: 1, Plugin: &types.PluginConfig{Type: "websocket"}},
+			}},
+			collection: "ethCollectionMean",
+			wantProblems: []string{
+				"assets.collection.ethCollectionMean.custom jobs[1].plugin.address: must be a local address",
+				"assets.collection.ethCollectionMean.custom jobs[1].URL: must not be set for a plugin job",
+				"assets.collection.ethCollectionMean.custom jobs[2].plugin.timeout: must be between 0 and 30 seconds",
+				"assets.collection.ethCollectionMean.custom jobs[3].plugin.type: must be one of exec, grpc, http",
+			},
+		},
+		{
+			name: "Test 10: When freshness of jobs is invalid",
+			config: types.CollectionConfig{CustomJobs: []types.JobConfig{
+				{Name: "eth_gemini", URL: validJob.URL, Selector: "last", Weight: 1, Freshness: &types.FreshnessConfig{TimestampSelector: "volume.timestamp", MaxAge: 60}},
+				{Name: "eth_kraken", URL: validJob.URL, Selector: "last", Weight: 1, Freshness: &types.FreshnessConfig{TimestampSelector: "volume["}},
+				{Name: "eth_exec", Weight: 1, Plugin: &types.PluginConfig{Type: "exec", Command: "/bin/sh"}, Freshness: &types.FreshnessConfig{MaxAge: 60}},
+			}},
+			collection: "ethCollectionMean",
+			wantProblems: []string{
+				"assets.collection.ethCollectionMean.custom jobs[1].freshness.timestamp selector: " + ValidateSelector(0, "volume[").Error(),
+				"assets.collection.ethCollectionMean.custom jobs[1].freshness.max age: must be greater than 0",
+				"assets.collection.ethCollectionMean.custom jobs[2].freshness.timestamp selector: is required",
+			},
+		},
+		{
+			name: "Test 11: When timeout and retries of jobs are invalid",
+			config: types.CollectionConfig{CustomJobs: []types.JobConfig{
+				{Name: "eth_gemini", URL: validJob.URL, Selector: "last", Weight: 1, Timeout: 2.5, Retries: &retries},
+				{Name: "eth_kraken", URL: validJob.URL, Selector: "last", Weight: 1, Timeout: 90, Retries: &tooManyRetries},
+				{Name: "eth_exec", Weight: 1, Plugin: &types.PluginConfig{Type: "exec", Command: "/bin/sh"}, Timeout: 2},
+			}},
+			collection: "ethCollectionMean",
+			wantProblems: []string{
+				"assets.collection.ethCollectionMean.custom jobs[1].timeout: must be between 0 and 60 seconds",
+				"assets.collection.ethCollectionMean.custom jobs[1].retries: must be between 0 and 7",
+				"assets.collection.ethCollectionMean.custom jobs[2]: timeout and retries of a plugin job are set in its plugin",
+			},
+		},
+		{
+			name: "Test 12: When bounds of reputation are invalid",
+			config: types.CollectionConfig{
+				CustomJobs: []types.JobConfig{validJob},
+				Reputation: &types.Reputation{MaxDeviation: 5, MaxFailureRate: 150, MinWeight: &negativeMinWeight, MinJobs: -1, MinSamples: 5},
+			},
+			collection: "ethCollectionMean",
+			wantProblems: []string{
+				"assets.collection.ethCollectionMean.reputation.max failure rate: must be between 0 and 100",
+				"assets.collection.ethCollectionMean.reputation.min weight: must be between 0 and 100",
+				"assets.collection.ethCollectionMean.reputation.min jobs: must not be negative",
+			},
+		},
+		{
+			name: "Test 13: When sanity bounds are invalid",
+			config: types.CollectionConfig{
+				CustomJobs:   []types.JobConfig{validJob},
+				SanityBounds: &types.SanityBounds{Min: &max, Max: &min, MaxChange: -5, Policy: "ignore"},
+			},
+			collection: "ethCollectionMean",
+			wantProblems: []string{
+				"assets.collection.ethCollectionMean.sanity bounds.min: must not be more than max",
+				"assets.collection.ethCollectionMean.sanity bounds.max change: must not be negative",
+				"assets.collection.ethCollectionMean.sanity bounds.policy: must be one of fallback, commit, block",
+			},
+		},
+		{
+			name: "Test 14: When sanity bounds have no bound",
+			config: types.CollectionConfig{
+				CustomJobs:   []types.JobConfig{validJob},
+				SanityBounds: &types.SanityBounds{Policy: "block"},
+			},
+			collection: "ethCollectionMean",
+			wantProblems: []string{
+				"assets.collection.ethCollectionMean.sanity bounds: at least one of min, max and max change is required",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := types.AssetsConfig{Assets: types.AssetsCollections{Collection: map[string]types.CollectionConfig{tt.collection: tt.config}}}
+			problems := ValidateAssetsConfig(config, collections)
+			if !reflect.DeepEqual(errorStrings(problems), tt.wantProblems) {
+				t.Errorf("ValidateAssetsConfig() problems = %v, want %v", errorStrings(problems), tt.wantProblems)
+			}
+		})
+	}
+}
+
+func TestValidateHTTPConfig(t *testing.T) {
+	dir := t.TempDir()
+	certFile, keyFile, _ := writeTestCertificate(t, dir, "client")
+
+	tests := []struct {
+		name         string
+		config       types.HTTPConfig
+		wantProblems []string
+	}{
+		{
+			name: "Test 1: When http settings are valid",
+			config: types.HTTPConfig{Proxy: "socks5://127.0.0.1:1080", CAFiles: []string{certFile},
+				ClientCertificate: &types.ClientCertificate{CertFile: certFile, KeyFile: keyFile},
+				MaxResponseSize:   1 << 20, ContentTypes: []string{"application/json", "text/*"}},
+			wantProblems: nil,
+		},
+		{
+			name: "Test 2: When http settings are invalid",
+			config: types.HTTPConfig{Proxy: "ftp://proxy.internal:21", CAFiles: []string{keyFile},
+				ClientCertificate: &types.ClientCertificate{CertFile: certFile},
+				MaxResponseSize:   -1, ContentTypes: []string{"json"}},
+			wantProblems: []string{
+				"http.proxy: invalid proxy ftp://proxy.internal:21, it must be a http, https, socks5 URL",
+				"http.ca files[0]: no PEM certificate in CA file " + keyFile,
+				"http.client certificate: cert file and key file are required",
+				"http.max response size: must not be negative",
+				"http.content types[0]: invalid content type json",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			problems := ValidateAssetsConfig(types.AssetsConfig{HTTP: &tt.config}, nil)
+			if !reflect.DeepEqual(errorStrings(problems), tt.wantProblems) {
+				t.Errorf("ValidateAssetsConfig() problems = %v, want %v", errorStrings(problems), tt.wantProblems)
+			}
+		})
+	}
+}
+
+func TestRemoveTrailingCommas(t *testing.T) {
+	tests := []struct {
+		name string
+		data string
+		want string
+	}{
+		{
+			name: "Test 1: When there are trailing commas in objects and arrays",
+			data: "{\"a\": [1, 2, ],\n \"b\": {\"c\": 1,\n},\n}",
+			want: "{\"a\": [1, 2 ],\n \"b\": {\"c\": 1\n}\n}",
+		},
+		{
+			name: "Test 2: When commas are inside strings",
+			data: `{"selector": "a,]", "b": "\",}",}`,
+			want: `{"selector": "a,]", "b": "\",}"}`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := string(removeTrailingCommas([]byte(tt.data))); got != tt.want {
+				t.Errorf("removeTrailingCommas() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func errorStrings(errs []error) []string {
+	var result []string
+	for _, err := range errs {
+		result = append(result, err.Error())
+	}
+	return result
+}
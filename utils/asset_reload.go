@@ -0,0 +1,163 @@
+//Package utils provides the utils functions
+package utils
+
+import (
+	"fmt"
+	"path/filepath"
+	"razor/core"
+	"razor/core/types"
+	"razor/metrics"
+	"razor/path"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/fsnotify/fsnotify"
+)
+
+//AssetsFile holds the validated content of assets.json while it is watched
+type AssetsFile struct {
+	mutex    sync.RWMutex
+	watching bool
+	epoch    uint32
+	current  assetsContent
+	pending  *assetsContent
+}
+
+var assetsFile = &AssetsFile{}
+
+//This function returns the content applied in the current epoch and whether the file is watched
+func (file *AssetsFile) Assets() (assetsContent, bool) {
+	file.mutex.RLock()
+	defer file.mutex.RUnlock()
+	return file.current, file.watching
+}
+
+//This function returns the content of assets.json applied in the current epoch, and whether it is watched
+func (*UtilsStruct) GetAppliedAssets() (string, *types.AssetsConfig, bool) {
+	assets, watching := assetsFile.Assets()
+	return assets.Data, assets.Config, watching
+}
+
+//This function sets the content which is used until a reloaded content is applied
+func (file *AssetsFile) Load(assets assetsContent) {
+	file.mutex.Lock()
+	defer file.mutex.Unlock()
+	file.watching = true
+	file.current = assets
+	file.pending = nil
+	assets.applyHTTPConfig()
+}
+
+//This function stores a reloaded content which replaces the current one from the next epoch
+func (file *AssetsFile) Reload(assets assetsContent) {
+	file.mutex.Lock()
+	defer file.mutex.Unlock()
+	file.pending = &assets
+}
+
+//This function sets the epoch of the file and applies the reloaded content if the epoch changed
+func (file *AssetsFile) SetEpoch(epoch uint32) {
+	file.mutex.Lock()
+	defer file.mutex.Unlock()
+	if file.epoch == epoch {
+		return
+	}
+	file.epoch = epoch
+	if file.pending != nil {
+		file.current = *file.pending
+		file.pending = nil
+		file.current.applyHTTPConfig()
+		log.Infof("Applying reloaded assets.json from epoch %d", epoch)
+		metrics.AssetsAppliedEpochMetric.Set(float64(epoch))
+	}
+}
+
+//This function loads assets.json and reloads it on every change, which is applied from the next epoch
+func (*UtilsStruct) WatchAssetsFile(client *ethclient.Client) error {
+	assetsFilePath, err := path.PathUtilsInterface.GetJobFilePath()
+	if err != nil {
+		return err
+	}
+	assets, problems, err := readValidAssets(client)
+	if err != nil {
+		return err
+	}
+	if len(problems) != 0 {
+		for _, problem := range problems {
+			log.Error(problem)
+		}
+		return fmt.Errorf("found %d problems in assets.json", len(problems))
+	}
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return err
+	}
+	// The directory is watched as editors often replace the file instead of writing to it
+	if err := watcher.Add(filepath.Dir(assetsFilePath)); err != nil {
+		watcher.Close()
+		return err
+	}
+	assetsFile.Load(assets)
+
+	go func() {
+		defer watcher.Close()
+		// Changes are reloaded once the file has been quiet for a while, so a file being written is not read half way
+		reloadTimer := time.NewTimer(core.AssetsReloadDelay)
+		reloadTimer.Stop()
+		for {
+			select {
+			case event, ok := <-watcher.Events:
+				if !ok {
+					return
+				}
+				if filepath.Clean(event.Name) == filepath.Clean(assetsFilePath) {
+					reloadTimer.Reset(core.AssetsReloadDelay)
+				}
+			case err, ok := <-watcher.Errors:
+				if !ok {
+					return
+				}
+				log.Error("Error in watching assets.json: ", err)
+			case <-reloadTimer.C:
+				reloadAssetsFile(client)
+			}
+		}
+	}()
+	return nil
+}
+
+//This function reads assets.json once and returns its decoded content with the problems found in that same content
+func readValidAssets(client *ethclient.Client) (assetsContent, []error, error) {
+	dataString, err := readAssetsFileFromDisk()
+	if err != nil {
+		return assetsContent{}, nil, err
+	}
+	problems, err := getAssetsProblems(client, dataString)
+	if err != nil || len(problems) != 0 {
+		return assetsContent{}, problems, err
+	}
+	assets, err := decodeAssets(dataString)
+	return assets, nil, err
+}
+
+//This function reads and validates assets.json, the last valid content is kept if the file has any problem
+func reloadAssetsFile(client *ethclient.Client) {
+	assets, problems, err := readValidAssets(client)
+	if err != nil {
+		log.Error("Error in reloading assets.json, keeping the last valid assets.json: ", err)
+		metrics.AssetsReloadsMetric.WithLabelValues("failure").Inc()
+		return
+	}
+	if len(problems) != 0 {
+		for _, problem := range problems {
+			log.Error(problem)
+		}
+		log.Errorf("Found %d problems in reloaded assets.json, keeping the last valid assets.json", len(problems))
+		metrics.AssetsReloadsMetric.WithLabelValues("failure").Inc()
+		return
+	}
+	assetsFile.Reload(assets)
+	log.Info("Reloaded assets.json, it will be applied from the next epoch")
+	metrics.AssetsReloadsMetric.WithLabelValues("success").Inc()
+}
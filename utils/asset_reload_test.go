@@ -0,0 +1,223 @@
+package utils
+
+import (
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"razor/path"
+	pathMocks "razor/path/mocks"
+	"razor/pkg/bindings"
+	"razor/utils/mocks"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestAssetsFileSetEpoch(t *testing.T) {
+	type args struct {
+		reload string
+		epochs []uint32
+	}
+	tests := []struct {
+		name string
+		args args
+		want string
+	}{
+		{
+			name: "Test 1: When reloaded content is applied in the next epoch",
+			args: args{
+				reload: "reloaded",
+				epochs: []uint32{5, 6},
+			},
+			want: "reloaded",
+		},
+		{
+			name: "Test 2: When reloaded content is not applied within the same epoch",
+			args: args{
+				reload: "reloaded",
+				epochs: []uint32{5, 5},
+			},
+			want: "loaded",
+		},
+		{
+			name: "Test 3: When there is no reloaded content",
+			args: args{
+				epochs: []uint32{5, 6},
+			},
+			want: "loaded",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			file := &AssetsFile{}
+			file.Load(assetsContent{Data: "loaded"})
+			file.SetEpoch(tt.args.epochs[0])
+			if tt.args.reload != "" {
+				file.Reload(assetsContent{Data: tt.args.reload})
+			}
+			for _, epoch := range tt.args.epochs[1:] {
+				file.SetEpoch(epoch)
+			}
+			got, watching := file.Assets()
+			if !watching {
+				t.Error("AssetsFile.Assets() is not watching after Load()")
+			}
+			if got.Data != tt.want {
+				t.Errorf("AssetsFile.Assets() got = %v, want %v", got.Data, tt.want)
+			}
+		})
+	}
+}
+
+func TestReloadAssetsFile(t *testing.T) {
+	var client *ethclient.Client
+	var fileInfo fs.FileInfo
+
+	collections := []bindings.StructsCollection{{Id: 1, Name: "ethCollectionMean", JobIDs: []uint16{1}}}
+
+	type args struct {
+		fileData       []byte
+		fileDataErr    error
+		collectionsErr error
+	}
+	tests := []struct {
+		name string
+		args args
+		want string
+	}{
+		{
+			name: "Test 1: When reloaded assets.json is valid",
+			args: args{
+				fileData: []byte(`{"assets": {"collection": {"ethCollectionMean": {"power": 3}}}}`),
+			},
+			want: `{"assets": {"collection": {"ethCollectionMean": {"power": 3}}}}`,
+		},
+		{
+			name: "Test 2: When reloaded assets.json has problems",
+			args: args{
+				fileData: []byte(`{"assets": {"collection": {"btcCollectionMean": {"power": 3}}}}`),
+			},
+			want: "last valid",
+		},
+		{
+			name: "Test 3: When reloaded assets.json is not JSON",
+			args: args{
+				fileData: []byte(`{"assets": `),
+			},
+			want: "last valid",
+		},
+		{
+			name: "Test 4: When there is an error in reading assets.json",
+			args: args{
+				fileDataErr: errors.New("read error"),
+			},
+			want: "last valid",
+		},
+		{
+			name: "Test 5: When there is an error in getting collections",
+			args: args{
+				fileData:       []byte(`{"assets": {"collection": {"ethCollectionMean": {"power": 3}}}}`),
+				collectionsErr: errors.New("collections error"),
+			},
+			want: "last valid",
+		},
+	}
+	defer func() { assetsFile = &AssetsFile{} }()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			utilsMock := new(mocks.Utils)
+			pathUtilsMock := new(pathMocks.PathInterface)
+			osUtilsMock := new(pathMocks.OSInterface)
+			ioUtilsMock := new(mocks.IoutilUtils)
+
+			optionsPackageStruct := OptionsPackageStruct{
+				UtilsInterface:  utilsMock,
+				IoutilInterface: ioUtilsMock,
+			}
+			path.PathUtilsInterface = pathUtilsMock
+			path.OSUtilsInterface = osUtilsMock
+			StartRazor(optionsPackageStruct)
+
+			pathUtilsMock.On("GetJobFilePath").Return("./razor/assets.json", nil)
+			osUtilsMock.On("Stat", mock.Anything).Return(fileInfo, nil)
+			osUtilsMock.On("Open", mock.Anything).Return(&os.File{}, nil)
+			ioUtilsMock.On("ReadAll", mock.Anything).Return(tt.args.fileData, tt.args.fileDataErr)
+			utilsMock.On("GetAllCollections", mock.AnythingOfType("*ethclient.Client")).Return(collections, tt.args.collectionsErr)
+
+			assetsFile = &AssetsFile{}
+			assetsFile.Load(assetsContent{Data: "last valid"})
+			assetsFile.SetEpoch(5)
+			reloadAssetsFile(client)
+			assetsFile.SetEpoch(6)
+
+			if got, _ := assetsFile.Assets(); got.Data != tt.want {
+				t.Errorf("reloadAssetsFile() applied = %v, want %v", got.Data, tt.want)
+			}
+		})
+	}
+}
+
+func TestWatchAssetsFile(t *testing.T) {
+	var client *ethclient.Client
+	var fileInfo fs.FileInfo
+
+	collections := []bindings.StructsCollection{{Id: 1, Name: "ethCollectionMean", JobIDs: []uint16{1}}}
+
+	tests := []struct {
+		name         string
+		fileData     []byte
+		wantErr      bool
+		wantWatching bool
+		want         string
+	}{
+		{
+			name:         "Test 1: When assets.json is valid",
+			fileData:     []byte(`{"assets": {"collection": {"ethCollectionMean": {"power": 3}}}}`),
+			wantErr:      false,
+			wantWatching: true,
+			want:         `{"assets": {"collection": {"ethCollectionMean": {"power": 3}}}}`,
+		},
+		{
+			name:         "Test 2: When assets.json has problems",
+			fileData:     []byte(`{"assets": {"collection": {"btcCollectionMean": {"power": 3}}}}`),
+			wantErr:      true,
+			wantWatching: false,
+		},
+	}
+	defer func() { assetsFile = &AssetsFile{} }()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			utilsMock := new(mocks.Utils)
+			pathUtilsMock := new(pathMocks.PathInterface)
+			osUtilsMock := new(pathMocks.OSInterface)
+			ioUtilsMock := new(mocks.IoutilUtils)
+
+			optionsPackageStruct := OptionsPackageStruct{
+				UtilsInterface:  utilsMock,
+				IoutilInterface: ioUtilsMock,
+			}
+			path.PathUtilsInterface = pathUtilsMock
+			path.OSUtilsInterface = osUtilsMock
+			utils := StartRazor(optionsPackageStruct)
+
+			pathUtilsMock.On("GetJobFilePath").Return(filepath.Join(t.TempDir(), "assets.json"), nil)
+			osUtilsMock.On("Stat", mock.Anything).Return(fileInfo, nil)
+			osUtilsMock.On("Open", mock.Anything).Return(&os.File{}, nil)
+			ioUtilsMock.On("ReadAll", mock.Anything).Return(tt.fileData, nil)
+			utilsMock.On("GetAllCollections", mock.AnythingOfType("*ethclient.Client")).Return(collections, nil)
+
+			assetsFile = &AssetsFile{}
+			err := utils.WatchAssetsFile(client)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("WatchAssetsFile() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			got, watching := assetsFile.Assets()
+			if watching != tt.wantWatching || got.Data != tt.want {
+				t.Errorf("WatchAssetsFile() loaded = %v, watching = %v, want %v, %v", got.Data, watching, tt.want, tt.wantWatching)
+			}
+			ioUtilsMock.AssertNumberOfCalls(t, "ReadAll", 1)
+		})
+	}
+}
@@ -1,9 +1,13 @@
 package utils
 
 import (
+	"bytes"
+	"context"
+	"encoding/json"
 	"errors"
 	"io/fs"
 	"math/big"
+	"net/http/httptest"
 	"os"
 	"razor/core/types"
 	"razor/path"
@@ -11,7 +15,9 @@ import (
 	"razor/pkg/bindings"
 	"razor/utils/mocks"
 	"reflect"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/avast/retry-go"
 	"github.com/ethereum/go-ethereum/accounts/abi/bind"
@@ -19,6 +25,19 @@ import (
 	"github.com/stretchr/testify/mock"
 )
 
+//This function mocks the state kept while collections are aggregated, as it is while voting without watching assets.json, recording or replaying
+func mockAggregationState(utilsMock *mocks.Utils) {
+	utilsMock.On("GetAppliedAssets").Return("", (*types.AssetsConfig)(nil), false)
+	utilsMock.On("GetReplayedChain").Return((*types.RecordedChain)(nil))
+	utilsMock.On("SettleJobHealth", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	utilsMock.On("RecordCollectionState", mock.Anything)
+	utilsMock.On("RecordJobHealth", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	utilsMock.On("ApplyJobReputation", mock.Anything, mock.Anything, mock.Anything).Return(func(collectionName string, jobsData []types.JobData, reputation types.Reputation) []types.JobData {
+		return jobsData
+	})
+	utilsMock.On("WriteHistory", mock.Anything)
+}
+
 func TestAggregate(t *testing.T) {
 	var client *ethclient.Client
 	var previousEpoch uint32
@@ -54,7 +73,7 @@ func TestAggregate(t *testing.T) {
 		jsonFileErr           error
 		fileData              []byte
 		fileDataErr           error
-		overrrideJobs         []bindings.StructsJob
+		overrrideJobs         []types.AssetJob
 		overrideJobIds        []uint16
 	}
 	tests := []struct {
@@ -164,6 +183,36 @@ func TestAggregate(t *testing.T) {
 			want:    nil,
 			wantErr: true,
 		},
+		{
+			name: "Test 10: When outlier filter of the collection rejects a value",
+			args: args{
+				collection:         collection,
+				activeJob:          job,
+				dataToCommit:       []*big.Int{big.NewInt(100), big.NewInt(102), big.NewInt(1000)},
+				weight:             []uint8{1, 1, 1},
+				prevCommitmentData: big.NewInt(1),
+				assetFilePath:      "./razor/assets.json",
+				jsonFile:           &os.File{},
+				fileData:           []byte(`{"assets": {"collection": {"ethCollectionMean": {"outlier filter": {"method": "percentage", "threshold": 10}}}}}`),
+			},
+			want:    big.NewInt(101),
+			wantErr: false,
+		},
+		{
+			name: "Test 11: When values left after outlier rejection are less than min quorum",
+			args: args{
+				collection:         collection,
+				activeJob:          job,
+				dataToCommit:       []*big.Int{big.NewInt(100), big.NewInt(102), big.NewInt(1000)},
+				weight:             []uint8{1, 1, 1},
+				prevCommitmentData: big.NewInt(1),
+				assetFilePath:      "./razor/assets.json",
+				jsonFile:           &os.File{},
+				fileData:           []byte(`{"assets": {"collection": {"ethCollectionMean": {"outlier filter": {"method": "percentage", "threshold": 10, "min quorum": 3}}}}}`),
+			},
+			want:    big.NewInt(1),
+			wantErr: false,
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -179,9 +228,10 @@ func TestAggregate(t *testing.T) {
 			path.PathUtilsInterface = pathUtilsMock
 			path.OSUtilsInterface = osUtilsMock
 			utils := StartRazor(optionsPackageStruct)
+			mockAggregationState(utilsMock)
 
 			utilsMock.On("GetActiveJob", mock.AnythingOfType("*ethclient.Client"), mock.AnythingOfType("uint16")).Return(tt.args.activeJob, tt.args.activeJobErr)
-			utilsMock.On("GetDataToCommitFromJobs", mock.Anything).Return(tt.args.dataToCommit, tt.args.weight, tt.args.dataToCommitErr)
+			utilsMock.On("GetDataToCommitFromJobs", mock.Anything).Return(getJobsData(tt.args.dataToCommit, tt.args.weight), tt.args.dataToCommitErr)
 			utilsMock.On("FetchPreviousValue", mock.AnythingOfType("*ethclient.Client"), mock.AnythingOfType("uint32"), mock.AnythingOfType("uint16")).Return(tt.args.prevCommitmentData, tt.args.prevCommitmentDataErr)
 			pathUtilsMock.On("GetJobFilePath").Return(tt.args.assetFilePath, tt.args.assetFilePathErr)
 			osUtilsMock.On("Stat", mock.Anything).Return(fileInfo, tt.args.statErr)
@@ -201,6 +251,194 @@ func TestAggregate(t *testing.T) {
 	}
 }
 
+func TestAggregateJobsData(t *testing.T) {
+	collection := bindings.StructsCollection{Id: 4, Power: 3, AggregationMethod: 1, Name: "ethCollectionMedian"}
+	jobsData := getJobsData([]*big.Int{big.NewInt(100), big.NewInt(200), big.NewInt(400)}, []uint8{1, 1, 2})
+	tests := []struct {
+		name        string
+		aggregation *types.CollectionAggregation
+		want        *big.Int
+		wantReport  types.AggregationReport
+		wantErr     bool
+	}{
+		{
+			name:       "Test 1: When the collection is aggregated with its method on chain",
+			want:       big.NewInt(200),
+			wantReport: types.AggregationReport{Source: "jobs", Method: "median", Power: 3, JobsData: jobsData},
+		},
+		{
+			name:        "Test 2: When the collection is aggregated with the method from assets.json",
+			aggregation: &types.CollectionAggregation{Method: "Mean"},
+			want:        big.NewInt(275),
+			wantReport:  types.AggregationReport{Source: "jobs", Method: "mean", Power: 3, JobsData: jobsData},
+		},
+		{
+			name:        "Test 3: When the collection cannot be aggregated",
+			aggregation: &types.CollectionAggregation{Method: "unknown"},
+			wantErr:     true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			aggregationReports = &AggregationReports{reports: make(map[uint16]types.AggregationReport)}
+			got, err := aggregateJobsData(collection, "jobs", jobsData, tt.aggregation)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("aggregateJobsData() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			gotReport, reported := (&UtilsStruct{}).GetAggregationReport(collection.Id)
+			if tt.wantErr {
+				if reported {
+					t.Errorf("aggregateJobsData() reported %v for a collection which is not aggregated", gotReport)
+				}
+				return
+			}
+			if got.Cmp(tt.want) != 0 {
+				t.Errorf("aggregateJobsData() got = %v, want %v", got, tt.want)
+			}
+			if !reported || !reflect.DeepEqual(gotReport, tt.wantReport) {
+				t.Errorf("aggregateJobsData() reported %v, want %v", gotReport, tt.wantReport)
+			}
+		})
+	}
+}
+
+func TestGetCollectionJobs(t *testing.T) {
+	var client *ethclient.Client
+	var fileInfo fs.FileInfo
+
+	job := bindings.StructsJob{Id: 1, Weight: 100, Power: 2, Name: "ethusd_gemini", Selector: "last",
+		Url: "https://api.gemini.com/v1/pubticker/ethusd",
+	}
+	overriddenJob := bindings.StructsJob{Id: 2, Weight: 2, Power: 2, Name: "ethusd_kraken", Selector: "last",
+		Url: "http://127.0.0.1/kraken",
+	}
+	collection := bindings.StructsCollection{Active: true, Id: 4, Power: 2, AggregationMethod: 2,
+		JobIDs: []uint16{1, 2}, Name: "ethCollectionMean",
+	}
+	fileData := []byte(`{"assets": {"collection": {"ethCollectionMean": {
+		"custom jobs": [{"name": "ethusd_local", "URL": "http://127.0.0.1/eth", "selector": "last", "power": 2, "weight": 1}],
+		"derived jobs": [{"name": "ethusd_avg", "expression": "avg(ethusd_gemini, ethusd_local)", "power": 2, "weight": 1}]
+	}}}}`)
+	customJob := types.AssetJob{StructsJob: bindings.StructsJob{Name: "ethusd_local", Url: "http://127.0.0.1/eth", Selector: "last", Power: 2, Weight: 1}}
+	volumeFileData := func(method string) []byte {
+		return []byte(`{"assets": {"collection": {"ethCollectionMean": {
+			"aggregation": {"method": "` + method + `"},
+			"custom jobs": [{"name": "ethusd_local", "URL": "http://127.0.0.1/eth", "selector": "last", "volume selector": "volume", "power": 2, "weight": 1}]
+		}}}}`)
+	}
+
+	type args struct {
+		assetFilePathErr error
+		statErr          error
+		fileData         []byte
+		fileDataErr      error
+		overrideJobs     []types.AssetJob
+		overrideJobIds   []uint16
+		activeJobErr     error
+	}
+	tests := []struct {
+		name    string
+		args    args
+		want    []types.AssetJob
+		wantErr bool
+	}{
+		{
+			name: "Test 1: When assets.json has overridden, custom and derived jobs of the collection",
+			args: args{
+				fileData:       fileData,
+				overrideJobs:   []types.AssetJob{{StructsJob: overriddenJob}},
+				overrideJobIds: []uint16{2},
+			},
+			want: []types.AssetJob{
+				{StructsJob: overriddenJob},
+				customJob,
+				{StructsJob: job},
+				{
+					StructsJob: bindings.StructsJob{Name: "ethusd_avg", Power: 2, Weight: 1},
+					Expression: "avg(ethusd_gemini, ethusd_local)",
+					Inputs:     map[string]types.AssetJob{"ethusd_gemini": {StructsJob: job}, "ethusd_local": customJob},
+				},
+			},
+		},
+		{
+			name: "Test 2: When assets.json does not exist",
+			args: args{
+				statErr: os.ErrNotExist,
+			},
+			want: []types.AssetJob{{StructsJob: job}, {StructsJob: job}},
+		},
+		{
+			name: "Test 3: When a job cannot be fetched",
+			args: args{
+				statErr:      os.ErrNotExist,
+				activeJobErr: errors.New("job error"),
+			},
+			want: nil,
+		},
+		{
+			name: "Test 4: When there is an error in getting assets file path",
+			args: args{
+				assetFilePathErr: errors.New("path error"),
+			},
+			wantErr: true,
+		},
+		{
+			name: "Test 5: When there is an error in reading assets.json",
+			args: args{
+				fileDataErr: errors.New("read error"),
+			},
+			wantErr: true,
+		},
+		{
+			name: "Test 6: When the collection is aggregated by vwap the volume selectors of its jobs are kept",
+			args: args{
+				fileData: volumeFileData("vwap"),
+			},
+			want: []types.AssetJob{{StructsJob: customJob.StructsJob, VolumeSelector: "volume"}, {StructsJob: job}, {StructsJob: job}},
+		},
+		{
+			name: "Test 7: When the collection is not aggregated by vwap the volumes of its jobs are not fetched",
+			args: args{
+				fileData: volumeFileData("median"),
+			},
+			want: []types.AssetJob{customJob, {StructsJob: job}, {StructsJob: job}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			utilsMock := new(mocks.Utils)
+			pathUtilsMock := new(pathMocks.PathInterface)
+			osUtilsMock := new(pathMocks.OSInterface)
+			ioUtilsMock := new(mocks.IoutilUtils)
+
+			optionsPackageStruct := OptionsPackageStruct{
+				UtilsInterface:  utilsMock,
+				IoutilInterface: ioUtilsMock,
+			}
+			path.PathUtilsInterface = pathUtilsMock
+			path.OSUtilsInterface = osUtilsMock
+			utils := StartRazor(optionsPackageStruct)
+			mockAggregationState(utilsMock)
+
+			utilsMock.On("GetActiveJob", mock.AnythingOfType("*ethclient.Client"), mock.AnythingOfType("uint16")).Return(job, tt.args.activeJobErr)
+			pathUtilsMock.On("GetJobFilePath").Return("./razor/assets.json", tt.args.assetFilePathErr)
+			osUtilsMock.On("Stat", mock.Anything).Return(fileInfo, tt.args.statErr)
+			osUtilsMock.On("Open", mock.Anything).Return(&os.File{}, nil)
+			ioUtilsMock.On("ReadAll", mock.Anything).Return(tt.args.fileData, tt.args.fileDataErr)
+			utilsMock.On("HandleOfficialJobsFromJSONFile", mock.Anything, mock.Anything, mock.Anything).Return(tt.args.overrideJobs, tt.args.overrideJobIds)
+
+			got, err := utils.GetCollectionJobs(client, collection)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("GetCollectionJobs() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("GetCollectionJobs() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
 func TestGetActiveCollectionIds(t *testing.T) {
 	var client *ethclient.Client
 	var callOpts bind.CallOpts
@@ -280,6 +518,7 @@ func TestGetActiveCollection(t *testing.T) {
 	type args struct {
 		collection    bindings.StructsCollection
 		collectionErr error
+		replayedChain *types.RecordedChain
 	}
 	tests := []struct {
 		name    string
@@ -311,6 +550,32 @@ func TestGetActiveCollection(t *testing.T) {
 			want:    bindings.StructsCollection{},
 			wantErr: true,
 		},
+		{
+			name: "Test 4: When the epoch is replayed the recorded collection is returned",
+			args: args{
+				collectionErr: errors.New("collection error"),
+				replayedChain: &types.RecordedChain{Collections: map[uint16]bindings.StructsCollection{0: collectionEth}},
+			},
+			want:    collectionEth,
+			wantErr: false,
+		},
+		{
+			name: "Test 5: When the epoch is replayed and the collection is not recorded",
+			args: args{
+				collection:    collectionEth,
+				replayedChain: &types.RecordedChain{},
+			},
+			want:    bindings.StructsCollection{},
+			wantErr: true,
+		},
+		{
+			name: "Test 6: When the epoch is replayed and the recorded collection is inactive",
+			args: args{
+				replayedChain: &types.RecordedChain{Collections: map[uint16]bindings.StructsCollection{0: collectionEthInactive}},
+			},
+			want:    bindings.StructsCollection{},
+			wantErr: true,
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -322,6 +587,8 @@ func TestGetActiveCollection(t *testing.T) {
 			utils := StartRazor(optionsPackageStruct)
 
 			utilsMock.On("GetCollection", mock.AnythingOfType("*ethclient.Client"), mock.AnythingOfType("uint16")).Return(tt.args.collection, tt.args.collectionErr)
+			utilsMock.On("GetReplayedChain").Return(tt.args.replayedChain)
+			utilsMock.On("RecordChain", mock.Anything)
 
 			got, err := utils.GetActiveCollection(client, collectionId)
 			if (err != nil) != tt.wantErr {
@@ -346,8 +613,9 @@ func TestGetActiveJob(t *testing.T) {
 	}
 
 	type args struct {
-		job    bindings.StructsJob
-		jobErr error
+		job           bindings.StructsJob
+		jobErr        error
+		replayedChain *types.RecordedChain
 	}
 	tests := []struct {
 		name    string
@@ -371,6 +639,24 @@ func TestGetActiveJob(t *testing.T) {
 			want:    bindings.StructsJob{},
 			wantErr: true,
 		},
+		{
+			name: "Test 3: When the epoch is replayed the recorded job is returned",
+			args: args{
+				jobErr:        errors.New("job error"),
+				replayedChain: &types.RecordedChain{Jobs: map[uint16]bindings.StructsJob{0: jobEth}},
+			},
+			want:    jobEth,
+			wantErr: false,
+		},
+		{
+			name: "Test 4: When the epoch is replayed and the job is not recorded",
+			args: args{
+				job:           jobEth,
+				replayedChain: &types.RecordedChain{},
+			},
+			want:    bindings.StructsJob{},
+			wantErr: true,
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -388,6 +674,8 @@ func TestGetActiveJob(t *testing.T) {
 			utilsMock.On("GetOptions").Return(callOpts)
 			assetManagerMock.On("Jobs", mock.AnythingOfType("*ethclient.Client"), mock.AnythingOfType("uint16")).Return(tt.args.job, tt.args.jobErr)
 			retryMock.On("RetryAttempts", mock.AnythingOfType("uint")).Return(retry.Attempts(1))
+			utilsMock.On("GetReplayedChain").Return(tt.args.replayedChain)
+			utilsMock.On("RecordChain", mock.Anything)
 
 			got, err := utils.GetActiveJob(client, jobId)
 			if (err != nil) != tt.wantErr {
@@ -547,15 +835,26 @@ func TestGetAllCollections(t *testing.T) {
 	}
 }
 
+func getJobsData(values []*big.Int, weights []uint8) []types.JobData {
+	var jobsData []types.JobData
+	for i := range values {
+		jobsData = append(jobsData, types.JobData{
+			Job:   types.AssetJob{StructsJob: bindings.StructsJob{Weight: weights[i]}},
+			Value: values[i],
+		})
+	}
+	return jobsData
+}
+
 func TestGetDataToCommitFromJobs(t *testing.T) {
-	jobsArray := []bindings.StructsJob{
-		{Id: 1, SelectorType: 1, Weight: 100,
+	jobsArray := []types.AssetJob{
+		{StructsJob: bindings.StructsJob{Id: 1, SelectorType: 1, Weight: 100,
 			Power: 2, Name: "ethusd_gemini", Selector: "last",
 			Url: "https://api.gemini.com/v1/pubticker/ethusd",
-		}, {Id: 2, SelectorType: 1, Weight: 100,
+		}}, {StructsJob: bindings.StructsJob{Id: 2, SelectorType: 1, Weight: 100,
 			Power: 2, Name: "ethusd_gemini", Selector: "last",
 			Url: "https://api.gemini.com/v1/pubticker/ethusd",
-		},
+		}},
 	}
 
 	type args struct {
@@ -565,12 +864,17 @@ func TestGetDataToCommitFromJobs(t *testing.T) {
 		overrideJobDataErr error
 		dataToAppend       *big.Int
 		dataToAppendErr    error
+		jobs               []types.AssetJob
+		volume             *big.Rat
+		volumeErr          error
+		deadlineReached    bool
 	}
 	tests := []struct {
-		name    string
-		args    args
-		want    []*big.Int
-		wantErr bool
+		name        string
+		args        args
+		want        []*big.Int
+		wantVolumes []*big.Rat
+		wantErr     bool
 	}{
 		{
 			name: "Test 1: When GetDataToCommitFromJobs() executes successfully",
@@ -616,6 +920,43 @@ func TestGetDataToCommitFromJobs(t *testing.T) {
 			want:    nil,
 			wantErr: false,
 		},
+		{
+			name: "Test 5: When volume is fetched for jobs having volume selector",
+			args: args{
+				jobs: []types.AssetJob{
+					jobsArray[0],
+					{StructsJob: jobsArray[1].StructsJob, VolumeSelector: "volume.ETH"},
+				},
+				dataToAppend: big.NewInt(1),
+				volume:       big.NewRat(1500, 1),
+			},
+			want:        []*big.Int{big.NewInt(1), big.NewInt(1)},
+			wantVolumes: []*big.Rat{nil, big.NewRat(1500, 1)},
+			wantErr:     false,
+		},
+		{
+			name: "Test 6: When volume of a job cannot be fetched, the job is dropped",
+			args: args{
+				jobs: []types.AssetJob{
+					jobsArray[0],
+					{StructsJob: jobsArray[1].StructsJob, VolumeSelector: "volume.ETH"},
+				},
+				dataToAppend: big.NewInt(1),
+				volumeErr:    errors.New("volume error"),
+			},
+			want:        []*big.Int{big.NewInt(1)},
+			wantVolumes: []*big.Rat{nil},
+			wantErr:     false,
+		},
+		{
+			name: "Test 7: When fetch deadline is reached before jobs are started",
+			args: args{
+				dataToAppend:    big.NewInt(1),
+				deadlineReached: true,
+			},
+			want:    nil,
+			wantErr: false,
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -628,32 +969,78 @@ func TestGetDataToCommitFromJobs(t *testing.T) {
 			}
 			utils := StartRazor(optionsPackageStruct)
 
+			fetchContext := context.Background()
+			if tt.args.deadlineReached {
+				var reachDeadline context.CancelFunc
+				fetchContext, reachDeadline = context.WithDeadline(context.Background(), time.Now())
+				defer reachDeadline()
+			}
+			utilsMock.On("GetFetchContext").Return(fetchContext)
 			pathMock.On("GetJobFilePath").Return(tt.args.jobPath, tt.args.jobPathErr)
 			utilsMock.On("ReadJSONData", mock.AnythingOfType("string")).Return(tt.args.overrideJobData, tt.args.overrideJobDataErr)
-			utilsMock.On("GetDataToCommitFromJob", mock.Anything).Return(tt.args.dataToAppend, tt.args.dataToAppendErr)
-
-			got, _, err := utils.GetDataToCommitFromJobs(jobsArray)
+			utilsMock.On("GetDataAndVolumeToCommitFromJob", mock.Anything).Return(tt.args.dataToAppend, func(job types.AssetJob) *big.Rat {
+				if job.VolumeSelector == "" {
+					return nil
+				}
+				return tt.args.volume
+			}, func(job types.AssetJob) error {
+				if job.VolumeSelector == "" {
+					return tt.args.dataToAppendErr
+				}
+				return tt.args.volumeErr
+			})
+
+			jobs := jobsArray
+			if tt.args.jobs != nil {
+				jobs = tt.args.jobs
+			}
+			got, err := utils.GetDataToCommitFromJobs(jobs)
 			if (err != nil) != tt.wantErr {
 				t.Errorf("GetDataToCommitFromJobs() error = %v, wantErr %v", err, tt.wantErr)
 				return
 			}
-			if !reflect.DeepEqual(got, tt.want) {
-				t.Errorf("GetDataToCommitFromJobs() got = %v, want %v", got, tt.want)
+			gotValues, _ := GetValuesAndWeightsFromJobsData(got)
+			if !reflect.DeepEqual(gotValues, tt.want) {
+				t.Errorf("GetDataToCommitFromJobs() got = %v, want %v", gotValues, tt.want)
+			}
+			if tt.wantVolumes != nil {
+				var gotVolumes []*big.Rat
+				for _, jobData := range got {
+					gotVolumes = append(gotVolumes, jobData.Volume)
+				}
+				if !reflect.DeepEqual(gotVolumes, tt.wantVolumes) {
+					t.Errorf("GetDataToCommitFromJobs() gotVolumes = %v, want %v", gotVolumes, tt.wantVolumes)
+				}
 			}
 		})
 	}
 }
 
 func TestGetDataToCommitFromJob(t *testing.T) {
-	job := bindings.StructsJob{Id: 1, SelectorType: 1, Weight: 100,
+	job := types.AssetJob{StructsJob: bindings.StructsJob{Id: 1, SelectorType: 1, Weight: 100,
 		Power: 2, Name: "ethusd_gemini", Selector: "last",
 		Url: "https://api.gemini.com/v1/pubticker/ethusd",
-	}
+	}}
 
-	job2 := bindings.StructsJob{Id: 1, SelectorType: 0, Weight: 100,
+	job2 := types.AssetJob{StructsJob: bindings.StructsJob{Id: 1, SelectorType: 0, Weight: 100,
 		Power: 2, Name: "ethusd_gemini", Selector: "last",
 		Url: "https://api.gemini.com/v1/pubticker/ethusd",
-	}
+	}}
+
+	cssJob := types.AssetJob{StructsJob: bindings.StructsJob{Id: 1, SelectorType: 2, Weight: 100,
+		Power: 2, Name: "ethusd_coinmarketcap", Selector: "div.priceValue",
+		Url: "https://coinmarketcap.com/currencies/ethereum/",
+	}}
+
+	gjsonJob := types.AssetJob{StructsJob: bindings.StructsJob{Id: 1, SelectorType: 5, Weight: 100,
+		Power: 2, Name: "ethusd_gemini", Selector: "last",
+		Url: "https://api.gemini.com/v1/pubticker/ethusd",
+	}}
+
+	invalidSelectorTypeJob := types.AssetJob{StructsJob: bindings.StructsJob{Id: 1, SelectorType: 9, Weight: 100,
+		Power: 2, Name: "ethusd_gemini", Selector: "last",
+		Url: "https://api.gemini.com/v1/pubticker/ethusd",
+	}}
 
 	response := []byte(`{
   			"userId": 1,
@@ -663,14 +1050,14 @@ func TestGetDataToCommitFromJob(t *testing.T) {
 	}`)
 
 	type args struct {
-		job           bindings.StructsJob
+		job           types.AssetJob
 		response      []byte
 		responseErr   error
 		parsedData    interface{}
 		parsedDataErr error
 		dataPoint     string
 		dataPointErr  error
-		datum         *big.Float
+		datum         *big.Rat
 		datumErr      error
 	}
 	tests := []struct {
@@ -686,7 +1073,7 @@ func TestGetDataToCommitFromJob(t *testing.T) {
 				response:   response,
 				parsedData: "abc",
 				dataPoint:  "1",
-				datum:      big.NewFloat(0.1),
+				datum:      big.NewRat(1, 10),
 			},
 			want:    big.NewInt(10),
 			wantErr: false,
@@ -698,7 +1085,7 @@ func TestGetDataToCommitFromJob(t *testing.T) {
 				responseErr: errors.New("response error"),
 				parsedData:  "abc",
 				dataPoint:   "1",
-				datum:       big.NewFloat(0.1),
+				datum:       big.NewRat(1, 10),
 			},
 			want:    big.NewInt(10),
 			wantErr: false,
@@ -710,7 +1097,7 @@ func TestGetDataToCommitFromJob(t *testing.T) {
 				response:      response,
 				parsedDataErr: errors.New("parsedData error"),
 				dataPoint:     "1",
-				datum:         big.NewFloat(0.1),
+				datum:         big.NewRat(1, 10),
 			},
 			want:    big.NewInt(10),
 			wantErr: false,
@@ -722,7 +1109,7 @@ func TestGetDataToCommitFromJob(t *testing.T) {
 				response:     response,
 				parsedData:   "abc",
 				dataPointErr: errors.New("dataPoint error"),
-				datum:        big.NewFloat(0.1),
+				datum:        big.NewRat(1, 10),
 			},
 			want:    nil,
 			wantErr: true,
@@ -746,7 +1133,7 @@ func TestGetDataToCommitFromJob(t *testing.T) {
 				response:   []byte(""),
 				parsedData: "abc",
 				dataPoint:  "1",
-				datum:      big.NewFloat(0.1),
+				datum:      big.NewRat(1, 10),
 			},
 			want:    nil,
 			wantErr: true,
@@ -758,7 +1145,7 @@ func TestGetDataToCommitFromJob(t *testing.T) {
 				responseErr: errors.New("API error"),
 				parsedData:  "abc",
 				dataPoint:   "1",
-				datum:       big.NewFloat(0.1),
+				datum:       big.NewRat(1, 10),
 			},
 			want:    nil,
 			wantErr: true,
@@ -770,7 +1157,64 @@ func TestGetDataToCommitFromJob(t *testing.T) {
 				response:      response,
 				parsedDataErr: errors.New("parseData error"),
 				dataPoint:     "1",
-				datum:         big.NewFloat(0.1),
+				datum:         big.NewRat(1, 10),
+			},
+			want:    nil,
+			wantErr: true,
+		},
+		{
+			name: "Test 9: When selector type is CSS",
+			args: args{
+				job:       cssJob,
+				dataPoint: "$1,234.5",
+				datum:     big.NewRat(12345, 10),
+			},
+			want:    big.NewInt(123450),
+			wantErr: false,
+		},
+		{
+			name: "Test 10: When selector type is CSS and there is an error in getting dataPoint",
+			args: args{
+				job:          cssJob,
+				dataPointErr: errors.New("no element matches CSS selector"),
+			},
+			want:    nil,
+			wantErr: true,
+		},
+		{
+			name: "Test 11: When selector type is gjson",
+			args: args{
+				job:      gjsonJob,
+				response: []byte(`{"bid": "1234.1", "last": "1234.5"}`),
+				datum:    big.NewRat(12345, 10),
+			},
+			want:    big.NewInt(123450),
+			wantErr: false,
+		},
+		{
+			name: "Test 12: When selector type is gjson and selector does not match",
+			args: args{
+				job:      gjsonJob,
+				response: []byte(`{"bid": "1234.1"}`),
+				datum:    big.NewRat(12345, 10),
+			},
+			want:    nil,
+			wantErr: true,
+		},
+		{
+			name: "Test 13: When selector type is gjson and there is an error in getting response",
+			args: args{
+				job:         gjsonJob,
+				responseErr: errors.New("API error"),
+			},
+			want:    nil,
+			wantErr: true,
+		},
+		{
+			name: "Test 14: When selector type is invalid",
+			args: args{
+				job:   invalidSelectorTypeJob,
+				datum: big.NewRat(12345, 10),
 			},
 			want:    nil,
 			wantErr: true,
@@ -787,10 +1231,11 @@ func TestGetDataToCommitFromJob(t *testing.T) {
 			}
 			utils := StartRazor(optionsPackageStruct)
 
-			utilsMock.On("GetDataFromAPI", mock.AnythingOfType("string")).Return(tt.args.response, tt.args.responseErr)
+			utilsMock.On("GetDataFromAPI", mock.AnythingOfType("string"), mock.Anything).Return(tt.args.response, tt.args.responseErr)
 			retryMock.On("RetryAttempts", mock.AnythingOfType("uint")).Return(retry.Attempts(1))
 			utilsMock.On("GetDataFromJSON", mock.Anything, mock.AnythingOfType("string")).Return(tt.args.parsedData, tt.args.parsedDataErr)
-			utilsMock.On("GetDataFromXHTML", mock.AnythingOfType("string"), mock.AnythingOfType("string")).Return(tt.args.dataPoint, tt.args.dataPointErr)
+			utilsMock.On("GetDataFromXHTML", mock.AnythingOfType("string"), mock.Anything, mock.Anything).Return([]string{tt.args.dataPoint}, tt.args.dataPointErr)
+			utilsMock.On("GetDataFromHTML", mock.AnythingOfType("string"), mock.Anything, mock.Anything).Return([]string{tt.args.dataPoint}, tt.args.dataPointErr)
 			utilsMock.On("ConvertToNumber", mock.Anything).Return(tt.args.datum, tt.args.datumErr)
 
 			got, err := utils.GetDataToCommitFromJob(tt.args.job)
@@ -805,6 +1250,37 @@ func TestGetDataToCommitFromJob(t *testing.T) {
 	}
 }
 
+func TestGetResponseFromJobWithQueryAuth(t *testing.T) {
+	t.Setenv("RAZOR_TEST_API_KEY", "query-secret")
+	// The source is closed, so that fetching the job fails
+	server := httptest.NewServer(nil)
+	server.Close()
+
+	retryMock := new(mocks.RetryUtils)
+	StartRazor(OptionsPackageStruct{RetryInterface: retryMock, UtilsInterface: &UtilsStruct{}})
+	retryMock.On("RetryAttempts", mock.AnythingOfType("uint")).Return(retry.Attempts(1))
+
+	logs := &bytes.Buffer{}
+	out := log.Out
+	log.SetOutput(logs)
+	defer log.SetOutput(out)
+
+	job := types.AssetJob{StructsJob: bindings.StructsJob{Url: server.URL + "/price"}, Request: types.JobRequest{Auth: &types.JobAuth{Query: "apikey", Env: "RAZOR_TEST_API_KEY"}}}
+	_, err := getResponseFromJob(job)
+	if err == nil {
+		t.Fatal("getResponseFromJob() error = nil, want an error")
+	}
+	if strings.Contains(err.Error(), "query-secret") {
+		t.Errorf("getResponseFromJob() error = %v, contains the API key", err)
+	}
+	if !strings.Contains(logs.String(), "Error in fetching data from API") {
+		t.Errorf("getResponseFromJob() did not log the failed fetch")
+	}
+	if strings.Contains(logs.String(), "query-secret") {
+		t.Errorf("getResponseFromJob() logged the API key: %s", logs.String())
+	}
+}
+
 func TestGetJobs(t *testing.T) {
 	var client *ethclient.Client
 
@@ -1031,51 +1507,382 @@ func TestGetAssetManagerWithOpts(t *testing.T) {
 	}
 }
 
-func TestGetCustomJobsFromJSONFile(t *testing.T) {
+func TestGetDataAndVolumeToCommitFromJob(t *testing.T) {
+	job := types.AssetJob{
+		StructsJob: bindings.StructsJob{Id: 1, SelectorType: 0, Weight: 100,
+			Power: 2, Name: "ethusd_gemini", Selector: "last",
+			Url: "https://api.gemini.com/v1/pubticker/ethusd",
+		},
+		VolumeSelector: "volume.ETH",
+	}
+
 	type args struct {
-		collection   string
-		jsonFileData string
+		job           types.AssetJob
+		response      []byte
+		responseErr   error
+		volumeData    interface{}
+		volumeDataErr error
+	}
+	tests := []struct {
+		name       string
+		args       args
+		want       *big.Int
+		wantVolume *big.Rat
+		wantErr    bool
+	}{
+		{
+			name: "Test 1: When GetDataAndVolumeToCommitFromJob() executes successfully",
+			args: args{
+				job:        job,
+				response:   []byte(`{"last": "1500.5", "volume": {"ETH": "2500.25"}}`),
+				volumeData: "2500.25",
+			},
+			want:       big.NewInt(150050),
+			wantVolume: big.NewRat(10001, 4),
+			wantErr:    false,
+		},
+		{
+			name: "Test 2: When there is an error in getting volume from parsed data",
+			args: args{
+				job:           job,
+				response:      []byte(`{"last": "1500.5"}`),
+				volumeDataErr: errors.New("unknown key volume"),
+			},
+			want:       nil,
+			wantVolume: nil,
+			wantErr:    true,
+		},
+		{
+			name: "Test 3: When volume is not a number",
+			args: args{
+				job:        job,
+				response:   []byte(`{"last": "1500.5", "volume": {"ETH": "n/a"}}`),
+				volumeData: "n/a",
+			},
+			want:       nil,
+			wantVolume: nil,
+			wantErr:    true,
+		},
+		{
+			name: "Test 4: When job has no volume selector",
+			args: args{
+				job:      types.AssetJob{StructsJob: job.StructsJob},
+				response: []byte(`{"last": "1500.5"}`),
+			},
+			want:       big.NewInt(150050),
+			wantVolume: nil,
+			wantErr:    false,
+		},
+		{
+			name: "Test 5: When there is an error in getting response",
+			args: args{
+				job:         job,
+				responseErr: errors.New("API error"),
+			},
+			want:       nil,
+			wantVolume: nil,
+			wantErr:    true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			retryMock := new(mocks.RetryUtils)
+			utilsMock := new(mocks.Utils)
+
+			optionsPackageStruct := OptionsPackageStruct{
+				RetryInterface: retryMock,
+				UtilsInterface: utilsMock,
+			}
+			utils := StartRazor(optionsPackageStruct)
+
+			utilsMock.On("GetDataFromAPI", mock.AnythingOfType("string"), mock.Anything).Return(tt.args.response, tt.args.responseErr)
+			retryMock.On("RetryAttempts", mock.AnythingOfType("uint")).Return(retry.Attempts(1))
+			utilsMock.On("GetDataFromJSON", mock.Anything, "last").Return("1500.5", nil)
+			utilsMock.On("GetDataFromJSON", mock.Anything, "volume.ETH").Return(tt.args.volumeData, tt.args.volumeDataErr)
+			utilsMock.On("ConvertToNumber", "1500.5").Return(big.NewRat(3001, 2), nil)
+			utilsMock.On("ConvertToNumber", "2500.25").Return(big.NewRat(10001, 4), nil)
+			utilsMock.On("ConvertToNumber", "n/a").Return(nil, errors.New("not a number"))
+
+			got, gotVolume, err := utils.GetDataAndVolumeToCommitFromJob(tt.args.job)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("GetDataAndVolumeToCommitFromJob() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("GetDataAndVolumeToCommitFromJob() got = %v, want %v", got, tt.want)
+			}
+			if !reflect.DeepEqual(gotVolume, tt.wantVolume) {
+				t.Errorf("GetDataAndVolumeToCommitFromJob() gotVolume = %v, want %v", gotVolume, tt.wantVolume)
+			}
+		})
+	}
+}
+
+func TestGetCustomJobsFromConfig(t *testing.T) {
+	type args struct {
+		collection string
 	}
 	tests := []struct {
 		name string
 		args args
-		want []bindings.StructsJob
+		want []types.AssetJob
 	}{
 		{
-			name: "Test 1: When collection is present in json file string",
+			name: "Test 1: When collection is present in assets.json",
 			args: args{
-				collection:   "ethCollection",
-				jsonFileData: jsonDataString,
+				collection: "ethCollection",
 			},
-			want: []bindings.StructsJob{
+			want: []types.AssetJob{
 				{
-					Url:      "http://127.0.0.1/eth1",
-					Selector: "eth1",
-					Power:    2,
-					Weight:   3,
+					StructsJob: bindings.StructsJob{
+						Name:     "eth1",
+						Url:      "http://127.0.0.1/eth1",
+						Selector: "eth1",
+						Power:    2,
+						Weight:   3,
+					},
+					VolumeSelector: "eth1_volume",
 				},
 				{
-					Url:      "http://127.0.0.1/eth2",
-					Selector: "eth2",
-					Power:    2,
-					Weight:   2,
+					StructsJob: bindings.StructsJob{
+						Url:          "http://127.0.0.1/eth2",
+						Selector:     "eth2",
+						SelectorType: 5,
+						Power:        2,
+						Weight:       2,
+					},
+					Request: types.JobRequest{
+						Method:   "POST",
+						Headers:  map[string]string{"Accept": "application/json"},
+						Body:     `{"symbol":"ETH"}`,
+						Auth:     &types.JobAuth{Env: "ETH2_API_KEY", Header: "X-API-KEY"},
+						Timeout:  2500 * time.Millisecond,
+						Attempts: 3,
+					},
 				},
 			},
 		},
 		{
-			name: "Test 2: When collection is not present in json file string",
+			name: "Test 2: When collection is not present in assets.json",
 			args: args{
-				collection:   "btcCollection",
-				jsonFileData: jsonDataString,
+				collection: "btcCollection",
 			},
 			want: nil,
 		},
 	}
+	config, err := DecodeAssetsConfig(jsonDataString)
+	if err != nil {
+		t.Fatal(err)
+	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			got := GetCustomJobsFromJSONFile(tt.args.collection, tt.args.jsonFileData)
+			got := GetCustomJobsFromConfig(tt.args.collection, config.Assets.Collection[tt.args.collection])
 			if !reflect.DeepEqual(got, tt.want) {
-				t.Errorf("GetCustomJobsFromJSONFile() got = %v, want %v", got, tt.want)
+				t.Errorf("GetCustomJobsFromConfig() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGetDerivedJobsFromConfig(t *testing.T) {
+	type args struct {
+		collection string
+	}
+	tests := []struct {
+		name string
+		args args
+		want []types.AssetJob
+	}{
+		{
+			name: "Test 1: When collection has derived jobs in assets.json",
+			args: args{
+				collection: "ethCollection",
+			},
+			want: []types.AssetJob{
+				{
+					StructsJob: bindings.StructsJob{
+						Name:   "ethbtc",
+						Power:  6,
+						Weight: 1,
+					},
+					Expression: "eth1 / btcusd",
+					Inputs: map[string]types.AssetJob{
+						"btcusd": {
+							StructsJob: bindings.StructsJob{
+								Name:         "btcusd",
+								Url:          "http://127.0.0.1/btc",
+								Selector:     "last",
+								SelectorType: 5,
+							},
+						},
+					},
+				},
+			},
+		},
+		{
+			name: "Test 2: When collection is not present in assets.json",
+			args: args{
+				collection: "btcCollection",
+			},
+			want: nil,
+		},
+	}
+	config, err := DecodeAssetsConfig(jsonDataString)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := GetDerivedJobsFromConfig(tt.args.collection, config.Assets.Collection[tt.args.collection])
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("GetDerivedJobsFromConfig() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGetJobFromConfig(t *testing.T) {
+	retries := int64(-1)
+	tests := []struct {
+		name      string
+		jobConfig types.JobConfig
+		want      types.AssetJob
+		wantErr   bool
+	}{
+		{
+			name: "Test 1: When job has a body which is a JSON string",
+			jobConfig: types.JobConfig{
+				Name:     "eth1",
+				URL:      "http://127.0.0.1/eth1",
+				Selector: "eth1",
+				Power:    -2,
+				Weight:   255,
+				Body:     json.RawMessage(`"symbol=ETH"`),
+			},
+			want: types.AssetJob{
+				StructsJob: bindings.StructsJob{
+					Name:     "eth1",
+					Url:      "http://127.0.0.1/eth1",
+					Selector: "eth1",
+					Power:    -2,
+					Weight:   255,
+				},
+				Request: types.JobRequest{Body: "symbol=ETH"},
+			},
+		},
+		{
+			name: "Test 2: When weight of job does not fit in a job",
+			jobConfig: types.JobConfig{
+				URL:    "http://127.0.0.1/eth1",
+				Weight: 256,
+			},
+			wantErr: true,
+		},
+		{
+			name: "Test 3: When power of job does not fit in a job",
+			jobConfig: types.JobConfig{
+				URL:   "http://127.0.0.1/eth1",
+				Power: 128,
+			},
+			wantErr: true,
+		},
+		{
+			name: "Test 4: When retries of job are negative",
+			jobConfig: types.JobConfig{
+				URL:     "http://127.0.0.1/eth1",
+				Retries: &retries,
+			},
+			wantErr: true,
+		},
+		{
+			name: "Test 5: When selector type of job is unknown",
+			jobConfig: types.JobConfig{
+				URL:          "http://127.0.0.1/eth1",
+				SelectorType: "yaml",
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := GetJobFromConfig(tt.jobConfig)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("GetJobFromConfig() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("GetJobFromConfig() got = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDecodeAssetsConfig(t *testing.T) {
+	tests := []struct {
+		name              string
+		dataString        string
+		wantOutlierFilter *types.OutlierFilter
+		wantAggregation   *types.CollectionAggregation
+		wantNil           bool
+		wantErr           bool
+	}{
+		{
+			name:       "Test 1: When collection has an outlier filter and aggregation in assets.json",
+			dataString: jsonDataString,
+			wantOutlierFilter: &types.OutlierFilter{
+				Method:    "MAD",
+				Threshold: 3.5,
+				MinQuorum: 2,
+			},
+			wantAggregation: &types.CollectionAggregation{
+				Method:         "trimmed mean",
+				TrimPercentage: 20,
+			},
+		},
+		{
+			name:       "Test 2: When collection has no outlier filter and aggregation in assets.json",
+			dataString: `{"assets": {"collection": {"ethCollection": {"power": 2,},},},}`,
+		},
+		{
+			name:       "Test 3: When assets.json is empty",
+			dataString: "",
+			wantNil:    true,
+		},
+		{
+			name:       "Test 4: When assets.json has a key which is not part of the file format",
+			dataString: `{"assets": {"collection": {"ethCollection": {"outlier filters": {"method": "MAD"}}}}}`,
+			wantErr:    true,
+		},
+		{
+			name:       "Test 5: When a value in assets.json has the wrong type",
+			dataString: `{"assets": {"collection": {"ethCollection": {"power": "2"}}}}`,
+			wantErr:    true,
+		},
+		{
+			name:       "Test 6: When assets.json is not JSON",
+			dataString: `{"assets": `,
+			wantErr:    true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := DecodeAssetsConfig(tt.dataString)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("DecodeAssetsConfig() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if tt.wantErr || tt.wantNil {
+				if got != nil {
+					t.Errorf("DecodeAssetsConfig() got = %+v, want nil", got)
+				}
+				return
+			}
+			collectionConfig := got.Assets.Collection["ethCollection"]
+			if !reflect.DeepEqual(collectionConfig.OutlierFilter, tt.wantOutlierFilter) {
+				t.Errorf("DecodeAssetsConfig() outlier filter = %+v, want %+v", collectionConfig.OutlierFilter, tt.wantOutlierFilter)
+			}
+			if !reflect.DeepEqual(collectionConfig.Aggregation, tt.wantAggregation) {
+				t.Errorf("DecodeAssetsConfig() aggregation = %+v, want %+v", collectionConfig.Aggregation, tt.wantAggregation)
 			}
 		})
 	}
@@ -1131,7 +1938,7 @@ func TestHandleOfficialJobsFromJSONFile(t *testing.T) {
 	tests := []struct {
 		name               string
 		args               args
-		want               []bindings.StructsJob
+		want               []types.AssetJob
 		wantOverrideJobIds []uint16
 	}{
 		{
@@ -1143,13 +1950,18 @@ func TestHandleOfficialJobsFromJSONFile(t *testing.T) {
 					Id: 1,
 				},
 			},
-			want: []bindings.StructsJob{
+			want: []types.AssetJob{
 				{
-					Id:       1,
-					Url:      "http://kucoin.com/eth1",
-					Selector: "eth1",
-					Power:    2,
-					Weight:   2,
+					StructsJob: bindings.StructsJob{
+						Id:       1,
+						Url:      "http://kucoin.com/eth1",
+						Selector: "eth1",
+						Power:    2,
+						Weight:   2,
+					},
+					Request: types.JobRequest{
+						Auth: &types.JobAuth{File: "/run/secrets/kucoin", Query: "apikey"},
+					},
 				},
 			},
 			wantOverrideJobIds: []uint16{1},
@@ -1187,7 +1999,12 @@ func TestHandleOfficialJobsFromJSONFile(t *testing.T) {
 			}
 			utils := StartRazor(optionsPackageStruct)
 
-			gotJobs, gotOverrideJobIds := utils.HandleOfficialJobsFromJSONFile(client, tt.args.collection, tt.args.dataString)
+			assets, err := decodeAssets(tt.args.dataString)
+			if err != nil {
+				t.Fatal(err)
+			}
+			collectionConfig, _ := assets.Collection(tt.args.collection.Name)
+			gotJobs, gotOverrideJobIds := utils.HandleOfficialJobsFromJSONFile(client, tt.args.collection, collectionConfig)
 			if !reflect.DeepEqual(gotJobs, tt.want) {
 				t.Errorf("HandleOfficialJobsFromJSONFile() gotJobs = %v, want %v", gotJobs, tt.want)
 			}
@@ -1203,12 +2020,25 @@ var jsonDataString = `{
     "collection": {
       "ethCollection": {
         "power": 2,
+        "outlier filter": {
+          "method": "MAD",
+          "threshold": 3.5,
+          "min quorum": 2
+        },
+        "aggregation": {
+          "method": "trimmed mean",
+          "trim percentage": 20
+        },
         "official jobs": {
           "1": {
             "URL": "http://kucoin.com/eth1",
             "selector": "eth1",
             "power": 2,
-            "weight": 2
+            "weight": 2,
+            "auth": {
+              "file": "/run/secrets/kucoin",
+              "query": "apikey"
+            }
           },
           "2": {
             "URL": "http://api.coinbase.com/eth2",
@@ -1219,17 +2049,71 @@ var jsonDataString = `{
         },
         "custom jobs": [
           {
+            "name": "eth1",
             "URL": "http://127.0.0.1/eth1",
             "selector": "eth1",
             "power": 2,
-            "weight": 3
+            "weight": 3,
+            "volume selector": "eth1_volume"
           },
           {
             "URL": "http://127.0.0.1/eth2",
             "selector": "eth2",
+            "selector type": "gjson",
+            "power": 2,
+            "weight": 2,
+            "method": "POST",
+            "headers": {
+              "Accept": "application/json"
+            },
+            "body": {"symbol":"ETH"},
+            "auth": {
+              "env": "ETH2_API_KEY",
+              "header": "X-API-KEY"
+            },
+            "timeout": 2.5,
+            "retries": 2
+          },
+          {
+            "URL": "http://127.0.0.1/eth3",
+            "selector": "eth3",
+            "selector type": "yaml",
             "power": 2,
             "weight": 2
           },
+        ],
+        "derived jobs": [
+          {
+            "name": "ethbtc",
+            "expression": "eth1 / btcusd",
+            "power": 6,
+            "weight": 1,
+            "inputs": {
+              "btcusd": {
+                "URL": "http://127.0.0.1/btc",
+                "selector": "last",
+                "selector type": "gjson"
+              }
+            }
+          },
+          {
+            "name": "ethinverse",
+            "power": 6,
+            "weight": 1
+          },
+          {
+            "name": "ethdot",
+            "expression": "eth1 / dotusd",
+            "power": 6,
+            "weight": 1,
+            "inputs": {
+              "dotusd": {
+                "URL": "http://127.0.0.1/dot",
+                "selector": "last",
+                "selector type": "yaml"
+              }
+            }
+          }
         ]
       }
     }
@@ -1288,6 +2172,7 @@ func TestGetAggregatedDataOfCollection(t *testing.T) {
 				UtilsInterface: utilsMock,
 			}
 			utils := StartRazor(optionsPackageStruct)
+			mockAggregationState(utilsMock)
 
 			utilsMock.On("GetActiveCollection", mock.Anything, mock.Anything).Return(tt.args.activeCollection, tt.args.activeCollectionErr)
 			utilsMock.On("Aggregate", mock.Anything, mock.Anything, mock.Anything).Return(tt.args.collectionData, tt.args.aggregationErr)
@@ -1433,6 +2318,7 @@ func TestGetCollectionIdFromIndex(t *testing.T) {
 	type args struct {
 		collectionId    uint16
 		collectionIdErr error
+		replayedChain   *types.RecordedChain
 	}
 	tests := []struct {
 		name    string
@@ -1456,19 +2342,41 @@ func TestGetCollectionIdFromIndex(t *testing.T) {
 			want:    0,
 			wantErr: true,
 		},
+		{
+			name: "Test 3: When the epoch is replayed the recorded collectionId is returned",
+			args: args{
+				collectionIdErr: errors.New("error in getting collectionId"),
+				replayedChain:   &types.RecordedChain{CollectionIds: map[uint16]uint16{0: 2}},
+			},
+			want:    2,
+			wantErr: false,
+		},
+		{
+			name: "Test 4: When the epoch is replayed and the collectionId is not recorded",
+			args: args{
+				collectionId:  1,
+				replayedChain: &types.RecordedChain{},
+			},
+			want:    0,
+			wantErr: true,
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			retryMock := new(mocks.RetryUtils)
+			utilsMock := new(mocks.Utils)
 			assetManagerMock := new(mocks.AssetManagerUtils)
 
 			optionsPackageStruct := OptionsPackageStruct{
 				RetryInterface:        retryMock,
+				UtilsInterface:        utilsMock,
 				AssetManagerInterface: assetManagerMock,
 			}
 			utils := StartRazor(optionsPackageStruct)
 			assetManagerMock.On("GetCollectionIdFromIndex", mock.AnythingOfType("*ethclient.Client"), mock.Anything).Return(tt.args.collectionId, tt.args.collectionIdErr)
 			retryMock.On("RetryAttempts", mock.AnythingOfType("uint")).Return(retry.Attempts(1))
+			utilsMock.On("GetReplayedChain").Return(tt.args.replayedChain)
+			utilsMock.On("RecordChain", mock.Anything)
 
 			got, err := utils.GetCollectionIdFromIndex(client, medianIndex)
 			if (err != nil) != tt.wantErr {
@@ -1538,3 +2446,34 @@ func TestGetCollectionIdFromLeafId(t *testing.T) {
 		})
 	}
 }
+
+func TestGetJobLogName(t *testing.T) {
+	tests := []struct {
+		name string
+		job  types.AssetJob
+		want string
+	}{
+		{
+			name: "Test 1: When job is an official job",
+			job:  types.AssetJob{StructsJob: bindings.StructsJob{Id: 4, Url: "https://api.example.com/price?apikey=secret"}},
+			want: "#4",
+		},
+		{
+			name: "Test 2: When job has a name",
+			job:  types.AssetJob{StructsJob: bindings.StructsJob{Name: "ethusd_example", Url: "https://api.example.com/price?apikey=secret"}},
+			want: "ethusd_example",
+		},
+		{
+			name: "Test 3: When job has an API key in the query of its URL",
+			job:  types.AssetJob{StructsJob: bindings.StructsJob{Url: "https://api.example.com/price?apikey=secret", Selector: "last"}},
+			want: "https://api.example.com/price?apikey=xxxxx jsonpath last",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := getJobLogName(tt.job); got != tt.want {
+				t.Errorf("getJobLogName() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -2,8 +2,11 @@
 package utils
 
 import (
+	"errors"
+	"fmt"
 	"math/big"
 	"razor/core"
+	"razor/core/types"
 	"razor/pkg/bindings"
 
 	"github.com/avast/retry-go"
@@ -58,13 +61,41 @@ func (*UtilsStruct) GetProposedBlock(client *ethclient.Client, epoch uint32, pro
 	return proposedBlock, nil
 }
 
-//This function fetches the previous value
-func (*UtilsStruct) FetchPreviousValue(client *ethclient.Client, epoch uint32, assetId uint16) (*big.Int, error) {
+//This function fetches the confirmed value of the collection in the epoch
+func (*UtilsStruct) FetchPreviousValue(client *ethclient.Client, epoch uint32, collectionId uint16) (*big.Int, error) {
+	key := getRecordedValueKey(epoch, collectionId)
+	if chain := UtilsInterface.GetReplayedChain(); chain != nil {
+		recorded, ok := chain.PreviousValues[key]
+		if !ok {
+			return big.NewInt(0), fmt.Errorf("confirmed value of collection %d in epoch %d is not recorded", collectionId, epoch)
+		}
+		if recorded.Err != "" {
+			return big.NewInt(0), errors.New(recorded.Err)
+		}
+		return recorded.Value, nil
+	}
+	value, err := fetchPreviousValue(client, epoch, collectionId)
+	recorded := types.RecordedValue{Value: value}
+	if err != nil {
+		recorded.Err = err.Error()
+	}
+	UtilsInterface.RecordChain(func(chain *types.RecordedChain) { chain.PreviousValues[key] = recorded })
+	return value, err
+}
+
+//This function returns the confirmed median of the collection in the block of the epoch
+func fetchPreviousValue(client *ethclient.Client, epoch uint32, collectionId uint16) (*big.Int, error) {
 	block, err := UtilsInterface.GetBlock(client, epoch)
 	if err != nil {
 		return big.NewInt(0), err
 	}
-	return block.Medians[assetId-1], nil
+	// Medians are in the order of the ids of the block, which skip the collections that were not revealed or are deactivated
+	for i, id := range block.Ids {
+		if id == collectionId && i < len(block.Medians) {
+			return block.Medians[i], nil
+		}
+	}
+	return big.NewInt(0), fmt.Errorf("collection %d has no confirmed value in epoch %d", collectionId, epoch)
 }
 
 //This function returns the block
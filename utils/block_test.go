@@ -3,6 +3,7 @@ package utils
 import (
 	"errors"
 	"math/big"
+	"razor/core/types"
 	"razor/pkg/bindings"
 	"razor/utils/mocks"
 	"reflect"
@@ -19,9 +20,10 @@ func TestFetchPreviousValue(t *testing.T) {
 	var epoch uint32
 
 	type args struct {
-		assetId  uint16
-		block    bindings.StructsBlock
-		blockErr error
+		assetId       uint16
+		block         bindings.StructsBlock
+		blockErr      error
+		replayedChain *types.RecordedChain
 	}
 	tests := []struct {
 		name    string
@@ -34,6 +36,7 @@ func TestFetchPreviousValue(t *testing.T) {
 			args: args{
 				assetId: 3,
 				block: bindings.StructsBlock{
+					Ids:     []uint16{1, 2, 3, 4},
 					Medians: []*big.Int{big.NewInt(2000), big.NewInt(1500), big.NewInt(4000), big.NewInt(6500)},
 				},
 			},
@@ -49,6 +52,67 @@ func TestFetchPreviousValue(t *testing.T) {
 			want:    big.NewInt(0),
 			wantErr: true,
 		},
+		{
+			name: "Test 3: When collections before the collection are not in the block",
+			args: args{
+				assetId: 5,
+				block: bindings.StructsBlock{
+					Ids:     []uint16{1, 3, 5, 6},
+					Medians: []*big.Int{big.NewInt(2000), big.NewInt(1500), big.NewInt(4000), big.NewInt(6500)},
+				},
+			},
+			want:    big.NewInt(4000),
+			wantErr: false,
+		},
+		{
+			name: "Test 4: When the collection is not in the block",
+			args: args{
+				assetId: 2,
+				block: bindings.StructsBlock{
+					Ids:     []uint16{1, 3, 5, 6},
+					Medians: []*big.Int{big.NewInt(2000), big.NewInt(1500), big.NewInt(4000), big.NewInt(6500)},
+				},
+			},
+			want:    big.NewInt(0),
+			wantErr: true,
+		},
+		{
+			name: "Test 5: When there is no confirmed block in the epoch",
+			args: args{
+				assetId: 2,
+				block:   bindings.StructsBlock{},
+			},
+			want:    big.NewInt(0),
+			wantErr: true,
+		},
+		{
+			name: "Test 6: When the epoch is replayed the recorded value is returned",
+			args: args{
+				assetId:       3,
+				blockErr:      errors.New("block error"),
+				replayedChain: &types.RecordedChain{PreviousValues: map[string]types.RecordedValue{"0/3": {Value: big.NewInt(4000)}}},
+			},
+			want:    big.NewInt(4000),
+			wantErr: false,
+		},
+		{
+			name: "Test 7: When the epoch is replayed and the recorded value is an error",
+			args: args{
+				assetId:       3,
+				replayedChain: &types.RecordedChain{PreviousValues: map[string]types.RecordedValue{"0/3": {Err: "block error"}}},
+			},
+			want:    big.NewInt(0),
+			wantErr: true,
+		},
+		{
+			name: "Test 8: When the epoch is replayed and the value is not recorded",
+			args: args{
+				assetId:       3,
+				replayedChain: &types.RecordedChain{},
+			},
+			want:    big.NewInt(0),
+			wantErr: true,
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -65,6 +129,8 @@ func TestFetchPreviousValue(t *testing.T) {
 
 			utilsMock.On("GetBlock", mock.AnythingOfType("*ethclient.Client"), mock.AnythingOfType("uint32")).Return(tt.args.block, tt.args.blockErr)
 			retryMock.On("RetryAttempts", mock.AnythingOfType("uint")).Return(retry.Attempts(1))
+			utilsMock.On("GetReplayedChain").Return(tt.args.replayedChain)
+			utilsMock.On("RecordChain", mock.Anything)
 
 			got, err := utils.FetchPreviousValue(client, epoch, tt.args.assetId)
 			if (err != nil) != tt.wantErr {
@@ -0,0 +1,195 @@
+//Package utils provides the utils functions
+package utils
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"net/http"
+	"net/url"
+	"razor/metrics"
+	"sort"
+	"strings"
+	"sync"
+)
+
+type cachedResponse struct {
+	done       chan struct{}
+	statusCode int
+	status     string
+	header     http.Header
+	body       []byte
+	err        error
+}
+
+//ResponseCache is a http.RoundTripper which fetches every distinct request only once per epoch
+type ResponseCache struct {
+	transport http.RoundTripper
+	mutex     sync.Mutex
+	epoch     uint32
+	responses map[string]*cachedResponse
+}
+
+var responseCache = NewResponseCache(fetchTransport)
+
+//This function returns a new response cache fetching the responses with the given transport
+func NewResponseCache(transport http.RoundTripper) *ResponseCache {
+	return &ResponseCache{
+		transport: transport,
+		responses: make(map[string]*cachedResponse),
+	}
+}
+
+//This function sets the epoch of the cache and drops the responses cached in previous epochs
+func (cache *ResponseCache) SetEpoch(epoch uint32) {
+	cache.mutex.Lock()
+	defer cache.mutex.Unlock()
+	if cache.epoch != epoch {
+		cache.epoch = epoch
+		cache.responses = make(map[string]*cachedResponse)
+	}
+}
+
+//This function returns the key of the request which is made up of its method, url, headers and body
+func getResponseCacheKey(req *http.Request, body []byte) string {
+	headerKeys := make([]string, 0, len(req.Header))
+	for key := range req.Header {
+		headerKeys = append(headerKeys, key)
+	}
+	sort.Strings(headerKeys)
+
+	var key strings.Builder
+	key.WriteString(req.Method + " " + req.URL.String() + "\n")
+	if options := getTransportOptionsKey(req); options != "" {
+		// Responses fetched through another proxy or with another client certificate can differ
+		key.WriteString("Transport: " + options + "\n")
+	}
+	for _, headerKey := range headerKeys {
+		key.WriteString(headerKey + ": " + strings.Join(req.Header[headerKey], ",") + "\n")
+	}
+	key.Write(body)
+	return key.String()
+}
+
+//This function returns the url as it can be logged, the values of its query are masked like its password as they can be API keys
+func getRedactedURL(requestURL *url.URL) string {
+	redacted := *requestURL
+	if redacted.RawQuery != "" {
+		query := redacted.Query()
+		for key := range query {
+			query[key] = []string{"xxxxx"}
+		}
+		redacted.RawQuery = query.Encode()
+	}
+	return redacted.Redacted()
+}
+
+//This function returns the response of the request, only the first request with a given key in an epoch reaches the source
+func (cache *ResponseCache) RoundTrip(req *http.Request) (*http.Response, error) {
+	cache.mutex.Lock()
+	if cache.epoch == 0 {
+		// Caching is enabled only once an epoch is set, e.g. while voting
+		cache.mutex.Unlock()
+		return cache.fetch(req)
+	}
+
+	var body []byte
+	if req.Body != nil {
+		var err error
+		body, err = io.ReadAll(req.Body)
+		req.Body.Close()
+		if err != nil {
+			cache.mutex.Unlock()
+			return nil, err
+		}
+		req.Body = io.NopCloser(bytes.NewReader(body))
+	}
+	key := getResponseCacheKey(req, body)
+	responses := cache.responses
+	if cached, ok := responses[key]; ok {
+		cache.mutex.Unlock()
+		// A request waiting for the same response to be fetched gives up when its own context is done
+		select {
+		case <-cached.done:
+		case <-req.Context().Done():
+			return nil, req.Context().Err()
+		}
+		if cached.err == nil {
+			log.Debugf("Response cache hit for %s %s", req.Method, getRedactedURL(req.URL))
+			metrics.ResponseCacheHitsMetric.Inc()
+			return cached.toResponse(req), nil
+		}
+		return cache.fetch(req)
+	}
+	cached := &cachedResponse{done: make(chan struct{})}
+	responses[key] = cached
+	cache.mutex.Unlock()
+	metrics.ResponseCacheMissesMetric.Inc()
+
+	cached.err = cache.fetchToCache(req, cached)
+	if cached.err != nil || cached.statusCode < 200 || cached.statusCode > 299 {
+		// Failed responses are not cached so that retries reach the source again
+		cache.mutex.Lock()
+		if responses[key] == cached {
+			delete(responses, key)
+		}
+		cache.mutex.Unlock()
+		if cached.err == nil {
+			cached.err = errors.New("response not cached")
+		} else {
+			close(cached.done)
+			return nil, cached.err
+		}
+	}
+	close(cached.done)
+	return cached.toResponse(req), nil
+}
+
+//This function fetches the response from the source into the cached response while holding a fetch slot for its host
+func (cache *ResponseCache) fetchToCache(req *http.Request, cached *cachedResponse) error {
+	release, err := AcquireFetchSlot(req.Context(), req.URL.String())
+	if err != nil {
+		return err
+	}
+	defer release()
+	response, err := cache.transport.RoundTrip(req)
+	if err != nil {
+		return err
+	}
+	defer response.Body.Close()
+	cached.statusCode = response.StatusCode
+	cached.status = response.Status
+	cached.header = response.Header.Clone()
+	cached.body, err = io.ReadAll(response.Body)
+	return err
+}
+
+//This function fetches the response from the source while holding a fetch slot for its host
+func (cache *ResponseCache) fetch(req *http.Request) (*http.Response, error) {
+	release, err := AcquireFetchSlot(req.Context(), req.URL.String())
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+	return cache.transport.RoundTrip(req)
+}
+
+//This function builds a new http response from the cached response
+func (cached *cachedResponse) toResponse(req *http.Request) *http.Response {
+	return &http.Response{
+		Status:        cached.status,
+		StatusCode:    cached.statusCode,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        cached.header.Clone(),
+		Body:          io.NopCloser(bytes.NewReader(cached.body)),
+		ContentLength: int64(len(cached.body)),
+		Request:       req,
+	}
+}
+
+//This function sets the epoch of the cache shared by the jobs, so that commands showing the details of jobs work on the same responses as the commit does
+func SetResponseCacheEpoch(epoch uint32) {
+	responseCache.SetEpoch(epoch)
+}
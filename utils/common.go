@@ -222,7 +222,7 @@ func (*UtilsStruct) CalculateBlockNumberAtEpochBeginning(client *ethclient.Clien
 
 }
 
-//This function saves data to commit JSON file
+//This function saves data to commit JSON file, the file is replaced at once and checksummed
 func (*UtilsStruct) SaveDataToCommitJsonFile(filePath string, epoch uint32, commitData types.CommitData) error {
 
 	var data types.CommitFileData
@@ -235,7 +235,7 @@ func (*UtilsStruct) SaveDataToCommitJsonFile(filePath string, epoch uint32, comm
 	if err != nil {
 		return err
 	}
-	err = OS.WriteFile(filePath, jsonData, 0600)
+	err = writeDataFile(filePath, jsonData)
 	if err != nil {
 		log.Error("Error in writing to file: ", err)
 		return err
@@ -255,6 +255,11 @@ func (*UtilsStruct) ReadFromCommitJsonFile(filePath string) (types.CommitFileDat
 		log.Error("Error in reading data from json file: ", err)
 		return types.CommitFileData{}, err
 	}
+	byteValue, err = openDataFile(byteValue, &types.CommitFileData{})
+	if err != nil {
+		log.Error("Error in opening data file: ", err)
+		return types.CommitFileData{}, err
+	}
 	var commitedData types.CommitFileData
 
 	err = JsonInterface.Unmarshal(byteValue, &commitedData)
@@ -289,7 +294,7 @@ func (*UtilsStruct) SaveDataToProposeJsonFile(filePath string, epoch uint32, pro
 	if err != nil {
 		return err
 	}
-	err = OS.WriteFile(filePath, jsonData, 0600)
+	err = writeDataFile(filePath, jsonData)
 	if err != nil {
 		log.Error("Error in writing to file: ", err)
 		return err
@@ -309,6 +314,11 @@ func (*UtilsStruct) ReadFromProposeJsonFile(filePath string) (types.ProposeFileD
 		log.Error("Error in reading data from json file: ", err)
 		return types.ProposeFileData{}, err
 	}
+	byteValue, err = openDataFile(byteValue, &types.ProposeFileData{})
+	if err != nil {
+		log.Error("Error in opening data file: ", err)
+		return types.ProposeFileData{}, err
+	}
 	var proposedData types.ProposeFileData
 
 	err = JsonInterface.Unmarshal(byteValue, &proposedData)
@@ -328,7 +338,7 @@ func (*UtilsStruct) SaveDataToDisputeJsonFile(filePath string, bountyIdQueue []u
 	if err != nil {
 		return err
 	}
-	err = OS.WriteFile(filePath, jsonData, 0600)
+	err = writeDataFile(filePath, jsonData)
 	if err != nil {
 		log.Error("Error in writing to file: ", err)
 		return err
@@ -348,6 +358,11 @@ func (*UtilsStruct) ReadFromDisputeJsonFile(filePath string) (types.DisputeFileD
 		log.Error("Error in reading data from json file: ", err)
 		return types.DisputeFileData{}, err
 	}
+	byteValue, err = openDataFile(byteValue, &types.DisputeFileData{})
+	if err != nil {
+		log.Error("Error in opening data file: ", err)
+		return types.DisputeFileData{}, err
+	}
 	var disputeData types.DisputeFileData
 
 	err = JsonInterface.Unmarshal(byteValue, &disputeData)
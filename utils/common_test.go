@@ -1015,6 +1015,7 @@ func TestSaveDataToCommitJsonFile(t *testing.T) {
 		jsonData     []byte
 		jsonDataErr  error
 		writeFileErr error
+		renameErr    error
 	}
 	tests := []struct {
 		name    string
@@ -1043,6 +1044,14 @@ func TestSaveDataToCommitJsonFile(t *testing.T) {
 			},
 			wantErr: true,
 		},
+		{
+			name: "Test 4: When the written file cannot be renamed over the file",
+			args: args{
+				jsonData:  []byte{},
+				renameErr: errors.New("error in renaming file"),
+			},
+			wantErr: true,
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -1056,7 +1065,8 @@ func TestSaveDataToCommitJsonFile(t *testing.T) {
 			utils := StartRazor(optionsPackageStruct)
 
 			jsonMock.On("Marshal", mock.Anything).Return(tt.args.jsonData, tt.args.jsonDataErr)
-			osMock.On("WriteFile", mock.Anything, mock.Anything, mock.Anything).Return(tt.args.writeFileErr)
+			mockCreateTemp(osMock, t.TempDir(), tt.args.writeFileErr)
+			osMock.On("Rename", mock.Anything, mock.Anything).Return(tt.args.renameErr)
 
 			if err := utils.SaveDataToCommitJsonFile(filePath, epoch, commitData); (err != nil) != tt.wantErr {
 				t.Errorf("SaveDataToCommitJsonFile() error = %v, wantErr %v", err, tt.wantErr)
@@ -1076,6 +1086,7 @@ func TestSaveDataToProposeJsonFile(t *testing.T) {
 		jsonData     []byte
 		jsonDataErr  error
 		writeFileErr error
+		renameErr    error
 	}
 	tests := []struct {
 		name    string
@@ -1104,6 +1115,14 @@ func TestSaveDataToProposeJsonFile(t *testing.T) {
 			},
 			wantErr: true,
 		},
+		{
+			name: "Test 4: When the written file cannot be renamed over the file",
+			args: args{
+				jsonData:  []byte{},
+				renameErr: errors.New("error in renaming file"),
+			},
+			wantErr: true,
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -1117,7 +1136,8 @@ func TestSaveDataToProposeJsonFile(t *testing.T) {
 			utils := StartRazor(optionsPackageStruct)
 
 			jsonMock.On("Marshal", mock.Anything).Return(tt.args.jsonData, tt.args.jsonDataErr)
-			osMock.On("WriteFile", mock.Anything, mock.Anything, mock.Anything).Return(tt.args.writeFileErr)
+			mockCreateTemp(osMock, t.TempDir(), tt.args.writeFileErr)
+			osMock.On("Rename", mock.Anything, mock.Anything).Return(tt.args.renameErr)
 			if err := utils.SaveDataToProposeJsonFile(filePath, epoch, proposeData); (err != nil) != tt.wantErr {
 				t.Errorf("SaveDataToProposeJsonFile() error = %v, wantErr %v", err, tt.wantErr)
 			}
@@ -1134,6 +1154,7 @@ func TestSaveDataToDisputeJsonFile(t *testing.T) {
 		jsonData     []byte
 		jsonDataErr  error
 		writeFileErr error
+		renameErr    error
 	}
 	tests := []struct {
 		name    string
@@ -1162,6 +1183,14 @@ func TestSaveDataToDisputeJsonFile(t *testing.T) {
 			},
 			wantErr: true,
 		},
+		{
+			name: "Test 4: When the written file cannot be renamed over the file",
+			args: args{
+				jsonData:  []byte{},
+				renameErr: errors.New("error in renaming file"),
+			},
+			wantErr: true,
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -1175,7 +1204,8 @@ func TestSaveDataToDisputeJsonFile(t *testing.T) {
 			utils := StartRazor(optionsPackageStruct)
 
 			jsonMock.On("Marshal", mock.Anything).Return(tt.args.jsonData, tt.args.jsonDataErr)
-			osMock.On("WriteFile", mock.Anything, mock.Anything, mock.Anything).Return(tt.args.writeFileErr)
+			mockCreateTemp(osMock, t.TempDir(), tt.args.writeFileErr)
+			osMock.On("Rename", mock.Anything, mock.Anything).Return(tt.args.renameErr)
 			if err := utils.SaveDataToDisputeJsonFile(filePath, bountyIdQueue); (err != nil) != tt.wantErr {
 				t.Errorf("SaveDataToDisputeJsonFile() error = %v, wantErr %v", err, tt.wantErr)
 			}
@@ -1183,6 +1213,21 @@ func TestSaveDataToDisputeJsonFile(t *testing.T) {
 	}
 }
 
+//This function stubs CreateTemp of osMock with a temporary file in dir, or with createErr if it is set
+func mockCreateTemp(osMock *mocks.OSUtils, dir string, createErr error) {
+	if createErr != nil {
+		osMock.On("CreateTemp", mock.Anything, mock.Anything).Return(nil, createErr)
+		return
+	}
+	osMock.On("CreateTemp", mock.Anything, mock.Anything).Return(func(_ string, pattern string) *os.File {
+		file, err := os.CreateTemp(dir, pattern)
+		if err != nil {
+			panic(err)
+		}
+		return file
+	}, nil)
+}
+
 func TestReadFromCommitJsonFile(t *testing.T) {
 	var filePath string
 	type args struct {
@@ -1202,7 +1247,7 @@ func TestReadFromCommitJsonFile(t *testing.T) {
 			name: "Test 1: When ReadFromCommitJsonFile() executes successfully",
 			args: args{
 				jsonFile:  &os.File{},
-				byteValue: []byte{},
+				byteValue: []byte("{}"),
 			},
 			want:    Types.CommitFileData{},
 			wantErr: false,
@@ -1228,7 +1273,7 @@ func TestReadFromCommitJsonFile(t *testing.T) {
 			name: "Test 4: When there is an error in unmarshal",
 			args: args{
 				jsonFile:     &os.File{},
-				byteValue:    []byte{},
+				byteValue:    []byte("{}"),
 				unmarshalErr: errors.New("error in unmarshal"),
 			},
 			want:    Types.CommitFileData{},
@@ -1281,7 +1326,7 @@ func TestReadFromProposeJsonFile(t *testing.T) {
 			name: "Test 1: When ReadFromProposeJsonFile() executes successfully",
 			args: args{
 				jsonFile:  &os.File{},
-				byteValue: []byte{},
+				byteValue: []byte("{}"),
 			},
 			want:    Types.ProposeFileData{},
 			wantErr: false,
@@ -1307,7 +1352,7 @@ func TestReadFromProposeJsonFile(t *testing.T) {
 			name: "Test 4: When there is an error in unmarshal",
 			args: args{
 				jsonFile:     &os.File{},
-				byteValue:    []byte{},
+				byteValue:    []byte("{}"),
 				unmarshalErr: errors.New("error in unmarshal"),
 			},
 			want:    Types.ProposeFileData{},
@@ -1361,7 +1406,7 @@ func TestReadFromDisputeJsonFile(t *testing.T) {
 			name: "Test 1: When ReadFromDisputeJsonFile() executes successfully",
 			args: args{
 				jsonFile:  &os.File{},
-				byteValue: []byte{},
+				byteValue: []byte("{}"),
 			},
 			want:    Types.DisputeFileData{},
 			wantErr: false,
@@ -1387,7 +1432,7 @@ func TestReadFromDisputeJsonFile(t *testing.T) {
 			name: "Test 4: When there is an error in unmarshal",
 			args: args{
 				jsonFile:     &os.File{},
-				byteValue:    []byte{},
+				byteValue:    []byte("{}"),
 				unmarshalErr: errors.New("error in unmarshal"),
 			},
 			want:    Types.DisputeFileData{},
@@ -0,0 +1,333 @@
+//Package utils provides the utils functions
+package utils
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"razor/core"
+	"razor/core/types"
+	"razor/path"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"golang.org/x/crypto/scrypt"
+)
+
+//DataFileKeys keeps the password data files are encrypted with and the keys derived from it
+type DataFileKeys struct {
+	mutex    sync.Mutex
+	password string
+	encrypt  bool
+	salt     []byte
+	keys     map[string][]byte
+}
+
+var dataFileKeys = &DataFileKeys{}
+
+//This function sets the password of the account which encrypted data files are read with, and with which data files are encrypted from now on if encrypt is set
+func SetDataFilesPassword(password string, encrypt bool) error {
+	dataFileKeys.mutex.Lock()
+	defer dataFileKeys.mutex.Unlock()
+	if encrypt && password == "" {
+		return errors.New("data files cannot be encrypted without a password")
+	}
+	dataFileKeys.password = password
+	dataFileKeys.encrypt = encrypt
+	dataFileKeys.salt = nil
+	dataFileKeys.keys = make(map[string][]byte)
+	return nil
+}
+
+//This function returns the key derived from the password with the salt, keys are derived once as it is slow on purpose
+func (dataFileKeys *DataFileKeys) key(salt []byte) ([]byte, error) {
+	if dataFileKeys.password == "" {
+		return nil, errors.New("data file is encrypted, the password of the account is needed to read it")
+	}
+	if key, ok := dataFileKeys.keys[string(salt)]; ok {
+		return key, nil
+	}
+	key, err := scrypt.Key([]byte(dataFileKeys.password), salt, core.DataFileScryptN, core.DataFileScryptR, core.DataFileScryptP, 32)
+	if err != nil {
+		return nil, err
+	}
+	if dataFileKeys.keys == nil {
+		dataFileKeys.keys = make(map[string][]byte)
+	}
+	dataFileKeys.keys[string(salt)] = key
+	return key, nil
+}
+
+//This function returns the content of a data file with the data, encrypted if a password is set for it and checksummed otherwise
+func sealDataFile(data []byte) ([]byte, error) {
+	if len(data) > 0 {
+		// Data is kept as it is written in the file, so that its checksum can be checked
+		var compacted bytes.Buffer
+		if err := json.Compact(&compacted, data); err != nil {
+			return nil, err
+		}
+		data = compacted.Bytes()
+	}
+	dataFile := types.DataFile{Version: core.DataFileVersion}
+
+	dataFileKeys.mutex.Lock()
+	defer dataFileKeys.mutex.Unlock()
+	if !dataFileKeys.encrypt {
+		checksum := sha256.Sum256(data)
+		dataFile.Checksum = "sha256:" + hex.EncodeToString(checksum[:])
+		dataFile.Data = data
+		return json.Marshal(dataFile)
+	}
+	// Encrypted data has no checksum in the clear, as values close to public prices could be guessed against it, GCM authenticates the data instead
+	if dataFileKeys.salt == nil {
+		salt := make([]byte, 16)
+		if _, err := rand.Read(salt); err != nil {
+			return nil, err
+		}
+		dataFileKeys.salt = salt
+	}
+	key, err := dataFileKeys.key(dataFileKeys.salt)
+	if err != nil {
+		return nil, err
+	}
+	gcm, err := newDataFileCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, err
+	}
+	dataFile.Encryption = &types.DataFileEncryption{
+		Cipher:     "aes-256-gcm",
+		Kdf:        "scrypt",
+		Salt:       dataFileKeys.salt,
+		Nonce:      nonce,
+		Ciphertext: gcm.Seal(nil, nonce, data, nil),
+	}
+	return json.Marshal(dataFile)
+}
+
+//This function returns the data in the content of a data file after decrypting it or checking its checksum
+//A file without a version is only returned as it is if it has the layout of legacy, which data files had before they were checksummed
+func openDataFile(content []byte, legacy interface{}) ([]byte, error) {
+	var dataFile types.DataFile
+	if err := json.Unmarshal(content, &dataFile); err != nil {
+		return nil, fmt.Errorf("data file cannot be parsed: %w", err)
+	}
+	if dataFile.Version == 0 {
+		decoder := json.NewDecoder(bytes.NewReader(content))
+		decoder.DisallowUnknownFields()
+		if err := decoder.Decode(legacy); err != nil {
+			return nil, fmt.Errorf("data file has no version and is not a data file of an older version: %w", err)
+		}
+		return content, nil
+	}
+	if dataFile.Version > core.DataFileVersion {
+		return nil, fmt.Errorf("data file has version %d, this version of razor reads up to version %d", dataFile.Version, core.DataFileVersion)
+	}
+
+	data := []byte(dataFile.Data)
+	if dataFile.Encryption != nil {
+		if dataFile.Encryption.Cipher != "aes-256-gcm" || dataFile.Encryption.Kdf != "scrypt" {
+			return nil, fmt.Errorf("data file is encrypted with unknown cipher %s and kdf %s", dataFile.Encryption.Cipher, dataFile.Encryption.Kdf)
+		}
+		dataFileKeys.mutex.Lock()
+		key, err := dataFileKeys.key(dataFile.Encryption.Salt)
+		dataFileKeys.mutex.Unlock()
+		if err != nil {
+			return nil, err
+		}
+		gcm, err := newDataFileCipher(key)
+		if err != nil {
+			return nil, err
+		}
+		if len(dataFile.Encryption.Nonce) != gcm.NonceSize() {
+			return nil, errors.New("data file has an invalid nonce")
+		}
+		if dataFile.Checksum != "" || len(dataFile.Data) != 0 {
+			return nil, errors.New("encrypted data file cannot have data or a checksum in the clear")
+		}
+		data, err = gcm.Open(nil, dataFile.Encryption.Nonce, dataFile.Encryption.Ciphertext, nil)
+		if err != nil {
+			return nil, errors.New("data file cannot be decrypted, the password is wrong or the file is corrupted")
+		}
+		return data, nil
+	}
+	checksum := sha256.Sum256(data)
+	if dataFile.Checksum != "sha256:"+hex.EncodeToString(checksum[:]) {
+		return nil, errors.New("data file is corrupted, its checksum does not match")
+	}
+	return data, nil
+}
+
+//This function returns the AES-GCM cipher of the key
+func newDataFileCipher(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+//This function writes the data to the file through a temporary file which is synced and renamed over it, so that the file is never left half written
+func WriteFileAtomically(filePath string, data []byte, perm fs.FileMode) error {
+	dir := filepath.Dir(filePath)
+	temporaryFile, err := OS.CreateTemp(dir, "."+filepath.Base(filePath)+".tmp*")
+	if err != nil {
+		return err
+	}
+	_, err = temporaryFile.Write(data)
+	if err == nil {
+		err = temporaryFile.Sync()
+	}
+	if closeErr := temporaryFile.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil {
+		err = os.Chmod(temporaryFile.Name(), perm)
+	}
+	if err == nil {
+		err = OS.Rename(temporaryFile.Name(), filePath)
+	}
+	if err != nil {
+		os.Remove(temporaryFile.Name())
+		return err
+	}
+	// The rename itself is only durable once the directory is synced
+	if directory, err := os.Open(dir); err == nil {
+		directory.Sync()
+		directory.Close()
+	}
+	return nil
+}
+
+//This function writes the data to the data file, checksummed and encrypted if a password is set for it, and creates the directory of the data file if it is not present
+func writeDataFile(filePath string, data []byte) error {
+	content, err := sealDataFile(data)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(filePath), 0700); err != nil {
+		return err
+	}
+	return WriteFileAtomically(filePath, content, 0600)
+}
+
+var dataFileKinds = map[string]string{
+	"_CommitData.json":   "commit",
+	"_proposedData.json": "propose",
+	"_disputeData.json":  "dispute",
+}
+
+//This function returns the data files in the directory and in the directories of epochs in it, the files of an epoch have its epoch
+func ListDataFilesInDir(dir string) ([]types.DataFileEntry, error) {
+	entries, err := readDataFilesDir(dir, 0)
+	if err != nil {
+		return nil, err
+	}
+	sort.SliceStable(entries, func(i, j int) bool {
+		if entries[i].Epoch != entries[j].Epoch {
+			return entries[i].Epoch < entries[j].Epoch
+		}
+		return entries[i].Path < entries[j].Path
+	})
+	return entries, nil
+}
+
+//This function returns the data files in the directory of the epoch, or in the directory of data files if the epoch is 0
+func readDataFilesDir(dir string, epoch uint32) ([]types.DataFileEntry, error) {
+	files, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+	var entries []types.DataFileEntry
+	for _, file := range files {
+		if file.IsDir() {
+			fileEpoch, err := strconv.ParseUint(file.Name(), 10, 32)
+			if epoch != 0 || err != nil {
+				continue
+			}
+			epochEntries, err := readDataFilesDir(filepath.Join(dir, file.Name()), uint32(fileEpoch))
+			if err != nil {
+				return nil, err
+			}
+			entries = append(entries, epochEntries...)
+			continue
+		}
+		for suffix, kind := range dataFileKinds {
+			if !strings.HasSuffix(file.Name(), suffix) {
+				continue
+			}
+			info, err := file.Info()
+			if err != nil {
+				return nil, err
+			}
+			entries = append(entries, types.DataFileEntry{
+				Epoch:   epoch,
+				Address: strings.TrimSuffix(file.Name(), suffix),
+				Kind:    kind,
+				Path:    filepath.Join(dir, file.Name()),
+				Size:    info.Size(),
+				ModTime: info.ModTime(),
+			})
+		}
+	}
+	return entries, nil
+}
+
+//This function removes the directories of the epochs in the directory which are not among the last retention epochs up to the epoch, and returns the epochs removed. Nothing is removed if retention is 0
+func PruneDataFilesInDir(dir string, epoch uint32, retention uint32) ([]uint32, error) {
+	if retention == 0 {
+		return nil, nil
+	}
+	files, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+	var prunedEpochs []uint32
+	for _, file := range files {
+		if !file.IsDir() {
+			continue
+		}
+		fileEpoch, err := strconv.ParseUint(file.Name(), 10, 32)
+		if err != nil || fileEpoch+uint64(retention) > uint64(epoch) {
+			continue
+		}
+		if err := os.RemoveAll(filepath.Join(dir, file.Name())); err != nil {
+			return prunedEpochs, err
+		}
+		prunedEpochs = append(prunedEpochs, uint32(fileEpoch))
+	}
+	sort.Slice(prunedEpochs, func(i, j int) bool { return prunedEpochs[i] < prunedEpochs[j] })
+	return prunedEpochs, nil
+}
+
+//This function returns the data files of every epoch
+func ListDataFiles() ([]types.DataFileEntry, error) {
+	dir, err := path.PathUtilsInterface.GetDataFilesDir()
+	if err != nil {
+		return nil, err
+	}
+	return ListDataFilesInDir(dir)
+}
+
+//This function removes the data files of the epochs which are not among the last retention epochs up to the epoch, and returns the epochs removed
+func PruneDataFiles(epoch uint32, retention uint32) ([]uint32, error) {
+	dir, err := path.PathUtilsInterface.GetDataFilesDir()
+	if err != nil {
+		return nil, err
+	}
+	return PruneDataFilesInDir(dir, epoch, retention)
+}
@@ -0,0 +1,304 @@
+package utils
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"razor/core"
+	"razor/core/types"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestDataFile(t *testing.T) {
+	scryptN := core.DataFileScryptN
+	core.DataFileScryptN = 1 << 10
+	defer func() {
+		core.DataFileScryptN = scryptN
+		dataFileKeys = &DataFileKeys{}
+	}()
+	data := []byte(`{"Epoch":5,"Leaves":[0,295050]}`)
+
+	tests := []struct {
+		name         string
+		password     string
+		encrypt      bool
+		readPassword string
+		modify       func(dataFile *types.DataFile)
+		want         string
+		wantErr      bool
+	}{
+		{
+			name: "Test 1: When data file is not encrypted",
+			want: string(data),
+		},
+		{
+			name:         "Test 2: When data file is encrypted and read with the password",
+			password:     "test",
+			encrypt:      true,
+			readPassword: "test",
+			want:         string(data),
+		},
+		{
+			name:         "Test 3: When data file is encrypted and read with another password",
+			password:     "test",
+			encrypt:      true,
+			readPassword: "other",
+			wantErr:      true,
+		},
+		{
+			name:     "Test 4: When data file is encrypted and read without a password",
+			password: "test",
+			encrypt:  true,
+			wantErr:  true,
+		},
+		{
+			name: "Test 5: When data of the file does not match its checksum",
+			modify: func(dataFile *types.DataFile) {
+				dataFile.Data = []byte(`{"Epoch":5,"Leaves":[0,295051]}`)
+			},
+			wantErr: true,
+		},
+		{
+			name:         "Test 6: When ciphertext of the file was changed",
+			password:     "test",
+			encrypt:      true,
+			readPassword: "test",
+			modify: func(dataFile *types.DataFile) {
+				dataFile.Encryption.Ciphertext[0] ^= 1
+			},
+			wantErr: true,
+		},
+		{
+			name:         "Test 7: When encrypted data file has a checksum in the clear",
+			password:     "test",
+			encrypt:      true,
+			readPassword: "test",
+			modify: func(dataFile *types.DataFile) {
+				dataFile.Checksum = "sha256:0000"
+			},
+			wantErr: true,
+		},
+		{
+			name: "Test 8: When data file has a newer version",
+			modify: func(dataFile *types.DataFile) {
+				dataFile.Version = core.DataFileVersion + 1
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := SetDataFilesPassword(tt.password, tt.encrypt); err != nil {
+				t.Fatal(err)
+			}
+			content, err := sealDataFile(data)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if tt.encrypt && (strings.Contains(string(content), "295050") || strings.Contains(string(content), "checksum")) {
+				t.Errorf("sealDataFile() wrote the data or its checksum in plaintext: %s", content)
+			}
+			if tt.modify != nil {
+				var dataFile types.DataFile
+				if err := json.Unmarshal(content, &dataFile); err != nil {
+					t.Fatal(err)
+				}
+				tt.modify(&dataFile)
+				content, _ = json.Marshal(dataFile)
+			}
+
+			if err := SetDataFilesPassword(tt.readPassword, false); err != nil {
+				t.Fatal(err)
+			}
+			got, err := openDataFile(content, &types.CommitFileData{})
+			if (err != nil) != tt.wantErr {
+				t.Errorf("openDataFile() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if string(got) != tt.want {
+				t.Errorf("openDataFile() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestOpenDataFileWithoutVersion(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		wantErr bool
+	}{
+		{
+			// Data files written before they were checksummed are plain JSON
+			name:    "Test 1: When data file has the layout of an older version",
+			content: `{"Epoch":5,"AssignedCollections":{"1":true},"SeqAllottedCollections":[1],"Leaves":[0,295050]}`,
+		},
+		{
+			name:    "Test 2: When version was removed from a data file",
+			content: `{"checksum":"sha256:0000","data":{"Epoch":5,"Leaves":[0,295051]}}`,
+			wantErr: true,
+		},
+		{
+			name:    "Test 3: When data file has fields of another layout",
+			content: `{"Epoch":5,"MediansData":[295050]}`,
+			wantErr: true,
+		},
+		{
+			name:    "Test 4: When data file is not JSON",
+			content: `Epoch 5`,
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := openDataFile([]byte(tt.content), &types.CommitFileData{})
+			if (err != nil) != tt.wantErr {
+				t.Errorf("openDataFile() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !tt.wantErr && string(got) != tt.content {
+				t.Errorf("openDataFile() = %s, want %s", got, tt.content)
+			}
+		})
+	}
+}
+
+func TestWriteFileAtomically(t *testing.T) {
+	OS = &OSStruct{}
+	dir := t.TempDir()
+	filePath := filepath.Join(dir, "0x000000000000000000000000000000000000dea1_CommitData.json")
+	if err := os.WriteFile(filePath, []byte("old data"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if err := WriteFileAtomically(filePath, []byte("new data"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	got, err := os.ReadFile(filePath)
+	if err != nil || string(got) != "new data" {
+		t.Errorf("WriteFileAtomically() wrote %s, %v", got, err)
+	}
+	files, _ := os.ReadDir(dir)
+	if len(files) != 1 {
+		t.Errorf("WriteFileAtomically() left %d files in the directory, want 1", len(files))
+	}
+	info, _ := os.Stat(filePath)
+	if info.Mode().Perm() != 0600 {
+		t.Errorf("WriteFileAtomically() wrote file with mode %v, want 0600", info.Mode().Perm())
+	}
+}
+
+func TestWriteDataFileCreatesDirectory(t *testing.T) {
+	OS = &OSStruct{}
+	filePath := filepath.Join(t.TempDir(), "5", "0x000000000000000000000000000000000000dea1_CommitData.json")
+	if err := writeDataFile(filePath, []byte(`{"Epoch": 5}`)); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(filePath); err != nil {
+		t.Errorf("writeDataFile() did not write the file in the directory it created: %s", err)
+	}
+}
+
+func TestListDataFilesInDir(t *testing.T) {
+	dir := t.TempDir()
+	for _, file := range []string{
+		"0x000000000000000000000000000000000000dea1_disputeData.json",
+		"0x000000000000000000000000000000000000dea1_CommitData.json",
+		"100/0x000000000000000000000000000000000000dea1_CommitData.json",
+		"100/0x000000000000000000000000000000000000dea1_proposedData.json",
+		"100/.0x000000000000000000000000000000000000dea1_CommitData.json.tmp123",
+		"99/0x000000000000000000000000000000000000dea2_CommitData.json",
+		"backup/0x000000000000000000000000000000000000dea1_CommitData.json",
+	} {
+		if err := os.MkdirAll(filepath.Dir(filepath.Join(dir, file)), 0700); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(filepath.Join(dir, file), []byte("{}"), 0600); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	entries, err := ListDataFilesInDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got []string
+	for _, entry := range entries {
+		got = append(got, fmt.Sprintf("%d %s %s", entry.Epoch, entry.Address, entry.Kind))
+	}
+	want := []string{
+		"0 0x000000000000000000000000000000000000dea1 commit",
+		"0 0x000000000000000000000000000000000000dea1 dispute",
+		"99 0x000000000000000000000000000000000000dea2 commit",
+		"100 0x000000000000000000000000000000000000dea1 commit",
+		"100 0x000000000000000000000000000000000000dea1 propose",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("ListDataFilesInDir() = %v, want %v", got, want)
+	}
+}
+
+func TestPruneDataFilesInDir(t *testing.T) {
+	tests := []struct {
+		name      string
+		epoch     uint32
+		retention uint32
+		want      []uint32
+		wantKept  []string
+	}{
+		{
+			name:      "Test 1: When data files of the epochs older than the retention are pruned",
+			epoch:     102,
+			retention: 2,
+			want:      []uint32{99, 100},
+			wantKept:  []string{"101", "102", "backup", "0x000000000000000000000000000000000000dea1_disputeData.json"},
+		},
+		{
+			name:      "Test 2: When every epoch is within the retention",
+			epoch:     102,
+			retention: 100,
+			wantKept:  []string{"99", "100", "101", "102", "backup", "0x000000000000000000000000000000000000dea1_disputeData.json"},
+		},
+		{
+			name:      "Test 3: When retention is 0",
+			epoch:     102,
+			retention: 0,
+			wantKept:  []string{"99", "100", "101", "102", "backup", "0x000000000000000000000000000000000000dea1_disputeData.json"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			for _, epochDir := range []string{"99", "100", "101", "102", "backup"} {
+				if err := os.Mkdir(filepath.Join(dir, epochDir), 0700); err != nil {
+					t.Fatal(err)
+				}
+				if err := os.WriteFile(filepath.Join(dir, epochDir, "0x000000000000000000000000000000000000dea1_CommitData.json"), []byte("{}"), 0600); err != nil {
+					t.Fatal(err)
+				}
+			}
+			if err := os.WriteFile(filepath.Join(dir, "0x000000000000000000000000000000000000dea1_disputeData.json"), []byte("{}"), 0600); err != nil {
+				t.Fatal(err)
+			}
+
+			got, err := PruneDataFilesInDir(dir, tt.epoch, tt.retention)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("PruneDataFilesInDir() = %v, want %v", got, tt.want)
+			}
+			for _, kept := range tt.wantKept {
+				if _, err := os.Stat(filepath.Join(dir, kept)); err != nil {
+					t.Errorf("PruneDataFilesInDir() removed %s: %v", kept, err)
+				}
+			}
+			files, _ := os.ReadDir(dir)
+			if len(files) != len(tt.wantKept) {
+				t.Errorf("PruneDataFilesInDir() kept %d files, want %d", len(files), len(tt.wantKept))
+			}
+		})
+	}
+}
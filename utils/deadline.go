@@ -0,0 +1,70 @@
+//Package utils provides the utils functions
+package utils
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+)
+
+var errFetchDeadlineExceeded = errors.New("fetch deadline exceeded")
+
+//FetchDeadline holds the context requests of jobs are sent with, which is done at the deadline of the current state
+type FetchDeadline struct {
+	mutex sync.RWMutex
+	ctx   context.Context
+}
+
+// Jobs are fetched without a deadline until one is set for the current state
+var fetchDeadline = &FetchDeadline{ctx: context.Background()}
+
+//This function sets the time by which every source has to be fetched and returns the function which removes it, requests still running at the deadline or once ctx is done are cancelled
+func SetFetchDeadline(ctx context.Context, deadline time.Time) func() {
+	ctx, cancel := context.WithDeadline(ctx, deadline)
+	fetchDeadline.mutex.Lock()
+	fetchDeadline.ctx = ctx
+	fetchDeadline.mutex.Unlock()
+	return func() {
+		cancel()
+		fetchDeadline.mutex.Lock()
+		if fetchDeadline.ctx == ctx {
+			fetchDeadline.ctx = context.Background()
+		}
+		fetchDeadline.mutex.Unlock()
+	}
+}
+
+//This function returns the context which is done once the fetch deadline is reached
+func (*UtilsStruct) GetFetchContext() context.Context {
+	return fetchDeadline.Context()
+}
+
+//This function returns the timeout of a single attempt to fetch a source, cut short by the fetch deadline
+func (*UtilsStruct) GetFetchAttemptTimeout(timeout time.Duration) (time.Duration, error) {
+	return fetchDeadline.Timeout(timeout)
+}
+
+//This function returns the context which is done once the fetch deadline is reached
+func (deadline *FetchDeadline) Context() context.Context {
+	deadline.mutex.RLock()
+	defer deadline.mutex.RUnlock()
+	return deadline.ctx
+}
+
+//This function returns the timeout of a single attempt to fetch a source, which is cut short so that it ends by the fetch deadline
+func (deadline *FetchDeadline) Timeout(timeout time.Duration) (time.Duration, error) {
+	ctx := deadline.Context()
+	if ctx.Err() != nil {
+		return 0, errFetchDeadlineExceeded
+	}
+	if until, ok := ctx.Deadline(); ok {
+		if remaining := time.Until(until); remaining < timeout {
+			if remaining <= 0 {
+				return 0, errFetchDeadlineExceeded
+			}
+			return remaining, nil
+		}
+	}
+	return timeout, nil
+}
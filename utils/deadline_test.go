@@ -0,0 +1,282 @@
+package utils
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"razor/core/types"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestFetchDeadlineTimeout(t *testing.T) {
+	tests := []struct {
+		name     string
+		deadline time.Duration
+		timeout  time.Duration
+		want     time.Duration
+		wantErr  bool
+	}{
+		{
+			name:    "Test 1: When there is no fetch deadline",
+			timeout: 10 * time.Second,
+			want:    10 * time.Second,
+		},
+		{
+			name:     "Test 2: When fetch deadline is after the timeout",
+			deadline: time.Minute,
+			timeout:  10 * time.Second,
+			want:     10 * time.Second,
+		},
+		{
+			name:     "Test 3: When fetch deadline is reached",
+			deadline: -time.Second,
+			timeout:  10 * time.Second,
+			wantErr:  true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.deadline != 0 {
+				removeFetchDeadline := SetFetchDeadline(context.Background(), time.Now().Add(tt.deadline))
+				defer removeFetchDeadline()
+			}
+			got, err := fetchDeadline.Timeout(tt.timeout)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Timeout() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if got != tt.want {
+				t.Errorf("Timeout() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	removeFetchDeadline := SetFetchDeadline(context.Background(), time.Now().Add(5*time.Second))
+	if got, err := fetchDeadline.Timeout(time.Minute); err != nil || got > 5*time.Second {
+		t.Errorf("Timeout() got = %v, err = %v, want at most 5s", got, err)
+	}
+	removeFetchDeadline()
+	if fetchDeadline.Context().Err() != nil {
+		t.Error("Context() is done after the fetch deadline is removed")
+	}
+}
+
+func TestGetDataFromAPIWithTimeoutAndRetries(t *testing.T) {
+	tests := []struct {
+		name         string
+		failures     int32
+		delay        time.Duration
+		request      types.JobRequest
+		deadline     time.Duration
+		wantRequests int32
+		wantErr      bool
+	}{
+		{
+			name:         "Test 1: When a failed request is retried",
+			failures:     2,
+			request:      types.JobRequest{Attempts: 3},
+			wantRequests: 3,
+		},
+		{
+			name:         "Test 2: When attempts of the job are used",
+			failures:     5,
+			request:      types.JobRequest{Attempts: 2},
+			wantRequests: 2,
+			wantErr:      true,
+		},
+		{
+			name:         "Test 3: When source is slower than the timeout of the job",
+			delay:        500 * time.Millisecond,
+			request:      types.JobRequest{Timeout: 50 * time.Millisecond, Attempts: 1},
+			wantRequests: 1,
+			wantErr:      true,
+		},
+		{
+			name:         "Test 4: When source is slower than the fetch deadline",
+			delay:        500 * time.Millisecond,
+			request:      types.JobRequest{Attempts: 5},
+			deadline:     100 * time.Millisecond,
+			wantRequests: 1,
+			wantErr:      true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var requests int32
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if atomic.AddInt32(&requests, 1) <= tt.failures {
+					w.WriteHeader(http.StatusServiceUnavailable)
+					return
+				}
+				select {
+				case <-time.After(tt.delay):
+				case <-r.Context().Done():
+					return
+				}
+				io.WriteString(w, `{"last": "1234.5"}`)
+			}))
+			defer server.Close()
+
+			StartRazor(OptionsPackageStruct{
+				UtilsInterface:  &UtilsStruct{},
+				RetryInterface:  RetryStruct{},
+				IoutilInterface: IoutilStruct{},
+			})
+			if tt.deadline != 0 {
+				removeFetchDeadline := SetFetchDeadline(context.Background(), time.Now().Add(tt.deadline))
+				defer removeFetchDeadline()
+			}
+
+			start := time.Now()
+			_, err := UtilsInterface.GetDataFromAPI(server.URL, tt.request)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("GetDataFromAPI() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got := atomic.LoadInt32(&requests); got != tt.wantRequests {
+				t.Errorf("GetDataFromAPI() made %d requests, want %d", got, tt.wantRequests)
+			}
+			if elapsed := time.Since(start); tt.delay != 0 && elapsed >= tt.delay {
+				t.Errorf("GetDataFromAPI() took %s, want it to be cut off before %s", elapsed, tt.delay)
+			}
+		})
+	}
+}
+
+func TestGetDataFromXHTMLWithRetries(t *testing.T) {
+	tests := []struct {
+		name         string
+		failures     int32
+		delay        time.Duration
+		request      types.JobRequest
+		deadline     time.Duration
+		want         string
+		wantRequests int32
+		wantErr      bool
+	}{
+		{
+			name:         "Test 1: When a failed request is retried",
+			failures:     1,
+			request:      types.JobRequest{Attempts: 2},
+			want:         "1234.5",
+			wantRequests: 2,
+		},
+		{
+			name:         "Test 2: When source is slower than the fetch deadline",
+			delay:        500 * time.Millisecond,
+			request:      types.JobRequest{Attempts: 5},
+			deadline:     100 * time.Millisecond,
+			wantRequests: 1,
+			wantErr:      true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var requests int32
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if atomic.AddInt32(&requests, 1) <= tt.failures {
+					w.WriteHeader(http.StatusServiceUnavailable)
+					return
+				}
+				select {
+				case <-time.After(tt.delay):
+				case <-r.Context().Done():
+					return
+				}
+				io.WriteString(w, `<html><body><span class="priceValue">1234.5</span></body></html>`)
+			}))
+			defer server.Close()
+
+			StartRazor(OptionsPackageStruct{UtilsInterface: &UtilsStruct{}, RetryInterface: RetryStruct{}})
+			if tt.deadline != 0 {
+				removeFetchDeadline := SetFetchDeadline(context.Background(), time.Now().Add(tt.deadline))
+				defer removeFetchDeadline()
+			}
+
+			start := time.Now()
+			got, err := UtilsInterface.GetDataFromXHTML(server.URL, []string{`//span[@class="priceValue"]`}, tt.request)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("GetDataFromXHTML() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err == nil && got[0] != tt.want {
+				t.Errorf("GetDataFromXHTML() got = %v, want %v", got, tt.want)
+			}
+			if got := atomic.LoadInt32(&requests); got != tt.wantRequests {
+				t.Errorf("GetDataFromXHTML() made %d requests, want %d", got, tt.wantRequests)
+			}
+			if elapsed := time.Since(start); tt.delay != 0 && elapsed >= tt.delay {
+				t.Errorf("GetDataFromXHTML() took %s, want it to be cut off before %s", elapsed, tt.delay)
+			}
+		})
+	}
+}
+
+func TestGetDataFromHTMLWithRetries(t *testing.T) {
+	tests := []struct {
+		name         string
+		failures     int32
+		delay        time.Duration
+		request      types.JobRequest
+		deadline     time.Duration
+		want         string
+		wantRequests int32
+		wantErr      bool
+	}{
+		{
+			name:         "Test 1: When a failed request is retried",
+			failures:     1,
+			request:      types.JobRequest{Attempts: 2},
+			want:         "1234.5",
+			wantRequests: 2,
+		},
+		{
+			name:         "Test 2: When source is slower than the fetch deadline",
+			delay:        500 * time.Millisecond,
+			request:      types.JobRequest{Attempts: 5},
+			deadline:     100 * time.Millisecond,
+			wantRequests: 1,
+			wantErr:      true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var requests int32
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if atomic.AddInt32(&requests, 1) <= tt.failures {
+					w.WriteHeader(http.StatusServiceUnavailable)
+					return
+				}
+				select {
+				case <-time.After(tt.delay):
+				case <-r.Context().Done():
+					return
+				}
+				io.WriteString(w, `<html><body><span class="priceValue">1234.5</span></body></html>`)
+			}))
+			defer server.Close()
+
+			StartRazor(OptionsPackageStruct{UtilsInterface: &UtilsStruct{}, RetryInterface: RetryStruct{}})
+			if tt.deadline != 0 {
+				removeFetchDeadline := SetFetchDeadline(context.Background(), time.Now().Add(tt.deadline))
+				defer removeFetchDeadline()
+			}
+
+			start := time.Now()
+			got, err := UtilsInterface.GetDataFromHTML(server.URL, []string{"span.priceValue"}, tt.request)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("GetDataFromHTML() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err == nil && got[0] != tt.want {
+				t.Errorf("GetDataFromHTML() got = %v, want %v", got, tt.want)
+			}
+			if got := atomic.LoadInt32(&requests); got != tt.wantRequests {
+				t.Errorf("GetDataFromHTML() made %d requests, want %d", got, tt.wantRequests)
+			}
+			if elapsed := time.Since(start); tt.delay != 0 && elapsed >= tt.delay {
+				t.Errorf("GetDataFromHTML() took %s, want it to be cut off before %s", elapsed, tt.delay)
+			}
+		})
+	}
+}
@@ -0,0 +1,104 @@
+//Package utils provides the utils functions
+package utils
+
+import (
+	"fmt"
+	"math/big"
+	"razor/core/types"
+	"strings"
+)
+
+//This function resolves the names in the expressions of the derived jobs to their inputs, other derived jobs or other jobs of the collection and skips the derived jobs which cannot be resolved or depend on each other in a cycle
+func ResolveDerivedJobs(collection string, derivedJobs []types.AssetJob, jobs []types.AssetJob) []types.AssetJob {
+	jobsByName := make(map[string]types.AssetJob)
+	for _, job := range jobs {
+		if job.Name != "" {
+			jobsByName[job.Name] = job
+		}
+	}
+	derivedJobsByName := make(map[string]types.AssetJob)
+	for _, derivedJob := range derivedJobs {
+		derivedJobsByName[derivedJob.Name] = derivedJob
+	}
+
+	resolvedJobs := make(map[string]types.AssetJob)
+	failedJobs := make(map[string]error)
+	visiting := make(map[string]bool)
+
+	var resolve func(name string, path []string) error
+	resolve = func(name string, path []string) error {
+		if _, ok := resolvedJobs[name]; ok {
+			return nil
+		}
+		if err, ok := failedJobs[name]; ok {
+			return err
+		}
+		path = append(path, name)
+		if visiting[name] {
+			return fmt.Errorf("cycle in derived jobs %s", strings.Join(path, " -> "))
+		}
+		visiting[name] = true
+		defer delete(visiting, name)
+
+		err := func() error {
+			derivedJob := derivedJobsByName[name]
+			expression, err := ParseExpression(derivedJob.Expression)
+			if err != nil {
+				return err
+			}
+			inputs := make(map[string]types.AssetJob)
+			for _, identifier := range expression.Identifiers() {
+				if input, ok := derivedJob.Inputs[identifier]; ok {
+					inputs[identifier] = input
+				} else if _, ok := derivedJobsByName[identifier]; ok {
+					if err := resolve(identifier, path); err != nil {
+						return err
+					}
+					inputs[identifier] = resolvedJobs[identifier]
+				} else if job, ok := jobsByName[identifier]; ok {
+					inputs[identifier] = job
+				} else {
+					return fmt.Errorf("unknown job %s in expression", identifier)
+				}
+			}
+			derivedJob.Inputs = inputs
+			resolvedJobs[name] = derivedJob
+			return nil
+		}()
+		if err != nil {
+			failedJobs[name] = err
+		}
+		return err
+	}
+
+	var resolvedDerivedJobs []types.AssetJob
+	for _, derivedJob := range derivedJobs {
+		if err := resolve(derivedJob.Name, nil); err != nil {
+			log.Errorf("Skipping derived job %s of collection %s: %s", derivedJob.Name, collection, err)
+			continue
+		}
+		resolvedDerivedJobs = append(resolvedDerivedJobs, resolvedJobs[derivedJob.Name])
+	}
+	return resolvedDerivedJobs
+}
+
+//This function returns the value of the derived job by evaluating its expression with exact decimals over the values of its inputs before their power is applied
+func getDatumFromDerivedJob(job types.AssetJob) (*big.Rat, error) {
+	expression, err := ParseExpression(job.Expression)
+	if err != nil {
+		return nil, err
+	}
+	values := make(map[string]*big.Rat)
+	for _, name := range expression.Identifiers() {
+		input, ok := job.Inputs[name]
+		if !ok {
+			return nil, fmt.Errorf("unknown job %s in expression", name)
+		}
+		value, err := getDatum(input)
+		if err != nil {
+			return nil, fmt.Errorf("error in fetching value of %s: %s", name, err)
+		}
+		values[name] = value
+	}
+	return expression.Evaluate(values)
+}
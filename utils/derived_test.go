@@ -0,0 +1,189 @@
+package utils
+
+import (
+	"errors"
+	"math/big"
+	"razor/core"
+	"razor/core/types"
+	"razor/pkg/bindings"
+	"razor/utils/mocks"
+	"reflect"
+	"testing"
+
+	"github.com/avast/retry-go"
+	"github.com/stretchr/testify/mock"
+)
+
+func getDerivedJob(name string, expression string, inputs map[string]types.AssetJob) types.AssetJob {
+	return types.AssetJob{
+		StructsJob: bindings.StructsJob{Name: name, Power: 6, Weight: 1},
+		Expression: expression,
+		Inputs:     inputs,
+	}
+}
+
+func TestResolveDerivedJobs(t *testing.T) {
+	ethusd := types.AssetJob{StructsJob: bindings.StructsJob{Id: 1, Name: "ethusd", Url: "http://127.0.0.1/ethusd", Selector: "last", SelectorType: core.GJSONSelectorType}}
+	btcusd := types.AssetJob{StructsJob: bindings.StructsJob{Name: "btcusd", Url: "http://127.0.0.1/btcusd", Selector: "last", SelectorType: core.GJSONSelectorType}}
+	jobs := []types.AssetJob{ethusd}
+
+	tests := []struct {
+		name        string
+		derivedJobs []types.AssetJob
+		want        []types.AssetJob
+	}{
+		{
+			name: "Test 1: When expression refers to a job of the collection and an input",
+			derivedJobs: []types.AssetJob{
+				getDerivedJob("ethbtc", "ethusd / btcusd", map[string]types.AssetJob{"btcusd": btcusd}),
+			},
+			want: []types.AssetJob{
+				getDerivedJob("ethbtc", "ethusd / btcusd", map[string]types.AssetJob{"btcusd": btcusd, "ethusd": ethusd}),
+			},
+		},
+		{
+			name: "Test 2: When expression refers to another derived job",
+			derivedJobs: []types.AssetJob{
+				getDerivedJob("btceth", "1 / ethbtc", nil),
+				getDerivedJob("ethbtc", "ethusd / btcusd", map[string]types.AssetJob{"btcusd": btcusd}),
+			},
+			want: []types.AssetJob{
+				getDerivedJob("btceth", "1 / ethbtc", map[string]types.AssetJob{
+					"ethbtc": getDerivedJob("ethbtc", "ethusd / btcusd", map[string]types.AssetJob{"btcusd": btcusd, "ethusd": ethusd}),
+				}),
+				getDerivedJob("ethbtc", "ethusd / btcusd", map[string]types.AssetJob{"btcusd": btcusd, "ethusd": ethusd}),
+			},
+		},
+		{
+			name: "Test 3: When input has the same name as a job of the collection, input is used",
+			derivedJobs: []types.AssetJob{
+				getDerivedJob("double", "2 * ethusd", map[string]types.AssetJob{"ethusd": btcusd}),
+			},
+			want: []types.AssetJob{
+				getDerivedJob("double", "2 * ethusd", map[string]types.AssetJob{"ethusd": btcusd}),
+			},
+		},
+		{
+			name: "Test 4: When derived jobs refer to each other in a cycle, all jobs depending on the cycle are skipped",
+			derivedJobs: []types.AssetJob{
+				getDerivedJob("a", "b + 1", nil),
+				getDerivedJob("b", "c * 2", nil),
+				getDerivedJob("c", "a - ethusd", nil),
+				getDerivedJob("d", "c / 2", nil),
+				getDerivedJob("e", "ethusd / 2", nil),
+			},
+			want: []types.AssetJob{
+				getDerivedJob("e", "ethusd / 2", map[string]types.AssetJob{"ethusd": ethusd}),
+			},
+		},
+		{
+			name: "Test 5: When derived job refers to itself",
+			derivedJobs: []types.AssetJob{
+				getDerivedJob("ethusd_avg", "(ethusd + ethusd_avg) / 2", nil),
+			},
+			want: nil,
+		},
+		{
+			name: "Test 6: When expression refers to an unknown job",
+			derivedJobs: []types.AssetJob{
+				getDerivedJob("ethdot", "ethusd / dotusd", nil),
+				getDerivedJob("doteth", "1 / ethdot", nil),
+			},
+			want: nil,
+		},
+		{
+			name: "Test 7: When expression is invalid",
+			derivedJobs: []types.AssetJob{
+				getDerivedJob("ethbtc", "ethusd / (btcusd", map[string]types.AssetJob{"btcusd": btcusd}),
+			},
+			want: nil,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := ResolveDerivedJobs("ethCollection", tt.derivedJobs, jobs)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ResolveDerivedJobs() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGetDataToCommitFromDerivedJob(t *testing.T) {
+	ethusd := types.AssetJob{StructsJob: bindings.StructsJob{Name: "ethusd", Url: "http://127.0.0.1/ethusd", Selector: "last", SelectorType: core.GJSONSelectorType, Power: 2}}
+	btcusd := types.AssetJob{StructsJob: bindings.StructsJob{Name: "btcusd", Url: "http://127.0.0.1/btcusd", Selector: "last", SelectorType: core.GJSONSelectorType, Power: 2}}
+	ethbtc := getDerivedJob("ethbtc", "ethusd / btcusd", map[string]types.AssetJob{"ethusd": ethusd, "btcusd": btcusd})
+
+	tests := []struct {
+		name      string
+		job       types.AssetJob
+		responses map[string]string
+		errors    map[string]error
+		want      *big.Int
+		wantErr   bool
+	}{
+		{
+			name:      "Test 1: When a cross rate is derived, it is computed exactly before power is applied",
+			job:       ethbtc,
+			responses: map[string]string{"http://127.0.0.1/ethusd": `{"last":"3000.12"}`, "http://127.0.0.1/btcusd": `{"last":"40000.25"}`},
+			want:      big.NewInt(75002),
+		},
+		{
+			name:      "Test 2: When derived job depends on another derived job",
+			job:       getDerivedJob("btceth", "1 / ethbtc", map[string]types.AssetJob{"ethbtc": ethbtc}),
+			responses: map[string]string{"http://127.0.0.1/ethusd": `{"last":"3000.12"}`, "http://127.0.0.1/btcusd": `{"last":"40000.25"}`},
+			want:      big.NewInt(13332883),
+		},
+		{
+			name:      "Test 3: When value of an input cannot be fetched",
+			job:       ethbtc,
+			responses: map[string]string{"http://127.0.0.1/ethusd": `{"last":"3000.12"}`},
+			errors:    map[string]error{"http://127.0.0.1/btcusd": errors.New("unable to reach API")},
+			wantErr:   true,
+		},
+		{
+			name:      "Test 4: When expression divides by zero",
+			job:       ethbtc,
+			responses: map[string]string{"http://127.0.0.1/ethusd": `{"last":"3000.12"}`, "http://127.0.0.1/btcusd": `{"last":"0"}`},
+			wantErr:   true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			utilsMock := new(mocks.Utils)
+			retryMock := new(mocks.RetryUtils)
+
+			optionsPackageStruct := OptionsPackageStruct{
+				UtilsInterface: utilsMock,
+				RetryInterface: retryMock,
+			}
+			utils := StartRazor(optionsPackageStruct)
+
+			for url, response := range tt.responses {
+				utilsMock.On("GetDataFromAPI", url, mock.Anything).Return([]byte(response), nil)
+			}
+			for url, err := range tt.errors {
+				utilsMock.On("GetDataFromAPI", url, mock.Anything).Return(nil, err)
+			}
+			utilsMock.On("ConvertToNumber", mock.Anything).Return(
+				func(num interface{}) *big.Rat {
+					value, _ := ParseDecimal(num.(string))
+					return value
+				},
+				func(num interface{}) error {
+					_, err := ParseDecimal(num.(string))
+					return err
+				})
+			retryMock.On("RetryAttempts", mock.AnythingOfType("uint")).Return(retry.Attempts(1))
+
+			got, err := utils.GetDataToCommitFromJob(tt.job)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("GetDataToCommitFromJob() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("GetDataToCommitFromJob() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
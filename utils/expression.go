@@ -0,0 +1,331 @@
+//Package utils provides the utils functions
+package utils
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+//Expression is a parsed arithmetic expression over named values, evaluated with exact decimals
+type Expression struct {
+	root expressionNode
+}
+
+type expressionNode interface {
+	evaluate(values map[string]*big.Rat) (*big.Rat, error)
+	identifiers(names map[string]bool)
+}
+
+type numberNode struct {
+	value *big.Rat
+}
+
+type identifierNode struct {
+	name string
+}
+
+type unaryNode struct {
+	operand expressionNode
+}
+
+type binaryNode struct {
+	operator    byte
+	left, right expressionNode
+}
+
+type functionNode struct {
+	name      string
+	arguments []expressionNode
+}
+
+var expressionFunctions = map[string]int{
+	"abs": 1,
+	"min": -1,
+	"max": -1,
+	"avg": -1,
+}
+
+//This function parses the expression which may use decimal numbers, job names, + - * /, parentheses and the functions abs, min, max and avg
+func ParseExpression(expression string) (*Expression, error) {
+	parser := &expressionParser{input: expression}
+	parser.next()
+	root, err := parser.parseSum()
+	if err != nil {
+		return nil, err
+	}
+	if parser.token != "" {
+		return nil, fmt.Errorf("unexpected %q at position %d in expression", parser.token, parser.tokenStart)
+	}
+	return &Expression{root: root}, nil
+}
+
+//This function returns the sorted names of the values the expression refers to
+func (expression *Expression) Identifiers() []string {
+	names := make(map[string]bool)
+	expression.root.identifiers(names)
+	identifiers := make([]string, 0, len(names))
+	for name := range names {
+		identifiers = append(identifiers, name)
+	}
+	sort.Strings(identifiers)
+	return identifiers
+}
+
+//This function evaluates the expression with the given values of the names it refers to
+func (expression *Expression) Evaluate(values map[string]*big.Rat) (*big.Rat, error) {
+	return expression.root.evaluate(values)
+}
+
+func (node *numberNode) evaluate(map[string]*big.Rat) (*big.Rat, error) {
+	return node.value, nil
+}
+
+func (node *numberNode) identifiers(map[string]bool) {}
+
+func (node *identifierNode) evaluate(values map[string]*big.Rat) (*big.Rat, error) {
+	value, ok := values[node.name]
+	if !ok || value == nil {
+		return nil, fmt.Errorf("no value for %s", node.name)
+	}
+	return value, nil
+}
+
+func (node *identifierNode) identifiers(names map[string]bool) {
+	names[node.name] = true
+}
+
+func (node *unaryNode) evaluate(values map[string]*big.Rat) (*big.Rat, error) {
+	operand, err := node.operand.evaluate(values)
+	if err != nil {
+		return nil, err
+	}
+	return new(big.Rat).Neg(operand), nil
+}
+
+func (node *unaryNode) identifiers(names map[string]bool) {
+	node.operand.identifiers(names)
+}
+
+func (node *binaryNode) evaluate(values map[string]*big.Rat) (*big.Rat, error) {
+	left, err := node.left.evaluate(values)
+	if err != nil {
+		return nil, err
+	}
+	right, err := node.right.evaluate(values)
+	if err != nil {
+		return nil, err
+	}
+	switch node.operator {
+	case '+':
+		return new(big.Rat).Add(left, right), nil
+	case '-':
+		return new(big.Rat).Sub(left, right), nil
+	case '*':
+		return new(big.Rat).Mul(left, right), nil
+	default:
+		if right.Sign() == 0 {
+			return nil, errors.New("division by zero")
+		}
+		return new(big.Rat).Quo(left, right), nil
+	}
+}
+
+func (node *binaryNode) identifiers(names map[string]bool) {
+	node.left.identifiers(names)
+	node.right.identifiers(names)
+}
+
+func (node *functionNode) evaluate(values map[string]*big.Rat) (*big.Rat, error) {
+	arguments := make([]*big.Rat, len(node.arguments))
+	for i, argument := range node.arguments {
+		value, err := argument.evaluate(values)
+		if err != nil {
+			return nil, err
+		}
+		arguments[i] = value
+	}
+	result := new(big.Rat).Set(arguments[0])
+	switch node.name {
+	case "abs":
+		result.Abs(result)
+	case "min":
+		for _, argument := range arguments[1:] {
+			if argument.Cmp(result) < 0 {
+				result.Set(argument)
+			}
+		}
+	case "max":
+		for _, argument := range arguments[1:] {
+			if argument.Cmp(result) > 0 {
+				result.Set(argument)
+			}
+		}
+	case "avg":
+		for _, argument := range arguments[1:] {
+			result.Add(result, argument)
+		}
+		result.Quo(result, new(big.Rat).SetInt64(int64(len(arguments))))
+	}
+	return result, nil
+}
+
+func (node *functionNode) identifiers(names map[string]bool) {
+	for _, argument := range node.arguments {
+		argument.identifiers(names)
+	}
+}
+
+type expressionParser struct {
+	input      string
+	position   int
+	token      string
+	tokenStart int
+}
+
+//This function moves the parser to the next token, an empty token marks the end of the input
+func (parser *expressionParser) next() {
+	for parser.position < len(parser.input) && unicode.IsSpace(rune(parser.input[parser.position])) {
+		parser.position++
+	}
+	parser.tokenStart = parser.position
+	if parser.position >= len(parser.input) {
+		parser.token = ""
+		return
+	}
+	char := parser.input[parser.position]
+	switch {
+	case isIdentifierChar(char) && !isDigit(char):
+		for parser.position < len(parser.input) && isIdentifierChar(parser.input[parser.position]) {
+			parser.position++
+		}
+	case isDigit(char) || char == '.':
+		for parser.position < len(parser.input) && (isDigit(parser.input[parser.position]) || parser.input[parser.position] == '.') {
+			parser.position++
+		}
+	default:
+		parser.position++
+	}
+	parser.token = parser.input[parser.tokenStart:parser.position]
+}
+
+func (parser *expressionParser) parseSum() (expressionNode, error) {
+	left, err := parser.parseProduct()
+	if err != nil {
+		return nil, err
+	}
+	for parser.token == "+" || parser.token == "-" {
+		operator := parser.token[0]
+		parser.next()
+		right, err := parser.parseProduct()
+		if err != nil {
+			return nil, err
+		}
+		left = &binaryNode{operator: operator, left: left, right: right}
+	}
+	return left, nil
+}
+
+func (parser *expressionParser) parseProduct() (expressionNode, error) {
+	left, err := parser.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+	for parser.token == "*" || parser.token == "/" {
+		operator := parser.token[0]
+		parser.next()
+		right, err := parser.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		left = &binaryNode{operator: operator, left: left, right: right}
+	}
+	return left, nil
+}
+
+func (parser *expressionParser) parseUnary() (expressionNode, error) {
+	switch parser.token {
+	case "-":
+		parser.next()
+		operand, err := parser.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return &unaryNode{operand: operand}, nil
+	case "+":
+		parser.next()
+		return parser.parseUnary()
+	}
+	return parser.parsePrimary()
+}
+
+func (parser *expressionParser) parsePrimary() (expressionNode, error) {
+	token, tokenStart := parser.token, parser.tokenStart
+	switch {
+	case token == "":
+		return nil, errors.New("unexpected end of expression")
+	case token == "(":
+		parser.next()
+		node, err := parser.parseSum()
+		if err != nil {
+			return nil, err
+		}
+		if parser.token != ")" {
+			return nil, fmt.Errorf("missing ) at position %d in expression", parser.tokenStart)
+		}
+		parser.next()
+		return node, nil
+	case isDigit(token[0]) || token[0] == '.':
+		value, err := ParseDecimal(token)
+		if err != nil {
+			return nil, fmt.Errorf("invalid number %q at position %d in expression", token, tokenStart)
+		}
+		parser.next()
+		return &numberNode{value: value}, nil
+	case isIdentifierChar(token[0]):
+		parser.next()
+		if parser.token != "(" {
+			return &identifierNode{name: token}, nil
+		}
+		return parser.parseFunction(token, tokenStart)
+	}
+	return nil, fmt.Errorf("unexpected %q at position %d in expression", token, tokenStart)
+}
+
+func (parser *expressionParser) parseFunction(name string, nameStart int) (expressionNode, error) {
+	numArguments, ok := expressionFunctions[strings.ToLower(name)]
+	if !ok {
+		return nil, fmt.Errorf("unknown function %s at position %d in expression", name, nameStart)
+	}
+	node := &functionNode{name: strings.ToLower(name)}
+	parser.next()
+	for parser.token != ")" {
+		if len(node.arguments) != 0 {
+			if parser.token != "," {
+				return nil, fmt.Errorf("missing ) at position %d in expression", parser.tokenStart)
+			}
+			parser.next()
+		}
+		argument, err := parser.parseSum()
+		if err != nil {
+			return nil, err
+		}
+		node.arguments = append(node.arguments, argument)
+	}
+	parser.next()
+	if len(node.arguments) == 0 || (numArguments > 0 && len(node.arguments) != numArguments) {
+		return nil, fmt.Errorf("wrong number of arguments for function %s at position %d in expression", name, nameStart)
+	}
+	return node, nil
+}
+
+func isDigit(char byte) bool {
+	return char >= '0' && char <= '9'
+}
+
+func isIdentifierChar(char byte) bool {
+	return char == '_' || isDigit(char) || (char >= 'a' && char <= 'z') || (char >= 'A' && char <= 'Z')
+}
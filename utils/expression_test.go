@@ -0,0 +1,165 @@
+package utils
+
+import (
+	"math/big"
+	"reflect"
+	"testing"
+)
+
+func TestParseExpression(t *testing.T) {
+	tests := []struct {
+		name            string
+		expression      string
+		wantIdentifiers []string
+		wantErr         bool
+	}{
+		{
+			name:            "Test 1: When expression is a ratio of two jobs",
+			expression:      "ethusd / btcusd",
+			wantIdentifiers: []string{"btcusd", "ethusd"},
+		},
+		{
+			name:            "Test 2: When expression uses functions, parentheses and numbers",
+			expression:      "avg(bid, ask) * (1 - 0.002) + abs(-spread_1)",
+			wantIdentifiers: []string{"ask", "bid", "spread_1"},
+		},
+		{
+			name:            "Test 3: When a job is referred to more than once",
+			expression:      "max(eth, 2 * eth)",
+			wantIdentifiers: []string{"eth"},
+		},
+		{
+			name:            "Test 4: When expression has no jobs",
+			expression:      "1 / 3",
+			wantIdentifiers: []string{},
+		},
+		{
+			name:       "Test 5: When parenthesis is not closed",
+			expression: "(ethusd / btcusd",
+			wantErr:    true,
+		},
+		{
+			name:       "Test 6: When operand is missing",
+			expression: "ethusd /",
+			wantErr:    true,
+		},
+		{
+			name:       "Test 7: When function is unknown",
+			expression: "sqrt(ethusd)",
+			wantErr:    true,
+		},
+		{
+			name:       "Test 8: When function has wrong number of arguments",
+			expression: "abs(bid, ask)",
+			wantErr:    true,
+		},
+		{
+			name:       "Test 9: When number is invalid",
+			expression: "1.2.3 * eth",
+			wantErr:    true,
+		},
+		{
+			name:       "Test 10: When expression has an unknown character",
+			expression: "eth ^ 2",
+			wantErr:    true,
+		},
+		{
+			name:       "Test 11: When expression is empty",
+			expression: " ",
+			wantErr:    true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseExpression(tt.expression)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ParseExpression() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if err != nil {
+				return
+			}
+			if !reflect.DeepEqual(got.Identifiers(), tt.wantIdentifiers) {
+				t.Errorf("Identifiers() got = %v, want %v", got.Identifiers(), tt.wantIdentifiers)
+			}
+		})
+	}
+}
+
+func TestExpressionEvaluate(t *testing.T) {
+	values := map[string]*big.Rat{
+		"ethusd": big.NewRat(300012, 100),
+		"btcusd": big.NewRat(4000025, 100),
+		"bid":    big.NewRat(2999, 1),
+		"ask":    big.NewRat(3002, 1),
+		"zero":   new(big.Rat),
+	}
+	tests := []struct {
+		name       string
+		expression string
+		want       *big.Rat
+		wantErr    bool
+	}{
+		{
+			name:       "Test 1: When a cross rate is computed, result is exact",
+			expression: "ethusd / btcusd",
+			want:       big.NewRat(300012, 4000025),
+		},
+		{
+			name:       "Test 2: When an inverse quote is computed",
+			expression: "1 / ethusd",
+			want:       big.NewRat(100, 300012),
+		},
+		{
+			name:       "Test 3: When average of bid and ask is computed",
+			expression: "(bid + ask) / 2",
+			want:       big.NewRat(6001, 2),
+		},
+		{
+			name:       "Test 4: When operators have different precedence",
+			expression: "bid - ask * 2 + 1",
+			want:       big.NewRat(2999-6004+1, 1),
+		},
+		{
+			name:       "Test 5: When unary minus is used",
+			expression: "-bid - -ask",
+			want:       big.NewRat(3, 1),
+		},
+		{
+			name:       "Test 6: When functions are used",
+			expression: "min(bid, ask) + max(bid, ask) - avg(bid, ask, 3001) + abs(bid - ask)",
+			want:       big.NewRat(9010, 3),
+		},
+		{
+			name:       "Test 7: When decimal number is used",
+			expression: "bid * 0.1",
+			want:       big.NewRat(2999, 10),
+		},
+		{
+			name:       "Test 8: When dividing by zero",
+			expression: "ethusd / zero",
+			wantErr:    true,
+		},
+		{
+			name:       "Test 9: When value of a job is missing",
+			expression: "ethusd / dotusd",
+			wantErr:    true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			expression, err := ParseExpression(tt.expression)
+			if err != nil {
+				t.Fatalf("ParseExpression() error = %v", err)
+			}
+			got, err := expression.Evaluate(values)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Evaluate() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if err == nil && got.Cmp(tt.want) != 0 {
+				t.Errorf("Evaluate() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,139 @@
+//Package utils provides the utils functions
+package utils
+
+import (
+	"fmt"
+	"math"
+	"math/big"
+	"razor/core"
+	"razor/core/types"
+	"razor/metrics"
+	"razor/pkg/bindings"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/ethclient"
+)
+
+var fallbackSteps = []string{"secondary jobs", "previous value", "skip"}
+
+// Without a fallback in assets.json a collection reuses its previous value, as it always has
+var defaultFallbackChain = []string{"previous value"}
+
+//This function returns the fallback of the collection from its config in assets.json
+func GetFallbackFromConfig(collection string, fallbackConfig *types.FallbackConfig) *types.CollectionFallback {
+	if fallbackConfig == nil {
+		return nil
+	}
+	collectionFallback := &types.CollectionFallback{}
+	if fallbackConfig.MaxStalenessEpochs < 0 || fallbackConfig.MaxStalenessEpochs > math.MaxUint32 {
+		log.Errorf("Ignoring max staleness epochs of collection %s: %d is not between 0 and %d", collection, fallbackConfig.MaxStalenessEpochs, uint32(math.MaxUint32))
+	} else {
+		collectionFallback.MaxStalenessEpochs = uint32(fallbackConfig.MaxStalenessEpochs)
+	}
+	for _, step := range fallbackConfig.Chain {
+		collectionFallback.Chain = append(collectionFallback.Chain, strings.ToLower(step))
+	}
+	for i, secondaryJob := range fallbackConfig.SecondaryJobs {
+		job, err := GetJobFromConfig(secondaryJob)
+		if err != nil {
+			log.Errorf("Skipping secondary job %d of collection %s: %s", i, collection, err)
+			continue
+		}
+		collectionFallback.SecondaryJobs = append(collectionFallback.SecondaryJobs, job)
+	}
+	return collectionFallback
+}
+
+//This function goes through the fallback chain of the collection whose jobs have failed until a step returns a value
+func aggregateWithFallback(client *ethclient.Client, previousEpoch uint32, collection bindings.StructsCollection, fallback *types.CollectionFallback, outlierFilter *types.OutlierFilter, reputation *types.Reputation, aggregation *types.CollectionAggregation) (*big.Int, error) {
+	chain := defaultFallbackChain
+	var maxStalenessEpochs uint32
+	if fallback != nil {
+		maxStalenessEpochs = fallback.MaxStalenessEpochs
+		if len(fallback.Chain) != 0 {
+			chain = fallback.Chain
+		}
+	}
+
+	for _, step := range chain {
+		switch step {
+		case "secondary jobs":
+			if fallback == nil || len(fallback.SecondaryJobs) == 0 {
+				continue
+			}
+			jobsData, err := getJobsDataOfCollection(collection, previousEpoch+1, fallback.SecondaryJobs, outlierFilter, reputation)
+			if err != nil || len(jobsData) == 0 {
+				log.Errorf("Secondary jobs of collection %s have failed: %v", collection.Name, err)
+				continue
+			}
+			log.Warnf("Jobs of collection %s have failed, using its secondary jobs", collection.Name)
+			metrics.CollectionFallbacksMetric.WithLabelValues(collection.Name, step).Inc()
+			metrics.CollectionStaleEpochsMetric.WithLabelValues(collection.Name).Set(0)
+			return aggregateJobsData(collection, step, jobsData, aggregation)
+		case "previous value":
+			previousValue, err := UtilsInterface.FetchPreviousValue(client, previousEpoch, collection.Id)
+			if err != nil {
+				log.Errorf("Error in fetching previous value of collection %s: %s", collection.Name, err)
+				continue
+			}
+			lookback := core.StalenessLookbackEpochs
+			if maxStalenessEpochs != 0 {
+				lookback = maxStalenessEpochs + 1
+			}
+			staleEpochs := getPreviousValueStaleness(previousEpoch+1, collection.Id, lookback)
+			metrics.CollectionStaleEpochsMetric.WithLabelValues(collection.Name).Set(float64(staleEpochs))
+			if maxStalenessEpochs != 0 && staleEpochs > maxStalenessEpochs {
+				log.Errorf("Collection %s has had no fresh value for %d epochs, more than its max staleness of %d epochs, not using its previous value", collection.Name, staleEpochs, maxStalenessEpochs)
+				metrics.CollectionStalenessExceededMetric.WithLabelValues(collection.Name).Inc()
+				continue
+			}
+			log.Warnf("Jobs of collection %s have failed, using its value of epoch %d, stale for %d epochs", collection.Name, previousEpoch, staleEpochs)
+			metrics.CollectionFallbacksMetric.WithLabelValues(collection.Name, step).Inc()
+			aggregationReports.Set(collection.Id, types.AggregationReport{Source: step, Power: collection.Power})
+			UtilsInterface.WriteHistory([]types.HistoryRecord{{CollectionId: collection.Id, Epoch: previousEpoch + 1, Kind: "previous", Value: previousValue}})
+			return previousValue, nil
+		case "skip":
+			metrics.CollectionFallbacksMetric.WithLabelValues(collection.Name, step).Inc()
+			return nil, fmt.Errorf("skipping commit as jobs of collection %s have failed", collection.Name)
+		}
+	}
+	return nil, fmt.Errorf("no value for collection %s after its fallback chain %s", collection.Name, strings.Join(chain, ", "))
+}
+
+//This function returns for how many epochs in a row the previous value of the collection is used in the epoch, from the values kept in the history in the last lookback epochs
+func getPreviousValueStaleness(epoch uint32, collectionId uint16, lookback uint32) uint32 {
+	fromEpoch := uint32(0)
+	if epoch > lookback {
+		fromEpoch = epoch - lookback
+	}
+	// Only the epochs before are read, as the collection can be aggregated more than once in the epoch
+	records, err := history.Read(collectionId, fromEpoch, epoch-1)
+	if err != nil {
+		log.Errorf("Error in reading history of collection %d, its previous value is used as if it had a fresh value in the epoch before: %s", collectionId, err)
+		return 1
+	}
+	// The value of an epoch in which the previous value was used is kept as aggregated too, though it is not fresh
+	usedPrevious := make(map[uint32]bool)
+	for _, record := range records {
+		if record.Kind == "previous" {
+			usedPrevious[record.Epoch] = true
+		}
+	}
+	var staleSince uint32
+	for _, record := range records {
+		switch record.Kind {
+		case "aggregated":
+			if !usedPrevious[record.Epoch] {
+				staleSince = 0
+			}
+		case "previous":
+			if staleSince == 0 {
+				staleSince = record.Epoch
+			}
+		}
+	}
+	if staleSince == 0 {
+		return 1
+	}
+	return epoch - staleSince + 1
+}
@@ -0,0 +1,275 @@
+package utils
+
+import (
+	"errors"
+	"math/big"
+	"razor/core/types"
+	"razor/pkg/bindings"
+	"razor/utils/mocks"
+	"reflect"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestGetFallbackFromConfig(t *testing.T) {
+	jsonFileData := `{"assets": {"collection": {
+		"ethCollectionMean": {"fallback": {
+			"chain": ["Secondary Jobs", "previous value", "skip"],
+			"max staleness epochs": 3,
+			"secondary jobs": [
+				{"URL": "https://api.kraken.com/0/public/Ticker?pair=ETHUSD", "selector": "result.XETHZUSD.c[0]", "power": 2, "weight": 1},
+				{"URL": "https://api.gemini.com/v1/pubticker/ethusd", "selector type": "yaml", "selector": "last", "power": 2, "weight": 1}
+			]
+		}},
+		"btcCollectionMean": {"power": 2}
+	}}}`
+
+	tests := []struct {
+		name       string
+		collection string
+		want       *types.CollectionFallback
+	}{
+		{
+			name:       "Test 1: When collection has a fallback",
+			collection: "ethCollectionMean",
+			want: &types.CollectionFallback{
+				Chain:              []string{"secondary jobs", "previous value", "skip"},
+				MaxStalenessEpochs: 3,
+				SecondaryJobs: []types.AssetJob{
+					{StructsJob: bindings.StructsJob{Url: "https://api.kraken.com/0/public/Ticker?pair=ETHUSD", Selector: "result.XETHZUSD.c[0]", Power: 2, Weight: 1}},
+				},
+			},
+		},
+		{
+			name:       "Test 2: When collection has no fallback",
+			collection: "btcCollectionMean",
+			want:       nil,
+		},
+	}
+	config, err := DecodeAssetsConfig(jsonFileData)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := GetFallbackFromConfig(tt.collection, config.Assets.Collection[tt.collection].Fallback); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("GetFallbackFromConfig() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGetPreviousValueStaleness(t *testing.T) {
+	var epoch uint32 = 11
+	var collectionId uint16 = 4
+
+	tests := []struct {
+		name     string
+		records  []types.HistoryRecord
+		lookback uint32
+		want     uint32
+	}{
+		{
+			name:     "Test 1: When the collection has no history",
+			lookback: 10,
+			want:     1,
+		},
+		{
+			name: "Test 2: When the collection had a fresh value in the epoch before",
+			records: []types.HistoryRecord{
+				{CollectionId: collectionId, Epoch: 10, Kind: "aggregated", Value: big.NewInt(300)},
+			},
+			lookback: 10,
+			want:     1,
+		},
+		{
+			name: "Test 3: When the previous value has been used since a fresh value",
+			records: []types.HistoryRecord{
+				{CollectionId: collectionId, Epoch: 7, Kind: "aggregated", Value: big.NewInt(300)},
+				{CollectionId: collectionId, Epoch: 8, Kind: "aggregated", Value: big.NewInt(300)},
+				{CollectionId: collectionId, Epoch: 8, Kind: "previous", Value: big.NewInt(300)},
+				{CollectionId: collectionId, Epoch: 10, Kind: "aggregated", Value: big.NewInt(300)},
+				{CollectionId: collectionId, Epoch: 10, Kind: "previous", Value: big.NewInt(300)},
+			},
+			lookback: 10,
+			want:     4,
+		},
+		{
+			name: "Test 4: When a value which does not change is fresh in every epoch",
+			records: []types.HistoryRecord{
+				{CollectionId: collectionId, Epoch: 8, Kind: "aggregated", Value: big.NewInt(100)},
+				{CollectionId: collectionId, Epoch: 8, Kind: "median", Value: big.NewInt(100)},
+				{CollectionId: collectionId, Epoch: 9, Kind: "aggregated", Value: big.NewInt(100)},
+				{CollectionId: collectionId, Epoch: 9, Kind: "median", Value: big.NewInt(100)},
+				{CollectionId: collectionId, Epoch: 10, Kind: "aggregated", Value: big.NewInt(100)},
+				{CollectionId: collectionId, Epoch: 10, Kind: "median", Value: big.NewInt(100)},
+			},
+			lookback: 10,
+			want:     1,
+		},
+		{
+			name: "Test 5: When the previous value has been used for longer than the epochs looked back at",
+			records: []types.HistoryRecord{
+				{CollectionId: collectionId, Epoch: 5, Kind: "previous", Value: big.NewInt(300)},
+				{CollectionId: collectionId, Epoch: 9, Kind: "previous", Value: big.NewInt(300)},
+				{CollectionId: collectionId, Epoch: 10, Kind: "previous", Value: big.NewInt(300)},
+			},
+			lookback: 3,
+			want:     3,
+		},
+		{
+			name: "Test 6: When the previous value is already used in the epoch",
+			records: []types.HistoryRecord{
+				{CollectionId: collectionId, Epoch: 10, Kind: "aggregated", Value: big.NewInt(300)},
+				{CollectionId: collectionId, Epoch: 11, Kind: "previous", Value: big.NewInt(300)},
+			},
+			lookback: 10,
+			want:     1,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			history = &HistoryStore{}
+			if err := history.Open(t.TempDir()); err != nil {
+				t.Fatal(err)
+			}
+			defer func() {
+				history.Close()
+				history = &HistoryStore{}
+			}()
+			if err := history.Write(tt.records); err != nil {
+				t.Fatal(err)
+			}
+
+			if got := getPreviousValueStaleness(epoch, collectionId, tt.lookback); got != tt.want {
+				t.Errorf("getPreviousValueStaleness() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAggregateWithFallback(t *testing.T) {
+	var client *ethclient.Client
+	var previousEpoch uint32 = 10
+
+	collection := bindings.StructsCollection{Active: true, Id: 4, Power: 2, AggregationMethod: 2,
+		JobIDs: []uint16{1, 2}, Name: "ethCollectionMean",
+	}
+	secondaryJobs := []types.AssetJob{{StructsJob: bindings.StructsJob{Url: "https://api.kraken.com/0/public/Ticker?pair=ETHUSD", Selector: "result.XETHZUSD.c[0]", Power: 2, Weight: 1}}}
+
+	type args struct {
+		fallback         *types.CollectionFallback
+		staleEpochs      uint32
+		secondaryData    []*big.Int
+		secondaryDataErr error
+		previousValue    *big.Int
+		previousValueErr error
+	}
+	tests := []struct {
+		name    string
+		args    args
+		want    *big.Int
+		wantErr bool
+	}{
+		{
+			name: "Test 1: When there is no fallback the previous value is used",
+			args: args{
+				previousValue: big.NewInt(300),
+			},
+			want: big.NewInt(300),
+		},
+		{
+			name: "Test 2: When secondary jobs return a value",
+			args: args{
+				fallback:      &types.CollectionFallback{Chain: []string{"secondary jobs", "previous value"}, SecondaryJobs: secondaryJobs},
+				secondaryData: []*big.Int{big.NewInt(310)},
+				previousValue: big.NewInt(300),
+			},
+			want: big.NewInt(310),
+		},
+		{
+			name: "Test 3: When secondary jobs fail the previous value is used",
+			args: args{
+				fallback:         &types.CollectionFallback{Chain: []string{"secondary jobs", "previous value"}, SecondaryJobs: secondaryJobs},
+				secondaryDataErr: errors.New("secondary jobs error"),
+				previousValue:    big.NewInt(300),
+			},
+			want: big.NewInt(300),
+		},
+		{
+			name: "Test 4: When previous value is staler than max staleness",
+			args: args{
+				fallback:      &types.CollectionFallback{Chain: []string{"previous value"}, MaxStalenessEpochs: 2},
+				staleEpochs:   3,
+				previousValue: big.NewInt(300),
+			},
+			wantErr: true,
+		},
+		{
+			name: "Test 5: When previous value is within max staleness",
+			args: args{
+				fallback:      &types.CollectionFallback{Chain: []string{"previous value"}, MaxStalenessEpochs: 3},
+				staleEpochs:   3,
+				previousValue: big.NewInt(300),
+			},
+			want: big.NewInt(300),
+		},
+		{
+			name: "Test 6: When commit is skipped before the previous value",
+			args: args{
+				fallback:      &types.CollectionFallback{Chain: []string{"skip", "previous value"}},
+				previousValue: big.NewInt(300),
+			},
+			wantErr: true,
+		},
+		{
+			name: "Test 7: When previous value cannot be fetched",
+			args: args{
+				previousValueErr: errors.New("collection 4 has no confirmed value in epoch 10"),
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			utilsMock := new(mocks.Utils)
+
+			optionsPackageStruct := OptionsPackageStruct{
+				UtilsInterface: utilsMock,
+			}
+			StartRazor(optionsPackageStruct)
+			mockAggregationState(utilsMock)
+
+			utilsMock.On("GetDataToCommitFromJobs", mock.Anything).Return(getJobsData(tt.args.secondaryData, []uint8{1}), tt.args.secondaryDataErr)
+			utilsMock.On("FetchPreviousValue", mock.AnythingOfType("*ethclient.Client"), previousEpoch, collection.Id).Return(tt.args.previousValue, tt.args.previousValueErr)
+
+			history = &HistoryStore{}
+			if err := history.Open(t.TempDir()); err != nil {
+				t.Fatal(err)
+			}
+			defer func() {
+				history.Close()
+				history = &HistoryStore{}
+			}()
+			// The previous value was used in the epochs before, since the epoch after its last fresh value
+			var records []types.HistoryRecord
+			for epoch := previousEpoch + 2 - tt.args.staleEpochs; epoch <= previousEpoch && tt.args.staleEpochs > 1; epoch++ {
+				records = append(records, types.HistoryRecord{CollectionId: collection.Id, Epoch: epoch, Kind: "previous", Value: tt.args.previousValue})
+			}
+			if err := history.Write(records); err != nil {
+				t.Fatal(err)
+			}
+
+			got, err := aggregateWithFallback(client, previousEpoch, collection, tt.args.fallback, nil, nil, nil)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("aggregateWithFallback() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("aggregateWithFallback() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,103 @@
+//Package utils provides the utils functions
+package utils
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"razor/core/types"
+	"razor/metrics"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Layouts of timestamps which are not numbers, times without a zone are in UTC
+var timestampLayouts = []string{time.RFC3339Nano, time.RFC1123Z, time.RFC1123, "2006-01-02 15:04:05", "2006-01-02T15:04:05"}
+
+//This function returns the freshness of a job from its config in assets.json, a job without a timestamp selector has no freshness
+func GetJobFreshnessFromConfig(freshness *types.FreshnessConfig) *types.JobFreshness {
+	if freshness == nil || freshness.TimestampSelector == "" {
+		return nil
+	}
+	return &types.JobFreshness{
+		TimestampSelector: freshness.TimestampSelector,
+		MaxAge:            time.Duration(freshness.MaxAge * float64(time.Second)),
+	}
+}
+
+//This function returns an error if the timestamp selected from the response of job is older than its max age, so that a frozen source fails like any other job
+func CheckJobFreshness(job types.AssetJob, dataPoint interface{}) error {
+	if job.Freshness == nil {
+		return nil
+	}
+	timestamp, err := ParseTimestamp(dataPoint)
+	if err != nil {
+		return err
+	}
+	age := UtilsInterface.GetResponseTime(job).Sub(timestamp)
+	if age > job.Freshness.MaxAge {
+		metrics.StaleResponsesMetric.WithLabelValues(job.Name).Inc()
+		log.Errorf("Response of job %s is %s old, its max age is %s", getJobLogName(job), age.Round(time.Second), job.Freshness.MaxAge)
+		return fmt.Errorf("response is older than max age %s", job.Freshness.MaxAge)
+	}
+	return nil
+}
+
+//This function parses a timestamp which is either a unix time, whose unit of seconds, milliseconds, microseconds or nanoseconds is found from its size, or a date string
+func ParseTimestamp(value interface{}) (time.Time, error) {
+	var text string
+	switch v := value.(type) {
+	case json.Number:
+		text = v.String()
+	case string:
+		text = strings.TrimSpace(v)
+	case float64:
+		text = strconv.FormatFloat(v, 'f', -1, 64)
+	default:
+		return time.Time{}, fmt.Errorf("timestamp %v is not a number or a string", value)
+	}
+	if text == "" {
+		return time.Time{}, errors.New("timestamp is empty")
+	}
+
+	// Whole unix times are parsed as integers, so that nanoseconds are not rounded by a float
+	if unixTime, err := strconv.ParseInt(text, 10, 64); err == nil {
+		unit := getUnixTimeUnit(unixTime)
+		if unixTime <= 0 || unixTime > math.MaxInt64/int64(unit) {
+			return time.Time{}, fmt.Errorf("invalid timestamp %s", text)
+		}
+		return time.Unix(0, unixTime*int64(unit)), nil
+	}
+	if unixTime, err := strconv.ParseFloat(text, 64); err == nil {
+		if unixTime <= 0 || unixTime >= math.MaxInt64 || math.IsNaN(unixTime) {
+			return time.Time{}, fmt.Errorf("invalid timestamp %s", text)
+		}
+		nanoseconds := unixTime * float64(getUnixTimeUnit(int64(unixTime)))
+		if nanoseconds > math.MaxInt64 {
+			return time.Time{}, fmt.Errorf("invalid timestamp %s", text)
+		}
+		return time.Unix(0, int64(nanoseconds)), nil
+	}
+	for _, layout := range timestampLayouts {
+		if timestamp, err := time.Parse(layout, text); err == nil {
+			return timestamp, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("invalid timestamp %s", text)
+}
+
+//This function returns the unit of a unix time from its size
+func getUnixTimeUnit(unixTime int64) time.Duration {
+	// Seconds since 1970 stay below 1e11 until the year 5138
+	switch {
+	case unixTime >= 1e17:
+		return time.Nanosecond
+	case unixTime >= 1e14:
+		return time.Microsecond
+	case unixTime >= 1e11:
+		return time.Millisecond
+	}
+	return time.Second
+}
@@ -0,0 +1,223 @@
+package utils
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"razor/core/types"
+	"razor/pkg/bindings"
+	"razor/utils/mocks"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/avast/retry-go"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestParseTimestamp(t *testing.T) {
+	want := time.Date(2022, 3, 1, 10, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name    string
+		value   interface{}
+		want    time.Time
+		wantErr bool
+	}{
+		{
+			name:  "Test 1: When timestamp is in seconds",
+			value: json.Number("1646128800"),
+			want:  want,
+		},
+		{
+			name:  "Test 2: When timestamp is in milliseconds",
+			value: "1646128800000",
+			want:  want,
+		},
+		{
+			name:  "Test 3: When timestamp is in microseconds",
+			value: float64(1646128800000000),
+			want:  want,
+		},
+		{
+			name:  "Test 4: When timestamp is in nanoseconds",
+			value: "1646128800000000000",
+			want:  want,
+		},
+		{
+			name:  "Test 5: When timestamp is in seconds with a fraction",
+			value: json.Number("1646128800.5"),
+			want:  want.Add(500 * time.Millisecond),
+		},
+		{
+			name:  "Test 6: When timestamp is RFC3339",
+			value: "2022-03-01T11:00:00+01:00",
+			want:  want,
+		},
+		{
+			name:  "Test 7: When timestamp is RFC1123",
+			value: "Tue, 01 Mar 2022 10:00:00 GMT",
+			want:  want,
+		},
+		{
+			name:  "Test 8: When timestamp has no zone",
+			value: "2022-03-01 10:00:00",
+			want:  want,
+		},
+		{
+			name:    "Test 9: When timestamp is not a date",
+			value:   "yesterday",
+			wantErr: true,
+		},
+		{
+			name:    "Test 10: When timestamp is negative",
+			value:   "-1646128800",
+			wantErr: true,
+		},
+		{
+			name:    "Test 11: When timestamp is not a number or a string",
+			value:   true,
+			wantErr: true,
+		},
+		{
+			name:  "Test 12: When timestamp in nanoseconds is not rounded",
+			value: json.Number("1646128800123456789"),
+			want:  want.Add(123456789 * time.Nanosecond),
+		},
+		{
+			name:    "Test 13: When timestamp overflows in nanoseconds",
+			value:   "99999999999999999",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseTimestamp(tt.value)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ParseTimestamp() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !got.Equal(tt.want) {
+				t.Errorf("ParseTimestamp() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGetJobFreshnessFromConfig(t *testing.T) {
+	tests := []struct {
+		name      string
+		freshness *types.FreshnessConfig
+		want      *types.JobFreshness
+	}{
+		{
+			name:      "Test 1: When job has a freshness",
+			freshness: &types.FreshnessConfig{TimestampSelector: "volume.timestamp", MaxAge: 90.5},
+			want:      &types.JobFreshness{TimestampSelector: "volume.timestamp", MaxAge: 90500 * time.Millisecond},
+		},
+		{
+			name:      "Test 2: When job has no freshness",
+			freshness: nil,
+			want:      nil,
+		},
+		{
+			name:      "Test 3: When freshness of job has no timestamp selector",
+			freshness: &types.FreshnessConfig{MaxAge: 90.5},
+			want:      nil,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := GetJobFreshnessFromConfig(tt.freshness); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("GetJobFreshnessFromConfig() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGetDataToCommitFromJobWithFreshness(t *testing.T) {
+	job := types.AssetJob{
+		StructsJob: bindings.StructsJob{Id: 1, SelectorType: 5, Weight: 100, Power: 2, Name: "ethusd_gemini", Selector: "last",
+			Url: "https://api.gemini.com/v1/pubticker/ethusd",
+		},
+		Freshness: &types.JobFreshness{TimestampSelector: "volume.timestamp", MaxAge: time.Minute},
+	}
+	responseWithAge := func(age time.Duration) []byte {
+		return []byte(fmt.Sprintf(`{"last": "1234.5", "volume": {"timestamp": %d}}`, time.Now().Add(-age).UnixNano()/int64(time.Millisecond)))
+	}
+
+	tests := []struct {
+		name     string
+		job      types.AssetJob
+		response []byte
+		want     *big.Int
+		wantErr  bool
+	}{
+		{
+			name:     "Test 1: When response is younger than max age",
+			job:      job,
+			response: responseWithAge(10 * time.Second),
+			want:     big.NewInt(123450),
+		},
+		{
+			name:     "Test 2: When response is older than max age",
+			job:      job,
+			response: responseWithAge(10 * time.Minute),
+			wantErr:  true,
+		},
+		{
+			name:     "Test 3: When response has no timestamp",
+			job:      job,
+			response: []byte(`{"last": "1234.5"}`),
+			wantErr:  true,
+		},
+		{
+			name:     "Test 4: When timestamp is invalid",
+			job:      job,
+			response: []byte(`{"last": "1234.5", "volume": {"timestamp": "yesterday"}}`),
+			wantErr:  true,
+		},
+		{
+			name:     "Test 5: When job has no freshness an old response is used",
+			job:      types.AssetJob{StructsJob: job.StructsJob},
+			response: responseWithAge(10 * time.Minute),
+			want:     big.NewInt(123450),
+		},
+		{
+			name: "Test 6: When an input of a derived job is older than max age",
+			job: types.AssetJob{
+				StructsJob: bindings.StructsJob{Power: 2, Name: "ethusd_derived"},
+				Expression: "gemini",
+				Inputs:     map[string]types.AssetJob{"gemini": job},
+			},
+			response: responseWithAge(10 * time.Minute),
+			wantErr:  true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			retryMock := new(mocks.RetryUtils)
+			utilsMock := new(mocks.Utils)
+
+			optionsPackageStruct := OptionsPackageStruct{
+				RetryInterface: retryMock,
+				UtilsInterface: utilsMock,
+			}
+			utils := StartRazor(optionsPackageStruct)
+
+			utilsMock.On("GetDataFromAPI", mock.AnythingOfType("string"), mock.Anything).Return(tt.response, nil)
+			retryMock.On("RetryAttempts", mock.AnythingOfType("uint")).Return(retry.Attempts(1))
+			utilsMock.On("ConvertToNumber", mock.Anything).Return(big.NewRat(12345, 10), nil)
+			utilsMock.On("GetResponseTime", mock.Anything).Return(func(job types.AssetJob) time.Time { return time.Now() })
+
+			got, err := utils.GetDataToCommitFromJob(tt.job)
+			utilsMock.AssertNumberOfCalls(t, "GetDataFromAPI", 1)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("GetDataToCommitFromJob() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("GetDataToCommitFromJob() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,414 @@
+//Package utils provides the utils functions
+package utils
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"math/big"
+	"os"
+	"path/filepath"
+	"razor/core"
+	"razor/core/types"
+	"razor/path"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/syndtr/goleveldb/leveldb"
+	"github.com/syndtr/goleveldb/leveldb/opt"
+	"github.com/syndtr/goleveldb/leveldb/util"
+)
+
+//HistoryStore keeps the values of every collection by epoch in a leveldb database, which is kept open while voting
+type HistoryStore struct {
+	mutex           sync.Mutex
+	db              *leveldb.DB
+	mediansEpoch    uint32
+	attemptedEpoch  uint32
+	mediansAttempts int
+}
+
+// Values are only kept once the store is opened, e.g. while voting
+var history = &HistoryStore{}
+
+//This function returns the key of a value, keys sort by collection, epoch, kind and job
+func getHistoryKey(record types.HistoryRecord) []byte {
+	return []byte(fmt.Sprintf("%05d/%010d/%s/%s", record.CollectionId, record.Epoch, record.Kind, record.Job))
+}
+
+//This function returns the record stored with the key and value
+func parseHistoryRecord(key []byte, value []byte) (types.HistoryRecord, error) {
+	parts := strings.SplitN(string(key), "/", 4)
+	if len(parts) != 4 {
+		return types.HistoryRecord{}, fmt.Errorf("invalid history key %s", key)
+	}
+	collectionId, err := strconv.ParseUint(parts[0], 10, 16)
+	if err != nil {
+		return types.HistoryRecord{}, fmt.Errorf("invalid history key %s", key)
+	}
+	epoch, err := strconv.ParseUint(parts[1], 10, 32)
+	if err != nil {
+		return types.HistoryRecord{}, fmt.Errorf("invalid history key %s", key)
+	}
+	number, ok := new(big.Int).SetString(string(value), 10)
+	if !ok {
+		return types.HistoryRecord{}, fmt.Errorf("invalid value of history key %s", key)
+	}
+	return types.HistoryRecord{CollectionId: uint16(collectionId), Epoch: uint32(epoch), Kind: parts[2], Job: parts[3], Value: number}, nil
+}
+
+//This function keeps the values in the database in the directory from now on
+func (store *HistoryStore) Open(dir string) error {
+	db, err := leveldb.OpenFile(dir, nil)
+	if err != nil {
+		return err
+	}
+	store.mutex.Lock()
+	defer store.mutex.Unlock()
+	if store.db != nil {
+		store.db.Close()
+	}
+	store.db = db
+	return nil
+}
+
+//This function returns whether the values are kept
+func (store *HistoryStore) IsOpen() bool {
+	store.mutex.Lock()
+	defer store.mutex.Unlock()
+	return store.db != nil
+}
+
+//This function closes the database, values are not kept anymore
+func (store *HistoryStore) Close() error {
+	store.mutex.Lock()
+	defer store.mutex.Unlock()
+	if store.db == nil {
+		return nil
+	}
+	err := store.db.Close()
+	store.db = nil
+	return err
+}
+
+//This function writes the records to the database, nothing is written if it is not opened
+func (store *HistoryStore) Write(records []types.HistoryRecord) error {
+	if len(records) == 0 {
+		return nil
+	}
+	store.mutex.Lock()
+	defer store.mutex.Unlock()
+	if store.db == nil {
+		return nil
+	}
+	batch := new(leveldb.Batch)
+	for _, record := range records {
+		if record.Value == nil {
+			continue
+		}
+		batch.Put(getHistoryKey(record), []byte(record.Value.String()))
+	}
+	if err := store.db.Write(batch, nil); err != nil {
+		return fmt.Errorf("error in writing %d values to history: %s", batch.Len(), err)
+	}
+	return nil
+}
+
+//This function returns whether the confirmed medians of the epoch are to be fetched, they are fetched until kept at most core.MediansHistoryAttempts times per epoch
+func (store *HistoryStore) ShouldFetchMedians(epoch uint32) bool {
+	store.mutex.Lock()
+	defer store.mutex.Unlock()
+	if store.db == nil || store.mediansEpoch == epoch {
+		return false
+	}
+	if store.attemptedEpoch != epoch {
+		store.attemptedEpoch = epoch
+		store.mediansAttempts = 0
+	}
+	if store.mediansAttempts >= core.MediansHistoryAttempts {
+		return false
+	}
+	store.mediansAttempts++
+	return true
+}
+
+//This function writes the confirmed medians of the epoch, so that they are only fetched once per epoch
+func (store *HistoryStore) WriteMedians(epoch uint32, records []types.HistoryRecord) error {
+	if err := store.Write(records); err != nil {
+		return err
+	}
+	store.mutex.Lock()
+	store.mediansEpoch = epoch
+	store.mutex.Unlock()
+	return nil
+}
+
+//This function returns whether the history database is open, values are only kept while voting
+func (*UtilsStruct) IsHistoryOpen() bool {
+	return history.IsOpen()
+}
+
+//This function returns whether the confirmed medians of the epoch are to be fetched
+func (*UtilsStruct) ShouldFetchMediansHistory(epoch uint32) bool {
+	return history.ShouldFetchMedians(epoch)
+}
+
+//This function keeps the confirmed medians of the epoch in the history database
+func (*UtilsStruct) WriteMediansHistory(epoch uint32, records []types.HistoryRecord) error {
+	return history.WriteMedians(epoch, records)
+}
+
+//This function writes the records to the history database and logs them as dropped if they cannot be written
+func (*UtilsStruct) WriteHistory(records []types.HistoryRecord) {
+	// Values of a replayed epoch are not the ones of the epoch, they are only compared with them
+	if responseArchive.isReplaying() {
+		return
+	}
+	if err := history.Write(records); err != nil {
+		log.Error("Dropped values of history: ", err)
+	}
+}
+
+//The number of times the database is copied while the vote process keeps it open, if it changes while it is copied
+const historyCopyAttempts = 5
+
+var errHistoryChanged = errors.New("history database changed while it was copied")
+
+//This function opens the database in the directory read only, while the vote process keeps it locked a snapshot of it is copied and read
+func openHistoryForReading(dir string) (*leveldb.DB, func(), error) {
+	options := &opt.Options{ReadOnly: true, ErrorIfMissing: true}
+	db, err := leveldb.OpenFile(dir, options)
+	if err == nil {
+		return db, func() { db.Close() }, nil
+	}
+	if !isHistoryLockedErr(err) {
+		return nil, nil, err
+	}
+	var copyDir string
+	for attempt := 1; attempt <= historyCopyAttempts; attempt++ {
+		copyDir, err = copyHistoryDatabase(dir)
+		if !errors.Is(err, errHistoryChanged) {
+			break
+		}
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+	db, err = leveldb.OpenFile(copyDir, options)
+	if err != nil {
+		os.RemoveAll(copyDir)
+		return nil, nil, err
+	}
+	return db, func() {
+		db.Close()
+		os.RemoveAll(copyDir)
+	}, nil
+}
+
+//This function copies the files of the database to a temporary directory, leaving out its lock
+//The manifest is copied first and must not change until the tables and journals are copied, so every table it lists is copied
+//A journal being written while it is copied ends in a partial record, which is dropped when the copy is opened
+func copyHistoryDatabase(dir string) (string, error) {
+	manifest, manifestSize, err := getHistoryManifest(dir)
+	if err != nil {
+		return "", err
+	}
+	copyDir, err := os.MkdirTemp("", "razor-history-")
+	if err != nil {
+		return "", err
+	}
+	err = copyHistoryFile(dir, copyDir, manifest)
+	if err == nil {
+		err = os.WriteFile(filepath.Join(copyDir, "CURRENT"), []byte(manifest+"\n"), 0600)
+	}
+	if err != nil {
+		os.RemoveAll(copyDir)
+		return "", err
+	}
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		os.RemoveAll(copyDir)
+		return "", err
+	}
+	for _, entry := range entries {
+		if entry.IsDir() || entry.Name() == "LOCK" || entry.Name() == "CURRENT" || strings.HasPrefix(entry.Name(), "MANIFEST-") {
+			continue
+		}
+		err := copyHistoryFile(dir, copyDir, entry.Name())
+		if errors.Is(err, os.ErrNotExist) {
+			// Tables which are compacted away while copying are not listed in the copied manifest, else it changes
+			continue
+		}
+		if err != nil {
+			os.RemoveAll(copyDir)
+			return "", err
+		}
+	}
+	currentManifest, currentManifestSize, err := getHistoryManifest(dir)
+	if err == nil && (currentManifest != manifest || currentManifestSize != manifestSize) {
+		err = errHistoryChanged
+	}
+	if err != nil {
+		os.RemoveAll(copyDir)
+		return "", err
+	}
+	return copyDir, nil
+}
+
+//This function returns the name and size of the manifest of the database, which lists its tables
+func getHistoryManifest(dir string) (string, int64, error) {
+	current, err := os.ReadFile(filepath.Join(dir, "CURRENT"))
+	if err != nil {
+		return "", 0, err
+	}
+	manifest := strings.TrimSpace(string(current))
+	info, err := os.Stat(filepath.Join(dir, manifest))
+	if errors.Is(err, os.ErrNotExist) {
+		// The manifest is replaced by a new one after CURRENT is read
+		return "", 0, errHistoryChanged
+	}
+	if err != nil {
+		return "", 0, err
+	}
+	return manifest, info.Size(), nil
+}
+
+//This function copies a file of the database to the directory of its copy
+func copyHistoryFile(dir string, copyDir string, name string) error {
+	data, err := os.ReadFile(filepath.Join(dir, name))
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(filepath.Join(copyDir, name), data, 0600)
+}
+
+//This function returns the values of the collection from the from epoch to the to epoch, both included
+func ReadHistoryFromDir(dir string, collectionId uint16, fromEpoch uint32, toEpoch uint32) ([]types.HistoryRecord, error) {
+	db, closeDatabase, err := openHistoryForReading(dir)
+	if err != nil {
+		return nil, err
+	}
+	defer closeDatabase()
+	return readHistory(db, collectionId, fromEpoch, toEpoch)
+}
+
+//This function returns the values of the collection kept from the from epoch to the to epoch, none are kept while the database is not open
+func (store *HistoryStore) Read(collectionId uint16, fromEpoch uint32, toEpoch uint32) ([]types.HistoryRecord, error) {
+	store.mutex.Lock()
+	defer store.mutex.Unlock()
+	if store.db == nil {
+		return nil, nil
+	}
+	return readHistory(store.db, collectionId, fromEpoch, toEpoch)
+}
+
+//This function returns the values of the collection in the database from the from epoch to the to epoch, both included
+func readHistory(db *leveldb.DB, collectionId uint16, fromEpoch uint32, toEpoch uint32) ([]types.HistoryRecord, error) {
+	limit := []byte(fmt.Sprintf("%05d/", int(collectionId)+1))
+	if toEpoch < math.MaxUint32 {
+		limit = getHistoryKey(types.HistoryRecord{CollectionId: collectionId, Epoch: toEpoch + 1})
+	}
+	iterator := db.NewIterator(&util.Range{Start: getHistoryKey(types.HistoryRecord{CollectionId: collectionId, Epoch: fromEpoch}), Limit: limit}, nil)
+	defer iterator.Release()
+	var records []types.HistoryRecord
+	for iterator.Next() {
+		record, err := parseHistoryRecord(iterator.Key(), iterator.Value())
+		if err != nil {
+			return nil, err
+		}
+		records = append(records, record)
+	}
+	return records, iterator.Error()
+}
+
+//This function returns the directory of the history database
+func getHistoryPath() (string, error) {
+	razorPath, err := path.PathUtilsInterface.GetDefaultPath()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(razorPath, "history"), nil
+}
+
+//This function keeps the values fetched, committed and revealed while voting, and the confirmed medians, in the history database
+func OpenHistory() error {
+	dir, err := getHistoryPath()
+	if err != nil {
+		return err
+	}
+	return history.Open(dir)
+}
+
+//This function returns the values of the collection kept in the history database from the from epoch to the to epoch, both included
+func ReadHistory(collectionId uint16, fromEpoch uint32, toEpoch uint32) ([]types.HistoryRecord, error) {
+	dir, err := getHistoryPath()
+	if err != nil {
+		return nil, err
+	}
+	records, err := ReadHistoryFromDir(dir, collectionId, fromEpoch, toEpoch)
+	if errors.Is(err, leveldb.ErrNotFound) || (err != nil && strings.Contains(err.Error(), "does not exist")) {
+		return nil, errors.New("no history has been kept yet, it is kept while voting")
+	}
+	return records, err
+}
+
+//This function keeps the values of the jobs of the collection fetched in the epoch
+func recordJobValuesHistory(collectionId uint16, epoch uint32, jobsData []types.JobData) {
+	var records []types.HistoryRecord
+	for _, jobData := range jobsData {
+		records = append(records, types.HistoryRecord{CollectionId: collectionId, Epoch: epoch, Kind: "job", Job: getJobHealthKey(jobData.Job), Value: jobData.Value})
+	}
+	UtilsInterface.WriteHistory(records)
+}
+
+//This function keeps the leaves of the assigned collections, which are either committed or revealed in the epoch
+func RecordLeavesHistory(client *ethclient.Client, epoch uint32, kind string, commitData types.CommitData) {
+	if !UtilsInterface.IsHistoryOpen() {
+		return
+	}
+	var indexes []int
+	for index, assigned := range commitData.AssignedCollections {
+		if assigned && index < len(commitData.Leaves) {
+			indexes = append(indexes, index)
+		}
+	}
+	sort.Ints(indexes)
+	var records []types.HistoryRecord
+	for _, index := range indexes {
+		collectionId, err := UtilsInterface.GetCollectionIdFromIndex(client, uint16(index))
+		if err != nil {
+			log.Errorf("Error in getting collection of leaf %d to keep its %s value: %s", index, kind, err)
+			continue
+		}
+		records = append(records, types.HistoryRecord{CollectionId: collectionId, Epoch: epoch, Kind: kind, Value: commitData.Leaves[index]})
+	}
+	UtilsInterface.WriteHistory(records)
+}
+
+//This function keeps the confirmed medians of every collection in the epoch, they are fetched until kept a bounded number of times per epoch
+func RecordMediansHistory(client *ethclient.Client, epoch uint32) {
+	if !UtilsInterface.ShouldFetchMediansHistory(epoch) {
+		return
+	}
+	block, err := UtilsInterface.GetBlock(client, epoch)
+	if err != nil {
+		log.Errorf("Error in getting block of epoch %d to keep its confirmed medians: %s", epoch, err)
+		return
+	}
+	// Medians are in the order of the ids of the block, a block which is not confirmed yet has none
+	if len(block.Ids) == 0 || len(block.Medians) == 0 {
+		return
+	}
+	var records []types.HistoryRecord
+	for i, id := range block.Ids {
+		if i < len(block.Medians) {
+			records = append(records, types.HistoryRecord{CollectionId: id, Epoch: epoch, Kind: "median", Value: block.Medians[i]})
+		}
+	}
+	if err := UtilsInterface.WriteMediansHistory(epoch, records); err != nil {
+		log.Error("Dropped confirmed medians of history: ", err)
+	}
+}
@@ -0,0 +1,14 @@
+//go:build !windows
+// +build !windows
+
+package utils
+
+import (
+	"errors"
+	"syscall"
+)
+
+//This function returns whether the database could not be opened as another process keeps it locked, flock fails with EWOULDBLOCK then
+func isHistoryLockedErr(err error) bool {
+	return errors.Is(err, syscall.EWOULDBLOCK) || errors.Is(err, syscall.EAGAIN)
+}
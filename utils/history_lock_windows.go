@@ -0,0 +1,17 @@
+//go:build windows
+// +build windows
+
+package utils
+
+import (
+	"errors"
+	"syscall"
+)
+
+//ERROR_SHARING_VIOLATION, which CreateFile returns for the lock file while another process keeps it open
+const errorSharingViolation = syscall.Errno(32)
+
+//This function returns whether the database could not be opened as another process keeps it locked
+func isHistoryLockedErr(err error) bool {
+	return errors.Is(err, errorSharingViolation)
+}
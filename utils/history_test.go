@@ -0,0 +1,282 @@
+package utils
+
+import (
+	"errors"
+	"math"
+	"math/big"
+	"razor/core"
+	"razor/core/types"
+	"razor/pkg/bindings"
+	"razor/utils/mocks"
+	"reflect"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestHistoryStore(t *testing.T) {
+	dir := t.TempDir()
+	store := &HistoryStore{}
+	defer store.Close()
+	// Values are not kept until the store is opened
+	if err := store.Write([]types.HistoryRecord{{CollectionId: 1, Epoch: 9, Kind: "aggregated", Value: big.NewInt(1)}}); err != nil {
+		t.Fatal(err)
+	}
+	if err := store.Open(dir); err != nil {
+		t.Fatal(err)
+	}
+	err := store.Write([]types.HistoryRecord{
+		{CollectionId: 1, Epoch: 10, Kind: "job", Job: "#1", Value: big.NewInt(295050)},
+		{CollectionId: 1, Epoch: 10, Kind: "job", Job: "ethusd_gemini", Value: big.NewInt(295100)},
+		{CollectionId: 1, Epoch: 10, Kind: "aggregated", Value: big.NewInt(295075)},
+		{CollectionId: 2, Epoch: 10, Kind: "aggregated", Value: big.NewInt(4500000)},
+		{CollectionId: 1, Epoch: 11, Kind: "median", Value: big.NewInt(295080)},
+		{CollectionId: 1, Epoch: 12, Kind: "committed", Value: big.NewInt(296000)},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name         string
+		collectionId uint16
+		fromEpoch    uint32
+		toEpoch      uint32
+		want         []types.HistoryRecord
+	}{
+		{
+			name:         "Test 1: When every epoch of the collection is read",
+			collectionId: 1,
+			toEpoch:      4294967295,
+			want: []types.HistoryRecord{
+				{CollectionId: 1, Epoch: 10, Kind: "aggregated", Value: big.NewInt(295075)},
+				{CollectionId: 1, Epoch: 10, Kind: "job", Job: "#1", Value: big.NewInt(295050)},
+				{CollectionId: 1, Epoch: 10, Kind: "job", Job: "ethusd_gemini", Value: big.NewInt(295100)},
+				{CollectionId: 1, Epoch: 11, Kind: "median", Value: big.NewInt(295080)},
+				{CollectionId: 1, Epoch: 12, Kind: "committed", Value: big.NewInt(296000)},
+			},
+		},
+		{
+			name:         "Test 2: When a range of epochs is read",
+			collectionId: 1,
+			fromEpoch:    11,
+			toEpoch:      11,
+			want: []types.HistoryRecord{
+				{CollectionId: 1, Epoch: 11, Kind: "median", Value: big.NewInt(295080)},
+			},
+		},
+		{
+			name:         "Test 3: When collection has no values in the epochs",
+			collectionId: 3,
+			toEpoch:      4294967295,
+		},
+	}
+	// The database is read while the store keeps it open, as the history command does while voting
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ReadHistoryFromDir(dir, tt.collectionId, tt.fromEpoch, tt.toEpoch)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ReadHistoryFromDir() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestReadHistoryWhileWriting(t *testing.T) {
+	dir := t.TempDir()
+	store := &HistoryStore{}
+	defer store.Close()
+	if err := store.Open(dir); err != nil {
+		t.Fatal(err)
+	}
+	done := make(chan struct{})
+	written := make(chan error, 1)
+	go func() {
+		for epoch := uint32(1); epoch <= 1000; epoch++ {
+			select {
+			case <-done:
+				written <- nil
+				return
+			default:
+			}
+			var records []types.HistoryRecord
+			for job := 0; job < 100; job++ {
+				records = append(records, types.HistoryRecord{CollectionId: 1, Epoch: epoch, Kind: "job", Job: "#" + strconv.Itoa(job), Value: big.NewInt(int64(epoch))})
+			}
+			if err := store.Write(records); err != nil {
+				written <- err
+				return
+			}
+			time.Sleep(time.Millisecond)
+		}
+		<-done
+		written <- nil
+	}()
+
+	// Every read is a snapshot of the database, with all the values of an epoch or none of them as they are written together
+	for read := 0; read < 20; read++ {
+		records, err := ReadHistoryFromDir(dir, 1, 0, math.MaxUint32)
+		if err != nil {
+			t.Fatal(err)
+		}
+		jobs := make(map[uint32]int)
+		for _, record := range records {
+			jobs[record.Epoch]++
+		}
+		for epoch, count := range jobs {
+			if count != 100 {
+				t.Errorf("ReadHistoryFromDir() read %d values of epoch %d, want 100", count, epoch)
+			}
+		}
+	}
+	close(done)
+	if err := <-written; err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestRecordLeavesHistory(t *testing.T) {
+	var client *ethclient.Client
+	dir := t.TempDir()
+	defer func() {
+		history.Close()
+		history = &HistoryStore{}
+	}()
+	history = &HistoryStore{}
+	if err := history.Open(dir); err != nil {
+		t.Fatal(err)
+	}
+
+	utilsMock := new(mocks.Utils)
+	StartRazor(OptionsPackageStruct{UtilsInterface: utilsMock})
+	mockHistoryStore(utilsMock)
+	utilsMock.On("GetCollectionIdFromIndex", mock.AnythingOfType("*ethclient.Client"), uint16(0)).Return(uint16(0), errors.New("collection error"))
+	utilsMock.On("GetCollectionIdFromIndex", mock.AnythingOfType("*ethclient.Client"), uint16(2)).Return(uint16(3), nil)
+
+	RecordLeavesHistory(client, 10, "revealed", types.CommitData{
+		AssignedCollections: map[int]bool{0: true, 1: false, 2: true},
+		Leaves:              []*big.Int{big.NewInt(100), big.NewInt(200), big.NewInt(300)},
+	})
+	recordJobValuesHistory(3, 10, []types.JobData{{Job: types.AssetJob{StructsJob: bindings.StructsJob{Id: 4}}, Value: big.NewInt(301)}})
+
+	got, err := ReadHistoryFromDir(dir, 3, 0, 4294967295)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []types.HistoryRecord{
+		{CollectionId: 3, Epoch: 10, Kind: "job", Job: "#4", Value: big.NewInt(301)},
+		{CollectionId: 3, Epoch: 10, Kind: "revealed", Value: big.NewInt(300)},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("RecordLeavesHistory() kept %+v, want %+v", got, want)
+	}
+}
+
+func TestRecordMediansHistory(t *testing.T) {
+	var client *ethclient.Client
+	dir := t.TempDir()
+	defer func() {
+		history.Close()
+		history = &HistoryStore{}
+	}()
+	history = &HistoryStore{}
+	if err := history.Open(dir); err != nil {
+		t.Fatal(err)
+	}
+
+	utilsMock := new(mocks.Utils)
+	StartRazor(OptionsPackageStruct{UtilsInterface: utilsMock})
+	mockHistoryStore(utilsMock)
+	// Block of epoch 11 is not confirmed yet the first time it is fetched
+	utilsMock.On("GetBlock", mock.AnythingOfType("*ethclient.Client"), uint32(11)).Return(bindings.StructsBlock{}, nil).Once()
+	utilsMock.On("GetBlock", mock.AnythingOfType("*ethclient.Client"), uint32(11)).Return(bindings.StructsBlock{Ids: []uint16{1, 3}, Medians: []*big.Int{big.NewInt(295080), big.NewInt(4500000)}}, nil).Once()
+
+	RecordMediansHistory(client, 11)
+	RecordMediansHistory(client, 11)
+	// Medians of an epoch are only fetched until they are kept
+	RecordMediansHistory(client, 11)
+	utilsMock.AssertNumberOfCalls(t, "GetBlock", 2)
+
+	tests := []struct {
+		name         string
+		collectionId uint16
+		want         []types.HistoryRecord
+	}{
+		{
+			name:         "Test 1: When collection has a confirmed median",
+			collectionId: 3,
+			want:         []types.HistoryRecord{{CollectionId: 3, Epoch: 11, Kind: "median", Value: big.NewInt(4500000)}},
+		},
+		{
+			name:         "Test 2: When collection has no confirmed median",
+			collectionId: 2,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ReadHistoryFromDir(dir, tt.collectionId, 0, 4294967295)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("RecordMediansHistory() kept %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRecordMediansHistoryOfUnconfirmedBlock(t *testing.T) {
+	var client *ethclient.Client
+	dir := t.TempDir()
+	defer func() {
+		history.Close()
+		history = &HistoryStore{}
+	}()
+	history = &HistoryStore{}
+	if err := history.Open(dir); err != nil {
+		t.Fatal(err)
+	}
+
+	utilsMock := new(mocks.Utils)
+	StartRazor(OptionsPackageStruct{UtilsInterface: utilsMock})
+	mockHistoryStore(utilsMock)
+	// Block of epoch 11 is never confirmed, block of epoch 12 is
+	utilsMock.On("GetBlock", mock.AnythingOfType("*ethclient.Client"), uint32(11)).Return(bindings.StructsBlock{}, nil)
+	utilsMock.On("GetBlock", mock.AnythingOfType("*ethclient.Client"), uint32(12)).Return(bindings.StructsBlock{Ids: []uint16{3}, Medians: []*big.Int{big.NewInt(4500000)}}, nil)
+
+	// Medians are recorded on every block of the epoch
+	for i := 0; i < 3*core.MediansHistoryAttempts; i++ {
+		RecordMediansHistory(client, 11)
+	}
+	utilsMock.AssertNumberOfCalls(t, "GetBlock", core.MediansHistoryAttempts)
+
+	RecordMediansHistory(client, 12)
+	RecordMediansHistory(client, 12)
+	utilsMock.AssertNumberOfCalls(t, "GetBlock", core.MediansHistoryAttempts+1)
+
+	got, err := ReadHistoryFromDir(dir, 3, 0, 4294967295)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []types.HistoryRecord{{CollectionId: 3, Epoch: 12, Kind: "median", Value: big.NewInt(4500000)}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("RecordMediansHistory() kept %+v, want %+v", got, want)
+	}
+}
+
+//This function stubs the history methods of utilsMock with the ones of the open history store
+func mockHistoryStore(utilsMock *mocks.Utils) {
+	utils := &UtilsStruct{}
+	utilsMock.On("IsHistoryOpen").Return(utils.IsHistoryOpen)
+	utilsMock.On("ShouldFetchMediansHistory", mock.AnythingOfType("uint32")).Return(utils.ShouldFetchMediansHistory)
+	utilsMock.On("WriteMediansHistory", mock.AnythingOfType("uint32"), mock.Anything).Return(utils.WriteMediansHistory)
+	utilsMock.On("WriteHistory", mock.Anything).Run(func(args mock.Arguments) {
+		utils.WriteHistory(args.Get(0).([]types.HistoryRecord))
+	})
+}
@@ -85,7 +85,7 @@ type Utils interface {
 	GetOptions() bind.CallOpts
 	GetNumberOfProposedBlocks(client *ethclient.Client, epoch uint32) (uint8, error)
 	GetSortedProposedBlockId(client *ethclient.Client, epoch uint32, index *big.Int) (uint32, error)
-	FetchPreviousValue(client *ethclient.Client, epoch uint32, assetId uint16) (*big.Int, error)
+	FetchPreviousValue(client *ethclient.Client, epoch uint32, collectionId uint16) (*big.Int, error)
 	GetBlock(client *ethclient.Client, epoch uint32) (bindings.StructsBlock, error)
 	GetMaxAltBlocks(client *ethclient.Client) (uint8, error)
 	GetMinSafeRazor(client *ethclient.Client) (*big.Int, error)
@@ -120,21 +120,45 @@ type Utils interface {
 	GetCollection(client *ethclient.Client, collectionId uint16) (bindings.StructsCollection, error)
 	GetActiveCollection(client *ethclient.Client, collectionId uint16) (bindings.StructsCollection, error)
 	Aggregate(client *ethclient.Client, previousEpoch uint32, collection bindings.StructsCollection) (*big.Int, error)
-	GetDataToCommitFromJobs(jobs []bindings.StructsJob) ([]*big.Int, []uint8, error)
-	GetDataToCommitFromJob(job bindings.StructsJob) (*big.Int, error)
+	GetCollectionJobs(client *ethclient.Client, collection bindings.StructsCollection) ([]types.AssetJob, error)
+	ValidateAssetsFile(client *ethclient.Client) ([]error, error)
+	WatchAssetsFile(client *ethclient.Client) error
+	GetDataToCommitFromJobs(jobs []types.AssetJob) ([]types.JobData, error)
+	GetDataToCommitFromJob(job types.AssetJob) (*big.Int, error)
+	GetDatumFromJob(job types.AssetJob) (*big.Rat, error)
+	GetDataAndVolumeToCommitFromJob(job types.AssetJob) (*big.Int, *big.Rat, error)
 	GetAssignedCollections(client *ethclient.Client, numActiveCollections uint16, seed []byte) (map[int]bool, []*big.Int, error)
 	GetLeafIdOfACollection(client *ethclient.Client, collectionId uint16) (uint16, error)
 	GetCollectionIdFromIndex(client *ethclient.Client, medianIndex uint16) (uint16, error)
 	GetCollectionIdFromLeafId(client *ethclient.Client, leafId uint16) (uint16, error)
 	GetNumActiveCollections(client *ethclient.Client) (uint16, error)
 	GetAggregatedDataOfCollection(client *ethclient.Client, collectionId uint16, epoch uint32) (*big.Int, error)
+	SetAggregationEpoch(epoch uint32)
+	GetAggregationReport(collectionId uint16) (types.AggregationReport, bool)
+	GetFetchContext() context.Context
+	GetFetchAttemptTimeout(timeout time.Duration) (time.Duration, error)
+	GetAppliedAssets() (string, *types.AssetsConfig, bool)
+	RecordJobHealth(collectionName string, epoch uint32, jobs []types.AssetJob, jobsData []types.JobData)
+	ApplyJobReputation(collectionName string, jobsData []types.JobData, reputation types.Reputation) []types.JobData
+	SettleJobHealth(client *ethclient.Client, previousEpoch uint32, collection string, collectionId uint16)
+	IsReplaying() bool
+	GetReplayedChain() *types.RecordedChain
+	RecordChain(update func(chain *types.RecordedChain))
+	RecordCollectionState(collection bindings.StructsCollection)
+	GetResponseTime(job types.AssetJob) time.Time
+	IsHistoryOpen() bool
+	ShouldFetchMediansHistory(epoch uint32) bool
+	WriteMediansHistory(epoch uint32, records []types.HistoryRecord) error
+	WriteHistory(records []types.HistoryRecord)
+	CheckSanityBounds(client *ethclient.Client, collectionId uint16, epoch uint32, value *big.Int) (*big.Int, error)
 	GetJobs(client *ethclient.Client) ([]bindings.StructsJob, error)
 	GetAllCollections(client *ethclient.Client) ([]bindings.StructsCollection, error)
 	GetActiveCollectionIds(client *ethclient.Client) ([]uint16, error)
-	GetDataFromAPI(url string) ([]byte, error)
+	GetDataFromAPI(url string, request types.JobRequest) ([]byte, error)
 	GetDataFromJSON(jsonObject map[string]interface{}, selector string) (interface{}, error)
-	HandleOfficialJobsFromJSONFile(client *ethclient.Client, collection bindings.StructsCollection, dataString string) ([]bindings.StructsJob, []uint16)
-	GetDataFromXHTML(url string, selector string) (string, error)
+	HandleOfficialJobsFromJSONFile(client *ethclient.Client, collection bindings.StructsCollection, collectionConfig types.CollectionConfig) ([]types.AssetJob, []uint16)
+	GetDataFromXHTML(url string, selectors []string, request types.JobRequest) ([]string, error)
+	GetDataFromHTML(url string, selectors []string, request types.JobRequest) ([]string, error)
 	ConnectToClient(provider string) *ethclient.Client
 	FetchBalance(client *ethclient.Client, accountAddress string) (*big.Int, error)
 	GetDelayedState(client *ethclient.Client, buffer int32) (int64, error)
@@ -165,7 +189,7 @@ type Utils interface {
 	GetSaltFromBlockchain(client *ethclient.Client) ([32]byte, error)
 	GetStakerSRZRBalance(client *ethclient.Client, staker bindings.StructsStaker) (*big.Int, error)
 	GetRemainingTimeOfCurrentState(client *ethclient.Client, bufferPercent int32) (int64, error)
-	ConvertToNumber(num interface{}) (*big.Float, error)
+	ConvertToNumber(num interface{}) (*big.Rat, error)
 	SecondsToReadableTime(input int) string
 	AssignLogFile(flagSet *pflag.FlagSet)
 	CalculateBlockNumberAtEpochBeginning(client *ethclient.Client, epochLength int64, currentBlockNumber *big.Int) (*big.Int, error)
@@ -194,6 +218,8 @@ type OSUtils interface {
 	OpenFile(name string, flag int, perm fs.FileMode) (*os.File, error)
 	Open(name string) (*os.File, error)
 	WriteFile(name string, data []byte, perm fs.FileMode) error
+	CreateTemp(dir string, pattern string) (*os.File, error)
+	Rename(oldPath string, newPath string) error
 }
 
 type BufioUtils interface {
@@ -208,6 +234,7 @@ type MerkleTreeInterface interface {
 	CreateMerkle(values []*big.Int) [][][]byte
 	GetProofPath(tree [][][]byte, assetId uint16) [][32]byte
 	GetMerkleRoot(tree [][][]byte) [32]byte
+	VerifyProofPath(root [32]byte, value *big.Int, leafId uint16, leafCount int, proof [][32]byte) bool
 }
 type IoutilUtils interface {
 	ReadAll(body io.ReadCloser) ([]byte, error)
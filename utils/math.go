@@ -2,45 +2,79 @@
 package utils
 
 import (
+	"encoding/json"
 	"errors"
+	"fmt"
 	"math"
 	"math/big"
 	"math/rand"
+	"razor/core"
+	"razor/core/types"
+	"regexp"
 	"sort"
 	"strconv"
+	"strings"
 )
 
+var decimalRegex = regexp.MustCompile(`^[+-]?(\d+\.?\d*|\.\d+)([eE][+-]?\d{1,3})?$`)
+
 //This function converts interface to number
-func (*UtilsStruct) ConvertToNumber(num interface{}) (*big.Float, error) {
-	if num == nil {
-		return big.NewFloat(0), errors.New("no data provided")
-	}
+func (*UtilsStruct) ConvertToNumber(num interface{}) (*big.Rat, error) {
 	switch v := num.(type) {
+	case nil:
+		return nil, errors.New("no data provided")
+	case json.Number:
+		return ParseDecimal(string(v))
+	case string:
+		return ParseDecimal(v)
 	case int:
-		return big.NewFloat(float64(v)), nil
+		return new(big.Rat).SetInt64(int64(v)), nil
+	case int64:
+		return new(big.Rat).SetInt64(v), nil
+	case uint64:
+		return new(big.Rat).SetUint64(v), nil
 	case float64:
-		return big.NewFloat(v), nil
-	case string:
-		convertedNumber, err := strconv.ParseFloat(v, 64)
-		if err != nil {
-			log.Error("Error in converting from string to float: ", err)
-			return big.NewFloat(0), err
+		if math.IsNaN(v) || math.IsInf(v, 0) {
+			return nil, errors.New("cannot convert " + strconv.FormatFloat(v, 'f', -1, 64) + " to number")
 		}
-		return big.NewFloat(convertedNumber), nil
+		// Shortest representation of the float is used so that 0.1 is converted to exactly 1/10
+		return ParseDecimal(strconv.FormatFloat(v, 'f', -1, 64))
+	}
+	return nil, fmt.Errorf("cannot convert value of type %T to number", num)
+}
+
+//This function parses the decimal string exactly
+func ParseDecimal(value string) (*big.Rat, error) {
+	value = strings.TrimSpace(value)
+	if !decimalRegex.MatchString(value) {
+		log.Errorf("Error in converting %q to number", value)
+		return nil, errors.New("invalid decimal number " + strconv.Quote(value))
 	}
-	return big.NewFloat(0), nil
+	number, ok := new(big.Rat).SetString(value)
+	if !ok {
+		return nil, errors.New("invalid decimal number " + strconv.Quote(value))
+	}
+	return number, nil
 }
 
 //This function helps in multiplying with power
-func MultiplyWithPower(num *big.Float, power int8) *big.Int {
+func MultiplyWithPower(num *big.Rat, power int8) *big.Int {
 	if num == nil {
 		return big.NewInt(0)
 	}
-	decimalMultiplier := big.NewFloat(math.Pow(10, float64(power)))
-	value := big.NewFloat(1).Mul(num, decimalMultiplier)
-	result := new(big.Int)
-	value.Int(result)
-	return result
+	absolutePower := int64(power)
+	if absolutePower < 0 {
+		absolutePower = -absolutePower
+	}
+	decimalMultiplier := new(big.Int).Exp(big.NewInt(10), big.NewInt(absolutePower), nil)
+	value := new(big.Rat).Set(num)
+	if power >= 0 {
+		value.Mul(value, new(big.Rat).SetInt(decimalMultiplier))
+	} else {
+		value.Quo(value, new(big.Rat).SetInt(decimalMultiplier))
+	}
+	// Truncates towards zero
+	return new(big.Int).Quo(value.Num(), value.Denom())
 }
 
 //This function multiplies float and big int
@@ -103,6 +137,9 @@ func performAggregation(data []*big.Int, weight []uint8, aggregationMethod uint3
 		return nil, errors.New("aggregation cannot be performed for nil data")
 	}
 	totalWeight := CalculateSumOfUint8Array(weight)
+	if totalWeight == 0 {
+		return nil, errors.New("aggregation cannot be performed when total weight is 0")
+	}
 	// convention is 1 for median and 2 for mean
 	switch aggregationMethod {
 	case 1:
@@ -115,6 +152,187 @@ func performAggregation(data []*big.Int, weight []uint8, aggregationMethod uint3
 	return nil, errors.New("invalid aggregation method")
 }
 
+//This function performs the aggregation using the method set for the collection in assets.json
+func performLocalAggregation(jobsData []types.JobData, aggregation types.CollectionAggregation) (*big.Int, error) {
+	if len(jobsData) == 0 {
+		return nil, errors.New("aggregation cannot be performed for nil data")
+	}
+	data, weight := GetValuesAndWeightsFromJobsData(jobsData)
+	switch strings.ToLower(aggregation.Method) {
+	case "median":
+		return performAggregation(data, weight, 1)
+	case "mean":
+		return performAggregation(data, weight, 2)
+	case "trimmed mean":
+		return calculateTrimmedMean(data, weight, aggregation.TrimPercentage)
+	case "vwap":
+		return calculateVolumeWeightedAverage(jobsData)
+	case "min":
+		return calculateMin(data), nil
+	case "max":
+		return calculateMax(data), nil
+	case "mode":
+		return calculateWeightedMode(data, weight), nil
+	}
+	return nil, errors.New("invalid aggregation method " + aggregation.Method)
+}
+
+//This function calculates the weighted mean after removing trimPercentage of the values from both ends
+func calculateTrimmedMean(data []*big.Int, weight []uint8, trimPercentage float64) (*big.Int, error) {
+	if trimPercentage < 0 || trimPercentage >= 50 {
+		return nil, errors.New("trim percentage should be at least 0 and less than 50")
+	}
+	indices := make([]int, len(data))
+	for i := range indices {
+		indices[i] = i
+	}
+	sort.SliceStable(indices, func(i, j int) bool {
+		return data[indices[i]].Cmp(data[indices[j]]) < 0
+	})
+	trimCount := int(float64(len(data)) * trimPercentage / 100)
+	var (
+		trimmedData   []*big.Int
+		trimmedWeight []uint8
+	)
+	for _, index := range indices[trimCount : len(indices)-trimCount] {
+		trimmedData = append(trimmedData, data[index])
+		trimmedWeight = append(trimmedWeight, weight[index])
+	}
+	return performAggregation(trimmedData, trimmedWeight, 2)
+}
+
+//This function calculates the volume weighted average of the jobs having volume
+func calculateVolumeWeightedAverage(jobsData []types.JobData) (*big.Int, error) {
+	weightedSum := new(big.Rat)
+	totalVolume := new(big.Rat)
+	for _, jobData := range jobsData {
+		if jobData.Volume == nil || jobData.Volume.Sign() <= 0 {
+			log.Warnf("Ignoring job %s in volume weighted average as it has no volume", getJobLogName(jobData.Job))
+			continue
+		}
+		value := new(big.Rat).SetInt(jobData.Value)
+		weightedSum.Add(weightedSum, value.Mul(value, jobData.Volume))
+		totalVolume.Add(totalVolume, jobData.Volume)
+	}
+	if totalVolume.Sign() == 0 {
+		return nil, errors.New("volume weighted average cannot be performed without volumes")
+	}
+	weightedSum.Quo(weightedSum, totalVolume)
+	return new(big.Int).Quo(weightedSum.Num(), weightedSum.Denom()), nil
+}
+
+//This function returns the minimum value of data
+func calculateMin(data []*big.Int) *big.Int {
+	min := data[0]
+	for _, value := range data[1:] {
+		if value.Cmp(min) < 0 {
+			min = value
+		}
+	}
+	return min
+}
+
+//This function returns the maximum value of data
+func calculateMax(data []*big.Int) *big.Int {
+	max := data[0]
+	for _, value := range data[1:] {
+		if value.Cmp(max) > 0 {
+			max = value
+		}
+	}
+	return max
+}
+
+//This function returns the value having the highest total weight, the smallest such value in case of a tie
+func calculateWeightedMode(data []*big.Int, weight []uint8) *big.Int {
+	weights := make(map[string]uint)
+	var mode *big.Int
+	for i, value := range data {
+		key := value.String()
+		weights[key] += uint(weight[i])
+		if mode == nil || weights[key] > weights[mode.String()] || (weights[key] == weights[mode.String()] && value.Cmp(mode) < 0) {
+			mode = value
+		}
+	}
+	return mode
+}
+
+//This function removes the values deviating too much from the median of data as per the outlier filter of the collection
+func FilterOutliers(collectionName string, jobsData []types.JobData, filter types.OutlierFilter) ([]types.JobData, error) {
+	if len(jobsData) == 0 {
+		return jobsData, nil
+	}
+	values := make([]*big.Float, len(jobsData))
+	for i := range jobsData {
+		values[i] = new(big.Float).SetInt(jobsData[i].Value)
+	}
+	median := calculateMedian(values)
+	deviations := make([]*big.Float, len(values))
+	for i := range values {
+		deviations[i] = new(big.Float).Abs(new(big.Float).Sub(values[i], median))
+	}
+
+	// maxDeviation is the deviation from the median above which a value is rejected
+	var maxDeviation *big.Float
+	threshold := filter.Threshold
+	switch strings.ToLower(filter.Method) {
+	case "mad":
+		if threshold <= 0 {
+			threshold = core.DefaultMADThreshold
+		}
+		mad := calculateMedian(deviations)
+		if mad.Sign() == 0 {
+			// Most values equal the median, so values are rejected by the default percentage rather than for any difference from it
+			maxDeviation = new(big.Float).Abs(median)
+			maxDeviation.Mul(maxDeviation, big.NewFloat(core.DefaultDeviationPercentage/100))
+			break
+		}
+		maxDeviation = new(big.Float).Mul(mad, big.NewFloat(threshold))
+	case "percentage":
+		if threshold <= 0 {
+			threshold = core.DefaultDeviationPercentage
+		}
+		maxDeviation = new(big.Float).Abs(median)
+		maxDeviation.Mul(maxDeviation, big.NewFloat(threshold/100))
+	default:
+		log.Errorf("Invalid outlier filter method %s for collection %s, skipping outlier rejection", filter.Method, collectionName)
+		return jobsData, nil
+	}
+	if median.Sign() == 0 && maxDeviation.Sign() == 0 {
+		// A percentage of a median of 0 is 0, which would reject every value which is not 0
+		log.Warnf("Median of collection %s is 0, skipping outlier rejection", collectionName)
+		return jobsData, nil
+	}
+
+	var filteredJobsData []types.JobData
+	for i := range jobsData {
+		if deviations[i].Cmp(maxDeviation) > 0 {
+			log.Warnf("Rejecting value %s of job %s with weight %d for collection %s as it deviates by %s from the median %s", jobsData[i].Value, getJobLogName(jobsData[i].Job), jobsData[i].Job.Weight, collectionName, deviations[i].Text('f', 2), median.Text('f', 2))
+			continue
+		}
+		filteredJobsData = append(filteredJobsData, jobsData[i])
+	}
+	if len(filteredJobsData) < filter.MinQuorum {
+		return nil, errors.New("not enough jobs left for collection " + collectionName + " after outlier rejection, need " + strconv.Itoa(filter.MinQuorum) + " but have " + strconv.Itoa(len(filteredJobsData)))
+	}
+	return filteredJobsData, nil
+}
+
+//This function calculates the unweighted median of values
+func calculateMedian(values []*big.Float) *big.Float {
+	sorted := make([]*big.Float, len(values))
+	copy(sorted, values)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].Cmp(sorted[j]) < 0
+	})
+	middle := len(sorted) / 2
+	if len(sorted)%2 == 1 {
+		return sorted[middle]
+	}
+	median := new(big.Float).Add(sorted[middle-1], sorted[middle])
+	return median.Quo(median, big.NewFloat(2))
+}
+
 //This function calculates the weighted median
 func calculateWeightedMedian(data []*big.Int, weight []uint8, totalWeight uint) *big.Int {
 	if len(data) == 0 || len(weight) == 0 || totalWeight == 0 {
@@ -1,8 +1,12 @@
 package utils
 
 import (
+	"encoding/json"
 	"errors"
+	"math"
 	"math/big"
+	"razor/core/types"
+	"razor/pkg/bindings"
 	"razor/utils/mocks"
 	"reflect"
 	"testing"
@@ -51,13 +55,15 @@ func TestAllZero(t *testing.T) {
 }
 
 func TestConvertToNumber(t *testing.T) {
+	largePrice, _ := new(big.Rat).SetString("123456789012345678123456789/1000000000")
+
 	type args struct {
 		num interface{}
 	}
 	tests := []struct {
 		name    string
 		args    args
-		want    *big.Float
+		want    *big.Rat
 		wantErr bool
 	}{
 		{
@@ -65,7 +71,7 @@ func TestConvertToNumber(t *testing.T) {
 			args: args{
 				num: 4,
 			},
-			want:    big.NewFloat(4),
+			want:    big.NewRat(4, 1),
 			wantErr: false,
 		},
 		{
@@ -73,7 +79,7 @@ func TestConvertToNumber(t *testing.T) {
 			args: args{
 				num: 0.4,
 			},
-			want:    big.NewFloat(0.4),
+			want:    big.NewRat(2, 5),
 			wantErr: false,
 		},
 		{
@@ -81,7 +87,31 @@ func TestConvertToNumber(t *testing.T) {
 			args: args{
 				num: "4",
 			},
-			want:    big.NewFloat(4),
+			want:    big.NewRat(4, 1),
+			wantErr: false,
+		},
+		{
+			name: "Test string with more digits than float64 can hold",
+			args: args{
+				num: "123456789012345678.123456789",
+			},
+			want:    largePrice,
+			wantErr: false,
+		},
+		{
+			name: "Test string with exponent and spaces",
+			args: args{
+				num: " 1.5e3 ",
+			},
+			want:    big.NewRat(1500, 1),
+			wantErr: false,
+		},
+		{
+			name: "Test json number",
+			args: args{
+				num: json.Number("0.000000000000000001"),
+			},
+			want:    big.NewRat(1, 1000000000000000000),
 			wantErr: false,
 		},
 		{
@@ -89,7 +119,7 @@ func TestConvertToNumber(t *testing.T) {
 			args: args{
 				num: nil,
 			},
-			want:    big.NewFloat(0),
+			want:    nil,
 			wantErr: true,
 		},
 		{
@@ -97,7 +127,47 @@ func TestConvertToNumber(t *testing.T) {
 			args: args{
 				num: "4w",
 			},
-			want:    big.NewFloat(0),
+			want:    nil,
+			wantErr: true,
+		},
+		{
+			name: "Test fraction string",
+			args: args{
+				num: "1/3",
+			},
+			want:    nil,
+			wantErr: true,
+		},
+		{
+			name: "Test empty string",
+			args: args{
+				num: "",
+			},
+			want:    nil,
+			wantErr: true,
+		},
+		{
+			name: "Test NaN",
+			args: args{
+				num: math.NaN(),
+			},
+			want:    nil,
+			wantErr: true,
+		},
+		{
+			name: "Test bool",
+			args: args{
+				num: true,
+			},
+			want:    nil,
+			wantErr: true,
+		},
+		{
+			name: "Test nested object",
+			args: args{
+				num: map[string]interface{}{"price": "4"},
+			},
+			want:    nil,
 			wantErr: true,
 		},
 		{
@@ -105,8 +175,8 @@ func TestConvertToNumber(t *testing.T) {
 			args: args{
 				num: big.NewInt(4),
 			},
-			want:    big.NewFloat(0),
-			wantErr: false,
+			want:    nil,
+			wantErr: true,
 		},
 	}
 	for _, tt := range tests {
@@ -122,7 +192,7 @@ func TestConvertToNumber(t *testing.T) {
 				t.Errorf("ConvertToNumber() error = %v, wantErr %v", err, tt.wantErr)
 				return
 			}
-			if !reflect.DeepEqual(got, tt.want) {
+			if (got == nil) != (tt.want == nil) || (got != nil && got.Cmp(tt.want) != 0) {
 				t.Errorf("ConvertToNumber() got = %v, want %v", got, tt.want)
 			}
 		})
@@ -446,8 +516,11 @@ func TestGetAmountInDecimal(t *testing.T) {
 }
 
 func TestMultiplyWithPower(t *testing.T) {
+	largePrice, _ := new(big.Rat).SetString("123456789012345678.123456789")
+	wantLargePrice, _ := new(big.Int).SetString("123456789012345678123456789000000000", 10)
+
 	type args struct {
-		num   *big.Float
+		num   *big.Rat
 		power int8
 	}
 	tests := []struct {
@@ -458,15 +531,47 @@ func TestMultiplyWithPower(t *testing.T) {
 		{
 			name: "Test value when power is 8",
 			args: args{
-				num:   big.NewFloat(1.22342),
+				num:   big.NewRat(122342, 100000),
 				power: 8,
 			},
 			want: big.NewInt(122342000),
 		},
+		{
+			name: "Test value which is not exact as float when power is 2",
+			args: args{
+				num:   big.NewRat(29, 100),
+				power: 2,
+			},
+			want: big.NewInt(29),
+		},
+		{
+			name: "Test large value when power is 18",
+			args: args{
+				num:   largePrice,
+				power: 18,
+			},
+			want: wantLargePrice,
+		},
+		{
+			name: "Test value when power is negative",
+			args: args{
+				num:   big.NewRat(123456, 1),
+				power: -3,
+			},
+			want: big.NewInt(123),
+		},
+		{
+			name: "Test negative value is truncated towards zero",
+			args: args{
+				num:   big.NewRat(-15, 10),
+				power: 0,
+			},
+			want: big.NewInt(-1),
+		},
 		{
 			name: "Test value when number is 0",
 			args: args{
-				num:   big.NewFloat(0),
+				num:   big.NewRat(0, 1),
 				power: 0,
 			},
 			want: big.NewInt(0),
@@ -640,6 +745,26 @@ func Test_performAggregation(t *testing.T) {
 			want:    nil,
 			wantErr: true,
 		},
+		{
+			name: "Test Median when all weights are 0",
+			args: args{
+				data:              []*big.Int{big.NewInt(500), big.NewInt(1000)},
+				aggregationMethod: 1,
+				weight:            []uint8{0, 0},
+			},
+			want:    nil,
+			wantErr: true,
+		},
+		{
+			name: "Test Mean when all weights are 0",
+			args: args{
+				data:              []*big.Int{big.NewInt(500), big.NewInt(1000)},
+				aggregationMethod: 2,
+				weight:            []uint8{0, 0},
+			},
+			want:    nil,
+			wantErr: true,
+		},
 		{
 			name: "Test incorrect input for AggregationMethod",
 			args: args{
@@ -665,6 +790,300 @@ func Test_performAggregation(t *testing.T) {
 	}
 }
 
+func Test_performLocalAggregation(t *testing.T) {
+	data := []*big.Int{big.NewInt(100), big.NewInt(98), big.NewInt(1000), big.NewInt(102), big.NewInt(101)}
+	weight := []uint8{1, 1, 1, 1, 1}
+	volumeJobsData := []types.JobData{
+		{Value: big.NewInt(100), Volume: big.NewRat(3, 1), Job: types.AssetJob{StructsJob: bindings.StructsJob{Weight: 1}}},
+		{Value: big.NewInt(200), Volume: big.NewRat(1, 1), Job: types.AssetJob{StructsJob: bindings.StructsJob{Weight: 1}}},
+		{Value: big.NewInt(5000), Job: types.AssetJob{StructsJob: bindings.StructsJob{Weight: 1}}},
+	}
+
+	type args struct {
+		jobsData    []types.JobData
+		aggregation types.CollectionAggregation
+	}
+	tests := []struct {
+		name    string
+		args    args
+		want    *big.Int
+		wantErr bool
+	}{
+		{
+			name: "Test 1: When aggregation method is median",
+			args: args{
+				jobsData:    getJobsData(data, weight),
+				aggregation: types.CollectionAggregation{Method: "median"},
+			},
+			want:    big.NewInt(101),
+			wantErr: false,
+		},
+		{
+			name: "Test 2: When aggregation method is mean",
+			args: args{
+				jobsData:    getJobsData(data, weight),
+				aggregation: types.CollectionAggregation{Method: "Mean"},
+			},
+			want:    big.NewInt(280),
+			wantErr: false,
+		},
+		{
+			name: "Test 3: When aggregation method is trimmed mean",
+			args: args{
+				jobsData:    getJobsData(data, weight),
+				aggregation: types.CollectionAggregation{Method: "trimmed mean", TrimPercentage: 20},
+			},
+			want:    big.NewInt(101),
+			wantErr: false,
+		},
+		{
+			name: "Test 4: When trim percentage is invalid",
+			args: args{
+				jobsData:    getJobsData(data, weight),
+				aggregation: types.CollectionAggregation{Method: "trimmed mean", TrimPercentage: 50},
+			},
+			want:    nil,
+			wantErr: true,
+		},
+		{
+			name: "Test 5: When aggregation method is vwap",
+			args: args{
+				jobsData:    volumeJobsData,
+				aggregation: types.CollectionAggregation{Method: "VWAP"},
+			},
+			want:    big.NewInt(125),
+			wantErr: false,
+		},
+		{
+			name: "Test 6: When aggregation method is vwap and no job has volume",
+			args: args{
+				jobsData:    getJobsData(data, weight),
+				aggregation: types.CollectionAggregation{Method: "vwap"},
+			},
+			want:    nil,
+			wantErr: true,
+		},
+		{
+			name: "Test 7: When aggregation method is min",
+			args: args{
+				jobsData:    getJobsData(data, weight),
+				aggregation: types.CollectionAggregation{Method: "min"},
+			},
+			want:    big.NewInt(98),
+			wantErr: false,
+		},
+		{
+			name: "Test 8: When aggregation method is max",
+			args: args{
+				jobsData:    getJobsData(data, weight),
+				aggregation: types.CollectionAggregation{Method: "max"},
+			},
+			want:    big.NewInt(1000),
+			wantErr: false,
+		},
+		{
+			name: "Test 9: When aggregation method is mode",
+			args: args{
+				jobsData:    getJobsData([]*big.Int{big.NewInt(101), big.NewInt(100), big.NewInt(101), big.NewInt(99)}, []uint8{1, 3, 1, 1}),
+				aggregation: types.CollectionAggregation{Method: "mode"},
+			},
+			want:    big.NewInt(100),
+			wantErr: false,
+		},
+		{
+			name: "Test 10: When aggregation method is mode and values are tied",
+			args: args{
+				jobsData:    getJobsData([]*big.Int{big.NewInt(101), big.NewInt(100), big.NewInt(101), big.NewInt(100)}, []uint8{1, 1, 1, 1}),
+				aggregation: types.CollectionAggregation{Method: "mode"},
+			},
+			want:    big.NewInt(100),
+			wantErr: false,
+		},
+		{
+			name: "Test 11: When aggregation method is invalid",
+			args: args{
+				jobsData:    getJobsData(data, weight),
+				aggregation: types.CollectionAggregation{Method: "geometric mean"},
+			},
+			want:    nil,
+			wantErr: true,
+		},
+		{
+			name: "Test 12: When there is no data",
+			args: args{
+				aggregation: types.CollectionAggregation{Method: "min"},
+			},
+			want:    nil,
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := performLocalAggregation(tt.args.jobsData, tt.args.aggregation)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("performLocalAggregation() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("performLocalAggregation() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFilterOutliers(t *testing.T) {
+	type args struct {
+		data   []*big.Int
+		weight []uint8
+		filter types.OutlierFilter
+	}
+	tests := []struct {
+		name       string
+		args       args
+		want       []*big.Int
+		wantWeight []uint8
+		wantErr    bool
+	}{
+		{
+			name: "Test 1: When MAD filter rejects a glitched value",
+			args: args{
+				data:   []*big.Int{big.NewInt(100), big.NewInt(102), big.NewInt(98), big.NewInt(101), big.NewInt(1000)},
+				weight: []uint8{1, 2, 3, 4, 5},
+				filter: types.OutlierFilter{Method: "MAD", Threshold: 3},
+			},
+			want:       []*big.Int{big.NewInt(100), big.NewInt(102), big.NewInt(98), big.NewInt(101)},
+			wantWeight: []uint8{1, 2, 3, 4},
+			wantErr:    false,
+		},
+		{
+			name: "Test 2: When MAD filter uses default threshold",
+			args: args{
+				data:   []*big.Int{big.NewInt(100), big.NewInt(104), big.NewInt(96), big.NewInt(112)},
+				weight: []uint8{1, 1, 1, 1},
+				filter: types.OutlierFilter{Method: "mad"},
+			},
+			want:       []*big.Int{big.NewInt(100), big.NewInt(104), big.NewInt(96), big.NewInt(112)},
+			wantWeight: []uint8{1, 1, 1, 1},
+			wantErr:    false,
+		},
+		{
+			name: "Test 3: When percentage filter rejects values deviating from median",
+			args: args{
+				data:   []*big.Int{big.NewInt(100), big.NewInt(102), big.NewInt(98), big.NewInt(110)},
+				weight: []uint8{1, 1, 1, 1},
+				filter: types.OutlierFilter{Method: "percentage", Threshold: 5},
+			},
+			want:       []*big.Int{big.NewInt(100), big.NewInt(102), big.NewInt(98)},
+			wantWeight: []uint8{1, 1, 1},
+			wantErr:    false,
+		},
+		{
+			name: "Test 4: When values left after rejection are less than min quorum",
+			args: args{
+				data:   []*big.Int{big.NewInt(100), big.NewInt(102), big.NewInt(98), big.NewInt(110)},
+				weight: []uint8{1, 1, 1, 1},
+				filter: types.OutlierFilter{Method: "percentage", Threshold: 1, MinQuorum: 3},
+			},
+			want:       nil,
+			wantWeight: nil,
+			wantErr:    true,
+		},
+		{
+			name: "Test 5: When values left after rejection satisfy min quorum",
+			args: args{
+				data:   []*big.Int{big.NewInt(100), big.NewInt(102), big.NewInt(98), big.NewInt(110)},
+				weight: []uint8{1, 1, 1, 1},
+				filter: types.OutlierFilter{Method: "percentage", Threshold: 5, MinQuorum: 3},
+			},
+			want:       []*big.Int{big.NewInt(100), big.NewInt(102), big.NewInt(98)},
+			wantWeight: []uint8{1, 1, 1},
+			wantErr:    false,
+		},
+		{
+			name: "Test 6: When filter method is invalid",
+			args: args{
+				data:   []*big.Int{big.NewInt(100), big.NewInt(1000)},
+				weight: []uint8{1, 1},
+				filter: types.OutlierFilter{Method: "zscore", Threshold: 1},
+			},
+			want:       []*big.Int{big.NewInt(100), big.NewInt(1000)},
+			wantWeight: []uint8{1, 1},
+			wantErr:    false,
+		},
+		{
+			name: "Test 7: When there is no data",
+			args: args{
+				data:   []*big.Int{},
+				weight: []uint8{},
+				filter: types.OutlierFilter{Method: "MAD", MinQuorum: 1},
+			},
+			want:       nil,
+			wantWeight: nil,
+			wantErr:    false,
+		},
+		{
+			name: "Test 8: When MAD is 0 values within the default percentage are kept",
+			args: args{
+				data:   []*big.Int{big.NewInt(100), big.NewInt(100), big.NewInt(100), big.NewInt(101), big.NewInt(150)},
+				weight: []uint8{1, 1, 1, 1, 1},
+				filter: types.OutlierFilter{Method: "MAD"},
+			},
+			want:       []*big.Int{big.NewInt(100), big.NewInt(100), big.NewInt(100), big.NewInt(101)},
+			wantWeight: []uint8{1, 1, 1, 1},
+			wantErr:    false,
+		},
+		{
+			name: "Test 9: When the median and MAD are 0 no value is rejected",
+			args: args{
+				data:   []*big.Int{big.NewInt(0), big.NewInt(0), big.NewInt(0), big.NewInt(1), big.NewInt(2)},
+				weight: []uint8{1, 1, 1, 1, 1},
+				filter: types.OutlierFilter{Method: "MAD", MinQuorum: 5},
+			},
+			want:       []*big.Int{big.NewInt(0), big.NewInt(0), big.NewInt(0), big.NewInt(1), big.NewInt(2)},
+			wantWeight: []uint8{1, 1, 1, 1, 1},
+			wantErr:    false,
+		},
+		{
+			name: "Test 10: When the median is 0 percentage filter rejects no value",
+			args: args{
+				data:   []*big.Int{big.NewInt(-1), big.NewInt(0), big.NewInt(1)},
+				weight: []uint8{1, 1, 1},
+				filter: types.OutlierFilter{Method: "percentage", Threshold: 5},
+			},
+			want:       []*big.Int{big.NewInt(-1), big.NewInt(0), big.NewInt(1)},
+			wantWeight: []uint8{1, 1, 1},
+			wantErr:    false,
+		},
+		{
+			name: "Test 11: When the median is 0 and MAD is not 0 MAD filter still rejects a glitched value",
+			args: args{
+				data:   []*big.Int{big.NewInt(-2), big.NewInt(-1), big.NewInt(0), big.NewInt(1), big.NewInt(1000)},
+				weight: []uint8{1, 1, 1, 1, 1},
+				filter: types.OutlierFilter{Method: "MAD", Threshold: 3},
+			},
+			want:       []*big.Int{big.NewInt(-2), big.NewInt(-1), big.NewInt(0), big.NewInt(1)},
+			wantWeight: []uint8{1, 1, 1, 1},
+			wantErr:    false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gotJobsData, err := FilterOutliers("ethCollection", getJobsData(tt.args.data, tt.args.weight), tt.args.filter)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("FilterOutliers() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			got, gotWeight := GetValuesAndWeightsFromJobsData(gotJobsData)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("FilterOutliers() got = %v, want %v", got, tt.want)
+			}
+			if !reflect.DeepEqual(gotWeight, tt.wantWeight) {
+				t.Errorf("FilterOutliers() gotWeight = %v, want %v", gotWeight, tt.wantWeight)
+			}
+		})
+	}
+}
+
 func Test_calculateWeightedMedian(t *testing.T) {
 	type args struct {
 		data        []*big.Int
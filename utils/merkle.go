@@ -2,6 +2,8 @@
 package utils
 
 import (
+	"bytes"
+	"encoding/hex"
 	solsha3 "github.com/miguelmota/go-solidity-sha3"
 	"math/big"
 )
@@ -68,3 +70,37 @@ func (*MerkleTreeStruct) GetMerkleRoot(tree [][][]byte) [32]byte {
 	copy(root[:], tree[0][0])
 	return root
 }
+
+//This function verifies the proof path of the value at the leaf id against the root of a tree with the leaf count, the leaf and pairs are hashed as the contract does
+func (*MerkleTreeStruct) VerifyProofPath(root [32]byte, value *big.Int, leafId uint16, leafCount int, proof [][32]byte) bool {
+	if value == nil || int(leafId) >= leafCount {
+		return false
+	}
+	computedHash := solsha3.SoliditySHA3([]string{"uint256"}, []interface{}{value})
+	proofIndex := 0
+	for levelCount, nodeId := leafCount, int(leafId); levelCount > 1; levelCount, nodeId = (levelCount+1)/2, nodeId/2 {
+		// The last node of a level with an odd count is carried up without a sibling
+		if nodeId == levelCount-1 && levelCount%2 == 1 {
+			continue
+		}
+		if proofIndex >= len(proof) {
+			return false
+		}
+		sibling := proof[proofIndex][:]
+		if nodeId%2 == 1 {
+			computedHash = solsha3.SoliditySHA3([]string{"bytes32", "bytes32"}, []interface{}{sibling, computedHash})
+		} else {
+			computedHash = solsha3.SoliditySHA3([]string{"bytes32", "bytes32"}, []interface{}{computedHash, sibling})
+		}
+		proofIndex++
+	}
+	return proofIndex == len(proof) && bytes.Equal(computedHash, root[:])
+}
+
+//This function returns the commitment of the merkle root with the seed, which is sent while committing
+func CalculateCommitment(root [32]byte, seed []byte) [32]byte {
+	commitment := solsha3.SoliditySHA3([]string{"bytes32", "bytes32"}, []interface{}{"0x" + hex.EncodeToString(root[:]), "0x" + hex.EncodeToString(seed)})
+	var commitmentBytes32 [32]byte
+	copy(commitmentBytes32[:], commitment)
+	return commitmentBytes32
+}
@@ -139,3 +139,82 @@ func TestMerkleTreeStructGetProofPath(t *testing.T) {
 		})
 	}
 }
+
+func TestMerkleTreeStructVerifyProofPath(t *testing.T) {
+	me := &MerkleTreeStruct{}
+	for leafCount := 1; leafCount <= 9; leafCount++ {
+		var values []*big.Int
+		for i := 0; i < leafCount; i++ {
+			values = append(values, big.NewInt(int64(1000*i+7)))
+		}
+		tree := me.CreateMerkle(values)
+		root := me.GetMerkleRoot(tree)
+		for leafId := 0; leafId < leafCount; leafId++ {
+			proof := me.GetProofPath(tree, uint16(leafId))
+			if !me.VerifyProofPath(root, values[leafId], uint16(leafId), leafCount, proof) {
+				t.Errorf("VerifyProofPath() of leaf %d of %d leaves = false, want true", leafId, leafCount)
+			}
+		}
+	}
+
+	values := []*big.Int{big.NewInt(1), big.NewInt(2), big.NewInt(3)}
+	tree := me.CreateMerkle(values)
+	root := me.GetMerkleRoot(tree)
+	proof := me.GetProofPath(tree, 1)
+	tests := []struct {
+		name      string
+		value     *big.Int
+		leafId    uint16
+		leafCount int
+		proof     [][32]byte
+		want      bool
+	}{
+		{
+			name:      "Test 1: When proof of the value is valid",
+			value:     big.NewInt(2),
+			leafId:    1,
+			leafCount: 3,
+			proof:     proof,
+			want:      true,
+		},
+		{
+			name:      "Test 2: When value is not the one in the tree",
+			value:     big.NewInt(4),
+			leafId:    1,
+			leafCount: 3,
+			proof:     proof,
+			want:      false,
+		},
+		{
+			name:      "Test 3: When proof is of another leaf",
+			value:     big.NewInt(2),
+			leafId:    0,
+			leafCount: 3,
+			proof:     proof,
+			want:      false,
+		},
+		{
+			name:      "Test 4: When proof is missing a node",
+			value:     big.NewInt(2),
+			leafId:    1,
+			leafCount: 3,
+			proof:     proof[:1],
+			want:      false,
+		},
+		{
+			name:      "Test 5: When leaf id is outside the tree",
+			value:     big.NewInt(2),
+			leafId:    3,
+			leafCount: 3,
+			proof:     proof,
+			want:      false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := me.VerifyProofPath(root, tt.value, tt.leafId, tt.leafCount, tt.proof); got != tt.want {
+				t.Errorf("VerifyProofPath() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
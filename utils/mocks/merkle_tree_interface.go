@@ -60,3 +60,17 @@ func (_m *MerkleTreeInterface) GetProofPath(tree [][][]byte, assetId uint16) [][
 
 	return r0
 }
+
+// VerifyProofPath provides a mock function with given fields: root, value, leafId, leafCount, proof
+func (_m *MerkleTreeInterface) VerifyProofPath(root [32]byte, value *big.Int, leafId uint16, leafCount int, proof [][32]byte) bool {
+	ret := _m.Called(root, value, leafId, leafCount, proof)
+
+	var r0 bool
+	if rf, ok := ret.Get(0).(func([32]byte, *big.Int, uint16, int, [][32]byte) bool); ok {
+		r0 = rf(root, value, leafId, leafCount, proof)
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	return r0
+}
@@ -14,6 +14,29 @@ type OSUtils struct {
 	mock.Mock
 }
 
+// CreateTemp provides a mock function with given fields: dir, pattern
+func (_m *OSUtils) CreateTemp(dir string, pattern string) (*os.File, error) {
+	ret := _m.Called(dir, pattern)
+
+	var r0 *os.File
+	if rf, ok := ret.Get(0).(func(string, string) *os.File); ok {
+		r0 = rf(dir, pattern)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*os.File)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(string, string) error); ok {
+		r1 = rf(dir, pattern)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // Open provides a mock function with given fields: name
 func (_m *OSUtils) Open(name string) (*os.File, error) {
 	ret := _m.Called(name)
@@ -60,6 +83,20 @@ func (_m *OSUtils) OpenFile(name string, flag int, perm fs.FileMode) (*os.File,
 	return r0, r1
 }
 
+// Rename provides a mock function with given fields: oldPath, newPath
+func (_m *OSUtils) Rename(oldPath string, newPath string) error {
+	ret := _m.Called(oldPath, newPath)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string, string) error); ok {
+		r0 = rf(oldPath, newPath)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // WriteFile provides a mock function with given fields: name, data, perm
 func (_m *OSUtils) WriteFile(name string, data []byte, perm fs.FileMode) error {
 	ret := _m.Called(name, data, perm)
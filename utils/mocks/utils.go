@@ -3,6 +3,8 @@
 package mocks
 
 import (
+	context "context"
+
 	big "math/big"
 	bindings "razor/pkg/bindings"
 
@@ -20,6 +22,8 @@ import (
 
 	pflag "github.com/spf13/pflag"
 
+	time "time"
+
 	types "razor/core/types"
 )
 
@@ -65,6 +69,22 @@ func (_m *Utils) Aggregate(client *ethclient.Client, previousEpoch uint32, colle
 	return r0, r1
 }
 
+// ApplyJobReputation provides a mock function with given fields: collectionName, jobsData, reputation
+func (_m *Utils) ApplyJobReputation(collectionName string, jobsData []types.JobData, reputation types.Reputation) []types.JobData {
+	ret := _m.Called(collectionName, jobsData, reputation)
+
+	var r0 []types.JobData
+	if rf, ok := ret.Get(0).(func(string, []types.JobData, types.Reputation) []types.JobData); ok {
+		r0 = rf(collectionName, jobsData, reputation)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]types.JobData)
+		}
+	}
+
+	return r0
+}
+
 // AssignLogFile provides a mock function with given fields: flagSet
 func (_m *Utils) AssignLogFile(flagSet *pflag.FlagSet) {
 	_m.Called(flagSet)
@@ -172,6 +192,29 @@ func (_m *Utils) CheckEthBalanceIsZero(client *ethclient.Client, address string)
 	_m.Called(client, address)
 }
 
+// CheckSanityBounds provides a mock function with given fields: client, collectionId, epoch, value
+func (_m *Utils) CheckSanityBounds(client *ethclient.Client, collectionId uint16, epoch uint32, value *big.Int) (*big.Int, error) {
+	ret := _m.Called(client, collectionId, epoch, value)
+
+	var r0 *big.Int
+	if rf, ok := ret.Get(0).(func(*ethclient.Client, uint16, uint32, *big.Int) *big.Int); ok {
+		r0 = rf(client, collectionId, epoch, value)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*big.Int)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(*ethclient.Client, uint16, uint32, *big.Int) error); ok {
+		r1 = rf(client, collectionId, epoch, value)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // CheckTransactionReceipt provides a mock function with given fields: client, _txHash
 func (_m *Utils) CheckTransactionReceipt(client *ethclient.Client, _txHash string) int {
 	ret := _m.Called(client, _txHash)
@@ -203,15 +246,15 @@ func (_m *Utils) ConnectToClient(provider string) *ethclient.Client {
 }
 
 // ConvertToNumber provides a mock function with given fields: num
-func (_m *Utils) ConvertToNumber(num interface{}) (*big.Float, error) {
+func (_m *Utils) ConvertToNumber(num interface{}) (*big.Rat, error) {
 	ret := _m.Called(num)
 
-	var r0 *big.Float
-	if rf, ok := ret.Get(0).(func(interface{}) *big.Float); ok {
+	var r0 *big.Rat
+	if rf, ok := ret.Get(0).(func(interface{}) *big.Rat); ok {
 		r0 = rf(num)
 	} else {
 		if ret.Get(0) != nil {
-			r0 = ret.Get(0).(*big.Float)
+			r0 = ret.Get(0).(*big.Rat)
 		}
 	}
 
@@ -283,13 +326,13 @@ func (_m *Utils) FetchBalance(client *ethclient.Client, accountAddress string) (
 	return r0, r1
 }
 
-// FetchPreviousValue provides a mock function with given fields: client, epoch, assetId
-func (_m *Utils) FetchPreviousValue(client *ethclient.Client, epoch uint32, assetId uint16) (*big.Int, error) {
-	ret := _m.Called(client, epoch, assetId)
+// FetchPreviousValue provides a mock function with given fields: client, epoch, collectionId
+func (_m *Utils) FetchPreviousValue(client *ethclient.Client, epoch uint32, collectionId uint16) (*big.Int, error) {
+	ret := _m.Called(client, epoch, collectionId)
 
 	var r0 *big.Int
 	if rf, ok := ret.Get(0).(func(*ethclient.Client, uint32, uint16) *big.Int); ok {
-		r0 = rf(client, epoch, assetId)
+		r0 = rf(client, epoch, collectionId)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).(*big.Int)
@@ -298,7 +341,7 @@ func (_m *Utils) FetchPreviousValue(client *ethclient.Client, epoch uint32, asse
 
 	var r1 error
 	if rf, ok := ret.Get(1).(func(*ethclient.Client, uint32, uint16) error); ok {
-		r1 = rf(client, epoch, assetId)
+		r1 = rf(client, epoch, collectionId)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -417,6 +460,27 @@ func (_m *Utils) GetAggregatedDataOfCollection(client *ethclient.Client, collect
 	return r0, r1
 }
 
+// GetAggregationReport provides a mock function with given fields: collectionId
+func (_m *Utils) GetAggregationReport(collectionId uint16) (types.AggregationReport, bool) {
+	ret := _m.Called(collectionId)
+
+	var r0 types.AggregationReport
+	if rf, ok := ret.Get(0).(func(uint16) types.AggregationReport); ok {
+		r0 = rf(collectionId)
+	} else {
+		r0 = ret.Get(0).(types.AggregationReport)
+	}
+
+	var r1 bool
+	if rf, ok := ret.Get(1).(func(uint16) bool); ok {
+		r1 = rf(collectionId)
+	} else {
+		r1 = ret.Get(1).(bool)
+	}
+
+	return r0, r1
+}
+
 // GetAllCollections provides a mock function with given fields: client
 func (_m *Utils) GetAllCollections(client *ethclient.Client) ([]bindings.StructsCollection, error) {
 	ret := _m.Called(client)
@@ -440,6 +504,36 @@ func (_m *Utils) GetAllCollections(client *ethclient.Client) ([]bindings.Structs
 	return r0, r1
 }
 
+// GetAppliedAssets provides a mock function with given fields:
+func (_m *Utils) GetAppliedAssets() (string, *types.AssetsConfig, bool) {
+	ret := _m.Called()
+
+	var r0 string
+	if rf, ok := ret.Get(0).(func() string); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	var r1 *types.AssetsConfig
+	if rf, ok := ret.Get(1).(func() *types.AssetsConfig); ok {
+		r1 = rf()
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(*types.AssetsConfig)
+		}
+	}
+
+	var r2 bool
+	if rf, ok := ret.Get(2).(func() bool); ok {
+		r2 = rf()
+	} else {
+		r2 = ret.Get(2).(bool)
+	}
+
+	return r0, r1, r2
+}
+
 // GetAssignedCollections provides a mock function with given fields: client, numActiveCollections, seed
 func (_m *Utils) GetAssignedCollections(client *ethclient.Client, numActiveCollections uint16, seed []byte) (map[int]bool, []*big.Int, error) {
 	ret := _m.Called(client, numActiveCollections, seed)
@@ -616,6 +710,29 @@ func (_m *Utils) GetCollectionIdFromLeafId(client *ethclient.Client, leafId uint
 	return r0, r1
 }
 
+// GetCollectionJobs provides a mock function with given fields: client, collection
+func (_m *Utils) GetCollectionJobs(client *ethclient.Client, collection bindings.StructsCollection) ([]types.AssetJob, error) {
+	ret := _m.Called(client, collection)
+
+	var r0 []types.AssetJob
+	if rf, ok := ret.Get(0).(func(*ethclient.Client, bindings.StructsCollection) []types.AssetJob); ok {
+		r0 = rf(client, collection)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]types.AssetJob)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(*ethclient.Client, bindings.StructsCollection) error); ok {
+		r1 = rf(client, collection)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // GetCollectionManager provides a mock function with given fields: client
 func (_m *Utils) GetCollectionManager(client *ethclient.Client) *bindings.CollectionManager {
 	ret := _m.Called(client)
@@ -678,13 +795,45 @@ func (_m *Utils) GetCommitments(client *ethclient.Client, address string) ([32]b
 	return r0, r1
 }
 
-// GetDataFromAPI provides a mock function with given fields: url
-func (_m *Utils) GetDataFromAPI(url string) ([]byte, error) {
-	ret := _m.Called(url)
+// GetDataAndVolumeToCommitFromJob provides a mock function with given fields: job
+func (_m *Utils) GetDataAndVolumeToCommitFromJob(job types.AssetJob) (*big.Int, *big.Rat, error) {
+	ret := _m.Called(job)
+
+	var r0 *big.Int
+	if rf, ok := ret.Get(0).(func(types.AssetJob) *big.Int); ok {
+		r0 = rf(job)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*big.Int)
+		}
+	}
+
+	var r1 *big.Rat
+	if rf, ok := ret.Get(1).(func(types.AssetJob) *big.Rat); ok {
+		r1 = rf(job)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(*big.Rat)
+		}
+	}
+
+	var r2 error
+	if rf, ok := ret.Get(2).(func(types.AssetJob) error); ok {
+		r2 = rf(job)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
+// GetDataFromAPI provides a mock function with given fields: url, request
+func (_m *Utils) GetDataFromAPI(url string, request types.JobRequest) ([]byte, error) {
+	ret := _m.Called(url, request)
 
 	var r0 []byte
-	if rf, ok := ret.Get(0).(func(string) []byte); ok {
-		r0 = rf(url)
+	if rf, ok := ret.Get(0).(func(string, types.JobRequest) []byte); ok {
+		r0 = rf(url, request)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).([]byte)
@@ -692,8 +841,31 @@ func (_m *Utils) GetDataFromAPI(url string) ([]byte, error) {
 	}
 
 	var r1 error
-	if rf, ok := ret.Get(1).(func(string) error); ok {
-		r1 = rf(url)
+	if rf, ok := ret.Get(1).(func(string, types.JobRequest) error); ok {
+		r1 = rf(url, request)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetDataFromHTML provides a mock function with given fields: url, selectors, request
+func (_m *Utils) GetDataFromHTML(url string, selectors []string, request types.JobRequest) ([]string, error) {
+	ret := _m.Called(url, selectors, request)
+
+	var r0 []string
+	if rf, ok := ret.Get(0).(func(string, []string, types.JobRequest) []string); ok {
+		r0 = rf(url, selectors, request)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]string)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(string, []string, types.JobRequest) error); ok {
+		r1 = rf(url, selectors, request)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -724,20 +896,22 @@ func (_m *Utils) GetDataFromJSON(jsonObject map[string]interface{}, selector str
 	return r0, r1
 }
 
-// GetDataFromXHTML provides a mock function with given fields: url, selector
-func (_m *Utils) GetDataFromXHTML(url string, selector string) (string, error) {
-	ret := _m.Called(url, selector)
+// GetDataFromXHTML provides a mock function with given fields: url, selectors, request
+func (_m *Utils) GetDataFromXHTML(url string, selectors []string, request types.JobRequest) ([]string, error) {
+	ret := _m.Called(url, selectors, request)
 
-	var r0 string
-	if rf, ok := ret.Get(0).(func(string, string) string); ok {
-		r0 = rf(url, selector)
+	var r0 []string
+	if rf, ok := ret.Get(0).(func(string, []string, types.JobRequest) []string); ok {
+		r0 = rf(url, selectors, request)
 	} else {
-		r0 = ret.Get(0).(string)
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]string)
+		}
 	}
 
 	var r1 error
-	if rf, ok := ret.Get(1).(func(string, string) error); ok {
-		r1 = rf(url, selector)
+	if rf, ok := ret.Get(1).(func(string, []string, types.JobRequest) error); ok {
+		r1 = rf(url, selectors, request)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -746,11 +920,11 @@ func (_m *Utils) GetDataFromXHTML(url string, selector string) (string, error) {
 }
 
 // GetDataToCommitFromJob provides a mock function with given fields: job
-func (_m *Utils) GetDataToCommitFromJob(job bindings.StructsJob) (*big.Int, error) {
+func (_m *Utils) GetDataToCommitFromJob(job types.AssetJob) (*big.Int, error) {
 	ret := _m.Called(job)
 
 	var r0 *big.Int
-	if rf, ok := ret.Get(0).(func(bindings.StructsJob) *big.Int); ok {
+	if rf, ok := ret.Get(0).(func(types.AssetJob) *big.Int); ok {
 		r0 = rf(job)
 	} else {
 		if ret.Get(0) != nil {
@@ -759,7 +933,7 @@ func (_m *Utils) GetDataToCommitFromJob(job bindings.StructsJob) (*big.Int, erro
 	}
 
 	var r1 error
-	if rf, ok := ret.Get(1).(func(bindings.StructsJob) error); ok {
+	if rf, ok := ret.Get(1).(func(types.AssetJob) error); ok {
 		r1 = rf(job)
 	} else {
 		r1 = ret.Error(1)
@@ -769,35 +943,49 @@ func (_m *Utils) GetDataToCommitFromJob(job bindings.StructsJob) (*big.Int, erro
 }
 
 // GetDataToCommitFromJobs provides a mock function with given fields: jobs
-func (_m *Utils) GetDataToCommitFromJobs(jobs []bindings.StructsJob) ([]*big.Int, []uint8, error) {
+func (_m *Utils) GetDataToCommitFromJobs(jobs []types.AssetJob) ([]types.JobData, error) {
 	ret := _m.Called(jobs)
 
-	var r0 []*big.Int
-	if rf, ok := ret.Get(0).(func([]bindings.StructsJob) []*big.Int); ok {
+	var r0 []types.JobData
+	if rf, ok := ret.Get(0).(func([]types.AssetJob) []types.JobData); ok {
 		r0 = rf(jobs)
 	} else {
 		if ret.Get(0) != nil {
-			r0 = ret.Get(0).([]*big.Int)
+			r0 = ret.Get(0).([]types.JobData)
 		}
 	}
 
-	var r1 []uint8
-	if rf, ok := ret.Get(1).(func([]bindings.StructsJob) []uint8); ok {
+	var r1 error
+	if rf, ok := ret.Get(1).(func([]types.AssetJob) error); ok {
 		r1 = rf(jobs)
 	} else {
-		if ret.Get(1) != nil {
-			r1 = ret.Get(1).([]uint8)
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetDatumFromJob provides a mock function with given fields: job
+func (_m *Utils) GetDatumFromJob(job types.AssetJob) (*big.Rat, error) {
+	ret := _m.Called(job)
+
+	var r0 *big.Rat
+	if rf, ok := ret.Get(0).(func(types.AssetJob) *big.Rat); ok {
+		r0 = rf(job)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*big.Rat)
 		}
 	}
 
-	var r2 error
-	if rf, ok := ret.Get(2).(func([]bindings.StructsJob) error); ok {
-		r2 = rf(jobs)
+	var r1 error
+	if rf, ok := ret.Get(1).(func(types.AssetJob) error); ok {
+		r1 = rf(job)
 	} else {
-		r2 = ret.Error(2)
+		r1 = ret.Error(1)
 	}
 
-	return r0, r1, r2
+	return r0, r1
 }
 
 // GetDelayedState provides a mock function with given fields: client, buffer
@@ -905,6 +1093,41 @@ func (_m *Utils) GetEpochLimitForUpdateCommission(client *ethclient.Client) (uin
 	return r0, r1
 }
 
+// GetFetchAttemptTimeout provides a mock function with given fields: timeout
+func (_m *Utils) GetFetchAttemptTimeout(timeout time.Duration) (time.Duration, error) {
+	ret := _m.Called(timeout)
+
+	var r0 time.Duration
+	if rf, ok := ret.Get(0).(func(time.Duration) time.Duration); ok {
+		r0 = rf(timeout)
+	} else {
+		r0 = ret.Get(0).(time.Duration)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(time.Duration) error); ok {
+		r1 = rf(timeout)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetFetchContext provides a mock function with given fields:
+func (_m *Utils) GetFetchContext() context.Context {
+	ret := _m.Called()
+
+	var r0 context.Context
+	if rf, ok := ret.Get(0).(func() context.Context); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Get(0).(context.Context)
+	}
+
+	return r0
+}
+
 // GetGasLimit provides a mock function with given fields: transactionData, txnOpts
 func (_m *Utils) GetGasLimit(transactionData types.TransactionOptions, txnOpts *bind.TransactOpts) (uint64, error) {
 	ret := _m.Called(transactionData, txnOpts)
@@ -1302,6 +1525,36 @@ func (_m *Utils) GetRemainingTimeOfCurrentState(client *ethclient.Client, buffer
 	return r0, r1
 }
 
+// GetReplayedChain provides a mock function with given fields:
+func (_m *Utils) GetReplayedChain() *types.RecordedChain {
+	ret := _m.Called()
+
+	var r0 *types.RecordedChain
+	if rf, ok := ret.Get(0).(func() *types.RecordedChain); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*types.RecordedChain)
+		}
+	}
+
+	return r0
+}
+
+// GetResponseTime provides a mock function with given fields: job
+func (_m *Utils) GetResponseTime(job types.AssetJob) time.Time {
+	ret := _m.Called(job)
+
+	var r0 time.Time
+	if rf, ok := ret.Get(0).(func(types.AssetJob) time.Time); ok {
+		r0 = rf(job)
+	} else {
+		r0 = ret.Get(0).(time.Time)
+	}
+
+	return r0
+}
+
 // GetSaltFromBlockchain provides a mock function with given fields: client
 func (_m *Utils) GetSaltFromBlockchain(client *ethclient.Client) ([32]byte, error) {
 	ret := _m.Called(client)
@@ -1708,22 +1961,22 @@ func (_m *Utils) GetWithdrawInitiationPeriod(client *ethclient.Client) (uint8, e
 	return r0, r1
 }
 
-// HandleOfficialJobsFromJSONFile provides a mock function with given fields: client, collection, dataString
-func (_m *Utils) HandleOfficialJobsFromJSONFile(client *ethclient.Client, collection bindings.StructsCollection, dataString string) ([]bindings.StructsJob, []uint16) {
-	ret := _m.Called(client, collection, dataString)
+// HandleOfficialJobsFromJSONFile provides a mock function with given fields: client, collection, collectionConfig
+func (_m *Utils) HandleOfficialJobsFromJSONFile(client *ethclient.Client, collection bindings.StructsCollection, collectionConfig types.CollectionConfig) ([]types.AssetJob, []uint16) {
+	ret := _m.Called(client, collection, collectionConfig)
 
-	var r0 []bindings.StructsJob
-	if rf, ok := ret.Get(0).(func(*ethclient.Client, bindings.StructsCollection, string) []bindings.StructsJob); ok {
-		r0 = rf(client, collection, dataString)
+	var r0 []types.AssetJob
+	if rf, ok := ret.Get(0).(func(*ethclient.Client, bindings.StructsCollection, types.CollectionConfig) []types.AssetJob); ok {
+		r0 = rf(client, collection, collectionConfig)
 	} else {
 		if ret.Get(0) != nil {
-			r0 = ret.Get(0).([]bindings.StructsJob)
+			r0 = ret.Get(0).([]types.AssetJob)
 		}
 	}
 
 	var r1 []uint16
-	if rf, ok := ret.Get(1).(func(*ethclient.Client, bindings.StructsCollection, string) []uint16); ok {
-		r1 = rf(client, collection, dataString)
+	if rf, ok := ret.Get(1).(func(*ethclient.Client, bindings.StructsCollection, types.CollectionConfig) []uint16); ok {
+		r1 = rf(client, collection, collectionConfig)
 	} else {
 		if ret.Get(1) != nil {
 			r1 = ret.Get(1).([]uint16)
@@ -1768,6 +2021,34 @@ func (_m *Utils) IsFlagPassed(name string) bool {
 	return r0
 }
 
+// IsHistoryOpen provides a mock function with given fields:
+func (_m *Utils) IsHistoryOpen() bool {
+	ret := _m.Called()
+
+	var r0 bool
+	if rf, ok := ret.Get(0).(func() bool); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	return r0
+}
+
+// IsReplaying provides a mock function with given fields:
+func (_m *Utils) IsReplaying() bool {
+	ret := _m.Called()
+
+	var r0 bool
+	if rf, ok := ret.Get(0).(func() bool); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	return r0
+}
+
 // MultiplyFloatAndBigInt provides a mock function with given fields: bigIntVal, floatingVal
 func (_m *Utils) MultiplyFloatAndBigInt(bigIntVal *big.Int, floatingVal float64) *big.Int {
 	ret := _m.Called(bigIntVal, floatingVal)
@@ -1886,6 +2167,21 @@ func (_m *Utils) ReadJSONData(fileName string) (map[string]*types.StructsJob, er
 	return r0, r1
 }
 
+// RecordChain provides a mock function with given fields: update
+func (_m *Utils) RecordChain(update func(chain *types.RecordedChain)) {
+	_m.Called(update)
+}
+
+// RecordCollectionState provides a mock function with given fields: collection
+func (_m *Utils) RecordCollectionState(collection bindings.StructsCollection) {
+	_m.Called(collection)
+}
+
+// RecordJobHealth provides a mock function with given fields: collectionName, epoch, jobs, jobsData
+func (_m *Utils) RecordJobHealth(collectionName string, epoch uint32, jobs []types.AssetJob, jobsData []types.JobData) {
+	_m.Called(collectionName, epoch, jobs, jobsData)
+}
+
 // SaveDataToCommitJsonFile provides a mock function with given fields: filePath, epoch, commitData
 func (_m *Utils) SaveDataToCommitJsonFile(filePath string, epoch uint32, commitData types.CommitData) error {
 	ret := _m.Called(filePath, epoch, commitData)
@@ -1942,6 +2238,30 @@ func (_m *Utils) SecondsToReadableTime(input int) string {
 	return r0
 }
 
+// SetAggregationEpoch provides a mock function with given fields: epoch
+func (_m *Utils) SetAggregationEpoch(epoch uint32) {
+	_m.Called(epoch)
+}
+
+// SettleJobHealth provides a mock function with given fields: client, previousEpoch, collection, collectionId
+func (_m *Utils) SettleJobHealth(client *ethclient.Client, previousEpoch uint32, collection string, collectionId uint16) {
+	_m.Called(client, previousEpoch, collection, collectionId)
+}
+
+// ShouldFetchMediansHistory provides a mock function with given fields: epoch
+func (_m *Utils) ShouldFetchMediansHistory(epoch uint32) bool {
+	ret := _m.Called(epoch)
+
+	var r0 bool
+	if rf, ok := ret.Get(0).(func(uint32) bool); ok {
+		r0 = rf(epoch)
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	return r0
+}
+
 // SuggestGasPriceWithRetry provides a mock function with given fields: client
 func (_m *Utils) SuggestGasPriceWithRetry(client *ethclient.Client) (*big.Int, error) {
 	ret := _m.Called(client)
@@ -1986,6 +2306,29 @@ func (_m *Utils) ToAssign(client *ethclient.Client) (uint16, error) {
 	return r0, r1
 }
 
+// ValidateAssetsFile provides a mock function with given fields: client
+func (_m *Utils) ValidateAssetsFile(client *ethclient.Client) ([]error, error) {
+	ret := _m.Called(client)
+
+	var r0 []error
+	if rf, ok := ret.Get(0).(func(*ethclient.Client) []error); ok {
+		r0 = rf(client)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]error)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(*ethclient.Client) error); ok {
+		r1 = rf(client)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // WaitForBlockCompletion provides a mock function with given fields: client, hashToRead
 func (_m *Utils) WaitForBlockCompletion(client *ethclient.Client, hashToRead string) int {
 	ret := _m.Called(client, hashToRead)
@@ -2005,6 +2348,20 @@ func (_m *Utils) WaitTillNextNSecs(waitTime int32) {
 	_m.Called(waitTime)
 }
 
+// WatchAssetsFile provides a mock function with given fields: client
+func (_m *Utils) WatchAssetsFile(client *ethclient.Client) error {
+	ret := _m.Called(client)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(*ethclient.Client) error); ok {
+		r0 = rf(client)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // WriteDataToJSON provides a mock function with given fields: fileName, data
 func (_m *Utils) WriteDataToJSON(fileName string, data map[string]*types.StructsJob) error {
 	ret := _m.Called(fileName, data)
@@ -2018,3 +2375,22 @@ func (_m *Utils) WriteDataToJSON(fileName string, data map[string]*types.Structs
 
 	return r0
 }
+
+// WriteHistory provides a mock function with given fields: records
+func (_m *Utils) WriteHistory(records []types.HistoryRecord) {
+	_m.Called(records)
+}
+
+// WriteMediansHistory provides a mock function with given fields: epoch, records
+func (_m *Utils) WriteMediansHistory(epoch uint32, records []types.HistoryRecord) error {
+	ret := _m.Called(epoch, records)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(uint32, []types.HistoryRecord) error); ok {
+		r0 = rf(epoch, records)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
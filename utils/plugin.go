@@ -0,0 +1,272 @@
+//Package utils provides the utils functions
+package utils
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"os/exec"
+	"razor/core"
+	"razor/core/types"
+	"strings"
+	"time"
+
+	"golang.org/x/net/http2"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/structpb"
+)
+
+var pluginTypes = []string{"exec", "grpc", "http"}
+
+var pluginHTTPClient = &http.Client{}
+
+// gRPC plugins are local, so requests are sent over HTTP/2 without TLS
+var pluginGRPCClient = &http.Client{
+	Transport: &http2.Transport{
+		AllowHTTP: true,
+		DialTLS: func(network, address string, _ *tls.Config) (net.Conn, error) {
+			return net.Dial(network, address)
+		},
+	},
+}
+
+//This function returns the fields of a single output of the plugin job, the value field is used if no field is selected
+func getDataPointsFromPlugin(job types.AssetJob, fields []string) ([]interface{}, error) {
+	output, err := GetPluginOutput(*job.Plugin, job.Name)
+	if err != nil {
+		log.Errorf("Error in fetching data from plugin %s: %s", job.Url, err)
+		return nil, err
+	}
+	values := make([]interface{}, len(fields))
+	for index, field := range fields {
+		if field == "" {
+			field = "value"
+		}
+		value, ok := output[field]
+		if !ok {
+			return nil, fmt.Errorf("no %s in output of plugin", field)
+		}
+		values[index] = value
+	}
+	return values, nil
+}
+
+//This function sends the name of the job and its params to the plugin and returns its output, the plugin has to answer within its timeout
+func GetPluginOutput(plugin types.PluginSource, jobName string) (map[string]interface{}, error) {
+	timeout := plugin.Timeout
+	if timeout <= 0 {
+		timeout = core.DefaultPluginTimeout
+	}
+	fetchContext := UtilsInterface.GetFetchContext()
+	ctx, cancel := context.WithTimeout(fetchContext, timeout)
+	defer cancel()
+
+	request := map[string]interface{}{"name": jobName, "params": plugin.Params}
+	if plugin.Params == nil {
+		request["params"] = map[string]interface{}{}
+	}
+
+	var (
+		output map[string]interface{}
+		err    error
+	)
+	switch plugin.Type {
+	case "exec":
+		output, err = runExecPlugin(ctx, plugin, request)
+	case "http":
+		output, err = callHTTPPlugin(ctx, plugin, request)
+	case "grpc":
+		output, err = callGRPCPlugin(ctx, plugin, request)
+	default:
+		return nil, fmt.Errorf("invalid plugin type %s", plugin.Type)
+	}
+	if ctx.Err() == context.DeadlineExceeded {
+		if fetchContext.Err() != nil {
+			return nil, errFetchDeadlineExceeded
+		}
+		return nil, fmt.Errorf("plugin did not answer within %s", timeout)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if message, ok := output["error"]; ok && message != nil && message != "" {
+		return nil, fmt.Errorf("plugin returned error: %v", message)
+	}
+	return output, nil
+}
+
+//This function runs the command of the plugin with the request on its stdin and returns the JSON object written to its stdout
+func runExecPlugin(ctx context.Context, plugin types.PluginSource, request map[string]interface{}) (map[string]interface{}, error) {
+	input, err := json.Marshal(request)
+	if err != nil {
+		return nil, err
+	}
+	command := exec.CommandContext(ctx, plugin.Command, plugin.Args...)
+	command.Stdin = bytes.NewReader(append(input, '\n'))
+	stdout := &limitedBuffer{limit: core.MaxPluginOutputSize}
+	stderr := &limitedBuffer{limit: core.ResponseExcerptLength, truncate: true}
+	command.Stdout = stdout
+	command.Stderr = stderr
+	if err := command.Start(); err != nil {
+		return nil, err
+	}
+	done := make(chan error, 1)
+	go func() {
+		done <- command.Wait()
+	}()
+	select {
+	case <-ctx.Done():
+		// The plugin is killed once the context is done, it is not waited for as its children may still hold its output open
+		return nil, ctx.Err()
+	case err := <-done:
+		if err != nil {
+			if message := strings.TrimSpace(stderr.String()); message != "" {
+				return nil, fmt.Errorf("plugin %s failed: %s: %s", plugin.Command, err, message)
+			}
+			return nil, fmt.Errorf("plugin %s failed: %s", plugin.Command, err)
+		}
+	}
+	return parsePluginOutput(stdout.Bytes())
+}
+
+//This function posts the request to the plugin and returns the JSON object of its response
+func callHTTPPlugin(ctx context.Context, plugin types.PluginSource, request map[string]interface{}) (map[string]interface{}, error) {
+	input, err := json.Marshal(request)
+	if err != nil {
+		return nil, err
+	}
+	httpRequest, err := http.NewRequestWithContext(ctx, http.MethodPost, plugin.URL, bytes.NewReader(input))
+	if err != nil {
+		return nil, err
+	}
+	httpRequest.Header.Set("Content-Type", "application/json")
+	response, err := pluginHTTPClient.Do(httpRequest)
+	if err != nil {
+		return nil, err
+	}
+	defer response.Body.Close()
+	if response.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("plugin responded with status %s", response.Status)
+	}
+	body, err := readPluginResponse(response.Body)
+	if err != nil {
+		return nil, err
+	}
+	return parsePluginOutput(body)
+}
+
+//This function calls the Fetch method of the gRPC plugin with the request as a google.protobuf.Struct and returns the Struct it answers
+func callGRPCPlugin(ctx context.Context, plugin types.PluginSource, request map[string]interface{}) (map[string]interface{}, error) {
+	message, err := structpb.NewStruct(request)
+	if err != nil {
+		return nil, err
+	}
+	payload, err := proto.Marshal(message)
+	if err != nil {
+		return nil, err
+	}
+	// Every gRPC message is prefixed by a compression flag and its length
+	frame := make([]byte, 5+len(payload))
+	binary.BigEndian.PutUint32(frame[1:5], uint32(len(payload)))
+	copy(frame[5:], payload)
+
+	httpRequest, err := http.NewRequestWithContext(ctx, http.MethodPost, "http://"+plugin.Address+core.PluginGRPCMethod, bytes.NewReader(frame))
+	if err != nil {
+		return nil, err
+	}
+	httpRequest.Header.Set("Content-Type", "application/grpc")
+	httpRequest.Header.Set("TE", "trailers")
+	if deadline, ok := ctx.Deadline(); ok {
+		httpRequest.Header.Set("Grpc-Timeout", fmt.Sprintf("%dm", time.Until(deadline).Milliseconds()))
+	}
+	response, err := pluginGRPCClient.Do(httpRequest)
+	if err != nil {
+		return nil, err
+	}
+	defer response.Body.Close()
+	if response.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("plugin responded with status %s", response.Status)
+	}
+	body, err := readPluginResponse(response.Body)
+	if err != nil {
+		return nil, err
+	}
+	// A failed call may only have headers, otherwise its status is in the trailers which are known once the body is read
+	status, statusMessage := response.Trailer.Get("Grpc-Status"), response.Trailer.Get("Grpc-Message")
+	if status == "" {
+		status, statusMessage = response.Header.Get("Grpc-Status"), response.Header.Get("Grpc-Message")
+	}
+	if status != "0" {
+		return nil, fmt.Errorf("plugin returned gRPC status %s: %s", status, statusMessage)
+	}
+	if len(body) < 5 || body[0] != 0 || int(binary.BigEndian.Uint32(body[1:5])) != len(body)-5 {
+		return nil, errors.New("plugin returned an invalid gRPC message")
+	}
+	output := &structpb.Struct{}
+	if err := proto.Unmarshal(body[5:], output); err != nil {
+		return nil, err
+	}
+	return output.AsMap(), nil
+}
+
+//This function reads the response of the plugin, which must not be larger than the max plugin output size
+func readPluginResponse(body io.Reader) ([]byte, error) {
+	data, err := io.ReadAll(io.LimitReader(body, int64(core.MaxPluginOutputSize)+1))
+	if err != nil {
+		return nil, err
+	}
+	if len(data) > core.MaxPluginOutputSize {
+		return nil, errors.New("plugin output is too large")
+	}
+	return data, nil
+}
+
+//This function parses the output of the plugin which must be a single JSON object, numbers are kept exactly
+func parsePluginOutput(data []byte) (map[string]interface{}, error) {
+	var output map[string]interface{}
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	if err := decoder.Decode(&output); err != nil {
+		return nil, fmt.Errorf("plugin output is not a JSON object: %s", err)
+	}
+	if decoder.More() {
+		return nil, errors.New("plugin output has more than one JSON object")
+	}
+	return output, nil
+}
+
+//This function returns the source of the plugin which is shown in place of the URL of the job
+func getPluginSourceName(plugin types.PluginSource) string {
+	switch plugin.Type {
+	case "exec":
+		return "exec:" + strings.TrimSpace(plugin.Command+" "+strings.Join(plugin.Args, " "))
+	case "grpc":
+		return "grpc://" + plugin.Address
+	}
+	return plugin.URL
+}
+
+type limitedBuffer struct {
+	bytes.Buffer
+	limit    int
+	truncate bool
+}
+
+//This function writes to the buffer until its limit is reached, after which the data is dropped if the buffer truncates or else an error is returned
+func (buffer *limitedBuffer) Write(data []byte) (int, error) {
+	if buffer.Len()+len(data) <= buffer.limit {
+		return buffer.Buffer.Write(data)
+	}
+	if !buffer.truncate {
+		return 0, errors.New("plugin output is too large")
+	}
+	buffer.Buffer.Write(data[:buffer.limit-buffer.Len()])
+	return len(data), nil
+}
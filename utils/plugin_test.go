@@ -0,0 +1,277 @@
+package utils
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"io"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"razor/core/types"
+	"reflect"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"golang.org/x/net/http2"
+	"golang.org/x/net/http2/h2c"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/structpb"
+)
+
+func TestGetPluginOutputFromExec(t *testing.T) {
+	StartRazor(OptionsPackageStruct{UtilsInterface: &UtilsStruct{}})
+	tests := []struct {
+		name    string
+		script  string
+		timeout time.Duration
+		want    map[string]interface{}
+		wantErr bool
+	}{
+		{
+			name:   "Test 1: When plugin writes its output",
+			script: `read request; echo '{"value": "1234.567890123456789", "volume": 98765.4321}'`,
+			want:   map[string]interface{}{"value": "1234.567890123456789", "volume": json.Number("98765.4321")},
+		},
+		{
+			name:   "Test 2: When plugin gets the name and params of the job",
+			script: `read request; echo "$request"`,
+			want:   map[string]interface{}{"name": "eth_internal", "params": map[string]interface{}{"pair": "ETH-USD"}},
+		},
+		{
+			name:    "Test 3: When plugin returns an error",
+			script:  `echo '{"error": "source is down"}'`,
+			wantErr: true,
+		},
+		{
+			name:    "Test 4: When plugin exits with an error",
+			script:  `echo 'source is down' >&2; exit 3`,
+			wantErr: true,
+		},
+		{
+			name:    "Test 5: When output is not a JSON object",
+			script:  `echo 1234.56`,
+			wantErr: true,
+		},
+		{
+			name:    "Test 6: When output has more than one JSON object",
+			script:  `echo '{"value": "1"} {"value": "2"}'`,
+			wantErr: true,
+		},
+		{
+			name:    "Test 7: When plugin does not answer within its timeout",
+			script:  `sleep 5; echo '{"value": "1"}'`,
+			timeout: 100 * time.Millisecond,
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			plugin := types.PluginSource{
+				Type:    "exec",
+				Command: "/bin/sh",
+				Args:    []string{"-c", tt.script},
+				Timeout: tt.timeout,
+				Params:  map[string]interface{}{"pair": "ETH-USD"},
+			}
+			got, err := GetPluginOutput(plugin, "eth_internal")
+			if (err != nil) != tt.wantErr {
+				t.Errorf("GetPluginOutput() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("GetPluginOutput() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGetPluginOutputFromHTTP(t *testing.T) {
+	StartRazor(OptionsPackageStruct{UtilsInterface: &UtilsStruct{}})
+	tests := []struct {
+		name       string
+		statusCode int
+		body       string
+		want       map[string]interface{}
+		wantErr    bool
+	}{
+		{
+			name:       "Test 1: When plugin answers with its output",
+			statusCode: http.StatusOK,
+			body:       `{"value": "1234.56"}`,
+			want:       map[string]interface{}{"value": "1234.56"},
+		},
+		{
+			name:       "Test 2: When plugin answers with an error",
+			statusCode: http.StatusOK,
+			body:       `{"error": "source is down"}`,
+			wantErr:    true,
+		},
+		{
+			name:       "Test 3: When plugin answers with an error status",
+			statusCode: http.StatusInternalServerError,
+			body:       `{"value": "1234.56"}`,
+			wantErr:    true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				var request map[string]interface{}
+				if r.Method != http.MethodPost || json.NewDecoder(r.Body).Decode(&request) != nil || request["name"] != "eth_internal" {
+					w.WriteHeader(http.StatusBadRequest)
+					return
+				}
+				w.WriteHeader(tt.statusCode)
+				io.WriteString(w, tt.body)
+			}))
+			defer server.Close()
+
+			got, err := GetPluginOutput(types.PluginSource{Type: "http", URL: server.URL}, "eth_internal")
+			if (err != nil) != tt.wantErr {
+				t.Errorf("GetPluginOutput() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("GetPluginOutput() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGetPluginOutputFromGRPC(t *testing.T) {
+	StartRazor(OptionsPackageStruct{UtilsInterface: &UtilsStruct{}})
+	tests := []struct {
+		name    string
+		output  map[string]interface{}
+		status  string
+		want    map[string]interface{}
+		wantErr bool
+	}{
+		{
+			name:   "Test 1: When plugin answers with its output",
+			output: map[string]interface{}{"value": "1234.56", "volume": 100.5},
+			status: "0",
+			want:   map[string]interface{}{"value": "1234.56", "volume": 100.5},
+		},
+		{
+			name:    "Test 2: When plugin answers with an error",
+			output:  map[string]interface{}{"error": "source is down"},
+			status:  "0",
+			wantErr: true,
+		},
+		{
+			name:    "Test 3: When plugin answers with a failed status",
+			status:  "14",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Content-Type", "application/grpc")
+				w.Header().Set("Trailer", "Grpc-Status")
+				body, _ := io.ReadAll(r.Body)
+				request := &structpb.Struct{}
+				if r.URL.Path != "/razor.plugin.v1.DataSource/Fetch" || len(body) < 5 || proto.Unmarshal(body[5:], request) != nil || request.AsMap()["name"] != "eth_internal" {
+					w.Header().Set("Grpc-Status", "3")
+					return
+				}
+				if tt.output != nil {
+					output, _ := structpb.NewStruct(tt.output)
+					payload, _ := proto.Marshal(output)
+					frame := make([]byte, 5+len(payload))
+					binary.BigEndian.PutUint32(frame[1:5], uint32(len(payload)))
+					copy(frame[5:], payload)
+					w.Write(frame)
+				}
+				w.Header().Set("Grpc-Status", tt.status)
+			})
+			server := httptest.NewServer(h2c.NewHandler(handler, &http2.Server{}))
+			defer server.Close()
+
+			plugin := types.PluginSource{Type: "grpc", Address: strings.TrimPrefix(server.URL, "http://")}
+			got, err := GetPluginOutput(plugin, "eth_internal")
+			if (err != nil) != tt.wantErr {
+				t.Errorf("GetPluginOutput() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("GetPluginOutput() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGetDataToCommitFromPluginJob(t *testing.T) {
+	StartRazor(OptionsPackageStruct{UtilsInterface: &UtilsStruct{}})
+
+	// The plugin counts its calls, so that the value, volume and timestamp are checked to come from one output
+	calls := filepath.Join(t.TempDir(), "calls")
+	job := types.AssetJob{Plugin: &types.PluginSource{
+		Type:    "exec",
+		Command: "/bin/sh",
+		Args:    []string{"-c", `echo call >> ` + calls + `; echo '{"value": "1234.567", "volume": "10", "timestamp": ` + strconv.FormatInt(time.Now().Unix(), 10) + `}'`},
+	}}
+	job.Power = 2
+
+	got, err := UtilsInterface.GetDataToCommitFromJob(job)
+	if err != nil || got.Cmp(big.NewInt(123456)) != 0 {
+		t.Errorf("GetDataToCommitFromJob() got = %v, err = %v, want 123456", got, err)
+	}
+	job.VolumeSelector = "volume"
+	job.Freshness = &types.JobFreshness{TimestampSelector: "timestamp", MaxAge: time.Minute}
+	got, volume, err := UtilsInterface.GetDataAndVolumeToCommitFromJob(job)
+	if err != nil || got.Cmp(big.NewInt(123456)) != 0 || volume.Cmp(big.NewRat(10, 1)) != 0 {
+		t.Errorf("GetDataAndVolumeToCommitFromJob() got = %v, volume = %v, err = %v, want 123456 and 10", got, volume, err)
+	}
+	if output, _ := os.ReadFile(calls); strings.Count(string(output), "call") != 2 {
+		t.Errorf("Plugin was called %d times, want once per job", strings.Count(string(output), "call"))
+	}
+	job.Selector = "price"
+	if _, err := UtilsInterface.GetDataToCommitFromJob(job); err == nil {
+		t.Error("GetDataToCommitFromJob() selected a field which is not in the output of plugin")
+	}
+}
+
+func TestGetPluginSourceFromConfig(t *testing.T) {
+	tests := []struct {
+		name         string
+		pluginConfig types.PluginConfig
+		want         *types.PluginSource
+		wantErr      bool
+	}{
+		{
+			name: "Test 1: When plugin is an exec plugin",
+			pluginConfig: types.PluginConfig{Type: "Exec", Command: "/opt/razor/eth-price", Args: []string{"--pair", "ETH-USD"},
+				Timeout: 1.5, Params: map[string]interface{}{"pair": "ETH-USD"}},
+			want: &types.PluginSource{Type: "exec", Command: "/opt/razor/eth-price", Args: []string{"--pair", "ETH-USD"},
+				Timeout: 1500 * time.Millisecond, Params: map[string]interface{}{"pair": "ETH-USD"}},
+		},
+		{
+			name:         "Test 2: When plugin is a gRPC plugin",
+			pluginConfig: types.PluginConfig{Type: "grpc", Address: "127.0.0.1:50051"},
+			want:         &types.PluginSource{Type: "grpc", Address: "127.0.0.1:50051"},
+		},
+		{
+			name:         "Test 3: When plugin type is invalid",
+			pluginConfig: types.PluginConfig{Type: "websocket", URL: "ws://127.0.0.1:8080"},
+			wantErr:      true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := GetPluginSourceFromConfig(tt.pluginConfig)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("GetPluginSourceFromConfig() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("GetPluginSourceFromConfig() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,441 @@
+//Package utils provides the utils functions
+package utils
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"razor/core/types"
+	"razor/path"
+	"razor/pkg/bindings"
+	"strconv"
+	"sync"
+	"time"
+)
+
+//ResponseArchive is a http.RoundTripper which records or replays the responses of job requests and the chain reads of an epoch
+type ResponseArchive struct {
+	transport      http.RoundTripper
+	mutex          sync.Mutex
+	dir            string
+	retention      uint32
+	recording      bool
+	epoch          uint32
+	file           *os.File
+	recorded       map[string]bool
+	chain          *types.RecordedChain
+	recordedStates map[string]bool
+	replaying      bool
+	responses      map[string][]types.RecordedResponse
+	served         map[string]int
+	replayedAt     map[string]time.Time
+}
+
+// Responses served from the response cache are recorded as well
+var responseArchive = &ResponseArchive{transport: responseCache}
+
+//This function returns the directory in which the responses of an epoch are recorded
+func getRecordingDir(dir string, epoch uint32) string {
+	return filepath.Join(dir, strconv.Itoa(int(epoch)))
+}
+
+//This function returns an empty recording of what is read from the chain
+func newRecordedChain() *types.RecordedChain {
+	return &types.RecordedChain{
+		CollectionIds:  make(map[uint16]uint16),
+		Collections:    make(map[uint16]bindings.StructsCollection),
+		Jobs:           make(map[uint16]bindings.StructsJob),
+		PreviousValues: make(map[string]types.RecordedValue),
+		JobHealth:      make(map[string]types.JobHealth),
+	}
+}
+
+//This function records the responses of job requests in the directory from the next epoch on
+func (archive *ResponseArchive) Record(dir string, retention uint32) error {
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return err
+	}
+	archive.mutex.Lock()
+	defer archive.mutex.Unlock()
+	archive.dir = dir
+	archive.retention = retention
+	archive.recording = true
+	return nil
+}
+
+//This function starts recording the responses of a new epoch along with the assets.json they are aggregated with
+func (archive *ResponseArchive) SetEpoch(epoch uint32, readAssets func() (string, error)) {
+	archive.mutex.Lock()
+	defer archive.mutex.Unlock()
+	if !archive.recording || archive.epoch == epoch {
+		return
+	}
+	dataString, err := readAssets()
+	if err != nil {
+		log.Error("Error in reading assets.json to record: ", err)
+		return
+	}
+	if archive.file != nil {
+		archive.file.Close()
+		archive.file = nil
+	}
+	archive.epoch = epoch
+	dir := getRecordingDir(archive.dir, epoch)
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		log.Error("Error in creating recording directory: ", err)
+		return
+	}
+	if err := os.WriteFile(filepath.Join(dir, "assets.json"), []byte(dataString), 0600); err != nil {
+		log.Error("Error in recording assets.json: ", err)
+		return
+	}
+	file, err := os.OpenFile(filepath.Join(dir, "responses.jsonl"), os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0600)
+	if err != nil {
+		log.Error("Error in creating recording of responses: ", err)
+		return
+	}
+	archive.file = file
+	archive.recorded = make(map[string]bool)
+	archive.chain = newRecordedChain()
+	archive.recordedStates = make(map[string]bool)
+	archive.writeChain()
+	log.Infof("Recording responses of epoch %d in %s", epoch, dir)
+
+	prunedEpochs, err := PruneDataFilesInDir(archive.dir, epoch, archive.retention)
+	if err != nil {
+		log.Error("Error in removing old recordings: ", err)
+	} else if len(prunedEpochs) > 0 {
+		log.Debugf("Removed the recordings of epochs %v", prunedEpochs)
+	}
+}
+
+//This function replays the responses recorded in the epoch instead of fetching them and returns the assets.json recorded with them
+func (archive *ResponseArchive) Replay(dir string, epoch uint32) (string, error) {
+	recordingDir := getRecordingDir(dir, epoch)
+	dataString, err := os.ReadFile(filepath.Join(recordingDir, "assets.json"))
+	if err != nil {
+		return "", fmt.Errorf("no recording of epoch %d: %s", epoch, err)
+	}
+	responses, err := ReadRecordedResponses(filepath.Join(recordingDir, "responses.jsonl"))
+	if err != nil {
+		return "", err
+	}
+	chainData, err := os.ReadFile(filepath.Join(recordingDir, "chain.json"))
+	if err != nil {
+		return "", fmt.Errorf("recording of epoch %d has no values read from the chain, it can only be replayed if it is recorded with them: %s", epoch, err)
+	}
+	chain := newRecordedChain()
+	if err := json.Unmarshal(chainData, chain); err != nil {
+		return "", fmt.Errorf("invalid values read from the chain in recording of epoch %d: %s", epoch, err)
+	}
+
+	archive.mutex.Lock()
+	defer archive.mutex.Unlock()
+	archive.replaying = true
+	archive.responses = make(map[string][]types.RecordedResponse)
+	archive.served = make(map[string]int)
+	archive.replayedAt = make(map[string]time.Time)
+	archive.chain = chain
+	for _, response := range responses {
+		archive.responses[response.Key] = append(archive.responses[response.Key], response)
+	}
+	return string(dataString), nil
+}
+
+//This function returns whether an epoch is being replayed
+func (*UtilsStruct) IsReplaying() bool {
+	return responseArchive.isReplaying()
+}
+
+//This function returns what was read from the chain in the epoch being replayed
+func (*UtilsStruct) GetReplayedChain() *types.RecordedChain {
+	return responseArchive.replayedChain()
+}
+
+//This function updates what is recorded as read from the chain in the epoch, if the epoch is recorded
+func (*UtilsStruct) RecordChain(update func(chain *types.RecordedChain)) {
+	responseArchive.recordChain(update)
+}
+
+//This function records the health of the jobs of the collection, if the epoch is recorded
+func (*UtilsStruct) RecordCollectionState(collection bindings.StructsCollection) {
+	responseArchive.recordCollectionState(collection)
+}
+
+//This function returns the time the response of the job was fetched at
+func (*UtilsStruct) GetResponseTime(job types.AssetJob) time.Time {
+	return responseArchive.Now(job)
+}
+
+//This function returns whether an epoch is being replayed
+func (archive *ResponseArchive) isReplaying() bool {
+	archive.mutex.Lock()
+	defer archive.mutex.Unlock()
+	return archive.replaying
+}
+
+//This function returns what was read from the chain in the epoch being replayed
+func (archive *ResponseArchive) replayedChain() *types.RecordedChain {
+	archive.mutex.Lock()
+	defer archive.mutex.Unlock()
+	if !archive.replaying {
+		return nil
+	}
+	return archive.chain
+}
+
+//This function updates what is recorded as read from the chain in the epoch
+func (archive *ResponseArchive) recordChain(update func(chain *types.RecordedChain)) {
+	archive.mutex.Lock()
+	defer archive.mutex.Unlock()
+	if archive.file == nil || archive.chain == nil {
+		return
+	}
+	update(archive.chain)
+	archive.writeChain()
+}
+
+//This function writes the values read from the chain in the epoch to its recording, the caller must hold the mutex
+func (archive *ResponseArchive) writeChain() {
+	data, err := json.Marshal(archive.chain)
+	if err != nil {
+		log.Error("Error in recording values read from the chain: ", err)
+		return
+	}
+	if err := WriteFileAtomically(filepath.Join(getRecordingDir(archive.dir, archive.epoch), "chain.json"), data, 0600); err != nil {
+		log.Error("Error in recording values read from the chain: ", err)
+	}
+}
+
+//This function records the health of the jobs of the collection the first time it is aggregated in the epoch
+func (archive *ResponseArchive) recordCollectionState(collection bindings.StructsCollection) {
+	archive.mutex.Lock()
+	recorded := archive.file == nil || archive.recordedStates[collection.Name]
+	if !recorded {
+		archive.recordedStates[collection.Name] = true
+	}
+	archive.mutex.Unlock()
+	if recorded {
+		return
+	}
+	healths := jobHealth.snapshot(collection.Name)
+	archive.recordChain(func(chain *types.RecordedChain) {
+		for key, health := range healths {
+			chain.JobHealth[key] = health
+		}
+	})
+}
+
+//This function restores the health of the jobs of the collection as it was when the replayed epoch was recorded
+func restoreCollectionState(chain *types.RecordedChain, collection bindings.StructsCollection) {
+	jobHealth.restore(collection.Name, chain.JobHealth)
+}
+
+//This function returns the key a confirmed median is recorded with
+func getRecordedValueKey(epoch uint32, collectionId uint16) string {
+	return strconv.Itoa(int(epoch)) + "/" + strconv.Itoa(int(collectionId))
+}
+
+//This function returns the current time, or the time the replayed response of the job was recorded at
+func (archive *ResponseArchive) Now(job types.AssetJob) time.Time {
+	archive.mutex.Lock()
+	replaying := archive.replaying
+	archive.mutex.Unlock()
+	// Plugin jobs are not replayed, their output is as old as it is now
+	if !replaying || job.Plugin != nil {
+		return time.Now()
+	}
+	httpRequest, err := BuildJobRequest(job.Url, job.Request)
+	if err != nil {
+		return time.Now()
+	}
+	archive.mutex.Lock()
+	defer archive.mutex.Unlock()
+	if recordedAt, ok := archive.replayedAt[httpRequest.URL.String()]; ok {
+		return recordedAt
+	}
+	return time.Now()
+}
+
+//This function fetches the response of the request and records it, or returns its recorded response when replaying
+func (archive *ResponseArchive) RoundTrip(req *http.Request) (*http.Response, error) {
+	archive.mutex.Lock()
+	recording, replaying := archive.file != nil, archive.replaying
+	archive.mutex.Unlock()
+	if !recording && !replaying {
+		return archive.transport.RoundTrip(req)
+	}
+
+	var body []byte
+	if req.Body != nil {
+		var err error
+		body, err = io.ReadAll(req.Body)
+		req.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+		req.Body = io.NopCloser(bytes.NewReader(body))
+	}
+	key := getRecordedResponseKey(req, body)
+	if replaying {
+		return archive.replay(req, key)
+	}
+
+	response, err := archive.transport.RoundTrip(req)
+	if err != nil {
+		// Failed requests are not recorded, they fail in the replay as well
+		return nil, err
+	}
+	defer response.Body.Close()
+	responseBody, err := io.ReadAll(response.Body)
+	if err != nil {
+		return nil, err
+	}
+	archive.record(types.RecordedResponse{
+		Time:       time.Now(),
+		Method:     req.Method,
+		Url:        getRedactedURL(req.URL),
+		Key:        key,
+		StatusCode: response.StatusCode,
+		Status:     response.Status,
+		Header:     response.Header,
+		Body:       responseBody,
+	})
+	response.Body = io.NopCloser(bytes.NewReader(responseBody))
+	response.ContentLength = int64(len(responseBody))
+	return response, nil
+}
+
+//This function returns the key the response of the request is recorded with, a hash so that API keys are not recorded
+func getRecordedResponseKey(req *http.Request, body []byte) string {
+	hash := sha256.Sum256([]byte(getResponseCacheKey(req, body)))
+	return hex.EncodeToString(hash[:])
+}
+
+//This function appends the response to the recording of the epoch, a successful response is recorded once
+func (archive *ResponseArchive) record(response types.RecordedResponse) {
+	data, err := json.Marshal(response)
+	if err != nil {
+		log.Error("Error in recording response: ", err)
+		return
+	}
+	successful := response.StatusCode >= 200 && response.StatusCode <= 299
+	archive.mutex.Lock()
+	defer archive.mutex.Unlock()
+	if archive.file == nil || (successful && archive.recorded[response.Key]) {
+		return
+	}
+	if _, err := archive.file.Write(append(data, '\n')); err != nil {
+		log.Error("Error in recording response: ", err)
+		return
+	}
+	if successful {
+		archive.recorded[response.Key] = true
+	}
+}
+
+//This function returns the next recorded response of the request, the last one is returned again
+func (archive *ResponseArchive) replay(req *http.Request, key string) (*http.Response, error) {
+	archive.mutex.Lock()
+	defer archive.mutex.Unlock()
+	responses := archive.responses[key]
+	if len(responses) == 0 {
+		return nil, fmt.Errorf("no recorded response for %s %s", req.Method, getRedactedURL(req.URL))
+	}
+	index := archive.served[key]
+	if index >= len(responses) {
+		index = len(responses) - 1
+	}
+	archive.served[key] = index + 1
+	recorded := responses[index]
+	archive.replayedAt[req.URL.String()] = recorded.Time
+	return &http.Response{
+		Status:        recorded.Status,
+		StatusCode:    recorded.StatusCode,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        recorded.Header.Clone(),
+		Body:          io.NopCloser(bytes.NewReader(recorded.Body)),
+		ContentLength: int64(len(recorded.Body)),
+		Request:       req,
+	}, nil
+}
+
+//This function returns the responses recorded in the file
+func ReadRecordedResponses(file string) ([]types.RecordedResponse, error) {
+	recording, err := os.Open(file)
+	if errors.Is(err, os.ErrNotExist) {
+		// An epoch in which no job was fetched has no responses
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer recording.Close()
+
+	var responses []types.RecordedResponse
+	reader := bufio.NewReader(recording)
+	for line := 1; ; line++ {
+		data, err := reader.ReadBytes('\n')
+		if len(bytes.TrimSpace(data)) > 0 {
+			var response types.RecordedResponse
+			if err := json.Unmarshal(data, &response); err != nil {
+				// A response which was being written when the node stopped is left out
+				log.Warnf("Skipping invalid recorded response on line %d of %s: %s", line, file, err)
+			} else {
+				responses = append(responses, response)
+			}
+		}
+		if err == io.EOF {
+			return responses, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+}
+
+//This function returns the directory in which the responses of job requests are recorded
+func getRecordingsPath() (string, error) {
+	razorPath, err := path.PathUtilsInterface.GetDefaultPath()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(razorPath, "recordings"), nil
+}
+
+//This function records the responses of job requests of every epoch in the recordings directory
+func RecordResponses(retention uint32) error {
+	dir, err := getRecordingsPath()
+	if err != nil {
+		return err
+	}
+	return responseArchive.Record(dir, retention)
+}
+
+//This function replays the responses recorded in the epoch instead of fetching them
+func ReplayResponses(epoch uint32) error {
+	dir, err := getRecordingsPath()
+	if err != nil {
+		return err
+	}
+	dataString, err := responseArchive.Replay(dir, epoch)
+	if err != nil {
+		return err
+	}
+	assets, err := decodeAssets(dataString)
+	if err != nil {
+		return err
+	}
+	assetsFile.Load(assets)
+	return nil
+}
@@ -0,0 +1,357 @@
+package utils
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"razor/core/types"
+	"razor/pkg/bindings"
+	"razor/utils/mocks"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/avast/retry-go"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestResponseArchiveRecordAndReplay(t *testing.T) {
+	OS = &OSStruct{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		body, _ := io.ReadAll(r.Body)
+		io.WriteString(w, `{"path": "`+r.URL.Path+`", "body": "`+string(body)+`"}`)
+	}))
+	dir := t.TempDir()
+
+	cache := NewResponseCache(http.DefaultTransport)
+	cache.SetEpoch(5)
+	recorder := &ResponseArchive{transport: cache}
+	if err := recorder.Record(dir, 2); err != nil {
+		t.Fatal(err)
+	}
+	client := &http.Client{Transport: recorder}
+	// A response which is cached before the recording of the epoch starts is recorded when it is served from the cache
+	response, err := client.Post(server.URL+"/eth", "application/json", strings.NewReader("query"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	response.Body.Close()
+	recorder.SetEpoch(5, func() (string, error) { return `{"assets": {}}`, nil })
+	for _, path := range []string{"/eth", "/btc", "/btc"} {
+		response, err := client.Post(server.URL+path, "application/json", strings.NewReader("query"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		response.Body.Close()
+	}
+	// The next epoch is recorded separately
+	recorder.SetEpoch(6, func() (string, error) { return `{"assets": {}}`, nil })
+	server.Close()
+
+	replayer := &ResponseArchive{}
+	dataString, err := replayer.Replay(dir, 5)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if dataString != `{"assets": {}}` {
+		t.Errorf("Replay() returned assets.json %s", dataString)
+	}
+
+	tests := []struct {
+		name    string
+		path    string
+		body    string
+		want    string
+		wantErr bool
+	}{
+		{
+			name: "Test 1: When request was recorded",
+			path: "/eth",
+			body: "query",
+			want: `{"path": "/eth", "body": "query"}`,
+		},
+		{
+			name:    "Test 2: When request was recorded with another body",
+			path:    "/eth",
+			body:    "other query",
+			wantErr: true,
+		},
+		{
+			name:    "Test 3: When request was not recorded",
+			path:    "/sol",
+			body:    "query",
+			wantErr: true,
+		},
+	}
+	client = &http.Client{Transport: replayer}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			response, err := client.Post(server.URL+tt.path, "application/json", strings.NewReader(tt.body))
+			if (err != nil) != tt.wantErr {
+				t.Errorf("replayed request error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if err != nil {
+				return
+			}
+			defer response.Body.Close()
+			got, _ := io.ReadAll(response.Body)
+			if string(got) != tt.want {
+				t.Errorf("replayed response = %s, want %s", got, tt.want)
+			}
+		})
+	}
+
+	responses, err := ReadRecordedResponses(filepath.Join(dir, "5", "responses.jsonl"))
+	if err != nil || len(responses) != 2 {
+		t.Errorf("ReadRecordedResponses() recorded %d responses, want every response once", len(responses))
+	}
+	responses, err = ReadRecordedResponses(filepath.Join(dir, "6", "responses.jsonl"))
+	if err != nil || len(responses) != 0 {
+		t.Errorf("ReadRecordedResponses() of epoch without requests = %v, %v", responses, err)
+	}
+
+	// Only the recordings of the last 2 epochs are kept
+	recorder.SetEpoch(7, func() (string, error) { return `{"assets": {}}`, nil })
+	if _, err := os.Stat(getRecordingDir(dir, 5)); !os.IsNotExist(err) {
+		t.Errorf("Recording of epoch 5 is kept beyond the retention: %v", err)
+	}
+	if _, err := os.Stat(getRecordingDir(dir, 6)); err != nil {
+		t.Errorf("Recording of epoch 6 is removed within the retention: %v", err)
+	}
+}
+
+func TestResponseArchiveRecordAndReplayChain(t *testing.T) {
+	var client *ethclient.Client
+	dir := t.TempDir()
+	collection := bindings.StructsCollection{Active: true, Id: 3, Name: "ethCollection", JobIDs: []uint16{1}, Power: 2}
+	job := bindings.StructsJob{Id: 1, Url: "https://api.gemini.com/v1/pubticker/ethusd", Selector: "last"}
+	defer func() {
+		*responseArchive = ResponseArchive{transport: responseCache}
+		jobHealth = newJobHealthStore()
+	}()
+
+	utilsMock := new(mocks.Utils)
+	retryMock := new(mocks.RetryUtils)
+	assetManagerMock := new(mocks.AssetManagerUtils)
+	utils := StartRazor(OptionsPackageStruct{UtilsInterface: utilsMock, RetryInterface: retryMock, AssetManagerInterface: assetManagerMock, OS: &OSStruct{}})
+	retryMock.On("RetryAttempts", mock.AnythingOfType("uint")).Return(retry.Attempts(1))
+	assetManagerMock.On("GetCollectionIdFromIndex", mock.AnythingOfType("*ethclient.Client"), uint16(0)).Return(uint16(3), nil)
+	assetManagerMock.On("Jobs", mock.AnythingOfType("*ethclient.Client"), uint16(1)).Return(job, nil)
+	utilsMock.On("GetCollection", mock.AnythingOfType("*ethclient.Client"), uint16(3)).Return(collection, nil)
+	utilsMock.On("GetBlock", mock.AnythingOfType("*ethclient.Client"), uint32(4)).Return(bindings.StructsBlock{Ids: []uint16{3}, Medians: []*big.Int{big.NewInt(295050)}}, nil)
+	utilsMock.On("GetBlock", mock.AnythingOfType("*ethclient.Client"), uint32(3)).Return(bindings.StructsBlock{}, errors.New("no block"))
+	mockResponseArchive(utilsMock)
+
+	*responseArchive = ResponseArchive{transport: responseCache}
+	jobHealth = newJobHealthStore()
+	if err := responseArchive.Record(dir, 0); err != nil {
+		t.Fatal(err)
+	}
+	responseArchive.SetEpoch(5, func() (string, error) { return `{"assets": {}}`, nil })
+	jobHealth.Record("ethCollection", 4, []types.AssetJob{{StructsJob: job}}, nil)
+
+	if _, err := utils.GetCollectionIdFromIndex(client, 0); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := utils.GetActiveCollection(client, 3); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := utils.GetActiveJob(client, 1); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := utils.FetchPreviousValue(client, 4, 3); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := utils.FetchPreviousValue(client, 3, 3); err == nil {
+		t.Fatal("FetchPreviousValue() found a value in an epoch without block")
+	}
+	responseArchive.recordCollectionState(collection)
+	// Only the state the collection is first aggregated with in the epoch is recorded
+	jobHealth.Record("ethCollection", 5, []types.AssetJob{{StructsJob: job}}, []types.JobData{{Job: types.AssetJob{StructsJob: job}, Value: big.NewInt(295050)}})
+	responseArchive.recordCollectionState(collection)
+
+	// Nothing is read from the chain while replaying
+	replayMock := new(mocks.Utils)
+	replayAssetManagerMock := new(mocks.AssetManagerUtils)
+	utils = StartRazor(OptionsPackageStruct{UtilsInterface: replayMock, RetryInterface: retryMock, AssetManagerInterface: replayAssetManagerMock})
+	mockResponseArchive(replayMock)
+	*responseArchive = ResponseArchive{transport: responseCache}
+	jobHealth = newJobHealthStore()
+	jobHealth.file = filepath.Join(dir, "job_health.json")
+	if _, err := responseArchive.Replay(dir, 5); err != nil {
+		t.Fatal(err)
+	}
+
+	if got, err := utils.GetCollectionIdFromIndex(client, 0); err != nil || got != 3 {
+		t.Errorf("GetCollectionIdFromIndex() = %d, %v, want recorded id 3", got, err)
+	}
+	if _, err := utils.GetCollectionIdFromIndex(client, 1); err == nil {
+		t.Error("GetCollectionIdFromIndex() returned an id which is not recorded")
+	}
+	if got, err := utils.GetActiveCollection(client, 3); err != nil || !reflect.DeepEqual(got, collection) {
+		t.Errorf("GetActiveCollection() = %+v, %v, want recorded collection", got, err)
+	}
+	if got, err := utils.GetActiveJob(client, 1); err != nil || !reflect.DeepEqual(got, job) {
+		t.Errorf("GetActiveJob() = %+v, %v, want recorded job", got, err)
+	}
+	if got, err := utils.FetchPreviousValue(client, 4, 3); err != nil || got.Cmp(big.NewInt(295050)) != 0 {
+		t.Errorf("FetchPreviousValue() = %v, %v, want recorded median 295050", got, err)
+	}
+	if _, err := utils.FetchPreviousValue(client, 3, 3); err == nil || err.Error() != "no block" {
+		t.Errorf("FetchPreviousValue() error = %v, want recorded error", err)
+	}
+	if _, err := utils.FetchPreviousValue(client, 2, 3); err == nil {
+		t.Error("FetchPreviousValue() returned a median which is not recorded")
+	}
+
+	restoreCollectionState(responseArchive.replayedChain(), collection)
+	if health := jobHealth.Jobs["ethCollection/#1"]; health == nil || health.Epochs != 1 || health.Failures != 1 || health.LastEpoch != 4 {
+		t.Errorf("restored job health = %+v, want the health before the collection was aggregated in epoch 5", health)
+	}
+	jobHealth.changed = true
+	jobHealth.save()
+	if _, err := os.Stat(jobHealth.file); !os.IsNotExist(err) {
+		t.Errorf("job health is written while replaying: %v", err)
+	}
+
+	// Recordings without the values read from the chain cannot be replayed
+	if err := os.Remove(filepath.Join(getRecordingDir(dir, 5), "chain.json")); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := (&ResponseArchive{}).Replay(dir, 5); err == nil {
+		t.Error("Replay() replayed a recording without the values read from the chain")
+	}
+}
+
+//This function stubs the chain recording methods of utilsMock with the ones of the response archive
+func mockResponseArchive(utilsMock *mocks.Utils) {
+	utils := &UtilsStruct{}
+	utilsMock.On("GetReplayedChain").Return(utils.GetReplayedChain)
+	utilsMock.On("RecordChain", mock.Anything).Run(func(args mock.Arguments) {
+		utils.RecordChain(args.Get(0).(func(chain *types.RecordedChain)))
+	})
+}
+
+func TestReadRecordedResponses(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "responses.jsonl")
+	recorded := types.RecordedResponse{Method: "GET", Url: "https://api.gemini.com/v1/pubticker/ethusd", Key: "key", StatusCode: 200, Body: []byte(`{"last": "1234.5"}`)}
+	data, _ := json.Marshal(recorded)
+	// The last response was being written when the node stopped
+	if err := os.WriteFile(file, append(append(data, '\n'), data[:20]...), 0600); err != nil {
+		t.Fatal(err)
+	}
+	got, err := ReadRecordedResponses(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(got, []types.RecordedResponse{recorded}) {
+		t.Errorf("ReadRecordedResponses() = %+v, want %+v", got, recorded)
+	}
+}
+
+func TestGetDataToCommitFromJobWithReplay(t *testing.T) {
+	// A capture of a response recorded long ago, whose timestamp was fresh when it was recorded
+	recordedAt := time.Date(2022, 3, 1, 10, 0, 30, 0, time.UTC)
+	job := types.AssetJob{
+		StructsJob: bindings.StructsJob{Id: 1, SelectorType: 0, Weight: 100, Power: 2, Name: "ethusd_gemini", Selector: "last",
+			Url: "https://api.gemini.com/v1/pubticker/ethusd",
+		},
+		Request:   types.JobRequest{Attempts: 1},
+		Freshness: &types.JobFreshness{TimestampSelector: "volume.timestamp", MaxAge: time.Minute},
+	}
+	request, _ := http.NewRequest(http.MethodGet, job.Url, nil)
+	dir := t.TempDir()
+	writeTestRecording(t, dir, 5, []types.RecordedResponse{{
+		Time:       recordedAt,
+		Method:     http.MethodGet,
+		Url:        job.Url,
+		Key:        getRecordedResponseKey(request, nil),
+		StatusCode: 200,
+		Status:     "200 OK",
+		Header:     http.Header{"Content-Type": []string{"application/json"}},
+		Body:       []byte(`{"last": "2950.5", "volume": {"timestamp": 1646128800000}}`),
+	}})
+
+	// A response recorded later whose timestamp was stale when it was recorded, although it is younger than the response above
+	staleJob := job
+	staleJob.Url = "https://api.gemini.com/v1/pubticker/btcusd"
+	staleRequest, _ := http.NewRequest(http.MethodGet, staleJob.Url, nil)
+	writeTestRecording(t, dir, 6, []types.RecordedResponse{{
+		Time:       recordedAt,
+		Method:     http.MethodGet,
+		Url:        job.Url,
+		Key:        getRecordedResponseKey(request, nil),
+		StatusCode: 200,
+		Status:     "200 OK",
+		Header:     http.Header{"Content-Type": []string{"application/json"}},
+		Body:       []byte(`{"last": "2950.5", "volume": {"timestamp": 1646128800000}}`),
+	}, {
+		Time:       recordedAt.Add(10 * time.Minute),
+		Method:     http.MethodGet,
+		Url:        staleJob.Url,
+		Key:        getRecordedResponseKey(staleRequest, nil),
+		StatusCode: 200,
+		Status:     "200 OK",
+		Header:     http.Header{"Content-Type": []string{"application/json"}},
+		Body:       []byte(`{"last": "40000.5", "volume": {"timestamp": 1646129100000}}`),
+	}})
+
+	StartRazor(OptionsPackageStruct{UtilsInterface: &UtilsStruct{}, RetryInterface: RetryStruct{}, IoutilInterface: IoutilStruct{}})
+	defer func() { *responseArchive = ResponseArchive{transport: responseCache} }()
+	if _, err := responseArchive.Replay(dir, 5); err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := UtilsInterface.GetDataToCommitFromJob(job)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Cmp(big.NewInt(295050)) != 0 {
+		t.Errorf("GetDataToCommitFromJob() = %s, want 295050", got)
+	}
+
+	// The age of every response is found from the time it was recorded at
+	if _, err := responseArchive.Replay(dir, 6); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := UtilsInterface.GetDataToCommitFromJob(job); err != nil {
+		t.Errorf("GetDataToCommitFromJob() error = %v, want the response recorded first to be fresh", err)
+	}
+	if _, err := UtilsInterface.GetDataToCommitFromJob(staleJob); err == nil {
+		t.Error("GetDataToCommitFromJob() used a response which was stale when it was recorded")
+	}
+}
+
+//This function writes the responses as the recording of the epoch in the directory
+func writeTestRecording(t *testing.T, dir string, epoch uint32, responses []types.RecordedResponse) {
+	recordingDir := getRecordingDir(dir, epoch)
+	if err := os.MkdirAll(recordingDir, 0700); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(recordingDir, "assets.json"), []byte(`{"assets": {}}`), 0600); err != nil {
+		t.Fatal(err)
+	}
+	var lines []string
+	for _, response := range responses {
+		data, err := json.Marshal(response)
+		if err != nil {
+			t.Fatal(err)
+		}
+		lines = append(lines, string(data))
+	}
+	if err := os.WriteFile(filepath.Join(recordingDir, "responses.jsonl"), []byte(strings.Join(lines, "\n")+"\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(recordingDir, "chain.json"), []byte(`{}`), 0600); err != nil {
+		t.Fatal(err)
+	}
+}
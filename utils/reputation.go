@@ -0,0 +1,423 @@
+//Package utils provides the utils functions
+package utils
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"math/big"
+	"os"
+	"path/filepath"
+	"razor/core"
+	"razor/core/types"
+	"razor/metrics"
+	"razor/path"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/ethclient"
+)
+
+type pendingValues struct {
+	Epoch  uint32              `json:"epoch"`
+	Values map[string]*big.Int `json:"values"`
+}
+
+//JobHealthStore keeps the failures and deviations from the confirmed medians of the jobs
+type JobHealthStore struct {
+	mutex   sync.Mutex
+	file    string
+	epoch   uint32
+	changed bool
+	Jobs    map[string]*types.JobHealth `json:"jobs"`
+	Pending map[string]*pendingValues   `json:"pending"` // values waiting for the confirmed median of their epoch
+}
+
+// The health of jobs is only kept in memory until it is loaded from its file
+var jobHealth = newJobHealthStore()
+
+//This function returns an empty job health store
+func newJobHealthStore() *JobHealthStore {
+	return &JobHealthStore{
+		Jobs:    make(map[string]*types.JobHealth),
+		Pending: make(map[string]*pendingValues),
+	}
+}
+
+//This function returns the key of a job within its collection
+func getJobHealthKey(job types.AssetJob) string {
+	if job.Id != 0 {
+		return "#" + strconv.Itoa(int(job.Id))
+	}
+	if job.Name != "" {
+		return job.Name
+	}
+	// Unnamed jobs often select different values from the same URL
+	return job.Url + " " + getSelectorTypeName(job.SelectorType) + " " + job.Selector
+}
+
+//This function returns the file in which the health of jobs is kept
+func getJobHealthFilePath() (string, error) {
+	razorPath, err := path.PathUtilsInterface.GetDefaultPath()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(razorPath, "job_health.json"), nil
+}
+
+//This function loads the health of jobs from its file, which is then updated once per epoch
+func LoadJobHealth() error {
+	file, err := getJobHealthFilePath()
+	if err != nil {
+		return err
+	}
+	store, err := readJobHealthStore(file)
+	if err != nil {
+		return err
+	}
+	jobHealth.mutex.Lock()
+	defer jobHealth.mutex.Unlock()
+	jobHealth.file = file
+	jobHealth.Jobs = store.Jobs
+	jobHealth.Pending = store.Pending
+	return nil
+}
+
+//This function returns the health of every job kept in its file, sorted by collection and job
+func ReadJobHealth() ([]types.JobHealth, error) {
+	file, err := getJobHealthFilePath()
+	if err != nil {
+		return nil, err
+	}
+	store, err := readJobHealthStore(file)
+	if err != nil {
+		return nil, err
+	}
+	return store.report(), nil
+}
+
+//This function reads the job health store from the file, the store is empty if the file does not exist
+func readJobHealthStore(file string) (*JobHealthStore, error) {
+	store := newJobHealthStore()
+	data, err := os.ReadFile(file)
+	if errors.Is(err, os.ErrNotExist) {
+		return store, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(data, store); err != nil {
+		return nil, fmt.Errorf("invalid job health file %s: %s", file, err)
+	}
+	if store.Jobs == nil {
+		store.Jobs = make(map[string]*types.JobHealth)
+	}
+	if store.Pending == nil {
+		store.Pending = make(map[string]*pendingValues)
+	}
+	return store, nil
+}
+
+//This function writes the store to its file, so that the health of jobs is kept across restarts
+func (store *JobHealthStore) save() {
+	// The health of jobs is restored from the recording when an epoch is replayed, so it is never written then
+	if store.file == "" || !store.changed || responseArchive.isReplaying() {
+		return
+	}
+	data, err := json.Marshal(store)
+	if err != nil {
+		log.Error("Error in encoding job health: ", err)
+		return
+	}
+	if err := WriteFileAtomically(store.file, data, 0600); err != nil {
+		log.Error("Error in writing job health: ", err)
+		return
+	}
+	store.changed = false
+}
+
+//This function sets the epoch of the store and writes the changes of the previous epoch
+func (store *JobHealthStore) SetEpoch(epoch uint32) {
+	store.mutex.Lock()
+	defer store.mutex.Unlock()
+	if store.epoch == epoch {
+		return
+	}
+	store.epoch = epoch
+	store.save()
+}
+
+//This function returns the health of the job of the collection, which is created if it is not known yet
+func (store *JobHealthStore) get(collection string, job types.AssetJob) *types.JobHealth {
+	key := getJobHealthKey(job)
+	health, ok := store.Jobs[collection+"/"+key]
+	if !ok {
+		health = &types.JobHealth{Collection: collection, Job: key, WeightFactor: 1}
+		store.Jobs[collection+"/"+key] = health
+	}
+	health.Url = job.Url
+	return health
+}
+
+//This function returns a copy of the health of the jobs of the collection
+func (store *JobHealthStore) snapshot(collection string) map[string]types.JobHealth {
+	store.mutex.Lock()
+	defer store.mutex.Unlock()
+	healths := make(map[string]types.JobHealth)
+	for key, health := range store.Jobs {
+		if strings.HasPrefix(key, collection+"/") {
+			healths[key] = *health
+		}
+	}
+	return healths
+}
+
+//This function replaces the health of the jobs of the collection with the health in the snapshot
+func (store *JobHealthStore) restore(collection string, healths map[string]types.JobHealth) {
+	store.mutex.Lock()
+	defer store.mutex.Unlock()
+	for key := range store.Jobs {
+		if strings.HasPrefix(key, collection+"/") {
+			delete(store.Jobs, key)
+		}
+	}
+	for key, health := range healths {
+		if strings.HasPrefix(key, collection+"/") {
+			health := health
+			store.Jobs[key] = &health
+		}
+	}
+	delete(store.Pending, collection)
+}
+
+//This function records the failures and values of the jobs of the collection in the epoch
+func (store *JobHealthStore) Record(collection string, epoch uint32, jobs []types.AssetJob, jobsData []types.JobData) {
+	store.mutex.Lock()
+	defer store.mutex.Unlock()
+
+	values := make(map[string]*big.Int)
+	for _, jobData := range jobsData {
+		values[getJobHealthKey(jobData.Job)] = jobData.Value
+	}
+	pending, ok := store.Pending[collection]
+	if !ok || pending.Epoch != epoch {
+		pending = &pendingValues{Epoch: epoch, Values: make(map[string]*big.Int)}
+		store.Pending[collection] = pending
+	}
+	for _, job := range jobs {
+		health := store.get(collection, job)
+		if health.LastEpoch == epoch && health.Epochs > 0 {
+			// Jobs are only counted once in an epoch, e.g. when a collection is tested while voting
+			continue
+		}
+		health.LastEpoch = epoch
+		health.Epochs++
+		failure := 0.0
+		value, ok := values[health.Job]
+		if ok {
+			pending.Values[health.Job] = value
+		} else {
+			health.Failures++
+			failure = 100
+		}
+		health.FailureRate = movingAverage(health.FailureRate, failure, health.Epochs)
+	}
+	store.changed = true
+}
+
+//This function returns the epoch of the pending values of the collection
+func (store *JobHealthStore) PendingEpoch(collection string) (uint32, bool) {
+	store.mutex.Lock()
+	defer store.mutex.Unlock()
+	pending, ok := store.Pending[collection]
+	if !ok {
+		return 0, false
+	}
+	return pending.Epoch, true
+}
+
+//This function compares the pending values of the jobs of the collection with its confirmed median
+func (store *JobHealthStore) Settle(collection string, epoch uint32, median *big.Int) {
+	store.mutex.Lock()
+	defer store.mutex.Unlock()
+	pending, ok := store.Pending[collection]
+	if !ok || pending.Epoch != epoch {
+		return
+	}
+	delete(store.Pending, collection)
+	if median != nil && median.Sign() != 0 {
+		medianFloat := new(big.Float).SetInt(median)
+		for key, value := range pending.Values {
+			health, ok := store.Jobs[collection+"/"+key]
+			if !ok || value == nil {
+				continue
+			}
+			difference := new(big.Float).Sub(new(big.Float).SetInt(value), medianFloat)
+			deviation, _ := new(big.Float).Quo(difference.Abs(difference), new(big.Float).Abs(medianFloat)).Float64()
+			health.Samples++
+			health.Deviation = movingAverage(health.Deviation, deviation*100, health.Samples)
+			metrics.JobDeviationMetric.WithLabelValues(collection, key).Set(health.Deviation)
+		}
+	}
+	store.changed = true
+}
+
+//This function down-weights or excludes the unreliable jobs of the collection
+func (store *JobHealthStore) ApplyReputation(collection string, jobsData []types.JobData, reputation types.Reputation) []types.JobData {
+	store.mutex.Lock()
+	defer store.mutex.Unlock()
+	reputation = getReputationWithDefaults(reputation)
+
+	healths := make([]*types.JobHealth, len(jobsData))
+	var excluded []int
+	for i, jobData := range jobsData {
+		healths[i] = store.get(collection, jobData.Job)
+		healths[i].WeightFactor, healths[i].Status, healths[i].Reason = getJobWeightFactor(*healths[i], reputation)
+		if healths[i].WeightFactor == 0 {
+			excluded = append(excluded, i)
+		}
+	}
+	// The healthiest of the excluded jobs are kept so that the collection keeps its min jobs
+	sort.SliceStable(excluded, func(a, b int) bool {
+		return healths[excluded[a]].Deviation+healths[excluded[a]].FailureRate < healths[excluded[b]].Deviation+healths[excluded[b]].FailureRate
+	})
+	for _, i := range excluded {
+		if len(jobsData)-countExcluded(healths) >= reputation.MinJobs {
+			break
+		}
+		// A job kept with a min weight of 0 still gets the lowest weight, as a factor of 0 would exclude it
+		healths[i].WeightFactor = math.Max(*reputation.MinWeight/100, 0.01)
+		healths[i].Status = "down-weighted"
+		healths[i].Reason += fmt.Sprintf(", kept as collection needs %d jobs", reputation.MinJobs)
+	}
+
+	var weightedJobsData []types.JobData
+	for i, jobData := range jobsData {
+		metrics.JobWeightFactorMetric.WithLabelValues(collection, healths[i].Job).Set(healths[i].WeightFactor)
+		if healths[i].WeightFactor == 0 {
+			log.Warnf("Excluding job %s of collection %s: %s", healths[i].Job, collection, healths[i].Reason)
+			continue
+		}
+		if healths[i].WeightFactor < 1 && jobData.Job.Weight > 0 {
+			weight := uint8(math.Max(1, math.Round(float64(jobData.Job.Weight)*healths[i].WeightFactor)))
+			log.Infof("Down-weighting job %s of collection %s from %d to %d: %s", healths[i].Job, collection, jobData.Job.Weight, weight, healths[i].Reason)
+			jobData.Job.Weight = weight
+		}
+		weightedJobsData = append(weightedJobsData, jobData)
+	}
+	store.changed = true
+	return weightedJobsData
+}
+
+//This function returns the health of every job, sorted by collection and job
+func (store *JobHealthStore) report() []types.JobHealth {
+	var report []types.JobHealth
+	for _, health := range store.Jobs {
+		report = append(report, *health)
+	}
+	sort.Slice(report, func(i, j int) bool {
+		if report[i].Collection != report[j].Collection {
+			return report[i].Collection < report[j].Collection
+		}
+		return report[i].Job < report[j].Job
+	})
+	return report
+}
+
+//This function returns the weight factor of the job with its status and reason
+func getJobWeightFactor(health types.JobHealth, reputation types.Reputation) (float64, string, string) {
+	if int(health.Epochs) < reputation.MinSamples {
+		return 1, "learning", fmt.Sprintf("fetched in %d of %d epochs needed", health.Epochs, reputation.MinSamples)
+	}
+	if health.FailureRate > reputation.MaxFailureRate {
+		return 0, "excluded", fmt.Sprintf("fails %.1f%% of epochs", health.FailureRate)
+	}
+	minWeight := *reputation.MinWeight
+	factor := 1 - health.FailureRate/reputation.MaxFailureRate*(1-minWeight/100)
+	reason := fmt.Sprintf("fails %.1f%% of epochs", health.FailureRate)
+	if int(health.Samples) >= reputation.MinSamples {
+		if health.Deviation > reputation.MaxDeviation {
+			return 0, "excluded", fmt.Sprintf("deviates %.2f%% from median", health.Deviation)
+		}
+		deviationFactor := 1 - health.Deviation/reputation.MaxDeviation*(1-minWeight/100)
+		if deviationFactor < factor {
+			factor = deviationFactor
+			reason = fmt.Sprintf("deviates %.2f%% from median", health.Deviation)
+		}
+	}
+	// Factors are rounded so that a job which is almost always right keeps its weight
+	factor = math.Round(factor*100) / 100
+	if factor >= 1 {
+		return 1, "ok", ""
+	}
+	return factor, "down-weighted", reason
+}
+
+//This function returns the reputation with defaults for the bounds which are not set
+func getReputationWithDefaults(reputation types.Reputation) types.Reputation {
+	if reputation.MaxDeviation <= 0 {
+		reputation.MaxDeviation = core.DefaultReputationMaxDeviation
+	}
+	if reputation.MaxFailureRate <= 0 {
+		reputation.MaxFailureRate = core.DefaultReputationMaxFailureRate
+	}
+	if reputation.MinWeight == nil {
+		minWeight := core.DefaultReputationMinWeight
+		reputation.MinWeight = &minWeight
+	}
+	if reputation.MinJobs <= 0 {
+		reputation.MinJobs = core.DefaultReputationMinJobs
+	}
+	if reputation.MinSamples <= 0 {
+		reputation.MinSamples = core.DefaultReputationMinSamples
+	}
+	return reputation
+}
+
+//This function returns the number of excluded jobs
+func countExcluded(healths []*types.JobHealth) int {
+	count := 0
+	for _, health := range healths {
+		if health.WeightFactor == 0 {
+			count++
+		}
+	}
+	return count
+}
+
+//This function returns the moving average with the new value
+func movingAverage(average float64, value float64, count uint32) float64 {
+	weight := core.JobHealthDecay
+	if plainWeight := 1 / float64(count); plainWeight > weight {
+		weight = plainWeight
+	}
+	return average + weight*(value-average)
+}
+
+//This function records the health of the jobs of the collection in the epoch
+func (*UtilsStruct) RecordJobHealth(collectionName string, epoch uint32, jobs []types.AssetJob, jobsData []types.JobData) {
+	jobHealth.Record(collectionName, epoch, jobs, jobsData)
+}
+
+//This function applies the reputation of the collection to the data of its jobs
+func (*UtilsStruct) ApplyJobReputation(collectionName string, jobsData []types.JobData, reputation types.Reputation) []types.JobData {
+	return jobHealth.ApplyReputation(collectionName, jobsData, reputation)
+}
+
+//This function compares the pending values of the jobs of the collection with the confirmed median of their epoch
+func (*UtilsStruct) SettleJobHealth(client *ethclient.Client, previousEpoch uint32, collection string, collectionId uint16) {
+	// Collections are not assigned in every epoch, so the pending values can be of any earlier epoch
+	pendingEpoch, ok := jobHealth.PendingEpoch(collection)
+	if !ok || pendingEpoch > previousEpoch {
+		return
+	}
+	median, err := UtilsInterface.FetchPreviousValue(client, pendingEpoch, collectionId)
+	if err != nil {
+		log.Debugf("No confirmed median of collection %s in epoch %d to compare its jobs with: %s", collection, pendingEpoch, err)
+		median = nil
+	}
+	jobHealth.Settle(collection, pendingEpoch, median)
+}
@@ -0,0 +1,332 @@
+package utils
+
+import (
+	"errors"
+	"math/big"
+	"razor/core/types"
+	"razor/path"
+	pathMocks "razor/path/mocks"
+	"razor/pkg/bindings"
+	"razor/utils/mocks"
+	"reflect"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestGetJobWeightFactor(t *testing.T) {
+	reputation := getReputationWithDefaults(types.Reputation{})
+	tests := []struct {
+		name       string
+		health     types.JobHealth
+		wantFactor float64
+		wantStatus string
+	}{
+		{
+			name:       "Test 1: When job has been fetched in fewer epochs than min samples",
+			health:     types.JobHealth{Epochs: 3, FailureRate: 100},
+			wantFactor: 1,
+			wantStatus: "learning",
+		},
+		{
+			name:       "Test 2: When job never fails and is close to the median",
+			health:     types.JobHealth{Epochs: 10, Samples: 10, Deviation: 0.02},
+			wantFactor: 1,
+			wantStatus: "ok",
+		},
+		{
+			name:       "Test 3: When job deviates from the median",
+			health:     types.JobHealth{Epochs: 10, Samples: 10, Deviation: 2.5},
+			wantFactor: 0.63,
+			wantStatus: "down-weighted",
+		},
+		{
+			name:       "Test 4: When job fails often",
+			health:     types.JobHealth{Epochs: 10, Samples: 5, FailureRate: 25, Deviation: 0.1},
+			wantFactor: 0.63,
+			wantStatus: "down-weighted",
+		},
+		{
+			name:       "Test 5: When job deviates more than max deviation",
+			health:     types.JobHealth{Epochs: 10, Samples: 10, Deviation: 7},
+			wantFactor: 0,
+			wantStatus: "excluded",
+		},
+		{
+			name:       "Test 6: When job fails more than max failure rate",
+			health:     types.JobHealth{Epochs: 10, FailureRate: 60},
+			wantFactor: 0,
+			wantStatus: "excluded",
+		},
+		{
+			name:       "Test 7: When deviation of job has fewer samples than min samples",
+			health:     types.JobHealth{Epochs: 10, Samples: 2, Deviation: 7},
+			wantFactor: 1,
+			wantStatus: "ok",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			factor, status, _ := getJobWeightFactor(tt.health, reputation)
+			if factor != tt.wantFactor || status != tt.wantStatus {
+				t.Errorf("getJobWeightFactor() = %v, %s, want %v, %s", factor, status, tt.wantFactor, tt.wantStatus)
+			}
+		})
+	}
+}
+
+func TestGetJobHealthKey(t *testing.T) {
+	tests := []struct {
+		name string
+		job  types.AssetJob
+		want string
+	}{
+		{
+			name: "Test 1: When job is an official job",
+			job:  types.AssetJob{StructsJob: bindings.StructsJob{Id: 1, Name: "ethusd_gemini", Url: "https://api.gemini.com/v1/pubticker/ethusd"}},
+			want: "#1",
+		},
+		{
+			name: "Test 2: When job has a name",
+			job:  types.AssetJob{StructsJob: bindings.StructsJob{Name: "eth_coinbase", Url: "https://api.coinbase.com/v2/prices/ETH-USD/spot"}},
+			want: "eth_coinbase",
+		},
+		{
+			name: "Test 3: When job has no name",
+			job:  types.AssetJob{StructsJob: bindings.StructsJob{Url: "https://api.gemini.com/v1/pubticker/ethusd", Selector: "last", SelectorType: 5}},
+			want: "https://api.gemini.com/v1/pubticker/ethusd gjson last",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := getJobHealthKey(tt.job); got != tt.want {
+				t.Errorf("getJobHealthKey() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+	// Unnamed jobs selecting different values from the same URL have their own health
+	bid := types.AssetJob{StructsJob: bindings.StructsJob{Url: "https://api.gemini.com/v1/pubticker/ethusd", Selector: "bid", SelectorType: 5}}
+	ask := types.AssetJob{StructsJob: bindings.StructsJob{Url: "https://api.gemini.com/v1/pubticker/ethusd", Selector: "ask", SelectorType: 5}}
+	if getJobHealthKey(bid) == getJobHealthKey(ask) {
+		t.Errorf("getJobHealthKey() is the same for jobs with different selectors")
+	}
+}
+
+func TestJobHealthStoreApplyReputation(t *testing.T) {
+	minWeight, noMinWeight := 10.0, 0.0
+	jobs := []types.AssetJob{
+		{StructsJob: bindings.StructsJob{Id: 1, Url: "https://api.gemini.com/v1/pubticker/ethusd", Weight: 100}},
+		{StructsJob: bindings.StructsJob{Id: 2, Url: "https://api.kraken.com/0/public/Ticker?pair=ETHUSD", Weight: 100}},
+		{StructsJob: bindings.StructsJob{Name: "eth_coinbase", Url: "https://api.coinbase.com/v2/prices/ETH-USD/spot", Weight: 100}},
+	}
+	var jobsData []types.JobData
+	for _, job := range jobs {
+		jobsData = append(jobsData, types.JobData{Job: job, Value: big.NewInt(100000)})
+	}
+
+	tests := []struct {
+		name        string
+		healths     []types.JobHealth
+		reputation  types.Reputation
+		wantWeights []uint8
+		wantStatus  []string
+	}{
+		{
+			name: "Test 1: When every job is healthy",
+			healths: []types.JobHealth{
+				{Epochs: 10, Samples: 10},
+				{Epochs: 10, Samples: 10},
+				{Epochs: 10, Samples: 10},
+			},
+			wantWeights: []uint8{100, 100, 100},
+			wantStatus:  []string{"ok", "ok", "ok"},
+		},
+		{
+			name: "Test 2: When one job is down-weighted and another is excluded",
+			healths: []types.JobHealth{
+				{Epochs: 10, Samples: 10, Deviation: 2.5},
+				{Epochs: 10, Samples: 10, Deviation: 10},
+				{Epochs: 10, Samples: 10},
+			},
+			wantWeights: []uint8{63, 100},
+			wantStatus:  []string{"down-weighted", "excluded", "ok"},
+		},
+		{
+			name: "Test 3: When excluding jobs would leave fewer than min jobs",
+			healths: []types.JobHealth{
+				{Epochs: 10, Samples: 10, Deviation: 20},
+				{Epochs: 10, Samples: 10, Deviation: 10},
+				{Epochs: 10, Samples: 10},
+			},
+			reputation:  types.Reputation{MinJobs: 2, MinWeight: &minWeight},
+			wantWeights: []uint8{10, 100},
+			wantStatus:  []string{"excluded", "down-weighted", "ok"},
+		},
+		{
+			name: "Test 4: When min weight is 0",
+			healths: []types.JobHealth{
+				{Epochs: 10, Samples: 10, Deviation: 2.5},
+				{Epochs: 10, Samples: 10, Deviation: 10},
+				{Epochs: 10, Samples: 10},
+			},
+			reputation:  types.Reputation{MinJobs: 3, MinWeight: &noMinWeight},
+			wantWeights: []uint8{50, 1, 100},
+			wantStatus:  []string{"down-weighted", "down-weighted", "ok"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store := newJobHealthStore()
+			for i := range tt.healths {
+				health := tt.healths[i]
+				health.Collection = "ethCollection"
+				health.Job = getJobHealthKey(jobs[i])
+				store.Jobs["ethCollection/"+health.Job] = &health
+			}
+			got := store.ApplyReputation("ethCollection", jobsData, tt.reputation)
+			var weights []uint8
+			for _, jobData := range got {
+				weights = append(weights, jobData.Job.Weight)
+			}
+			var status []string
+			for _, job := range jobs {
+				status = append(status, store.Jobs["ethCollection/"+getJobHealthKey(job)].Status)
+			}
+			if !reflect.DeepEqual(weights, tt.wantWeights) || !reflect.DeepEqual(status, tt.wantStatus) {
+				t.Errorf("ApplyReputation() weights = %v, status = %v, want %v, %v", weights, status, tt.wantWeights, tt.wantStatus)
+			}
+			if jobsData[0].Job.Weight != 100 {
+				t.Errorf("ApplyReputation() changed the weight of the jobs it was given")
+			}
+		})
+	}
+}
+
+func TestSettleJobHealth(t *testing.T) {
+	var client *ethclient.Client
+	jobs := []types.AssetJob{
+		{StructsJob: bindings.StructsJob{Id: 1, Url: "https://api.gemini.com/v1/pubticker/ethusd", Weight: 100}},
+		{StructsJob: bindings.StructsJob{Id: 2, Url: "https://api.kraken.com/0/public/Ticker?pair=ETHUSD", Weight: 100}},
+		{StructsJob: bindings.StructsJob{Name: "eth_coinbase", Url: "https://api.coinbase.com/v2/prices/ETH-USD/spot", Weight: 100}},
+	}
+	jobsData := []types.JobData{
+		{Job: jobs[0], Value: big.NewInt(101000)},
+		{Job: jobs[2], Value: big.NewInt(99000)},
+	}
+
+	tests := []struct {
+		name          string
+		previousEpoch uint32
+		median        *big.Int
+		medianErr     error
+		wantFetches   int
+		wantDeviation []float64
+		wantSamples   []uint32
+	}{
+		{
+			name:          "Test 1: When values of jobs are compared with the confirmed median",
+			previousEpoch: 5,
+			median:        big.NewInt(100000),
+			wantFetches:   1,
+			wantDeviation: []float64{1, 0, 1},
+			wantSamples:   []uint32{1, 0, 1},
+		},
+		{
+			name:          "Test 2: When there is no confirmed median",
+			previousEpoch: 5,
+			medianErr:     errors.New("no block"),
+			wantFetches:   1,
+			wantDeviation: []float64{0, 0, 0},
+			wantSamples:   []uint32{0, 0, 0},
+		},
+		{
+			name:          "Test 3: When the collection is not aggregated for epochs after its values were recorded",
+			previousEpoch: 8,
+			median:        big.NewInt(100000),
+			wantFetches:   1,
+			wantDeviation: []float64{1, 0, 1},
+			wantSamples:   []uint32{1, 0, 1},
+		},
+		{
+			name:          "Test 4: When the values are of an epoch after the previous epoch",
+			previousEpoch: 4,
+			median:        big.NewInt(100000),
+			wantFetches:   0,
+			wantDeviation: []float64{0, 0, 0},
+			wantSamples:   []uint32{0, 0, 0},
+		},
+	}
+	defer func() { jobHealth = newJobHealthStore() }()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			utilsMock := new(mocks.Utils)
+			utils := StartRazor(OptionsPackageStruct{UtilsInterface: utilsMock})
+			utilsMock.On("FetchPreviousValue", mock.Anything, uint32(5), uint16(1)).Return(tt.median, tt.medianErr)
+
+			jobHealth = newJobHealthStore()
+			jobHealth.Record("ethCollection", 5, jobs, jobsData)
+			// Jobs are only counted once in an epoch
+			jobHealth.Record("ethCollection", 5, jobs, jobsData)
+			utils.SettleJobHealth(client, tt.previousEpoch, "ethCollection", 1)
+			utils.SettleJobHealth(client, tt.previousEpoch, "ethCollection", 1)
+
+			// The median is always of the epoch the values were recorded in
+			utilsMock.AssertNumberOfCalls(t, "FetchPreviousValue", tt.wantFetches)
+			for i, job := range jobs {
+				health := jobHealth.Jobs["ethCollection/"+getJobHealthKey(job)]
+				if health.Epochs != 1 || health.Deviation != tt.wantDeviation[i] || health.Samples != tt.wantSamples[i] {
+					t.Errorf("job %d has epochs = %d, deviation = %v, samples = %d, want 1, %v, %d", i, health.Epochs, health.Deviation, health.Samples, tt.wantDeviation[i], tt.wantSamples[i])
+				}
+			}
+			if failed := jobHealth.Jobs["ethCollection/#2"]; failed.Failures != 1 || failed.FailureRate != 100 {
+				t.Errorf("failed job has failures = %d, failure rate = %v, want 1, 100", failed.Failures, failed.FailureRate)
+			}
+		})
+	}
+}
+
+func TestLoadAndReadJobHealth(t *testing.T) {
+	OS = &OSStruct{}
+	dir := t.TempDir()
+	pathUtilsMock := new(pathMocks.PathInterface)
+	path.PathUtilsInterface = pathUtilsMock
+	pathUtilsMock.On("GetDefaultPath").Return(dir, nil)
+	defer func() { jobHealth = newJobHealthStore() }()
+
+	jobHealth = newJobHealthStore()
+	if err := LoadJobHealth(); err != nil {
+		t.Fatal(err)
+	}
+	jobHealth.SetEpoch(5)
+	jobs := []types.AssetJob{{StructsJob: bindings.StructsJob{Id: 1, Url: "https://api.gemini.com/v1/pubticker/ethusd"}}, {StructsJob: bindings.StructsJob{Name: "eth_coinbase", Url: "https://api.coinbase.com/v2/prices/ETH-USD/spot"}}}
+	jobHealth.Record("ethCollection", 5, jobs, []types.JobData{{Job: jobs[1], Value: big.NewInt(100000)}})
+	jobHealth.Record("btcCollection", 5, jobs[:1], nil)
+
+	// Health is only written once the epoch changes
+	if got, err := ReadJobHealth(); err != nil || len(got) != 0 {
+		t.Errorf("ReadJobHealth() within the epoch = %+v, err = %v, want nothing", got, err)
+	}
+	jobHealth.SetEpoch(6)
+	got, err := ReadJobHealth()
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []types.JobHealth{
+		{Collection: "btcCollection", Job: "#1", Url: jobs[0].Url, Epochs: 1, Failures: 1, FailureRate: 100, LastEpoch: 5, WeightFactor: 1},
+		{Collection: "ethCollection", Job: "#1", Url: jobs[0].Url, Epochs: 1, Failures: 1, FailureRate: 100, LastEpoch: 5, WeightFactor: 1},
+		{Collection: "ethCollection", Job: "eth_coinbase", Url: jobs[1].Url, Epochs: 1, LastEpoch: 5, WeightFactor: 1},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("ReadJobHealth() = %+v, want %+v", got, want)
+	}
+
+	// Values waiting for the median are kept across restarts
+	jobHealth = newJobHealthStore()
+	if err := LoadJobHealth(); err != nil {
+		t.Fatal(err)
+	}
+	if epoch, ok := jobHealth.PendingEpoch("ethCollection"); !ok || epoch != 5 {
+		t.Errorf("LoadJobHealth() did not load the values waiting for the median")
+	}
+}
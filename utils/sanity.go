@@ -0,0 +1,123 @@
+//Package utils provides the utils functions
+package utils
+
+import (
+	"fmt"
+	"math/big"
+	"razor/core/types"
+	"razor/metrics"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/ethclient"
+)
+
+var sanityPolicies = []string{"fallback", "commit", "block"}
+
+//This function returns the sanity bounds of the collection from its config in assets.json, their policy is fallback unless it is set
+func GetSanityBoundsFromConfig(bounds *types.SanityBounds) *types.SanityBounds {
+	if bounds == nil {
+		return nil
+	}
+	sanityBounds := *bounds
+	sanityBounds.Policy = strings.ToLower(sanityBounds.Policy)
+	if sanityBounds.Policy == "" {
+		sanityBounds.Policy = "fallback"
+	}
+	return &sanityBounds
+}
+
+//This function checks if any collection in assets.json has sanity bounds
+func hasSanityBounds(assets assetsContent) bool {
+	if assets.Config == nil {
+		return false
+	}
+	for _, collectionConfig := range assets.Config.Assets.Collection {
+		if collectionConfig.SanityBounds != nil {
+			return true
+		}
+	}
+	return false
+}
+
+//This function checks the value of the collection against its sanity bounds and applies their policy if it is outside of them
+func (*UtilsStruct) CheckSanityBounds(client *ethclient.Client, collectionId uint16, epoch uint32, value *big.Int) (*big.Int, error) {
+	assets, err := readAssetsFile()
+	// The collection is only fetched when there are sanity bounds in assets.json
+	if err != nil || !hasSanityBounds(assets) {
+		return value, err
+	}
+	collection, err := UtilsInterface.GetActiveCollection(client, collectionId)
+	if err != nil {
+		return nil, err
+	}
+	collectionConfig, _ := assets.Collection(collection.Name)
+	bounds := GetSanityBoundsFromConfig(collectionConfig.SanityBounds)
+	if bounds == nil {
+		return value, nil
+	}
+
+	previousValue := getPreviousValueForSanityCheck(client, epoch-1, collectionId, *bounds)
+	problem := checkSanityBounds(value, previousValue, *bounds)
+	if problem == nil {
+		return value, nil
+	}
+	metrics.SanityCheckFailuresMetric.WithLabelValues(collection.Name, bounds.Policy).Inc()
+
+	switch bounds.Policy {
+	case "commit":
+		log.Warnf("Committing value %s of collection %s although %s", value, collection.Name, problem)
+		return value, nil
+	case "block":
+		log.Errorf("Blocking commit as value %s of collection %s failed its sanity check: %s", value, collection.Name, problem)
+		// A collection without a value aborts the commit of the epoch like any other collection which fails
+		return nil, fmt.Errorf("commit is blocked as value of collection %s failed its sanity check: %s", collection.Name, problem)
+	}
+
+	log.Warnf("Value %s of collection %s failed its sanity check, using its fallback: %s", value, collection.Name, problem)
+	fallbackValue, err := aggregateWithFallback(client, epoch-1, collection, GetFallbackFromConfig(collection.Name, collectionConfig.Fallback), collectionConfig.OutlierFilter, collectionConfig.Reputation, collectionConfig.Aggregation)
+	if err != nil {
+		return nil, err
+	}
+	// A fallback which is not sane either is not committed
+	if problem := checkSanityBounds(fallbackValue, previousValue, *bounds); problem != nil {
+		log.Errorf("Blocking commit as fallback value %s of collection %s failed its sanity check too: %s", fallbackValue, collection.Name, problem)
+		return nil, fmt.Errorf("commit is blocked as value and fallback value of collection %s failed their sanity check: %s", collection.Name, problem)
+	}
+	return fallbackValue, nil
+}
+
+//This function returns the confirmed median of the collection in the previous epoch if its change is checked, nil if there is none
+func getPreviousValueForSanityCheck(client *ethclient.Client, previousEpoch uint32, collectionId uint16, bounds types.SanityBounds) *big.Int {
+	if bounds.MaxChange <= 0 {
+		return nil
+	}
+	previousValue, err := UtilsInterface.FetchPreviousValue(client, previousEpoch, collectionId)
+	if err != nil || previousValue == nil || previousValue.Sign() == 0 {
+		log.Debugf("Not checking change of collection %d as it has no previous value: %v", collectionId, err)
+		return nil
+	}
+	return previousValue
+}
+
+//This function returns why the value is outside the bounds, or nil if it is within them, its change is only checked if there is a previous value
+func checkSanityBounds(value *big.Int, previousValue *big.Int, bounds types.SanityBounds) error {
+	if value == nil {
+		return nil
+	}
+	valueFloat := new(big.Float).SetInt(value)
+	if bounds.Min != nil && valueFloat.Cmp(big.NewFloat(*bounds.Min)) < 0 {
+		return fmt.Errorf("it is less than min %v", *bounds.Min)
+	}
+	if bounds.Max != nil && valueFloat.Cmp(big.NewFloat(*bounds.Max)) > 0 {
+		return fmt.Errorf("it is more than max %v", *bounds.Max)
+	}
+	if previousValue != nil && bounds.MaxChange > 0 {
+		previousFloat := new(big.Float).SetInt(previousValue)
+		difference := new(big.Float).Sub(valueFloat, previousFloat)
+		change, _ := new(big.Float).Quo(difference.Abs(difference), previousFloat.Abs(previousFloat)).Float64()
+		if change*100 > bounds.MaxChange {
+			return fmt.Errorf("it changed %.2f%% from previous value %s, more than max change of %v%%", change*100, previousValue, bounds.MaxChange)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,215 @@
+package utils
+
+import (
+	"errors"
+	"io/fs"
+	"math/big"
+	"os"
+	"razor/core/types"
+	"razor/path"
+	pathMocks "razor/path/mocks"
+	"razor/pkg/bindings"
+	"razor/utils/mocks"
+	"reflect"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestCheckSanityBoundsOfValue(t *testing.T) {
+	var min, max float64 = 100000, 500000
+	tests := []struct {
+		name          string
+		value         *big.Int
+		previousValue *big.Int
+		bounds        types.SanityBounds
+		wantErr       bool
+	}{
+		{
+			name:   "Test 1: When value is within min and max",
+			value:  big.NewInt(300000),
+			bounds: types.SanityBounds{Min: &min, Max: &max},
+		},
+		{
+			name:    "Test 2: When value is less than min",
+			value:   big.NewInt(3000),
+			bounds:  types.SanityBounds{Min: &min, Max: &max},
+			wantErr: true,
+		},
+		{
+			name:    "Test 3: When value is more than max",
+			value:   big.NewInt(30000000),
+			bounds:  types.SanityBounds{Min: &min, Max: &max},
+			wantErr: true,
+		},
+		{
+			name:          "Test 4: When value changed less than max change",
+			value:         big.NewInt(310000),
+			previousValue: big.NewInt(300000),
+			bounds:        types.SanityBounds{MaxChange: 5},
+		},
+		{
+			name:          "Test 5: When value dropped more than max change",
+			value:         big.NewInt(250000),
+			previousValue: big.NewInt(300000),
+			bounds:        types.SanityBounds{MaxChange: 5},
+			wantErr:       true,
+		},
+		{
+			name:   "Test 6: When there is no previous value to check the change against",
+			value:  big.NewInt(250000),
+			bounds: types.SanityBounds{MaxChange: 5},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := checkSanityBounds(tt.value, tt.previousValue, tt.bounds); (err != nil) != tt.wantErr {
+				t.Errorf("checkSanityBounds() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestCheckSanityBounds(t *testing.T) {
+	var client *ethclient.Client
+	var fileInfo fs.FileInfo
+	collection := bindings.StructsCollection{Id: 1, Name: "ethCollectionMean"}
+	assetsWithPolicy := func(policy string) string {
+		return `{"assets": {"collection": {"ethCollectionMean": {"sanity bounds": {"min": 100000, "max": 500000, "max change": 10, "policy": "` + policy + `"}, "fallback": {"chain": ["previous value"]}}}}}`
+	}
+
+	type args struct {
+		fileData      string
+		value         *big.Int
+		previousValue *big.Int
+	}
+	tests := []struct {
+		name    string
+		args    args
+		want    *big.Int
+		wantErr bool
+	}{
+		{
+			name: "Test 1: When assets.json has no sanity bounds",
+			args: args{
+				fileData: `{"assets": {"collection": {"ethCollectionMean": {"power": 2}}}}`,
+				value:    big.NewInt(30000000),
+			},
+			want: big.NewInt(30000000),
+		},
+		{
+			name: "Test 2: When value is within the sanity bounds",
+			args: args{
+				fileData:      assetsWithPolicy("block"),
+				value:         big.NewInt(310000),
+				previousValue: big.NewInt(300000),
+			},
+			want: big.NewInt(310000),
+		},
+		{
+			name: "Test 3: When value fails the sanity check and the fallback is used",
+			args: args{
+				fileData:      assetsWithPolicy("fallback"),
+				value:         big.NewInt(30000000),
+				previousValue: big.NewInt(300000),
+			},
+			want: big.NewInt(300000),
+		},
+		{
+			name: "Test 4: When value fails the sanity check and is committed anyway",
+			args: args{
+				fileData:      assetsWithPolicy("commit"),
+				value:         big.NewInt(30000000),
+				previousValue: big.NewInt(300000),
+			},
+			want: big.NewInt(30000000),
+		},
+		{
+			name: "Test 5: When value fails the sanity check and commit is blocked",
+			args: args{
+				fileData:      assetsWithPolicy("block"),
+				value:         big.NewInt(30000000),
+				previousValue: big.NewInt(300000),
+			},
+			wantErr: true,
+		},
+		{
+			name: "Test 6: When fallback value fails the sanity check too",
+			args: args{
+				fileData:      assetsWithPolicy("fallback"),
+				value:         big.NewInt(30000000),
+				previousValue: big.NewInt(50000),
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			utilsMock := new(mocks.Utils)
+			pathUtilsMock := new(pathMocks.PathInterface)
+			osUtilsMock := new(pathMocks.OSInterface)
+			ioUtilsMock := new(mocks.IoutilUtils)
+
+			path.PathUtilsInterface = pathUtilsMock
+			path.OSUtilsInterface = osUtilsMock
+			utils := StartRazor(OptionsPackageStruct{UtilsInterface: utilsMock, IoutilInterface: ioUtilsMock})
+			mockAggregationState(utilsMock)
+
+			pathUtilsMock.On("GetJobFilePath").Return("./razor/assets.json", nil)
+			osUtilsMock.On("Stat", mock.Anything).Return(fileInfo, nil)
+			osUtilsMock.On("Open", mock.Anything).Return(&os.File{}, nil)
+			ioUtilsMock.On("ReadAll", mock.Anything).Return([]byte(tt.args.fileData), nil)
+			utilsMock.On("GetActiveCollection", mock.AnythingOfType("*ethclient.Client"), collection.Id).Return(collection, nil)
+			previousValueErr := error(nil)
+			if tt.args.previousValue == nil {
+				previousValueErr = errors.New("no previous value")
+			}
+			utilsMock.On("FetchPreviousValue", mock.AnythingOfType("*ethclient.Client"), uint32(9), collection.Id).Return(tt.args.previousValue, previousValueErr)
+
+			got, err := utils.CheckSanityBounds(client, collection.Id, 10, tt.args.value)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("CheckSanityBounds() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("CheckSanityBounds() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGetSanityBoundsFromConfig(t *testing.T) {
+	var min float64 = 100000
+	tests := []struct {
+		name     string
+		fileData string
+		want     *types.SanityBounds
+	}{
+		{
+			name:     "Test 1: When collection has no sanity bounds",
+			fileData: `{"assets": {"collection": {"ethCollectionMean": {"power": 2}}}}`,
+		},
+		{
+			name:     "Test 2: When sanity bounds have no policy",
+			fileData: `{"assets": {"collection": {"ethCollectionMean": {"sanity bounds": {"min": 100000, "max change": 10}}}}}`,
+			want:     &types.SanityBounds{Min: &min, MaxChange: 10, Policy: "fallback"},
+		},
+		{
+			name:     "Test 3: When sanity bounds have a policy",
+			fileData: `{"assets": {"collection": {"ethCollectionMean": {"sanity bounds": {"max change": 10, "policy": "Block"}}}}}`,
+			want:     &types.SanityBounds{MaxChange: 10, Policy: "block"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config, err := DecodeAssetsConfig(tt.fileData)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := GetSanityBoundsFromConfig(config.Assets.Collection["ethCollectionMean"].SanityBounds); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("GetSanityBoundsFromConfig() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,233 @@
+//Package utils provides the utils functions
+package utils
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"razor/core"
+	"razor/core/types"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/PaesslerAG/jsonpath"
+	"github.com/andybalholm/cascadia"
+	"github.com/antchfx/xpath"
+	"github.com/avast/retry-go"
+	"github.com/gocolly/colly"
+	"github.com/tidwall/gjson"
+)
+
+var selectorTypes = map[string]uint8{
+	"jsonpath": core.JSONSelectorType,
+	"xpath":    core.XHTMLSelectorType,
+	"css":      core.CSSSelectorType,
+	"regex":    core.RegexSelectorType,
+	"csv":      core.CSVSelectorType,
+	"gjson":    core.GJSONSelectorType,
+}
+
+//This function returns the selector type from its name used in assets.json
+func GetSelectorTypeFromName(name string) (uint8, error) {
+	selectorType, ok := selectorTypes[strings.ToLower(strings.TrimSpace(name))]
+	if !ok {
+		return 0, errors.New("invalid selector type " + name)
+	}
+	return selectorType, nil
+}
+
+//This function returns the name of the selector type used in assets.json
+func getSelectorTypeName(selectorType uint8) string {
+	for name, value := range selectorTypes {
+		if value == selectorType {
+			return name
+		}
+	}
+	return strconv.Itoa(int(selectorType))
+}
+
+//This function returns data from HTML using CSS selectors, all of them are selected from a single response
+func (*UtilsStruct) GetDataFromHTML(url string, selectors []string, request types.JobRequest) ([]string, error) {
+	httpRequest, err := BuildJobRequest(url, request)
+	if err != nil {
+		return nil, err
+	}
+	var priceData []string
+	var found []bool
+	err = fetchWithRetry(request, func(ctx context.Context, timeout time.Duration) error {
+		select {
+		case <-ctx.Done():
+			return retry.Unrecoverable(ctx.Err())
+		default:
+		}
+		// Every attempt scrapes with a new collector, so that a failed attempt leaves no data behind
+		data := make([]string, len(selectors))
+		dataFound := make([]bool, len(selectors))
+		c := colly.NewCollector()
+		c.WithTransport(contextTransport{ctx: ctx, base: getJobTransport(request)})
+		c.SetRequestTimeout(timeout)
+		for i := range selectors {
+			index := i
+			c.OnHTML(selectors[index], func(e *colly.HTMLElement) {
+				if !dataFound[index] {
+					data[index] = strings.TrimSpace(e.Text)
+					dataFound[index] = true
+				}
+			})
+		}
+		var body io.Reader
+		if request.Body != "" {
+			body = strings.NewReader(request.Body)
+		}
+		if err := c.Request(httpRequest.Method, httpRequest.URL.String(), body, nil, httpRequest.Header); err != nil {
+			return redactRequestError(err, httpRequest.URL)
+		}
+		priceData, found = data, dataFound
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	for index, selector := range selectors {
+		if !found[index] {
+			return nil, errors.New("no element matches CSS selector " + selector)
+		}
+	}
+	return priceData, nil
+}
+
+//This function returns the data from response selected by regex, which is the first capturing group if there is one
+func GetDataFromRegex(response []byte, selector string) (string, error) {
+	regex, err := regexp.Compile(selector)
+	if err != nil {
+		return "", err
+	}
+	match := regex.FindSubmatch(response)
+	if match == nil {
+		return "", errors.New("no match found for regex " + selector)
+	}
+	if len(match) > 1 {
+		return string(match[1]), nil
+	}
+	return string(match[0]), nil
+}
+
+//This function returns the data from CSV response selected by selector of the form [row:]column
+//Row is either the index of a data row or column=value selecting the first row having value in column, it defaults to the first data row.
+//Column is either the name of a column in the header row or its index.
+func GetDataFromCSV(response []byte, selector string) (string, error) {
+	records, err := csv.NewReader(bytes.NewReader(response)).ReadAll()
+	if err != nil {
+		return "", err
+	}
+	if len(records) < 2 {
+		return "", errors.New("CSV has no data rows")
+	}
+	header, rows := records[0], records[1:]
+
+	rowSelector, columnSelector := "0", selector
+	if index := strings.LastIndex(selector, ":"); index != -1 {
+		rowSelector, columnSelector = selector[:index], selector[index+1:]
+	}
+
+	column, err := getCSVColumnIndex(header, columnSelector)
+	if err != nil {
+		return "", err
+	}
+
+	var row []string
+	if key := strings.SplitN(rowSelector, "=", 2); len(key) == 2 {
+		keyColumn, err := getCSVColumnIndex(header, key[0])
+		if err != nil {
+			return "", err
+		}
+		for _, record := range rows {
+			if keyColumn < len(record) && record[keyColumn] == key[1] {
+				row = record
+				break
+			}
+		}
+		if row == nil {
+			return "", errors.New("no CSV row found with " + rowSelector)
+		}
+	} else {
+		rowIndex, err := strconv.Atoi(rowSelector)
+		if err != nil || rowIndex < 0 || rowIndex >= len(rows) {
+			return "", errors.New("invalid CSV row " + rowSelector)
+		}
+		row = rows[rowIndex]
+	}
+	if column >= len(row) {
+		return "", errors.New("CSV row has no column " + columnSelector)
+	}
+	return strings.TrimSpace(row[column]), nil
+}
+
+//This function returns the index of column in CSV header using its name or index
+func getCSVColumnIndex(header []string, column string) (int, error) {
+	for i, name := range header {
+		if strings.TrimSpace(name) == column {
+			return i, nil
+		}
+	}
+	index, err := strconv.Atoi(column)
+	if err != nil || index < 0 || index >= len(header) {
+		return 0, errors.New("invalid CSV column " + column)
+	}
+	return index, nil
+}
+
+//This function returns the data from JSON response selected by gjson path
+func GetDataFromGJSON(response []byte, selector string) (string, error) {
+	if !gjson.ValidBytes(response) {
+		return "", errors.New("invalid JSON response")
+	}
+	result := gjson.GetBytes(response, selector)
+	switch result.Type {
+	case gjson.Number:
+		// Raw keeps all the digits of the number
+		return result.Raw, nil
+	case gjson.String:
+		return result.String(), nil
+	case gjson.Null:
+		if !result.Exists() {
+			return "", errors.New("no value found for gjson path " + selector)
+		}
+	}
+	return "", errors.New("value selected by gjson path " + selector + " is not a number")
+}
+
+//This function checks that the selector can be used with the selector type
+func ValidateSelector(selectorType uint8, selector string) error {
+	if strings.TrimSpace(selector) == "" {
+		return errors.New("selector is empty")
+	}
+	var err error
+	switch selectorType {
+	case core.JSONSelectorType:
+		_, err = jsonpath.New(getJSONPath(selector))
+	case core.XHTMLSelectorType:
+		_, err = xpath.Compile(selector)
+	case core.CSSSelectorType:
+		_, err = cascadia.Compile(selector)
+	case core.RegexSelectorType:
+		_, err = regexp.Compile(selector)
+	case core.CSVSelectorType:
+		if strings.TrimSpace(selector[strings.LastIndex(selector, ":")+1:]) == "" {
+			err = errors.New("column is empty")
+		}
+	case core.GJSONSelectorType:
+		// Every string is a valid gjson path
+	default:
+		err = fmt.Errorf("invalid selector type %d", selectorType)
+	}
+	if err != nil {
+		return fmt.Errorf("invalid selector %q: %s", selector, err)
+	}
+	return nil
+}
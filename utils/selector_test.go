@@ -0,0 +1,384 @@
+package utils
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"razor/core"
+	"razor/core/types"
+	"razor/utils/mocks"
+	"testing"
+
+	"github.com/avast/retry-go"
+	"github.com/stretchr/testify/mock"
+)
+
+var htmlFixture = `<!DOCTYPE html>
+<html>
+  <body>
+    <div class="header"><h1>Ethereum price</h1></div>
+    <div class="price">
+      <span class="priceValue">$1,234.56</span>
+      <span class="priceChange">-2.5%</span>
+    </div>
+    <table id="markets">
+      <tr><td class="exchange">Gemini</td><td class="last">1,234.10</td></tr>
+      <tr><td class="exchange">Kraken</td><td class="last">1,235.20</td></tr>
+    </table>
+  </body>
+</html>`
+
+var textFixture = `ETH/USD last trade
+price=1234.56 volume=98765.4321
+updated 2022-03-01T10:00:00Z`
+
+var csvFixture = `symbol,last,volume
+BTC,40123.5,1200.25
+ETH,1234.56,98765.4321
+"RZR","0.01234","100,000"
+`
+
+var gjsonFixture = `{
+  "data": {
+    "symbol": "ETH",
+    "price": 1234.567890123456789012,
+    "priceString": "1234.56",
+    "active": true,
+    "markets": [
+      {"exchange": "gemini", "last": "1234.10"},
+      {"exchange": "kraken", "last": "1235.20"}
+    ]
+  }
+}`
+
+func TestGetSelectorTypeFromName(t *testing.T) {
+	tests := []struct {
+		name    string
+		want    uint8
+		wantErr bool
+	}{
+		{name: "jsonpath", want: core.JSONSelectorType},
+		{name: "XPath", want: core.XHTMLSelectorType},
+		{name: "css", want: core.CSSSelectorType},
+		{name: "regex", want: core.RegexSelectorType},
+		{name: " csv ", want: core.CSVSelectorType},
+		{name: "gjson", want: core.GJSONSelectorType},
+		{name: "yaml", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := GetSelectorTypeFromName(tt.name)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("GetSelectorTypeFromName() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if got != tt.want {
+				t.Errorf("GetSelectorTypeFromName() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGetDataFromHTMLWithCSSSelector(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/price" {
+			http.NotFound(w, r)
+			return
+		}
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		_, _ = w.Write([]byte(htmlFixture))
+	}))
+	defer server.Close()
+
+	tests := []struct {
+		name     string
+		path     string
+		selector string
+		want     string
+		wantErr  bool
+	}{
+		{
+			name:     "Test 1: When element is selected by class",
+			path:     "/price",
+			selector: "span.priceValue",
+			want:     "$1,234.56",
+		},
+		{
+			name:     "Test 2: When multiple elements match, first one is selected",
+			path:     "/price",
+			selector: "#markets td.last",
+			want:     "1,234.10",
+		},
+		{
+			name:     "Test 3: When element is selected by position",
+			path:     "/price",
+			selector: "#markets tr:nth-child(2) td.last",
+			want:     "1,235.20",
+		},
+		{
+			name:     "Test 4: When no element matches",
+			path:     "/price",
+			selector: "span.volume",
+			wantErr:  true,
+		},
+		{
+			name:     "Test 5: When page is not found",
+			path:     "/missing",
+			selector: "span.priceValue",
+			wantErr:  true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			utilsMock := new(mocks.Utils)
+			retryMock := new(mocks.RetryUtils)
+
+			optionsPackageStruct := OptionsPackageStruct{
+				UtilsInterface: utilsMock,
+				RetryInterface: retryMock,
+			}
+			utils := StartRazor(optionsPackageStruct)
+
+			retryMock.On("RetryAttempts", mock.AnythingOfType("uint")).Return(retry.Attempts(1))
+			mockFetchDeadline(utilsMock)
+
+			got, err := utils.GetDataFromHTML(server.URL+tt.path, []string{tt.selector}, types.JobRequest{})
+			if (err != nil) != tt.wantErr {
+				t.Errorf("GetDataFromHTML() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if err == nil && got[0] != tt.want {
+				t.Errorf("GetDataFromHTML() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGetDataFromRegex(t *testing.T) {
+	tests := []struct {
+		name     string
+		selector string
+		want     string
+		wantErr  bool
+	}{
+		{
+			name:     "Test 1: When regex has a capturing group",
+			selector: `price=([\d.]+)`,
+			want:     "1234.56",
+		},
+		{
+			name:     "Test 2: When regex has no capturing group",
+			selector: `\d+\.\d{4}`,
+			want:     "98765.4321",
+		},
+		{
+			name:     "Test 3: When regex has multiple capturing groups",
+			selector: `(volume)=([\d.]+)`,
+			want:     "volume",
+		},
+		{
+			name:     "Test 4: When regex does not match",
+			selector: `bid=([\d.]+)`,
+			wantErr:  true,
+		},
+		{
+			name:     "Test 5: When regex is invalid",
+			selector: `price=([\d.]+`,
+			wantErr:  true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := GetDataFromRegex([]byte(textFixture), tt.selector)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("GetDataFromRegex() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if got != tt.want {
+				t.Errorf("GetDataFromRegex() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGetDataFromCSV(t *testing.T) {
+	tests := []struct {
+		name     string
+		response string
+		selector string
+		want     string
+		wantErr  bool
+	}{
+		{
+			name:     "Test 1: When only column name is given",
+			response: csvFixture,
+			selector: "last",
+			want:     "40123.5",
+		},
+		{
+			name:     "Test 2: When row index and column name are given",
+			response: csvFixture,
+			selector: "1:last",
+			want:     "1234.56",
+		},
+		{
+			name:     "Test 3: When row is looked up by value of a column",
+			response: csvFixture,
+			selector: "symbol=ETH:volume",
+			want:     "98765.4321",
+		},
+		{
+			name:     "Test 4: When column index is given",
+			response: csvFixture,
+			selector: "symbol=RZR:1",
+			want:     "0.01234",
+		},
+		{
+			name:     "Test 5: When quoted field contains comma",
+			response: csvFixture,
+			selector: "2:volume",
+			want:     "100,000",
+		},
+		{
+			name:     "Test 6: When no row has the looked up value",
+			response: csvFixture,
+			selector: "symbol=DOT:last",
+			wantErr:  true,
+		},
+		{
+			name:     "Test 7: When row index is out of range",
+			response: csvFixture,
+			selector: "3:last",
+			wantErr:  true,
+		},
+		{
+			name:     "Test 8: When column does not exist",
+			response: csvFixture,
+			selector: "bid",
+			wantErr:  true,
+		},
+		{
+			name:     "Test 9: When CSV has only header",
+			response: "symbol,last\n",
+			selector: "last",
+			wantErr:  true,
+		},
+		{
+			name:     "Test 10: When CSV is malformed",
+			response: "symbol,last\nETH,\"1234.56\n",
+			selector: "last",
+			wantErr:  true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := GetDataFromCSV([]byte(tt.response), tt.selector)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("GetDataFromCSV() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if got != tt.want {
+				t.Errorf("GetDataFromCSV() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGetDataFromGJSON(t *testing.T) {
+	tests := []struct {
+		name     string
+		response string
+		selector string
+		want     string
+		wantErr  bool
+	}{
+		{
+			name:     "Test 1: When number is selected, all its digits are kept",
+			response: gjsonFixture,
+			selector: "data.price",
+			want:     "1234.567890123456789012",
+		},
+		{
+			name:     "Test 2: When string is selected",
+			response: gjsonFixture,
+			selector: "data.priceString",
+			want:     "1234.56",
+		},
+		{
+			name:     "Test 3: When array element is selected by query",
+			response: gjsonFixture,
+			selector: `data.markets.#(exchange=="kraken").last`,
+			want:     "1235.20",
+		},
+		{
+			name:     "Test 4: When array element is selected by index",
+			response: gjsonFixture,
+			selector: "data.markets.0.last",
+			want:     "1234.10",
+		},
+		{
+			name:     "Test 5: When path does not exist",
+			response: gjsonFixture,
+			selector: "data.volume",
+			wantErr:  true,
+		},
+		{
+			name:     "Test 6: When bool is selected",
+			response: gjsonFixture,
+			selector: "data.active",
+			wantErr:  true,
+		},
+		{
+			name:     "Test 7: When object is selected",
+			response: gjsonFixture,
+			selector: "data.markets.0",
+			wantErr:  true,
+		},
+		{
+			name:     "Test 8: When response is not JSON",
+			response: textFixture,
+			selector: "price",
+			wantErr:  true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := GetDataFromGJSON([]byte(tt.response), tt.selector)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("GetDataFromGJSON() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if got != tt.want {
+				t.Errorf("GetDataFromGJSON() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestValidateSelector(t *testing.T) {
+	tests := []struct {
+		name         string
+		selectorType uint8
+		selector     string
+		wantErr      bool
+	}{
+		{name: "Test 1: When jsonpath selector is valid", selectorType: core.JSONSelectorType, selector: "result.XETHZUSD.c[0]"},
+		{name: "Test 2: When jsonpath selector is invalid", selectorType: core.JSONSelectorType, selector: "result[", wantErr: true},
+		{name: "Test 3: When xpath selector is valid", selectorType: core.XHTMLSelectorType, selector: `//span[@class="priceValue"]`},
+		{name: "Test 4: When xpath selector is invalid", selectorType: core.XHTMLSelectorType, selector: "//span[", wantErr: true},
+		{name: "Test 5: When css selector is valid", selectorType: core.CSSSelectorType, selector: "#markets tr:nth-child(2) td.last"},
+		{name: "Test 6: When css selector is invalid", selectorType: core.CSSSelectorType, selector: "td.", wantErr: true},
+		{name: "Test 7: When regex selector is invalid", selectorType: core.RegexSelectorType, selector: "price=(", wantErr: true},
+		{name: "Test 8: When csv selector has no column", selectorType: core.CSVSelectorType, selector: "symbol=ETH:", wantErr: true},
+		{name: "Test 9: When gjson selector is valid", selectorType: core.GJSONSelectorType, selector: "data.markets.#(exchange==\"Gemini\").last"},
+		{name: "Test 10: When selector is empty", selectorType: core.GJSONSelectorType, selector: " ", wantErr: true},
+		{name: "Test 11: When selector type is invalid", selectorType: 9, selector: "last", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateSelector(tt.selectorType, tt.selector)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ValidateSelector() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -339,6 +339,16 @@ func (o OSStruct) WriteFile(name string, data []byte, perm fs.FileMode) error {
 	return os.WriteFile(name, data, perm)
 }
 
+//This function is used to create a new temporary file in the directory
+func (o OSStruct) CreateTemp(dir string, pattern string) (*os.File, error) {
+	return os.CreateTemp(dir, pattern)
+}
+
+//This function is used to rename the file
+func (o OSStruct) Rename(oldPath string, newPath string) error {
+	return os.Rename(oldPath, newPath)
+}
+
 //This function returns the transaction receipt
 func (c ClientStruct) TransactionReceipt(client *ethclient.Client, ctx context.Context, txHash common.Hash) (*types.Receipt, error) {
 	return client.TransactionReceipt(ctx, txHash)
@@ -0,0 +1,253 @@
+//Package utils provides the utils functions
+package utils
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"net/url"
+	"os"
+	"razor/core"
+	"razor/core/types"
+	"reflect"
+	"strings"
+	"sync"
+)
+
+var proxySchemes = []string{"http", "https", "socks5"}
+
+type transportOptionsKey struct{}
+
+// Options of the job a request is made for, they select the transport the request is sent with
+type transportOptions struct {
+	proxy             string
+	clientCertificate *types.ClientCertificate
+}
+
+//FetchTransport is the http.RoundTripper every job request is sent with, it applies the http settings of assets.json and the proxy and client certificate of the job
+type FetchTransport struct {
+	mutex      sync.Mutex
+	settings   types.HTTPConfig
+	rootCAs    *x509.CertPool
+	err        error
+	transports map[string]*http.Transport
+}
+
+var fetchTransport = &FetchTransport{transports: make(map[string]*http.Transport)}
+
+//This function applies the http settings of assets.json, they are only applied again once they change
+func (transport *FetchTransport) Configure(config *types.HTTPConfig) error {
+	var settings types.HTTPConfig
+	if config != nil {
+		settings = *config
+	}
+	transport.mutex.Lock()
+	defer transport.mutex.Unlock()
+	if reflect.DeepEqual(settings, transport.settings) && transport.transports != nil {
+		return transport.err
+	}
+	for _, previous := range transport.transports {
+		previous.CloseIdleConnections()
+	}
+	transport.settings = settings
+	transport.rootCAs = nil
+	transport.transports = make(map[string]*http.Transport)
+
+	// Requests fail while the settings are invalid, so that they are never sent without the proxy or certificates they require
+	transport.err = nil
+	if len(transport.settings.CAFiles) > 0 {
+		transport.rootCAs, transport.err = loadRootCAs(transport.settings.CAFiles)
+	}
+	return transport.err
+}
+
+//This function sends the request with the transport for the proxy and client certificate of its job and enforces the size and content type limits on the response
+func (transport *FetchTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	options, _ := req.Context().Value(transportOptionsKey{}).(transportOptions)
+	httpTransport, settings, err := transport.getTransport(options)
+	if err != nil {
+		return nil, err
+	}
+	response, err := httpTransport.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkContentType(response.Header.Get("Content-Type"), settings.ContentTypes); err != nil {
+		response.Body.Close()
+		return nil, err
+	}
+	maxResponseSize := settings.MaxResponseSize
+	if maxResponseSize <= 0 {
+		maxResponseSize = core.DefaultMaxResponseSize
+	}
+	if response.ContentLength > maxResponseSize {
+		response.Body.Close()
+		return nil, fmt.Errorf("response of %d bytes is larger than max response size of %d bytes", response.ContentLength, maxResponseSize)
+	}
+	response.Body = &limitedBody{ReadCloser: response.Body, remaining: maxResponseSize, limit: maxResponseSize}
+	return response, nil
+}
+
+//This function returns the transport for the options of a job, transports are shared by the jobs with the same proxy and client certificate
+func (transport *FetchTransport) getTransport(options transportOptions) (*http.Transport, types.HTTPConfig, error) {
+	transport.mutex.Lock()
+	defer transport.mutex.Unlock()
+	if transport.err != nil {
+		return nil, transport.settings, transport.err
+	}
+	proxy := options.proxy
+	if proxy == "" {
+		proxy = transport.settings.Proxy
+	}
+	clientCertificate := options.clientCertificate
+	if clientCertificate == nil {
+		clientCertificate = transport.settings.ClientCertificate
+	}
+	key := proxy
+	if clientCertificate != nil {
+		key += "\n" + clientCertificate.CertFile + "\n" + clientCertificate.KeyFile
+	}
+	if transport.transports == nil {
+		transport.transports = make(map[string]*http.Transport)
+	}
+	if httpTransport, ok := transport.transports[key]; ok {
+		return httpTransport, transport.settings, nil
+	}
+	httpTransport, err := newHTTPTransport(proxy, clientCertificate, transport.rootCAs)
+	if err != nil {
+		return nil, transport.settings, err
+	}
+	transport.transports[key] = httpTransport
+	return httpTransport, transport.settings, nil
+}
+
+//This function returns a transport sending requests through the proxy, the proxy of the environment is used if there is none
+func newHTTPTransport(proxy string, clientCertificate *types.ClientCertificate, rootCAs *x509.CertPool) (*http.Transport, error) {
+	httpTransport := http.DefaultTransport.(*http.Transport).Clone()
+	if proxy != "" {
+		proxyUrl, err := ParseProxyURL(proxy)
+		if err != nil {
+			return nil, err
+		}
+		httpTransport.Proxy = http.ProxyURL(proxyUrl)
+	}
+	tlsConfig := &tls.Config{MinVersion: tls.VersionTLS12, RootCAs: rootCAs}
+	if clientCertificate != nil {
+		certificate, err := tls.LoadX509KeyPair(clientCertificate.CertFile, clientCertificate.KeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("error in loading client certificate: %s", err)
+		}
+		tlsConfig.Certificates = []tls.Certificate{certificate}
+	}
+	httpTransport.TLSClientConfig = tlsConfig
+	return httpTransport, nil
+}
+
+//This function parses the URL of a HTTP, HTTPS or SOCKS5 proxy
+func ParseProxyURL(proxy string) (*url.URL, error) {
+	proxyUrl, err := url.Parse(proxy)
+	if err != nil || !Contains(proxySchemes, proxyUrl.Scheme) || proxyUrl.Host == "" {
+		return nil, fmt.Errorf("invalid proxy %s, it must be a %s URL", proxy, strings.Join(proxySchemes, ", "))
+	}
+	return proxyUrl, nil
+}
+
+//This function returns the system certificate pool with the certificates of the CA files added to it
+func loadRootCAs(caFiles []string) (*x509.CertPool, error) {
+	rootCAs, err := x509.SystemCertPool()
+	if err != nil || rootCAs == nil {
+		rootCAs = x509.NewCertPool()
+	}
+	for _, caFile := range caFiles {
+		data, err := os.ReadFile(caFile)
+		if err != nil {
+			return nil, fmt.Errorf("error in reading CA file: %s", err)
+		}
+		if !rootCAs.AppendCertsFromPEM(data) {
+			return nil, fmt.Errorf("no PEM certificate in CA file %s", caFile)
+		}
+	}
+	return rootCAs, nil
+}
+
+//This function checks the content type of a response against the allowed ones, which can end with /* to allow every subtype
+func checkContentType(contentType string, allowedTypes []string) error {
+	if len(allowedTypes) == 0 {
+		return nil
+	}
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return fmt.Errorf("response has invalid content type %q", contentType)
+	}
+	for _, allowedType := range allowedTypes {
+		allowedType = strings.ToLower(allowedType)
+		if mediaType == allowedType || (strings.HasSuffix(allowedType, "/*") && strings.HasPrefix(mediaType, strings.TrimSuffix(allowedType, "*"))) {
+			return nil
+		}
+	}
+	return fmt.Errorf("response has content type %s which is not one of %s", mediaType, strings.Join(allowedTypes, ", "))
+}
+
+type limitedBody struct {
+	io.ReadCloser
+	remaining int64
+	limit     int64
+}
+
+//This function reads the body of the response until more than its limit is read, which is an error
+func (body *limitedBody) Read(data []byte) (int, error) {
+	if body.remaining < 0 {
+		return 0, fmt.Errorf("response is larger than max response size of %d bytes", body.limit)
+	}
+	if int64(len(data)) > body.remaining+1 {
+		data = data[:body.remaining+1]
+	}
+	n, err := body.ReadCloser.Read(data)
+	body.remaining -= int64(n)
+	if body.remaining < 0 {
+		return n, fmt.Errorf("response is larger than max response size of %d bytes", body.limit)
+	}
+	return n, err
+}
+
+//This function returns the transport sending the requests of a job, the responses are shared with other jobs through the response cache
+func getJobTransport(request types.JobRequest) http.RoundTripper {
+	return jobTransport{options: transportOptions{proxy: request.Proxy, clientCertificate: request.ClientCertificate}}
+}
+
+type jobTransport struct {
+	options transportOptions
+}
+
+//This function sends the request through the response recording and cache with the options of its job
+func (transport jobTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	ctx := context.WithValue(req.Context(), transportOptionsKey{}, transport.options)
+	return responseArchive.RoundTrip(req.WithContext(ctx))
+}
+
+//This function returns the key identifying the options of the request in the response cache, it is empty if the request has none
+func getTransportOptionsKey(req *http.Request) string {
+	options, _ := req.Context().Value(transportOptionsKey{}).(transportOptions)
+	key := options.proxy
+	if options.clientCertificate != nil {
+		key += " " + options.clientCertificate.CertFile
+	}
+	return key
+}
+
+//This function reads assets.json and applies its http settings, so that jobs which are not read from assets.json are fetched with them too
+func LoadHTTPConfig() error {
+	assets, err := readAssetsFile()
+	if err != nil {
+		return err
+	}
+	var config *types.HTTPConfig
+	if assets.Config != nil {
+		config = assets.Config.HTTP
+	}
+	return fetchTransport.Configure(config)
+}
@@ -0,0 +1,268 @@
+package utils
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"fmt"
+	"io"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"razor/core/types"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestFetchTransportWithProxy(t *testing.T) {
+	newProxy := func(name string) *httptest.Server {
+		// A proxy of plain http requests gets the absolute URL of the source
+		return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			io.WriteString(w, fmt.Sprintf(`{"proxy": "%s", "host": "%s"}`, name, r.URL.Host))
+		}))
+	}
+	globalProxy, jobProxy := newProxy("global"), newProxy("job")
+	defer globalProxy.Close()
+	defer jobProxy.Close()
+
+	StartRazor(OptionsPackageStruct{UtilsInterface: &UtilsStruct{}, RetryInterface: RetryStruct{}, IoutilInterface: IoutilStruct{}})
+	defer fetchTransport.Configure(nil)
+	if err := fetchTransport.Configure(&types.HTTPConfig{Proxy: globalProxy.URL}); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name    string
+		request types.JobRequest
+		want    string
+	}{
+		{
+			name:    "Test 1: When job uses the proxy of the http settings",
+			request: types.JobRequest{Attempts: 1},
+			want:    `{"proxy": "global", "host": "prices.example"}`,
+		},
+		{
+			name:    "Test 2: When job has its own proxy",
+			request: types.JobRequest{Attempts: 1, Proxy: jobProxy.URL},
+			want:    `{"proxy": "job", "host": "prices.example"}`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := UtilsInterface.GetDataFromAPI("http://prices.example/eth", tt.request)
+			if err != nil || string(got) != tt.want {
+				t.Errorf("GetDataFromAPI() got = %s, err = %v, want %s", got, err, tt.want)
+			}
+		})
+	}
+}
+
+func TestFetchTransportWithCertificates(t *testing.T) {
+	dir := t.TempDir()
+	clientCertFile, clientKeyFile, clientCert := writeTestCertificate(t, dir, "client")
+
+	clientCAs := x509.NewCertPool()
+	clientCAs.AddCert(clientCert)
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		io.WriteString(w, `{"last": "1234.5"}`)
+	}))
+	server.TLS = &tls.Config{ClientAuth: tls.RequireAndVerifyClientCert, ClientCAs: clientCAs}
+	server.StartTLS()
+	defer server.Close()
+
+	caFile := filepath.Join(dir, "ca.pem")
+	if err := os.WriteFile(caFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw}), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	StartRazor(OptionsPackageStruct{UtilsInterface: &UtilsStruct{}, RetryInterface: RetryStruct{}, IoutilInterface: IoutilStruct{}})
+	defer fetchTransport.Configure(nil)
+
+	clientCertificate := &types.ClientCertificate{CertFile: clientCertFile, KeyFile: clientKeyFile}
+	tests := []struct {
+		name    string
+		config  types.HTTPConfig
+		request types.JobRequest
+		wantErr bool
+	}{
+		{
+			name:   "Test 1: When CA file and client certificate are in the http settings",
+			config: types.HTTPConfig{CAFiles: []string{caFile}, ClientCertificate: clientCertificate},
+		},
+		{
+			name:    "Test 2: When job has its own client certificate",
+			config:  types.HTTPConfig{CAFiles: []string{caFile}},
+			request: types.JobRequest{ClientCertificate: &types.ClientCertificate{CertFile: clientCertFile, KeyFile: clientKeyFile}},
+		},
+		{
+			name:    "Test 3: When there is no client certificate",
+			config:  types.HTTPConfig{CAFiles: []string{caFile}},
+			wantErr: true,
+		},
+		{
+			name:    "Test 4: When certificate of the source is not signed by a known CA",
+			config:  types.HTTPConfig{ClientCertificate: clientCertificate},
+			wantErr: true,
+		},
+		{
+			name:    "Test 5: When CA file cannot be read",
+			config:  types.HTTPConfig{CAFiles: []string{filepath.Join(dir, "missing.pem")}, ClientCertificate: clientCertificate},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fetchTransport.Configure(&tt.config)
+			tt.request.Attempts = 1
+			got, err := UtilsInterface.GetDataFromAPI(server.URL, tt.request)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("GetDataFromAPI() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !tt.wantErr && string(got) != `{"last": "1234.5"}` {
+				t.Errorf("GetDataFromAPI() got = %s", got)
+			}
+		})
+	}
+}
+
+func TestFetchTransportLimits(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/html":
+			w.Header().Set("Content-Type", "text/html; charset=utf-8")
+			io.WriteString(w, `<span class="last">1234.5</span>`)
+		case "/large":
+			// The size of a chunked response is only known once it is read
+			w.Header().Set("Content-Type", "application/json")
+			w.(http.Flusher).Flush()
+			io.WriteString(w, `{"last": "1234.5", "padding": "`+strings.Repeat("0", 100)+`"}`)
+		default:
+			w.Header().Set("Content-Type", "application/json")
+			io.WriteString(w, `{"last": "1234.5"}`)
+		}
+	}))
+	defer server.Close()
+
+	StartRazor(OptionsPackageStruct{UtilsInterface: &UtilsStruct{}, RetryInterface: RetryStruct{}, IoutilInterface: IoutilStruct{}})
+	defer fetchTransport.Configure(nil)
+	if err := fetchTransport.Configure(&types.HTTPConfig{MaxResponseSize: 64, ContentTypes: []string{"application/json", "text/plain"}}); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name    string
+		path    string
+		wantErr bool
+	}{
+		{
+			name: "Test 1: When response is within the limits",
+			path: "/json",
+		},
+		{
+			name:    "Test 2: When content type of response is not allowed",
+			path:    "/html",
+			wantErr: true,
+		},
+		{
+			name:    "Test 3: When response is larger than max response size",
+			path:    "/large",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := UtilsInterface.GetDataFromAPI(server.URL+tt.path, types.JobRequest{Attempts: 1})
+			if (err != nil) != tt.wantErr {
+				t.Errorf("GetDataFromAPI() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestCheckContentType(t *testing.T) {
+	tests := []struct {
+		name         string
+		contentType  string
+		allowedTypes []string
+		wantErr      bool
+	}{
+		{
+			name:        "Test 1: When every content type is allowed",
+			contentType: "text/html",
+		},
+		{
+			name:         "Test 2: When content type with parameters is allowed",
+			contentType:  "application/json; charset=utf-8",
+			allowedTypes: []string{"application/json"},
+		},
+		{
+			name:         "Test 3: When every subtype is allowed",
+			contentType:  "text/csv",
+			allowedTypes: []string{"text/*"},
+		},
+		{
+			name:         "Test 4: When content type is not allowed",
+			contentType:  "text/html",
+			allowedTypes: []string{"application/json", "text/plain"},
+			wantErr:      true,
+		},
+		{
+			name:         "Test 5: When response has no content type",
+			allowedTypes: []string{"application/json"},
+			wantErr:      true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := checkContentType(tt.contentType, tt.allowedTypes); (err != nil) != tt.wantErr {
+				t.Errorf("checkContentType() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+//This function writes a self signed certificate and its key to the directory and returns their files and the certificate
+func writeTestCertificate(t *testing.T, dir string, name string) (string, string, *x509.Certificate) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: name},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		KeyUsage:              x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	certificate, err := x509.ParseCertificate(der)
+	if err != nil {
+		t.Fatal(err)
+	}
+	keyDer, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	certFile, keyFile := filepath.Join(dir, name+".pem"), filepath.Join(dir, name+"-key.pem")
+	if err := os.WriteFile(certFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(keyFile, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDer}), 0600); err != nil {
+		t.Fatal(err)
+	}
+	return certFile, keyFile, certificate
+}
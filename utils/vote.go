@@ -38,7 +38,7 @@ func (*UtilsStruct) GetCommitments(client *ethclient.Client, address string) ([3
 			return nil
 		}, RetryInterface.RetryAttempts(core.MaxRetries))
 	if commitmentErr != nil {
-		return [32]byte{}, err
+		return [32]byte{}, commitmentErr
 	}
 	return commitments.CommitmentHash, nil
 }
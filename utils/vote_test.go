@@ -57,7 +57,7 @@ func TestGetCommitments(t *testing.T) {
 				commitmentErr: errors.New("commitments error"),
 			},
 			want:    [32]byte{},
-			wantErr: false,
+			wantErr: true,
 		},
 	}
 	for _, tt := range tests {